@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/services"
+	"url-shortener/internal/utils"
+)
+
+// runCommand runs a maintenance subcommand instead of the HTTP server,
+// e.g. `url-shortener warm-cache -top 1000`.
+func runCommand(name string, args []string) {
+	switch name {
+	case "warm-cache":
+		warmCache(args)
+	case "export-clicks":
+		exportClicks(args)
+	default:
+		log.Fatalf("unknown command %q, available commands: warm-cache, export-clicks", name)
+	}
+}
+
+func warmCache(args []string) {
+	flags := flag.NewFlagSet("warm-cache", flag.ExitOnError)
+	configPath := flags.String("config", "./config.json", "path to the config file")
+	topN := flags.Int("top", 0, "only load the N most clicked links in the window (0 loads every link)")
+	window := flags.Duration("window", 24*time.Hour, "window used to rank links by clicks")
+	batchSize := flags.Int("batch", 500, "number of links written per Redis pipeline")
+	rate := flags.Int("rate", 0, "maximum number of links loaded per second (0 is unlimited)")
+	flags.Parse(args)
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	dbCluster, err := db.NewPostgresClusterFromConfig(&cfg.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer dbCluster.Close()
+	dbCluster.StartHealthChecks(context.Background(), cfg.Database.ReplicaCheckInterval)
+	redisClient, err := db.NewRedisClient(&cfg.Redis)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	timeProvider := utils.NewTimeProvider()
+	warmupService := services.NewCacheWarmupService(
+		repositories.NewURLListingRepositoryPostgresql(dbCluster),
+		repositories.NewURLCacheRepositoryRedis(redisClient, repositories.NewCachePolicy(cfg.Cache, timeProvider)),
+		timeProvider,
+	)
+	opts := models.CacheWarmupOptions{TopN: *topN, Window: *window, BatchSize: *batchSize, RatePerSecond: *rate}
+	progress, err := warmupService.WarmUp(context.Background(), opts, func(p models.CacheWarmupProgress) {
+		log.Printf("scanned=%d loaded=%d skipped=%d", p.Scanned, p.Loaded, p.Skipped)
+	})
+	if err != nil {
+		log.Printf("Cache warm-up failed: %v", err)
+		os.Exit(1)
+	}
+	log.Printf("Cache warm-up finished in %s: scanned=%d loaded=%d skipped=%d",
+		progress.FinishedAt.Sub(*progress.StartedAt).Round(time.Millisecond), progress.Scanned, progress.Loaded, progress.Skipped)
+}
+
+func exportClicks(args []string) {
+	flags := flag.NewFlagSet("export-clicks", flag.ExitOnError)
+	configPath := flags.String("config", "./config.json", "path to the config file")
+	format := flags.String("format", services.ExportFormatCSV, "file format: csv, jsonl or parquet")
+	shortPath := flags.String("short-path", "", "only export clicks of this short URL")
+	owner := flags.String("owner", "", "only export clicks of short URLs created by this user")
+	from := flags.String("from", "", "start of the range, inclusive, as RFC 3339 (default the first click)")
+	to := flags.String("to", "", "end of the range, exclusive, as RFC 3339 (default now)")
+	outPath := flags.String("out", "", "file to write to (default stdout)")
+	flags.Parse(args)
+
+	filter := models.ClickEventFilter{ShortPath: *shortPath, Owner: *owner}
+	var err error
+	if *from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, *from); err != nil {
+			log.Fatalf("invalid -from: %v", err)
+		}
+	}
+	if *to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, *to); err != nil {
+			log.Fatalf("invalid -to: %v", err)
+		}
+	}
+	if _, _, err := services.ExportContentType(*format); err != nil {
+		log.Fatal(err)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	dbCluster, err := db.NewPostgresClusterFromConfig(&cfg.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer dbCluster.Close()
+	dbCluster.StartHealthChecks(context.Background(), cfg.Database.ReplicaCheckInterval)
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	exportService := services.NewClickExportService(repositories.NewClickEventRepositoryPostgresql(dbCluster), utils.NewTimeProvider(), cfg.Exports.PageSize)
+	exported, err := exportService.Export(context.Background(), filter, *format, out)
+	if err != nil {
+		log.Printf("Click export failed after %d clicks: %v", exported, err)
+		os.Exit(1)
+	}
+	log.Printf("Exported %d clicks", exported)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/db"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/services"
+
+	"github.com/nats-io/nats.go"
+)
+
+// newEventSinks returns the sinks the outbox relay sends link events to, cache invalidation first, and a function
+// closing their connections and files.
+func newEventSinks(outboxConfig config.OutboxConfig, redisClient db.RedisClient, cache repositories.URLRepository) ([]services.EventSink, func(), error) {
+	sinks := []services.EventSink{services.NewCacheInvalidationSink(cache)}
+	var closers []func()
+	closeAll := func() {
+		for _, closer := range closers {
+			closer()
+		}
+	}
+
+	if outboxConfig.RedisStream != "" {
+		sinks = append(sinks, services.NewRedisStreamSink(repositories.NewLinkEventStreamRepositoryRedis(redisClient), outboxConfig.RedisStream, outboxConfig.RedisStreamMaxLen))
+	}
+	if outboxConfig.NATSURL != "" {
+		// A server that is down does not keep the shortener from starting, events wait in the outbox until it is back.
+		conn, err := nats.Connect(outboxConfig.NATSURL, nats.Name("url-shortener"), nats.RetryOnFailedConnect(true), nats.MaxReconnects(-1))
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+		closers = append(closers, func() { _ = conn.Drain() })
+		sinks = append(sinks, services.NewNATSSink(conn, outboxConfig.NATSSubject))
+	}
+	switch outboxConfig.FilePath {
+	case "":
+	case "-":
+		sinks = append(sinks, services.NewWriterSink("stdout", os.Stdout))
+	default:
+		file, err := os.OpenFile(outboxConfig.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+		closers = append(closers, func() { _ = file.Close() })
+		sinks = append(sinks, services.NewWriterSink("file", file))
+	}
+	return sinks, closeAll, nil
+}
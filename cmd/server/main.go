@@ -1,8 +1,16 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
+	// Embedded so time zones of the stats API resolve in images without tzdata.
+	_ "time/tzdata"
 
 	// Import net/http for status codes
 	api "url-shortener/generated" // Import the generated package
@@ -18,6 +26,10 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1], os.Args[2:])
+		return
+	}
 
 	gin.SetMode(gin.ReleaseMode)
 	defaultConfig, err := config.LoadConfig("./config.json")
@@ -25,32 +37,110 @@ func main() {
 		log.Fatal(err)
 	}
 
-	dbConn, err := db.NewPostgresConnection(&defaultConfig.Database)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	dbCluster, err := db.NewPostgresClusterFromConfig(&defaultConfig.Database)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer dbConn.Close()
+	defer dbCluster.Close()
+	dbCluster.StartHealthChecks(ctx, defaultConfig.Database.ReplicaCheckInterval)
 
-	pgRepo := repositories.NewURLRepositoryPostgresql(dbConn)
-	redisClient := db.NewRedisClient(&defaultConfig.Redis)
-	redisRepo := repositories.NewURLRepositoryRedis(redisClient, time.Hour)
+	pgRepo := repositories.NewURLRepositoryPostgresql(dbCluster)
+	redisClient, err := db.NewRedisClient(&defaultConfig.Redis)
+	if err != nil {
+		log.Fatal(err)
+	}
 	timeProvider := utils.NewTimeProvider()
+	cachePolicy := repositories.NewCachePolicy(defaultConfig.Cache, timeProvider)
+	redisRepo := repositories.NewURLRepositoryRedis(redisClient, cachePolicy)
 	urlRepo := repositories.NewURLRepository(redisRepo, pgRepo, timeProvider)
-	urlStatPgRepo := repositories.NewURLStatisticsRepositoryPostgresql(dbConn)
+	urlStatPgRepo := repositories.NewURLStatisticsRepositoryPostgresql(dbCluster, defaultConfig.Stats.BreakdownWindow)
 	idGenerator := utils.NewNanoIDGenerator(12)
 
-	urlService := services.NewURLService(urlRepo, urlStatPgRepo, idGenerator, timeProvider)
-	urlStatService := services.NewURLStatsService(urlStatPgRepo)
+	geoIPResolver, err := utils.NewGeoIPResolver(defaultConfig.GeoIP.DatabasePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	geoIPResolver.StartReloading(ctx, defaultConfig.GeoIP.ReloadInterval)
+
+	botClassifier, err := utils.NewBotClassifier(defaultConfig.Bots.RulesPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	uniqueVisitorRepo := repositories.NewUniqueVisitorRepositoryRedis(redisClient)
+	ipAnonymizer, err := services.NewIPAnonymizer(defaultConfig.Privacy, repositories.NewIPSaltRepositoryRedis(redisClient))
+	if err != nil {
+		log.Fatal(err)
+	}
+	clickRollupRepo := repositories.NewClickRollupRepositoryPostgresql(dbCluster)
+	accessLogPartitionJob := services.NewAccessLogPartitionJob(repositories.NewAccessLogPartitionRepositoryPostgresql(dbCluster), clickRollupRepo, defaultConfig.Partitions, timeProvider)
+	if err := accessLogPartitionJob.CreatePartitions(ctx); err != nil {
+		log.Fatal(err)
+	}
+	accessLogPipeline := services.NewAccessLogPipeline(urlStatPgRepo, uniqueVisitorRepo, geoIPResolver, ipAnonymizer, defaultConfig.Clicks)
+	accessLogPipeline.Start()
+
+	clickStreamHub := services.NewClickStreamHub(
+		repositories.NewClickStreamRepositoryRedis(redisClient, defaultConfig.Streams.HistorySize, defaultConfig.Streams.HistoryTTL),
+		geoIPResolver,
+		defaultConfig.Streams,
+	)
+	clickStreamHub.Start()
+
+	clickCounterRepo := repositories.NewClickCounterRepositoryRedis(redisClient)
+	clickDedupRepo := repositories.NewClickDedupRepositoryRedis(redisClient)
+	issuedClickRepo := repositories.NewIssuedClickRepositoryRedis(redisClient, defaultConfig.Conversions.AttributionWindow)
+	urlService := services.NewURLService(urlRepo, repositories.NewURLArchiveRepositoryPostgresql(dbCluster), accessLogPipeline, clickCounterRepo, clickDedupRepo, issuedClickRepo, clickStreamHub, idGenerator, timeProvider, defaultConfig.Conversions.ClickIDParam, defaultConfig.Clicks.DedupWindow)
+	conversionRepo := repositories.NewConversionRepositoryPostgresql(dbCluster)
+	urlStatService := services.NewURLStatsService(urlStatPgRepo, uniqueVisitorRepo, clickCounterRepo, conversionRepo, timeProvider)
+
+	cacheWarmupService := services.NewCacheWarmupService(
+		repositories.NewURLListingRepositoryPostgresql(dbCluster),
+		repositories.NewURLCacheRepositoryRedis(redisClient, cachePolicy),
+		timeProvider,
+	)
+
+	scheduler := services.NewScheduler()
+	scheduler.Add(services.NewClickRollupJob(clickRollupRepo, defaultConfig.Rollups, timeProvider), defaultConfig.Rollups.Interval)
+	scheduler.Add(accessLogPartitionJob, defaultConfig.Partitions.Interval)
+	scheduler.Add(services.NewClickCounterReconcileJob(clickRollupRepo, clickCounterRepo, defaultConfig.Counters, timeProvider), defaultConfig.Counters.ReconcileInterval)
+	trafficAnomalyRepo := repositories.NewTrafficAnomalyRepositoryPostgresql(dbCluster)
+	scheduler.Add(services.NewTrafficAnomalyJob(trafficAnomalyRepo, services.NewWebhookAlertNotifier(defaultConfig.Alerts), defaultConfig.Alerts, timeProvider), defaultConfig.Alerts.Interval)
+	scheduler.Add(services.NewAccessLogPurgeJob(repositories.NewAccessLogPurgeRepositoryPostgresql(dbCluster), defaultConfig.Privacy, timeProvider), defaultConfig.Privacy.PurgeInterval)
+	scheduler.Add(services.NewWebhookDispatchJob(repositories.NewWebhookDeliveryRepositoryPostgresql(dbCluster), defaultConfig.Webhooks, timeProvider), defaultConfig.Webhooks.Interval)
+	eventSinks, closeEventSinks, err := newEventSinks(defaultConfig.Outbox, redisClient, redisRepo)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer closeEventSinks()
+	scheduler.Add(services.NewOutboxRelayJob(repositories.NewOutboxRepositoryPostgresql(dbCluster), eventSinks, defaultConfig.Outbox, timeProvider), defaultConfig.Outbox.RelayInterval)
+	scheduler.Start(ctx)
 
-	serverInterface := handlers.NewURLHandler(urlService, urlStatService, timeProvider)
+	serverInterface := handlers.NewServer(
+		handlers.NewURLHandler(urlService, urlStatService, timeProvider, utils.NewVisitorFingerprinter(defaultConfig.Visitors.Secret), botClassifier, defaultConfig.Privacy.HonorDNT, defaultConfig.Audit.ActorHeader),
+		handlers.NewAdminHandler(cacheWarmupService, accessLogPipeline),
+		handlers.NewExportHandler(services.NewClickExportService(repositories.NewClickEventRepositoryPostgresql(dbCluster), timeProvider, defaultConfig.Exports.PageSize)),
+		handlers.NewClickStreamHandler(urlService, clickStreamHub, defaultConfig.Streams),
+		handlers.NewAlertHandler(services.NewAlertSettingsService(trafficAnomalyRepo, urlRepo, defaultConfig.Alerts)),
+		handlers.NewConversionHandler(services.NewConversionService(conversionRepo, issuedClickRepo, defaultConfig.Conversions, timeProvider)),
+		handlers.NewWebhookHandler(services.NewWebhookService(repositories.NewWebhookSubscriptionRepositoryPostgresql(dbCluster), timeProvider)),
+		handlers.NewAuditHandler(services.NewAuditLogService(repositories.NewAuditLogRepositoryPostgresql(dbCluster))),
+	)
 
 	router := gin.New()
+	// Only forwarding headers set by these proxies are used for the client IP recorded with each click.
+	if err := router.SetTrustedProxies(defaultConfig.Server.TrustedProxies); err != nil {
+		log.Fatal(err)
+	}
 	router.Use(gin.LoggerWithFormatter(utils.CustomLogFormatter))
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, // Allow all domains (change for production)
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Last-Event-ID", "X-Request-ID", defaultConfig.Audit.ActorHeader},
+		ExposeHeaders:    []string{"Content-Length", "X-Request-ID"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -61,6 +151,31 @@ func main() {
 		},
 	})
 
-	log.Print("Starting server on :" + defaultConfig.Server.Port)
-	router.Run(":" + defaultConfig.Server.Port)
+	server := &http.Server{Addr: ":" + defaultConfig.Server.Port, Handler: router}
+	// Live click streams stay open until closed, they would otherwise hold up the shutdown until it times out.
+	server.RegisterOnShutdown(clickStreamHub.CloseStreams)
+	go func() {
+		log.Print("Starting server on :" + defaultConfig.Server.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Print("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), defaultConfig.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error shutting down server: %v", err)
+	}
+	if err := scheduler.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error stopping background jobs: %v", err)
+	}
+	if err := clickStreamHub.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error stopping live click streams: %v", err)
+	}
+	// Redirects have stopped, so everything still queued can be flushed.
+	if err := accessLogPipeline.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error draining access logs: %v", err)
+	}
 }
@@ -7,6 +7,238 @@ import (
 	"time"
 )
 
+// Defines values for AuditEntryAction.
+const (
+	Create  AuditEntryAction = "create"
+	Delete  AuditEntryAction = "delete"
+	Restore AuditEntryAction = "restore"
+	Update  AuditEntryAction = "update"
+)
+
+// Defines values for WebhookDeliveryStatus.
+const (
+	Failed    WebhookDeliveryStatus = "failed"
+	Pending   WebhookDeliveryStatus = "pending"
+	Succeeded WebhookDeliveryStatus = "succeeded"
+)
+
+// Defines values for WebhookSubscriptionRequestEventTypes.
+const (
+	LinkCreated  WebhookSubscriptionRequestEventTypes = "link.created"
+	LinkDeleted  WebhookSubscriptionRequestEventTypes = "link.deleted"
+	LinkExpired  WebhookSubscriptionRequestEventTypes = "link.expired"
+	LinkRestored WebhookSubscriptionRequestEventTypes = "link.restored"
+	LinkUpdated  WebhookSubscriptionRequestEventTypes = "link.updated"
+)
+
+// Defines values for ExportClicksParamsFormat.
+const (
+	Csv     ExportClicksParamsFormat = "csv"
+	Jsonl   ExportClicksParamsFormat = "jsonl"
+	Parquet ExportClicksParamsFormat = "parquet"
+)
+
+// Defines values for GetDomainStatsParamsWindow.
+const (
+	GetDomainStatsParamsWindowAll  GetDomainStatsParamsWindow = "all"
+	GetDomainStatsParamsWindowN24h GetDomainStatsParamsWindow = "24h"
+	GetDomainStatsParamsWindowN30d GetDomainStatsParamsWindow = "30d"
+	GetDomainStatsParamsWindowN7d  GetDomainStatsParamsWindow = "7d"
+)
+
+// Defines values for GetTopLinksParamsWindow.
+const (
+	GetTopLinksParamsWindowAll  GetTopLinksParamsWindow = "all"
+	GetTopLinksParamsWindowN24h GetTopLinksParamsWindow = "24h"
+	GetTopLinksParamsWindowN30d GetTopLinksParamsWindow = "30d"
+	GetTopLinksParamsWindowN7d  GetTopLinksParamsWindow = "7d"
+)
+
+// Defines values for GetShortUrlStatsTimeseriesParamsInterval.
+const (
+	Day  GetShortUrlStatsTimeseriesParamsInterval = "day"
+	Hour GetShortUrlStatsTimeseriesParamsInterval = "hour"
+	Week GetShortUrlStatsTimeseriesParamsInterval = "week"
+)
+
+// AlertSettings When clicks of a link in a bucket alert as a spike or drop against its baseline
+type AlertSettings struct {
+	// DropZ Standard deviations below the baseline that alert as a drop, greater than 0
+	DropZ *float64 `json:"dropZ,omitempty"`
+
+	// Enabled Whether the link alerts at all, defaults to true
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MinClicks Least clicks of a spike, or baseline of a drop, that alert
+	MinClicks *int `json:"minClicks,omitempty"`
+
+	// SpikeZ Standard deviations above the baseline that alert as a spike, greater than 0
+	SpikeZ *float64 `json:"spikeZ,omitempty"`
+}
+
+// ArchivedUrl defines model for ArchivedUrl.
+type ArchivedUrl struct {
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	CreatedBy *string    `json:"createdBy,omitempty"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+
+	// DeletedBy Who deleted the URL, system for expired ones
+	DeletedBy   *string    `json:"deletedBy,omitempty"`
+	Expiry      *time.Time `json:"expiry,omitempty"`
+	Id          *int64     `json:"id,omitempty"`
+	ModifiedAt  *time.Time `json:"modifiedAt,omitempty"`
+	ModifiedBy  *string    `json:"modifiedBy,omitempty"`
+	OriginalUrl *string    `json:"originalUrl,omitempty"`
+	ShortPath   *string    `json:"shortPath,omitempty"`
+}
+
+// AuditEntry defines model for AuditEntry.
+type AuditEntry struct {
+	Action *AuditEntryAction `json:"action,omitempty"`
+
+	// Actor Who made the change, system when the request did not say
+	Actor *string `json:"actor,omitempty"`
+
+	// After The short URL after the change, missing for deleted ones
+	After *map[string]interface{} `json:"after,omitempty"`
+
+	// Before The short URL before the change, missing for created ones
+	Before *map[string]interface{} `json:"before,omitempty"`
+
+	// Changes Every field that changed with its old and new value, e.g. {"originalUrl": {"from": "...", "to": "..."}}
+	Changes   *map[string]interface{} `json:"changes,omitempty"`
+	CreatedAt *time.Time              `json:"createdAt,omitempty"`
+	Id        *int64                  `json:"id,omitempty"`
+	RequestId *string                 `json:"requestId,omitempty"`
+	ShortPath *string                 `json:"shortPath,omitempty"`
+	SourceIp  *string                 `json:"sourceIp,omitempty"`
+	UserAgent *string                 `json:"userAgent,omitempty"`
+}
+
+// AuditEntryAction defines model for AuditEntry.Action.
+type AuditEntryAction string
+
+// BreakdownEntry defines model for BreakdownEntry.
+type BreakdownEntry struct {
+	// Count Number of accesses with this value
+	Count *int    `json:"count,omitempty"`
+	Value *string `json:"value,omitempty"`
+}
+
+// CacheWarmupRequest defines model for CacheWarmupRequest.
+type CacheWarmupRequest struct {
+	// BatchSize Number of links written per Redis pipeline, defaults to 500
+	BatchSize *int `json:"batchSize,omitempty"`
+
+	// RatePerSecond Maximum number of links loaded per second. Unlimited when omitted
+	RatePerSecond *int `json:"ratePerSecond,omitempty"`
+
+	// TopN Only load the N most clicked links in the window. Loads every link when omitted
+	TopN *int `json:"topN,omitempty"`
+
+	// WindowHours Window in hours used to rank links by clicks, defaults to 24
+	WindowHours *int `json:"windowHours,omitempty"`
+}
+
+// CacheWarmupStatus defines model for CacheWarmupStatus.
+type CacheWarmupStatus struct {
+	Error      *string    `json:"error,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	// Loaded Number of links written to Redis
+	Loaded  *int  `json:"loaded,omitempty"`
+	Running *bool `json:"running,omitempty"`
+
+	// Scanned Number of links read from PostgreSQL
+	Scanned *int `json:"scanned,omitempty"`
+
+	// Skipped Number of links skipped because they have expired
+	Skipped   *int       `json:"skipped,omitempty"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+}
+
+// ClickIngestionStats defines model for ClickIngestionStats.
+type ClickIngestionStats struct {
+	// Dropped Clicks dropped because the queue was full
+	Dropped *int `json:"dropped,omitempty"`
+
+	// Enqueued Clicks accepted into the queue since startup
+	Enqueued *int `json:"enqueued,omitempty"`
+
+	// Failed Clicks lost because writing their batch failed
+	Failed *int `json:"failed,omitempty"`
+
+	// Flushed Clicks written to PostgreSQL
+	Flushed *int `json:"flushed,omitempty"`
+
+	// Queued Clicks currently waiting in the queue
+	Queued *int `json:"queued,omitempty"`
+}
+
+// ClickStreamEvent Data of a click event, its id is also the SSE event id
+type ClickStreamEvent struct {
+	AccessedAt   *time.Time `json:"accessedAt,omitempty"`
+	Browser      *string    `json:"browser,omitempty"`
+	City         *string    `json:"city,omitempty"`
+	Country      *string    `json:"country,omitempty"`
+	DeviceClass  *string    `json:"deviceClass,omitempty"`
+	Id           *string    `json:"id,omitempty"`
+	IsBot        *bool      `json:"isBot,omitempty"`
+	Os           *string    `json:"os,omitempty"`
+	ReferrerHost *string    `json:"referrerHost,omitempty"`
+	Region       *string    `json:"region,omitempty"`
+	ShortPath    *string    `json:"shortPath,omitempty"`
+}
+
+// Conversion defines model for Conversion.
+type Conversion struct {
+	ClickId       *string    `json:"clickId,omitempty"`
+	ConvertedAt   *time.Time `json:"convertedAt,omitempty"`
+	ShortPath     *string    `json:"shortPath,omitempty"`
+	TransactionId *string    `json:"transactionId,omitempty"`
+	Type          *string    `json:"type,omitempty"`
+	Value         *float64   `json:"value,omitempty"`
+}
+
+// ConversionRequest defines model for ConversionRequest.
+type ConversionRequest struct {
+	// ClickId Click ID the destination received in its query string
+	ClickId string `json:"clickId"`
+
+	// TransactionId Identifies the conversion, reporting the same one again for the click is ignored
+	TransactionId *string `json:"transactionId,omitempty"`
+
+	// Type Kind of conversion, e.g. signup or purchase, at most 64 characters
+	Type string `json:"type"`
+
+	// Value Value of the conversion, e.g. the order total. Defaults to 0
+	Value *float64 `json:"value,omitempty"`
+}
+
+// ConversionTypeStats defines model for ConversionTypeStats.
+type ConversionTypeStats struct {
+	Conversions *int     `json:"conversions,omitempty"`
+	Type        *string  `json:"type,omitempty"`
+	Value       *float64 `json:"value,omitempty"`
+}
+
+// DomainStat defines model for DomainStat.
+type DomainStat struct {
+	Clicks *int `json:"clicks,omitempty"`
+
+	// Domain Lower cased host of the original URLs
+	Domain *string `json:"domain,omitempty"`
+
+	// Links Number of short URLs pointing at the host
+	Links *int `json:"links,omitempty"`
+}
+
+// DomainStats defines model for DomainStats.
+type DomainStats struct {
+	Domains *[]DomainStat `json:"domains,omitempty"`
+	Window  *string       `json:"window,omitempty"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	Message *string `json:"message,omitempty"`
@@ -20,18 +252,232 @@ type ShortenedUrlDetails struct {
 	ShortUrl    *string    `json:"shortUrl,omitempty"`
 }
 
-// URLStatistics defines model for URLStatistics.
+// TimeseriesBucket defines model for TimeseriesBucket.
+type TimeseriesBucket struct {
+	Count *int `json:"count,omitempty"`
+
+	// Start Start of the bucket in the requested time zone
+	Start *time.Time `json:"start,omitempty"`
+}
+
+// TopLink defines model for TopLink.
+type TopLink struct {
+	Clicks      *int    `json:"clicks,omitempty"`
+	OriginalUrl *string `json:"originalUrl,omitempty"`
+	ShortPath   *string `json:"short-path,omitempty"`
+}
+
+// TopLinks defines model for TopLinks.
+type TopLinks struct {
+	Links  *[]TopLink `json:"links,omitempty"`
+	Window *string    `json:"window,omitempty"`
+}
+
+// URLStatistics The breakdowns only count clicks within the configured stats.breakdown_window, 30 days by default
 type URLStatistics struct {
 	// AllTime Total number of accesses
 	AllTime *int `json:"allTime,omitempty"`
 
+	// Browsers Most common browsers
+	Browsers *[]BreakdownEntry `json:"browsers,omitempty"`
+
+	// Cities Most common cities resolved from the client IP
+	Cities *[]BreakdownEntry `json:"cities,omitempty"`
+
+	// ConversionRate Share of all time clicks with at least one conversion, from 0 to 1
+	ConversionRate *float64 `json:"conversionRate,omitempty"`
+
+	// ConversionTypes Conversions and their value per type, most frequent first
+	ConversionTypes *[]ConversionTypeStats `json:"conversionTypes,omitempty"`
+
+	// ConversionValue Sum of the values of all conversions
+	ConversionValue *float64 `json:"conversionValue,omitempty"`
+
+	// Conversions Number of conversions reported for clicks of the URL
+	Conversions *int `json:"conversions,omitempty"`
+
+	// Countries Most common ISO 3166-1 country codes resolved from the client IP
+	Countries *[]BreakdownEntry `json:"countries,omitempty"`
+
+	// Devices Clicks by device class: desktop, mobile, tablet, bot or unknown
+	Devices *[]BreakdownEntry `json:"devices,omitempty"`
+
+	// Languages Most common preferred languages from the Accept-Language header
+	Languages *[]BreakdownEntry `json:"languages,omitempty"`
+
 	// Last24Hours Number of accesses in the last 24 hours
 	Last24Hours *int `json:"last24Hours,omitempty"`
 
+	// OperatingSystems Most common operating systems
+	OperatingSystems *[]BreakdownEntry `json:"operatingSystems,omitempty"`
+
 	// PastWeek Number of accesses in the past week
 	PastWeek *int `json:"pastWeek,omitempty"`
+
+	// Referrers Most common referring hosts, "direct" when the click had no referrer
+	Referrers *[]BreakdownEntry `json:"referrers,omitempty"`
+
+	// Regions Most common ISO 3166-2 region codes resolved from the client IP, e.g. DE-BY
+	Regions *[]BreakdownEntry `json:"regions,omitempty"`
+
+	// UniqueAllTime Estimated number of distinct visitors overall
+	UniqueAllTime *int `json:"uniqueAllTime,omitempty"`
+
+	// UniqueLast24Hours Estimated number of distinct visitors in the last 24 hours
+	UniqueLast24Hours *int `json:"uniqueLast24Hours,omitempty"`
+
+	// UniquePastWeek Estimated number of distinct visitors in the past week
+	UniquePastWeek *int `json:"uniquePastWeek,omitempty"`
+}
+
+// URLTimeseries defines model for URLTimeseries.
+type URLTimeseries struct {
+	Buckets  *[]TimeseriesBucket `json:"buckets,omitempty"`
+	From     *time.Time          `json:"from,omitempty"`
+	Interval *string             `json:"interval,omitempty"`
+	Timezone *string             `json:"timezone,omitempty"`
+	To       *time.Time          `json:"to,omitempty"`
+}
+
+// WebhookDelivery defines model for WebhookDelivery.
+type WebhookDelivery struct {
+	Attempts    *int       `json:"attempts,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	DeliveredAt *time.Time `json:"deliveredAt,omitempty"`
+
+	// EventId ID of the event, the same for every delivery of it
+	EventId        *int64  `json:"eventId,omitempty"`
+	EventType      *string `json:"eventType,omitempty"`
+	Id             *int64  `json:"id,omitempty"`
+	LastError      *string `json:"lastError,omitempty"`
+	LastStatusCode *int    `json:"lastStatusCode,omitempty"`
+
+	// NextAttemptAt When a pending delivery is attempted next
+	NextAttemptAt *time.Time             `json:"nextAttemptAt,omitempty"`
+	Status        *WebhookDeliveryStatus `json:"status,omitempty"`
+}
+
+// WebhookDeliveryStatus defines model for WebhookDelivery.Status.
+type WebhookDeliveryStatus string
+
+// WebhookSubscription defines model for WebhookSubscription.
+type WebhookSubscription struct {
+	Active     *bool      `json:"active,omitempty"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty"`
+	EventTypes *[]string  `json:"eventTypes,omitempty"`
+	Id         *int64     `json:"id,omitempty"`
+
+	// Secret Only returned when the subscription is created
+	Secret    *string    `json:"secret,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	Url       *string    `json:"url,omitempty"`
+}
+
+// WebhookSubscriptionRequest defines model for WebhookSubscriptionRequest.
+type WebhookSubscriptionRequest struct {
+	// Active Inactive subscriptions get no new deliveries. Defaults to true
+	Active *bool `json:"active,omitempty"`
+
+	// EventTypes Events to receive, every event when empty
+	EventTypes *[]WebhookSubscriptionRequestEventTypes `json:"eventTypes,omitempty"`
+
+	// Secret Key the payloads are signed with, a random one is generated when left out
+	Secret *string `json:"secret,omitempty"`
+
+	// Url Absolute http or https URL the events are posted to. Loopback, private and link-local hosts are rejected
+	Url string `json:"url"`
+}
+
+// WebhookSubscriptionRequestEventTypes defines model for WebhookSubscriptionRequest.EventTypes.
+type WebhookSubscriptionRequestEventTypes string
+
+// ListArchivedUrlsParams defines parameters for ListArchivedUrls.
+type ListArchivedUrlsParams struct {
+	// ShortPath Only return versions of this short path
+	ShortPath *string `form:"shortPath,omitempty" json:"shortPath,omitempty"`
+
+	// Before Only return versions archived before this one
+	Before *int64 `form:"before,omitempty" json:"before,omitempty"`
+
+	// Limit Number of versions to return, defaults to 50 and is at most 500
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListAuditEntriesParams defines parameters for ListAuditEntries.
+type ListAuditEntriesParams struct {
+	// Actor Only return changes made by this actor
+	Actor *string `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// ShortPath Only return changes of this short URL
+	ShortPath *string `form:"shortPath,omitempty" json:"shortPath,omitempty"`
+
+	// From Only return changes made at or after this time
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Only return changes made before this time
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+
+	// Before Only return entries older than this entry
+	Before *int64 `form:"before,omitempty" json:"before,omitempty"`
+
+	// Limit Number of entries to return, defaults to 50 and is at most 500
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
+// ExportClicksParams defines parameters for ExportClicks.
+type ExportClicksParams struct {
+	// Format File format, defaults to csv
+	Format *ExportClicksParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// ShortPath Only export clicks of this short URL
+	ShortPath *string `form:"shortPath,omitempty" json:"shortPath,omitempty"`
+
+	// Owner Only export clicks of short URLs created by this user, including deleted ones
+	Owner *string `form:"owner,omitempty" json:"owner,omitempty"`
+
+	// From Start of the range, inclusive. Defaults to the first click
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To End of the range, exclusive. Defaults to now
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// ExportClicksParamsFormat defines parameters for ExportClicks.
+type ExportClicksParamsFormat string
+
+// StreamOwnerEventsParams defines parameters for StreamOwnerEvents.
+type StreamOwnerEventsParams struct {
+	// IncludeBots Also send clicks by crawlers and link unfurlers. Defaults to false
+	IncludeBots *bool `form:"includeBots,omitempty" json:"includeBots,omitempty"`
+
+	// LastEventID ID of the last event received before reconnecting, the recent events after it are sent first
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
+// GetDomainStatsParams defines parameters for GetDomainStats.
+type GetDomainStatsParams struct {
+	// Window Period the clicks are counted in, defaults to 24h. Clicks by bots are not counted
+	Window *GetDomainStatsParamsWindow `form:"window,omitempty" json:"window,omitempty"`
+
+	// Limit Number of hosts to return, 1 to 100, defaults to 10
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetDomainStatsParamsWindow defines parameters for GetDomainStats.
+type GetDomainStatsParamsWindow string
+
+// GetTopLinksParams defines parameters for GetTopLinks.
+type GetTopLinksParams struct {
+	// Window Period the clicks are counted in, defaults to 24h. Clicks by bots are not counted
+	Window *GetTopLinksParamsWindow `form:"window,omitempty" json:"window,omitempty"`
+
+	// Limit Number of links to return, 1 to 100, defaults to 10
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetTopLinksParamsWindow defines parameters for GetTopLinks.
+type GetTopLinksParamsWindow string
+
 // CreateShortUrlJSONBody defines parameters for CreateShortUrl.
 type CreateShortUrlJSONBody struct {
 	Expiry      *time.Time `json:"expiry,omitempty"`
@@ -44,8 +490,68 @@ type UpdateShortUrlJSONBody struct {
 	OriginalUrl string     `json:"originalUrl"`
 }
 
+// StreamShortUrlEventsParams defines parameters for StreamShortUrlEvents.
+type StreamShortUrlEventsParams struct {
+	// IncludeBots Also send clicks by crawlers and link unfurlers. Defaults to false
+	IncludeBots *bool `form:"includeBots,omitempty" json:"includeBots,omitempty"`
+
+	// LastEventID ID of the last event received before reconnecting, the recent events after it are sent first
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
+// GetShortUrlStatsParams defines parameters for GetShortUrlStats.
+type GetShortUrlStatsParams struct {
+	// IncludeBots Also count clicks by crawlers and link unfurlers. Defaults to false. Unique visitors never include them
+	IncludeBots *bool `form:"includeBots,omitempty" json:"includeBots,omitempty"`
+}
+
+// GetShortUrlStatsTimeseriesParams defines parameters for GetShortUrlStatsTimeseries.
+type GetShortUrlStatsTimeseriesParams struct {
+	// From Start of the range, inclusive. Defaults to 24 buckets of hour, 30 of day or 12 of week before to
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To End of the range, exclusive. Defaults to now
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+
+	// Interval Bucket size, defaults to day
+	Interval *GetShortUrlStatsTimeseriesParamsInterval `form:"interval,omitempty" json:"interval,omitempty"`
+
+	// Tz IANA time zone the buckets are aligned to, e.g. Europe/Berlin. Defaults to UTC. Weeks start on Monday
+	Tz *string `form:"tz,omitempty" json:"tz,omitempty"`
+
+	// IncludeBots Also count clicks by crawlers and link unfurlers. Defaults to false
+	IncludeBots *bool `form:"includeBots,omitempty" json:"includeBots,omitempty"`
+}
+
+// GetShortUrlStatsTimeseriesParamsInterval defines parameters for GetShortUrlStatsTimeseries.
+type GetShortUrlStatsTimeseriesParamsInterval string
+
+// ListWebhookDeliveriesParams defines parameters for ListWebhookDeliveries.
+type ListWebhookDeliveriesParams struct {
+	// Before Only return deliveries older than this delivery
+	Before *int64 `form:"before,omitempty" json:"before,omitempty"`
+
+	// Limit Number of deliveries to return, defaults to 50 and is at most 500
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// StartCacheWarmupJSONRequestBody defines body for StartCacheWarmup for application/json ContentType.
+type StartCacheWarmupJSONRequestBody = CacheWarmupRequest
+
+// RecordConversionJSONRequestBody defines body for RecordConversion for application/json ContentType.
+type RecordConversionJSONRequestBody = ConversionRequest
+
 // CreateShortUrlJSONRequestBody defines body for CreateShortUrl for application/json ContentType.
 type CreateShortUrlJSONRequestBody CreateShortUrlJSONBody
 
 // UpdateShortUrlJSONRequestBody defines body for UpdateShortUrl for application/json ContentType.
 type UpdateShortUrlJSONRequestBody UpdateShortUrlJSONBody
+
+// SetShortUrlAlertSettingsJSONRequestBody defines body for SetShortUrlAlertSettings for application/json ContentType.
+type SetShortUrlAlertSettingsJSONRequestBody = AlertSettings
+
+// CreateWebhookJSONRequestBody defines body for CreateWebhook for application/json ContentType.
+type CreateWebhookJSONRequestBody = WebhookSubscriptionRequest
+
+// UpdateWebhookJSONRequestBody defines body for UpdateWebhook for application/json ContentType.
+type UpdateWebhookJSONRequestBody = WebhookSubscriptionRequest
@@ -20,6 +20,36 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Get the progress of the current or last cache warm-up
+	// (GET /admin/cache/warmup)
+	GetCacheWarmupStatus(c *gin.Context)
+	// Start rebuilding the Redis cache from PostgreSQL
+	// (POST /admin/cache/warmup)
+	StartCacheWarmup(c *gin.Context)
+	// Get counters of the click ingestion queue
+	// (GET /admin/clicks/stats)
+	GetClickIngestionStats(c *gin.Context)
+	// List deleted and expired shortened URLs
+	// (GET /archived-urls)
+	ListArchivedUrls(c *gin.Context, params ListArchivedUrlsParams)
+	// List changes made to short URLs
+	// (GET /audit)
+	ListAuditEntries(c *gin.Context, params ListAuditEntriesParams)
+	// Record a conversion of a click
+	// (POST /conversions)
+	RecordConversion(c *gin.Context)
+	// Export raw clicks
+	// (GET /exports/clicks)
+	ExportClicks(c *gin.Context, params ExportClicksParams)
+	// Stream the clicks of every short URL of an owner live
+	// (GET /owners/{owner}/events)
+	StreamOwnerEvents(c *gin.Context, owner string, params StreamOwnerEventsParams)
+	// Get clicks and number of short URLs per destination host
+	// (GET /stats/domains)
+	GetDomainStats(c *gin.Context, params GetDomainStatsParams)
+	// Get the most clicked short URLs
+	// (GET /stats/top)
+	GetTopLinks(c *gin.Context, params GetTopLinksParams)
 	// Create a shortened URL
 	// (POST /urls)
 	CreateShortUrl(c *gin.Context)
@@ -32,9 +62,42 @@ type ServerInterface interface {
 	// Update a shortened URL
 	// (PUT /urls/{short-path})
 	UpdateShortUrl(c *gin.Context, shortPath string)
+	// Get the traffic alert settings of a shortened URL
+	// (GET /urls/{short-path}/alerts)
+	GetShortUrlAlertSettings(c *gin.Context, shortPath string)
+	// Set the traffic alert settings of a shortened URL
+	// (PUT /urls/{short-path}/alerts)
+	SetShortUrlAlertSettings(c *gin.Context, shortPath string)
+	// Stream the clicks of a shortened URL live
+	// (GET /urls/{short-path}/events)
+	StreamShortUrlEvents(c *gin.Context, shortPath string, params StreamShortUrlEventsParams)
+	// Restore a deleted or expired shortened URL
+	// (POST /urls/{short-path}/restore)
+	RestoreShortUrl(c *gin.Context, shortPath string)
 	// Get access statistics for a shortened URL
 	// (GET /urls/{short-path}/stats)
-	GetShortUrlStats(c *gin.Context, shortPath string)
+	GetShortUrlStats(c *gin.Context, shortPath string, params GetShortUrlStatsParams)
+	// Get access counts of a shortened URL bucketed over time
+	// (GET /urls/{short-path}/stats/timeseries)
+	GetShortUrlStatsTimeseries(c *gin.Context, shortPath string, params GetShortUrlStatsTimeseriesParams)
+	// List the webhook subscriptions
+	// (GET /webhooks)
+	ListWebhooks(c *gin.Context)
+	// Subscribe a webhook to link events
+	// (POST /webhooks)
+	CreateWebhook(c *gin.Context)
+	// Delete a webhook subscription and its deliveries
+	// (DELETE /webhooks/{id})
+	DeleteWebhook(c *gin.Context, id int64)
+	// Get a webhook subscription
+	// (GET /webhooks/{id})
+	GetWebhook(c *gin.Context, id int64)
+	// Replace a webhook subscription
+	// (PUT /webhooks/{id})
+	UpdateWebhook(c *gin.Context, id int64)
+	// List the deliveries of a webhook subscription
+	// (GET /webhooks/{id}/deliveries)
+	ListWebhookDeliveries(c *gin.Context, id int64, params ListWebhookDeliveriesParams)
 	// Redirect to the original URL
 	// (GET /{short-path})
 	RedirectToOriginalUrl(c *gin.Context, shortPath string)
@@ -49,6 +112,348 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(c *gin.Context)
 
+// GetCacheWarmupStatus operation middleware
+func (siw *ServerInterfaceWrapper) GetCacheWarmupStatus(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetCacheWarmupStatus(c)
+}
+
+// StartCacheWarmup operation middleware
+func (siw *ServerInterfaceWrapper) StartCacheWarmup(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.StartCacheWarmup(c)
+}
+
+// GetClickIngestionStats operation middleware
+func (siw *ServerInterfaceWrapper) GetClickIngestionStats(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetClickIngestionStats(c)
+}
+
+// ListArchivedUrls operation middleware
+func (siw *ServerInterfaceWrapper) ListArchivedUrls(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListArchivedUrlsParams
+
+	// ------------- Optional query parameter "shortPath" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "shortPath", c.Request.URL.Query(), &params.ShortPath)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter shortPath: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "before", c.Request.URL.Query(), &params.Before)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter before: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListArchivedUrls(c, params)
+}
+
+// ListAuditEntries operation middleware
+func (siw *ServerInterfaceWrapper) ListAuditEntries(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAuditEntriesParams
+
+	// ------------- Optional query parameter "actor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "actor", c.Request.URL.Query(), &params.Actor)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter actor: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "shortPath" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "shortPath", c.Request.URL.Query(), &params.ShortPath)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter shortPath: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", c.Request.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter from: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", c.Request.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter to: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "before", c.Request.URL.Query(), &params.Before)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter before: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListAuditEntries(c, params)
+}
+
+// RecordConversion operation middleware
+func (siw *ServerInterfaceWrapper) RecordConversion(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RecordConversion(c)
+}
+
+// ExportClicks operation middleware
+func (siw *ServerInterfaceWrapper) ExportClicks(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportClicksParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", c.Request.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter format: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "shortPath" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "shortPath", c.Request.URL.Query(), &params.ShortPath)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter shortPath: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "owner" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "owner", c.Request.URL.Query(), &params.Owner)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter owner: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", c.Request.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter from: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", c.Request.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter to: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ExportClicks(c, params)
+}
+
+// StreamOwnerEvents operation middleware
+func (siw *ServerInterfaceWrapper) StreamOwnerEvents(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "owner" -------------
+	var owner string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "owner", c.Param("owner"), &owner, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter owner: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StreamOwnerEventsParams
+
+	// ------------- Optional query parameter "includeBots" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeBots", c.Request.URL.Query(), &params.IncludeBots)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter includeBots: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	headers := c.Request.Header
+
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for Last-Event-ID, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Last-Event-ID", valueList[0], &LastEventID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter Last-Event-ID: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.LastEventID = &LastEventID
+
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.StreamOwnerEvents(c, owner, params)
+}
+
+// GetDomainStats operation middleware
+func (siw *ServerInterfaceWrapper) GetDomainStats(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetDomainStatsParams
+
+	// ------------- Optional query parameter "window" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "window", c.Request.URL.Query(), &params.Window)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter window: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetDomainStats(c, params)
+}
+
+// GetTopLinks operation middleware
+func (siw *ServerInterfaceWrapper) GetTopLinks(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTopLinksParams
+
+	// ------------- Optional query parameter "window" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "window", c.Request.URL.Query(), &params.Window)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter window: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetTopLinks(c, params)
+}
+
 // CreateShortUrl operation middleware
 func (siw *ServerInterfaceWrapper) CreateShortUrl(c *gin.Context) {
 
@@ -134,6 +539,134 @@ func (siw *ServerInterfaceWrapper) UpdateShortUrl(c *gin.Context) {
 	siw.Handler.UpdateShortUrl(c, shortPath)
 }
 
+// GetShortUrlAlertSettings operation middleware
+func (siw *ServerInterfaceWrapper) GetShortUrlAlertSettings(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "short-path" -------------
+	var shortPath string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "short-path", c.Param("short-path"), &shortPath, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter short-path: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetShortUrlAlertSettings(c, shortPath)
+}
+
+// SetShortUrlAlertSettings operation middleware
+func (siw *ServerInterfaceWrapper) SetShortUrlAlertSettings(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "short-path" -------------
+	var shortPath string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "short-path", c.Param("short-path"), &shortPath, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter short-path: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.SetShortUrlAlertSettings(c, shortPath)
+}
+
+// StreamShortUrlEvents operation middleware
+func (siw *ServerInterfaceWrapper) StreamShortUrlEvents(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "short-path" -------------
+	var shortPath string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "short-path", c.Param("short-path"), &shortPath, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter short-path: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StreamShortUrlEventsParams
+
+	// ------------- Optional query parameter "includeBots" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeBots", c.Request.URL.Query(), &params.IncludeBots)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter includeBots: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	headers := c.Request.Header
+
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for Last-Event-ID, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Last-Event-ID", valueList[0], &LastEventID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter Last-Event-ID: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.LastEventID = &LastEventID
+
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.StreamShortUrlEvents(c, shortPath, params)
+}
+
+// RestoreShortUrl operation middleware
+func (siw *ServerInterfaceWrapper) RestoreShortUrl(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "short-path" -------------
+	var shortPath string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "short-path", c.Param("short-path"), &shortPath, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter short-path: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RestoreShortUrl(c, shortPath)
+}
+
 // GetShortUrlStats operation middleware
 func (siw *ServerInterfaceWrapper) GetShortUrlStats(c *gin.Context) {
 
@@ -148,6 +681,225 @@ func (siw *ServerInterfaceWrapper) GetShortUrlStats(c *gin.Context) {
 		return
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetShortUrlStatsParams
+
+	// ------------- Optional query parameter "includeBots" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeBots", c.Request.URL.Query(), &params.IncludeBots)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter includeBots: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetShortUrlStats(c, shortPath, params)
+}
+
+// GetShortUrlStatsTimeseries operation middleware
+func (siw *ServerInterfaceWrapper) GetShortUrlStatsTimeseries(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "short-path" -------------
+	var shortPath string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "short-path", c.Param("short-path"), &shortPath, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter short-path: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetShortUrlStatsTimeseriesParams
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", c.Request.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter from: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", c.Request.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter to: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "interval" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "interval", c.Request.URL.Query(), &params.Interval)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter interval: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "tz" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tz", c.Request.URL.Query(), &params.Tz)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter tz: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "includeBots" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeBots", c.Request.URL.Query(), &params.IncludeBots)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter includeBots: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetShortUrlStatsTimeseries(c, shortPath, params)
+}
+
+// ListWebhooks operation middleware
+func (siw *ServerInterfaceWrapper) ListWebhooks(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListWebhooks(c)
+}
+
+// CreateWebhook operation middleware
+func (siw *ServerInterfaceWrapper) CreateWebhook(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreateWebhook(c)
+}
+
+// DeleteWebhook operation middleware
+func (siw *ServerInterfaceWrapper) DeleteWebhook(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeleteWebhook(c, id)
+}
+
+// GetWebhook operation middleware
+func (siw *ServerInterfaceWrapper) GetWebhook(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetWebhook(c, id)
+}
+
+// UpdateWebhook operation middleware
+func (siw *ServerInterfaceWrapper) UpdateWebhook(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateWebhook(c, id)
+}
+
+// ListWebhookDeliveries operation middleware
+func (siw *ServerInterfaceWrapper) ListWebhookDeliveries(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListWebhookDeliveriesParams
+
+	// ------------- Optional query parameter "before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "before", c.Request.URL.Query(), &params.Before)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter before: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -155,7 +907,7 @@ func (siw *ServerInterfaceWrapper) GetShortUrlStats(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.GetShortUrlStats(c, shortPath)
+	siw.Handler.ListWebhookDeliveries(c, id, params)
 }
 
 // RedirectToOriginalUrl operation middleware
@@ -209,34 +961,131 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 		ErrorHandler:       errorHandler,
 	}
 
+	router.GET(options.BaseURL+"/admin/cache/warmup", wrapper.GetCacheWarmupStatus)
+	router.POST(options.BaseURL+"/admin/cache/warmup", wrapper.StartCacheWarmup)
+	router.GET(options.BaseURL+"/admin/clicks/stats", wrapper.GetClickIngestionStats)
+	router.GET(options.BaseURL+"/archived-urls", wrapper.ListArchivedUrls)
+	router.GET(options.BaseURL+"/audit", wrapper.ListAuditEntries)
+	router.POST(options.BaseURL+"/conversions", wrapper.RecordConversion)
+	router.GET(options.BaseURL+"/exports/clicks", wrapper.ExportClicks)
+	router.GET(options.BaseURL+"/owners/:owner/events", wrapper.StreamOwnerEvents)
+	router.GET(options.BaseURL+"/stats/domains", wrapper.GetDomainStats)
+	router.GET(options.BaseURL+"/stats/top", wrapper.GetTopLinks)
 	router.POST(options.BaseURL+"/urls", wrapper.CreateShortUrl)
 	router.DELETE(options.BaseURL+"/urls/:short-path", wrapper.DeleteShortUrl)
 	router.GET(options.BaseURL+"/urls/:short-path", wrapper.GetShortUrlDetails)
 	router.PUT(options.BaseURL+"/urls/:short-path", wrapper.UpdateShortUrl)
+	router.GET(options.BaseURL+"/urls/:short-path/alerts", wrapper.GetShortUrlAlertSettings)
+	router.PUT(options.BaseURL+"/urls/:short-path/alerts", wrapper.SetShortUrlAlertSettings)
+	router.GET(options.BaseURL+"/urls/:short-path/events", wrapper.StreamShortUrlEvents)
+	router.POST(options.BaseURL+"/urls/:short-path/restore", wrapper.RestoreShortUrl)
 	router.GET(options.BaseURL+"/urls/:short-path/stats", wrapper.GetShortUrlStats)
+	router.GET(options.BaseURL+"/urls/:short-path/stats/timeseries", wrapper.GetShortUrlStatsTimeseries)
+	router.GET(options.BaseURL+"/webhooks", wrapper.ListWebhooks)
+	router.POST(options.BaseURL+"/webhooks", wrapper.CreateWebhook)
+	router.DELETE(options.BaseURL+"/webhooks/:id", wrapper.DeleteWebhook)
+	router.GET(options.BaseURL+"/webhooks/:id", wrapper.GetWebhook)
+	router.PUT(options.BaseURL+"/webhooks/:id", wrapper.UpdateWebhook)
+	router.GET(options.BaseURL+"/webhooks/:id/deliveries", wrapper.ListWebhookDeliveries)
 	router.GET(options.BaseURL+"/:short-path", wrapper.RedirectToOriginalUrl)
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xYS4/bNhD+KwTbozZ2HBdIddsmQbrA9gE/0EOwh4k0sphSJEuOtjEM//diKMkvKSnc",
-	"BN4suqf1jjTkx5nvmxlxIzNbOWvQUJDpRoasxArizzfeWz/D4KwJyAbnrUNPCuPjCkOAVXxAa4cylYG8",
-	"Miu53Sadxb7/gBnJbSLnpfWEBvOl16+RQOnQXxI/OuXX/KuwvgKSqcyB8IpUhTI53SaR1quVMqCXXg/A",
-	"SGTgTa8cUPnpx8O+Q0dYzm7nBKQCqWwAPGi9YJzpRuYYMq8cKWtkKheWQAtTV+/RC1sIyDIMAcP+RMoQ",
-	"rtDzJhoCTaY/29qH/kq/9tYQyggqUbCbmExFGR2HFnYQ6A/EP89ZlX3E3+zUX7EfITYpU9j+Dtdi9ma+",
-	"ENe/34jCehEaLiizEsvZbUhEBQZW/C+VWCUCTC5KMLlmk8dcecwoPGMQijRvuZzdio5RnteVibxHH5rt",
-	"nj8bPxtHfjg04JRM5Yto4ihQGQM7qn3LQBuI/3IqgfHe5DKVrzwC4bwjSCI9/lVjoJ9sHvmZWUNooiM4",
-	"p1UWXUcfAgPoVPTVCb5zqr3qv75tUCqPuUzfHfneDSZr/zb5GqOhEXvEOhk//4KTnqUtNh0zZlcumCEi",
-	"i9nIOSLT8fgsVN97LGQqvxvty9yorXGj4wI3gOLG3INWuWiTLxystYUWx4+Xw/HKmkKrjMRVDAdoj5Cv",
-	"Ox21gZlcENDCWhbtuotMEFdiBoRCq0qRwI8ZYt7g+uGyCSP0BrQI6O/RC2SHyLhQVxWw9FppC9iHj4PK",
-	"coJVYOFwiH/hioQVI75j91guRpt9Q9k2VU4jYb96vI72g+rhwEOFhFzU322kYqyxLSXSQBXFsW9Vp8JM",
-	"DqJzKqW7nmin/fLLB2qg5iLUscYXtdbrhsfTy6WHgRhLorC1eSLtOaRtGHUOaRO5woHG9hap42U3hl2U",
-	"nl8vrkMT5Sc4lzePhUfyCu+7TvLE/EfA/Fmbs10SeU49RwauHpDB0uVwwQr9/5sdL67z44mxjvn9xibG",
-	"B603DzivNskQWWv9JstMUxC+eCocBYLmIuXfuu88vvhIe+/xVchAyK/jlCnC7qUH7L3HleGpC/8XebxF",
-	"am+HDnNaWP8ZxRwwpFHL6efToERm7X3Pwv520IQuqJMX40n/E6pDFS+prOj6Y3viEiHH5sLu1jZJGv4K",
-	"I7u7zxJk5edgbZ9G1Mczou5SGq9NT9jR6aF7jdXA/nHBhs01z2iyJHLpaKRtBrq0gdKX45djub3b/hMA",
-	"AP//sQScXaUXAAA=",
+	"H4sIAAAAAAAC/+w9XXPbOJJ/BcW7R/ojTmZuz1X34Ni+HdcmM7ko2dzdJnUFkS0JaxLgAKAcTcr//aob",
+	"4JcISpRjy54Zv8zEIgE0Gv3djea3KFF5oSRIa6LTb5FJFpBz+udZBtpOwFoh5/RDCibRorBCyeg0+rQA",
+	"yZJMJNeGqRnjLBPymgnJOJuWyTVYxnECxg3jzBTiGpjSLNWqYHzOhTSWCWvYlBvIhIQojgqtCtBWgFtN",
+	"q+J/+8tOLJcp1ylLYSk4/mjYFDJ1w+wC6tmYXfAOADhbzOYauAWNTyU7juJopnTObXQapaqcZgiEXRUQ",
+	"nUayzKego9s4AsmnGaRBBNgFTQZu77ScYbRwFrMUZrzMrGFWMavL1uRTpTLgEmfPhTwnHPbnfwPc2A6G",
+	"CYsxorHep5rVm2u23KwkpIW52wcNHolQPlVL2IxQD8vuGL2tf1HTf0JiEbYznSzEEtKPOkMAu4SQ0BLp",
+	"mcU/mum5hQMr8tYKxmoh5zifH/J6hUN6T1PIYMcJ/ZDXqz7+Pi0U848JYx/fv4mZWRkLOZspzeBrITSk",
+	"TEkwoanp+Wo8KCLtvCuk/fFV8MBzlYqZ2G2f1ZgBzCkt5kLyzJ9T77lZKG3fcbsIPA2ee5kKeymtXvWP",
+	"nScOwd8ikGUenf7Dn2oUR2WRun84vEdxpMFYpSH6EtgTT6zS4XPLeerIPFlwOYf63G5QtuHvGn4twViW",
+	"ipRJZZnhqxDa+MwCLcHTVOD8PHvX2gxyf7y2/IcFMEIXEgyjCTqQ5MIYIedEQhV5dUmoQeMUZrj571jf",
+	"zTAIgOenQQDcGLMbBJdL0Cs2E5ClTrq4WVJ2I+yCdIPKUsZlyiTcsCXPSogZHM4P2bfPbUr8HJ3iLzOt",
+	"cvzn5+jw8PBzFLPPkVWtH25vg5DvLlxGM6Annqt0Z16JI6NKncBVEXxYGtBnc5B2JJu91sCvU3UjB1gt",
+	"UaWbq3tAP5PEJhWTJGAMGHc0diGMO4/gtt2TcZCd82QBn7jOy+K9w1Yfuim3yWIifoNNEKICNuxGC2tB",
+	"sgI0ew+pMKwQBemvrjr+4fg4fGLcwjvQE0iUDGj8t/yryMucybVlM8VTSGlVQ0MP2UeZiVwgz5AsUTkC",
+	"lgYXtar4ub/WLzJb0cTElD+zXFXGAKR+WeFk1I2Qqbo5ZG8UTw0D4ip8YfvKbuRPqtQh+44e4iILfIGV",
+	"BhWcYprLaw/AdOUgMl3snoTYYcvpTyy3pekfPmjthHePCWZCCrPYjXXdQY2nI6scGYWJpZQS5z39FrDr",
+	"TMKlHLOUBp4ylF3snTJ2rmHyX2+Cy5lrURRjZvQvsikkvDQk1FdswZdQmSLh6S3XOwnC4IEiNVyhKkDY",
+	"8EwDR4rWanAj587U9c/b8LNfSyiB3XDDZmWWBTcAkl4anheFWIEcKaRVrVmNkAkw2n9ZBKeecZFtmDhD",
+	"1qygRdJBtWkXINBOt8mC+fHBqbPSLDbM3aLELfSxZfdJqTVIm63YDXcQevFB48YyLM41sRp4frmEkM64",
+	"4JY7p4QkA4ojaWPS5iJlwjCeGYf8yeTSPWUi7Tl/XuHsxNxTrW4MhIVFImzYoiXVp4f8hKVI4DzjxgSf",
+	"i7BiF+a1smGpoMITaZiB1qB/UsYOvDD3tvB3m9znSi5BGz/dmh1A7JsOIArH7WgqbbZxrObSODN/YFH3",
+	"Q+BBbWPcyd1scDBodLRQEWAndnVBNJyCsUKS08w0JIA+LPIVkvuvJSpiD3I8Yvfdda5SkBZ9MUMrJTXI",
+	"MdNQKF0JGWZ4DkxJcFEVMtdpAMEpDBNzqTpCv4/e7sp/EzJFFm4vSWa3EXNZFkxpVpQ6WXADMQY7yDL5",
+	"8RWa75onFnTQza1PrLvY3/FnXG19k7Qi/qh0CppZZXl2yC5aZsbYaAOa4aT20IX0x+rf+7KROD6sChjQ",
+	"YQ2kbY5uG3UPQ7kXKueCFOsAyQ6Ak9K4QIxJ3YBmCUfbboHn6E+icq/QOQweJ5kam2yR2rc0rFBCErly",
+	"S5PjQiMVTrPfkCFBD+mfwkJO//hXDbPoNPqXoyaseeRjmkfNZM0JRVxrvmqM4ZFS9FJrpd+DKZQ00Ics",
+	"B2P4fKwTNEFUgaTw1wVYLrLAZncNE40K1RwUg/4nPg6PDW3hg8jBgBZgXlPsd4OPOWB6BgOTuiZIH1IW",
+	"nbAMOiQiB/abovjxXa3WD6p4I+T1bhz1XfjdAEXg6GteG0Xl1W6+l8Q/vn+DrCKMFUmA0zF+NK3iCoYp",
+	"dFfpjKuINYYK/HElSs7EvMQoqEFWPqwH/p8DKWYvj1nKV+RSeleybxNmGZJZABTUDS2nvIpWBM1kbyUG",
+	"dvSWPGyV50qy+q14HNLXIiwB3CeiFluDq7p3mAajsiV4t9Arc5CWXb27R3gaG4jbAFInC65deiHLHJe1",
+	"zhUleUbpCSW7aptAPkb1/GJceiXpqNsAghp9bCgY6BwrUqMUccEZY2eEzEguSMtmQhs7Flchhb8RYX8P",
+	"mzKTMq+kFUFnKuw1I82uONmoYluveZsQUheurXNGPiER5ATn+mwlyqvJL+zlix9/PHjh+Fsjn6f7IlPn",
+	"g5lBt5bkBb7CEvTTTtEkv7aYDsvVVGQQM4vpOxuzqbJoupbyWqobeX8QZlzOSz7fhsbCO3kpqwc0iDuj",
+	"0MTBG/+ELYCnoO8TRmNPXg0E+gJhXi+3cRg7eeUCgEEaQvnM0babUOJkCw7qt32e5R7Fa8GN/QRwvcv2",
+	"cAy7wUHh6L1zyrfsyb2Ge0Kz1mDaIRUaEvs5atJIzhlbcEwisWri+9u8Cw+M5eMT5t7fzsbeC7u4PHj9",
+	"P/cHbSnFryWcDWnzS2NFTtmmRqOnwlghE8uWwgirtGFqCZoPRALdAm820fy4RUazgVvx3SAN7rTcJrIc",
+	"MNQa8zuQPiHLeQf7cd2UDxwhksoOCTNpQS952E7GQWS/Bx+q74lGf4LpQqnrC8gEJkX6qOHWQl7YAQv/",
+	"DqnB1C212yAKggZDQBeVCvdR1DrcQ3UFuCfmV1zhm8JG8Zi8JM32YShAMTq9iWxxOZiewacuqXOuUghj",
+	"WMJXe+bO4MwOVBhxVoBMUb7WWxWG+ZNDhoKvdqTvR56mzzJVNQV+8iiOTJkkAClFynzA/ssuZDYppy3g",
+	"Q9UMSwhHhe9AaPURdvl6IMLXMO7owzWQaLADaUkNttSyym0SVbY2j+fjtxQC3RVv7LTbcnQcInAWgyHe",
+	"5kjW2E66J51NGTYHi9obaxE8JQow3YjkYJVX97h6ZRDSDfcR5NizNg1yKEZaX7U1cEW+GBo4bJBNf3oE",
+	"V3/64pHqzyYHSH/6wpk0WDmzTj1DRPE3WHnNtcooCY2uI0aLfTFHzDjTXKYqJ39RIDIlaF6nxzOYWaZK",
+	"u+HwuyueTY3KSgtsYS1FpPH/hkpZannpwCiUixMpTJCrYsqT65gVWiy5BfInEQ0HmUp45iw4GqUBKSpE",
+	"wGsBZYSuH0W+Ja03UwHA2fvLyQd29u6KhLhxoT8UbxgqjVnOJZ/70H4eE4ALLtMMf9LgLEtziGAJm+GS",
+	"uOUqgKhx3iiO6gRP9OLw+PDYG+qSFyI6jV7ST2gx2wXR0hFPcyGPEkzGH91QNh5/nruT9ja7S1JEfwXb",
+	"T9ojRlwklOY7OT72IXLrU4S8KDKR0CxH/zROPDpzY6tv3luMcLvmCuJLDCE/KAtWaDXXYAxKKS1gCSkd",
+	"minznOuV24Oj1urFKvvgMqRITWTxJe1pEeN8bvDIzxBdwliHlugLeh/KBJBFAczWDqK6JOi1SlcPgaNK",
+	"1t3e3t72TuXkMU/FlxcgKb66R/roxuEDUFzJJc9EWhfyeRHl4Pj3/cFxruQsE4llB6yLGJ5p4OkKjf+K",
+	"IBG4H/aLJAsasz0G9BI0c0U3Xa5x0XgN01JkaZV7dDVWjlECNSxDDHMb10KHojhHpkrwDAqdQGHJQ4qd",
+	"wHKhQ3U51uo9Fx8DvVn01C9VYmdtkroeYyP2fMH0QamzNuLWsnvcgvHRUNTBLieHgr8uKc1d2SeXTMkE",
+	"2IJqu73+oPAqvSiUPGTvuHG5aJFWsJOcrN7mpi4jVWQt4RtonrMCc2Hx2qm+Eca26r4NqSTNc7AUc/nH",
+	"BsuT1WFPgkOY1s6iOBL4OqXfoziSPCf1XVcixC0a6On2UYtWyK+3ixC4FFRoafdWZ92tBvhtPBzGquGw",
+	"yoO2XtxIZgN5SS4u7sodQ7BRkWIIJQ0kX76Tz0YFHVqE0Dc8+4xXve7yyy1ue4KCE+m8Zjc8mOpCgKnS",
+	"vnWS3XM8mnRv0Q6EHLfgOR5L5Qc53dVSO0cgZs4DoMXcwv76SJWSj1nWkgzDnA0U7b8LX1d1/QJ24mtf",
+	"R+7q8qcrx1mufj9Mv9WzO7J0tV5XjLiEyQNJkc4WOVmbVfG/MMx7wKG1KeYWFCIbw2Lj0d0SZhvAsOqe",
+	"gQBHJljsX90jIhiI+PYkUisY/kgStblaM0ag4ts1GjoCda/mOhmRwtBdm1roPF2x3mEgqxr50RbnhFsv",
+	"xdcyu5XfuO45ZRmkTv51awz99YvKaLy6YMJ2Cg/tAuowQU8yv4dE6bRVAPpA/mivuvK2GzWxuoTbh7Te",
+	"mx2GSL2VN28QSmF1h1Vk9VZxJmIZC88rR00TFiGNkUgX6An5e0tIpifHL/a0jeZpDdGjOddJC1IE4dX+",
+	"QPhZ+VNrbiZdXdAV2U4RbEe5oN61WkxLz1NUFPUUZYxjWMZbCG7V1rdEzHlLrDhBA18Lpa05akrYgnaj",
+	"q+V3xp8LBNfFI1nashDfq5sqMMpJ0KDpZ2JmFMu4ngPz67FUkexe0NU9lik5b/NSTyRd0jB/A3qLofif",
+	"IqPMV85tVz0nZjlkM9HbHW1cXyalQXiomVv41xJsMOMTNF7cfjuVNg9sP/ZWbFXYVvczK5O5NKBjJmSS",
+	"lVXqrH2BNASaupGwoyXdKdDU7tYorWnEEtYyIwtwpFST7gOauJcyXYMKvgahkurmHq3c3cy6pUwPeYGR",
+	"s8OK+E6/hZacCsn1KrTvznxfD2TaF1H9MRa+2iMk/o3vhUNdxhPhI6oahxaU73S0T1JsO6HGNL/x3NqS",
+	"1K2SWieoie/M0Tf6/+2Ry1wNy2uQ9R1Pb61gwG5CwBxMQFpG+USGdOxl+SE7V3leJ8QMPuYGa8y0nQK3",
+	"hinJuGQizVAJoELoyWmnJ35BGC8dhD1hTWzkI3BrMqVr+u0kY84yoxDkWi/hnVPNbzLQpk7fsVLOSo0/",
+	"dfl7xjMz5Mc64QivFe2lB1GdvL2Nh8syXJCE8F0b4d5r0ZAoKSGxQs5jb5YnIP3rxvv8wjZHUtes4hp1",
+	"AaCHFouZDgjzB1cXG6X0dilEEoDAOPCnvVtcvH3/bzAo7mZmqgDppcXJHrMtH5TCTOqK1vewEJmTneM4",
+	"V+mq4k0YX/L2VKQJQvFyj9hqkEI2TEktfxgW8cUNIffyQXTAtZVNJokTTE1bCTRWJSMxwLBkYpMgpBzQ",
+	"UetOz1AuqH0naIvJ+A60UGkbSGQ3l4FBM3b93vrikDV1xVPlJaZUVdYmHRAm3ocI2Zknr1Ag/huOfHmM",
+	"/8WqxTFmZhOdcmUJrdjUC6qvPz7ugv/iEUNS465dBZNo7jEzNT004ae42/XAScjHsjzcGVN1AOHzKVoe",
+	"fwWPLacbZfA2HuhOTKm6jbeZL63aWBRSX176UzCk67Pwu2XI+rCCmrOoG1M8c+HduRApvIO2YGi4x2xV",
+	"Oj9cUHRObv6kupp59/DtvV4trQeVWmytl2uPDdfNbYsTv/iOne50q7V/+JN2xraucX0uaSJ0VPHxOq39",
+	"eFa/x4xhBwwvNzpRgUEgV9/9FGWGY23GG/RV9/Y2FQWguDj61lw1vnURA2qK15MeF/R7S3psd+CbmXfy",
+	"4vvK61U/lIEbquKSVHtvDPb2We09hYCASGXZTJXymWh3IVpHUbsQbTxoQVZ0WTVA2Ct53h9eQ70cBmgu",
+	"dY/Xs+3PlP87oPz3/szqQ2wKrMaxQVEG2OAj1W3tTUL/+WzHvfN512KsbuY8KYvxUeXNI9qr7jBY4n99",
+	"kmLGCYTvtgqPXGfyTQGcSuh0m77/TpVwdxOhGiB8gRn/RjvUURcE1YGcmaBqLLH/2pZJHUfvMM2TjXdY",
+	"zWczkTDexe5G1XjmKLOlEtdzExoMVpOY+pIgpvUyhhf5qsoCD1t1ZP3s5WPR9/0X1wVIe39Kb1e+Mnz5",
+	"iNrO1qTzzLlDl7rug3PDKudJFzJU0mCHWoY7ioHngoY/bEHDExAoz0UVf4KiijURvLWQoi+Lq6+jDN44",
+	"eKuWvs+wvxhWX3FslR3b+oshOCtznRS4YdU3Y6oLC3QnqxrgbaDAXQQC6XGi0Y8S7qs7bTxCifz249y7",
+	"N/6hS02C2l+Vxrexpgp+Yc0gPeJF5aqVydMMENJp48e5qtprHb75ubs3v/W2fMVVAzVSD23ddNrA7mze",
+	"4LdTsJta0xdNAklRZ98g3ebfawI9pEjo9s0NOUmU6woWPD2OXVFrludcwF3DH7x3pjOlNzhPI5S2r7vq",
+	"dPYbxfCtZoD7ZP0dboOcvPL9vI0rbyw1NYDGpoh8hYLyxQn+gT0QW7dA/4h3RnpwuJ6LzIjf1j5ZlfLV",
+	"oNTz7RVD1W6I3CiO3GhE6KhSt6uzn8+a1uqtBuzO8+aZ6+1lle8QellqVcDRa9CZkF18ffxwfsiwK6Zx",
+	"PYDQYXir5PB27G/RvlXOE1cnLYYeVieEgU4ouzoxDGmrspJRYNy3oLjHmZqx48dLSFViwlEwsn5Ndc86",
+	"6G46yBNCwHF0BIGm6BIcojdppBvXSnFY7+AN+E/VS/toaRBqtDmit4EfttbOcS3ngwziOsu7Jofm6TYd",
+	"oG/+hfbUOs36XNrd6UIBCtwrXZDu9PVcgIZDdtntMCtMu6cjE/XXOP77YCLmkttSw8HJDz/67uUYGjAL",
+	"fvLDj//xuTw+fpks4Cv76e3Z+cHkpzN8rfqyh0pX9EK/lYwrivN7eaBuBRs6hj5AOerO5D2OnB+9FpUu",
+	"+2gfOrbU6PRJ5jsczqao/ioWssoZCFDlAgI81JaHR99EOqLOsqHa7SY4fQBv2PTe3tpmVMFlkHCq7rD7",
+	"djyDwDztZFld7RgSvq5BkDWt3sBD4njIiXtkijl+EoIsoJYRq05RPVPpSEswSKKD5kG51Tq4hsJ3opZV",
+	"A2exhH47D1evtH9CfnpmwdPgpscuOAybBc8svC13UGQ8gZ3YuGehHLUU0Zj+sMNdIGsH4A6NILuf4Bgb",
+	"kvxuobCx4WCDmF7PwWqre2o72ILkD9R5cP2zKyNc9IY+HjEX8juUFHU8oE3Ts50Fx/oNsqCF+t63Ffyg",
+	"fmnV4e8xb//SdbDvYqmCijqSq853a6PYVx3R6DfKHVP4Ihqxn5uJrcXx18G6fb6l8/u5pVMfae+rxi1u",
+	"qF5DbsDxNKGjZvr4SLSwtjg9OqKPhCyUsad/Of7LcXT75fb/BwA03mfXPo0AAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
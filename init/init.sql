@@ -8,8 +8,11 @@ CREATE TABLE IF NOT EXISTS urls (
 	modified_by VARCHAR(255)
 );
 
+-- Every deletion or expiry of a link leaves a row, a path deleted more than once has one per deletion.
+-- Databases created with short_path as the key are converted with migrations/002_urls_archive_id.sql.
 CREATE TABLE IF NOT EXISTS urls_archive (
-	short_path VARCHAR(255) PRIMARY KEY NOT NULL,
+	id BIGSERIAL PRIMARY KEY,
+	short_path VARCHAR(255) NOT NULL,
 	original_url TEXT NOT NULL,
 	expiry TIMESTAMP WITHOUT TIME ZONE,
 	created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL,
@@ -20,13 +23,221 @@ CREATE TABLE IF NOT EXISTS urls_archive (
 	deleted_by VARCHAR(255)
 );
 
+CREATE INDEX IF NOT EXISTS idx_urls_archive_short_path ON urls_archive(short_path, id);
+
+-- Partitioned by month of accessed_at (UTC). Monthly partitions are created ahead of time and dropped after the
+-- raw retention by the partition maintenance job, clicks outside every partition land in the default partition.
+-- Databases created before partitioning are converted with migrations/001_partition_url_access_logs.sql.
 CREATE TABLE IF NOT EXISTS url_access_logs (
-    id SERIAL PRIMARY KEY,
+    id BIGSERIAL,
+    short_path VARCHAR(255) NOT NULL,
+    accessed_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+    referrer_host VARCHAR(255),
+    browser VARCHAR(64),
+    os VARCHAR(64),
+    device_class VARCHAR(16),
+    client_ip VARCHAR(45),
+    language VARCHAR(35),
+    query_string TEXT,
+    country VARCHAR(2),
+    region VARCHAR(8),
+    city VARCHAR(128),
+    is_bot BOOLEAN NOT NULL DEFAULT FALSE,
+    click_id VARCHAR(32),
+    is_duplicate BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (id, accessed_at)
+) PARTITION BY RANGE (accessed_at);
+
+CREATE TABLE IF NOT EXISTS url_access_logs_default PARTITION OF url_access_logs DEFAULT;
+
+-- Click metadata, for databases created before these columns were added to the table above.
+ALTER TABLE url_access_logs
+    ADD COLUMN IF NOT EXISTS referrer_host VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS browser VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS os VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS device_class VARCHAR(16),
+    ADD COLUMN IF NOT EXISTS client_ip VARCHAR(45),
+    ADD COLUMN IF NOT EXISTS language VARCHAR(35),
+    ADD COLUMN IF NOT EXISTS query_string TEXT,
+    ADD COLUMN IF NOT EXISTS country VARCHAR(2),
+    ADD COLUMN IF NOT EXISTS region VARCHAR(8),
+    ADD COLUMN IF NOT EXISTS city VARCHAR(128),
+    ADD COLUMN IF NOT EXISTS is_bot BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS click_id VARCHAR(32),
+    ADD COLUMN IF NOT EXISTS is_duplicate BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_accessed_at ON url_access_logs(short_path, accessed_at);
+-- Lets the rollup job read an hour of clicks without scanning the whole month.
+CREATE INDEX IF NOT EXISTS idx_url_access_logs_accessed_at_brin ON url_access_logs USING BRIN (accessed_at);
+-- Lets every page of a click export start right after the previous one.
+CREATE INDEX IF NOT EXISTS idx_url_access_logs_accessed_at_id ON url_access_logs(accessed_at, id);
+-- Finds the click a conversion is reported for.
+CREATE INDEX IF NOT EXISTS idx_url_access_logs_click_id ON url_access_logs(click_id) WHERE click_id IS NOT NULL;
+
+-- Clicks per short path and hour or day, maintained by the rollup job up to the watermark in click_rollup_watermarks.
+-- clicks leaves out bots, they are counted in bot_clicks.
+CREATE TABLE IF NOT EXISTS url_click_rollups_hourly (
+    short_path VARCHAR(255) NOT NULL,
+    bucket TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    clicks BIGINT NOT NULL,
+    bot_clicks BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (short_path, bucket)
+);
+
+CREATE TABLE IF NOT EXISTS url_click_rollups_daily (
+    short_path VARCHAR(255) NOT NULL,
+    bucket DATE NOT NULL,
+    clicks BIGINT NOT NULL,
+    bot_clicks BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (short_path, bucket)
+);
+
+ALTER TABLE url_click_rollups_hourly ADD COLUMN IF NOT EXISTS bot_clicks BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE url_click_rollups_daily ADD COLUMN IF NOT EXISTS bot_clicks BIGINT NOT NULL DEFAULT 0;
+
+-- The top links, domain stats and counter reconciliation read a range of buckets across all links.
+CREATE INDEX IF NOT EXISTS idx_url_click_rollups_hourly_bucket ON url_click_rollups_hourly(bucket);
+CREATE INDEX IF NOT EXISTS idx_url_click_rollups_daily_bucket ON url_click_rollups_daily(bucket);
+
+CREATE TABLE IF NOT EXISTS click_rollup_watermarks (
+    name VARCHAR(64) PRIMARY KEY,
+    rolled_up_to TIMESTAMP WITHOUT TIME ZONE NOT NULL
+);
+
+-- Baseline of the clicks per detection bucket of every recently clicked link, maintained by the anomaly detection
+-- job up to its watermark in click_rollup_watermarks.
+CREATE TABLE IF NOT EXISTS link_traffic_baselines (
+    short_path VARCHAR(255) PRIMARY KEY,
+    mean DOUBLE PRECISION NOT NULL,
+    variance DOUBLE PRECISION NOT NULL,
+    samples INTEGER NOT NULL,
+    state VARCHAR(8) NOT NULL DEFAULT 'normal',
+    alerted_at TIMESTAMP WITHOUT TIME ZONE
+);
+
+-- Alerts raised by the anomaly detection, written with the bucket that raised them and deleted once every webhook
+-- received them or they ran out of attempts.
+CREATE TABLE IF NOT EXISTS traffic_alerts (
+    id BIGSERIAL PRIMARY KEY,
+    dedup_key VARCHAR(320) NOT NULL UNIQUE,
+    kind VARCHAR(8) NOT NULL,
+    short_path VARCHAR(255) NOT NULL,
+    bucket_start TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    bucket_end TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    clicks BIGINT NOT NULL,
+    baseline DOUBLE PRECISION NOT NULL,
+    z_score DOUBLE PRECISION NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITHOUT TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_traffic_alerts_due ON traffic_alerts(next_attempt_at);
+
+-- Per link overrides of the anomaly thresholds, NULL uses the configured default.
+CREATE TABLE IF NOT EXISTS link_alert_settings (
+    short_path VARCHAR(255) PRIMARY KEY,
+    spike_z DOUBLE PRECISION,
+    drop_z DOUBLE PRECISION,
+    min_clicks INTEGER,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+-- Conversions reported by destinations for the click IDs appended to redirects.
+CREATE TABLE IF NOT EXISTS conversions (
+    id BIGSERIAL PRIMARY KEY,
+    click_id VARCHAR(32) NOT NULL,
+    short_path VARCHAR(255) NOT NULL,
+    clicked_at TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    type VARCHAR(64) NOT NULL,
+    value NUMERIC(18, 4) NOT NULL DEFAULT 0,
+    transaction_id VARCHAR(255),
+    converted_at TIMESTAMP WITHOUT TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversions_short_path ON conversions(short_path, type);
+-- A conversion reported again with the same transaction ID is ignored.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_conversions_transaction ON conversions(click_id, transaction_id) WHERE transaction_id IS NOT NULL;
+
+-- Link lifecycle events, written in the same transaction as the change. Consumers read them in (txid, id) order up
+-- to the oldest transaction still running, so an event is never skipped because it committed after a later id.
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    txid XID8 NOT NULL DEFAULT pg_current_xact_id(),
+    type VARCHAR(32) NOT NULL,
+    short_path VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC')
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_txid_id ON outbox_events(txid, id);
+
+-- The last outbox event each consumer has read.
+CREATE TABLE IF NOT EXISTS outbox_offsets (
+    consumer VARCHAR(64) PRIMARY KEY,
+    txid XID8 NOT NULL,
+    event_id BIGINT NOT NULL
+);
+
+-- Endpoints that receive link lifecycle events, every type when event_types is empty.
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITHOUT TIME ZONE NOT NULL
+);
+
+-- One row per event and subscription, retried until it succeeds or runs out of attempts.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL REFERENCES outbox_events(id),
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    last_status_code INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    delivered_at TIMESTAMP WITHOUT TIME ZONE,
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, id);
+
+-- Who changed which link, from where, and how. Rows can only be added, the trigger below rejects changes.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    action VARCHAR(16) NOT NULL,
     short_path VARCHAR(255) NOT NULL,
-    accessed_at TIMESTAMP WITHOUT TIME ZONE DEFAULT NOW()
+    actor VARCHAR(255) NOT NULL,
+    source_ip VARCHAR(45),
+    user_agent TEXT,
+    request_id VARCHAR(128),
+    before JSONB,
+    after JSONB,
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL
 );
 
-CREATE INDEX idx_accessed_at ON url_access_logs(short_path, accessed_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_short_path ON audit_log(short_path, id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor, id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+
+CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION reject_audit_log_change();
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_log_change();
 
 -- Index for fast lookups by original URL
 CREATE INDEX IF NOT EXISTS idx_urls_original_url ON urls(original_url);
@@ -42,13 +253,26 @@ CREATE INDEX IF NOT EXISTS idx_urls_expiry_short_path ON urls(expiry, short_path
 
 CREATE OR REPLACE FUNCTION move_expired_urls_to_archive() RETURNS void AS $$
 BEGIN
-    -- Insert expired URLs into the archive table
-    INSERT INTO urls_archive (short_path, original_url, expiry, created_at, created_by, modified_at, modified_by, deleted_at, deleted_by)
-    SELECT short_path, original_url, expiry, created_at, created_by, modified_at, modified_by, NOW(), 'system'
-    FROM urls
-    WHERE expiry < NOW();
-
-    -- Delete expired URLs from the main table
-    DELETE FROM urls WHERE expiry < NOW();
+    -- Move expired URLs to the archive table and record a link.expired event for each, in the same transaction.
+    WITH expired AS (
+        DELETE FROM urls WHERE expiry < NOW()
+        RETURNING short_path, original_url, expiry, created_at, created_by, modified_at, modified_by, NOW()::timestamp AS deleted_at
+    ), archived AS (
+        INSERT INTO urls_archive (short_path, original_url, expiry, created_at, created_by, modified_at, modified_by, deleted_at, deleted_by)
+        SELECT short_path, original_url, expiry, created_at, created_by, modified_at, modified_by, deleted_at, 'system'
+        FROM expired
+    )
+    INSERT INTO outbox_events (type, short_path, payload)
+    SELECT 'link.expired', short_path, jsonb_strip_nulls(jsonb_build_object(
+        'shortPath', short_path,
+        'originalUrl', original_url,
+        'expiry', to_char(expiry, 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"'),
+        'createdAt', to_char(created_at, 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"'),
+        'createdBy', created_by,
+        'modifiedAt', to_char(modified_at, 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"'),
+        'modifiedBy', modified_by,
+        'deletedAt', to_char(deleted_at, 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"'),
+        'deletedBy', 'system'))
+    FROM expired;
 END;
-$$ LANGUAGE plpgsql;
\ No newline at end of file
+$$ LANGUAGE plpgsql;
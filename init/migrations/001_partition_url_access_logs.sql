@@ -0,0 +1,77 @@
+-- Converts an unpartitioned url_access_logs into the partitioned table of init.sql.
+-- Clicks are copied into monthly partitions, so run it in a maintenance window: redirects keep working but
+-- clicks are queued in memory while the table is locked and dropped if the queue fills up.
+-- Files in this directory are not run by the postgres image on startup, run it with
+--   psql -v ON_ERROR_STOP=1 -f 001_partition_url_access_logs.sql
+-- after the click metadata ALTER TABLE of init.sql. Every column that ALTER adds is copied, so a column added to
+-- url_access_logs has to be added here as well.
+BEGIN;
+
+LOCK TABLE url_access_logs IN ACCESS EXCLUSIVE MODE;
+
+ALTER TABLE url_access_logs RENAME TO url_access_logs_unpartitioned;
+ALTER INDEX IF EXISTS idx_accessed_at RENAME TO idx_accessed_at_unpartitioned;
+-- init.sql creates these on the old table as well, their names are needed for the indexes of the new one.
+DROP INDEX IF EXISTS idx_url_access_logs_accessed_at_brin;
+DROP INDEX IF EXISTS idx_url_access_logs_accessed_at_id;
+DROP INDEX IF EXISTS idx_url_access_logs_click_id;
+
+CREATE TABLE url_access_logs (
+    id BIGSERIAL,
+    short_path VARCHAR(255) NOT NULL,
+    accessed_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+    referrer_host VARCHAR(255),
+    browser VARCHAR(64),
+    os VARCHAR(64),
+    device_class VARCHAR(16),
+    client_ip VARCHAR(45),
+    language VARCHAR(35),
+    query_string TEXT,
+    country VARCHAR(2),
+    region VARCHAR(8),
+    city VARCHAR(128),
+    is_bot BOOLEAN NOT NULL DEFAULT FALSE,
+    click_id VARCHAR(32),
+    is_duplicate BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (id, accessed_at)
+) PARTITION BY RANGE (accessed_at);
+
+CREATE TABLE url_access_logs_default PARTITION OF url_access_logs DEFAULT;
+CREATE INDEX idx_accessed_at ON url_access_logs(short_path, accessed_at);
+CREATE INDEX idx_url_access_logs_accessed_at_brin ON url_access_logs USING BRIN (accessed_at);
+CREATE INDEX idx_url_access_logs_accessed_at_id ON url_access_logs(accessed_at, id);
+CREATE INDEX idx_url_access_logs_click_id ON url_access_logs(click_id) WHERE click_id IS NOT NULL;
+
+-- One partition per month with clicks, up to the month after the current one. Later months are created by the
+-- partition maintenance job.
+DO $$
+DECLARE
+    month DATE;
+BEGIN
+    FOR month IN
+        SELECT generate_series(
+            date_trunc('month', COALESCE(MIN(accessed_at), NOW() AT TIME ZONE 'UTC')),
+            date_trunc('month', NOW() AT TIME ZONE 'UTC') + INTERVAL '1 month',
+            INTERVAL '1 month')::date
+        FROM url_access_logs_unpartitioned
+    LOOP
+        EXECUTE format('CREATE TABLE %I PARTITION OF url_access_logs FOR VALUES FROM (%L) TO (%L)',
+            'url_access_logs_p' || to_char(month, 'YYYYMM'), month, month + INTERVAL '1 month');
+    END LOOP;
+END
+$$;
+
+-- Ids are kept, the cursors of running click exports point at them.
+INSERT INTO url_access_logs (id, short_path, accessed_at, referrer_host, browser, os, device_class, client_ip, language,
+                             query_string, country, region, city, is_bot, click_id,
+                             is_duplicate)
+SELECT id, short_path, COALESCE(accessed_at, NOW() AT TIME ZONE 'UTC'), referrer_host, browser, os, device_class, client_ip,
+       language, query_string, country, region, city, is_bot, click_id, is_duplicate
+FROM url_access_logs_unpartitioned;
+
+-- The new table has its own sequence, new clicks continue after the copied ids.
+SELECT setval(pg_get_serial_sequence('url_access_logs', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM url_access_logs;
+
+DROP TABLE url_access_logs_unpartitioned;
+
+COMMIT;
@@ -0,0 +1,24 @@
+-- Keys urls_archive by a generated id instead of short_path, so a path that is deleted again after being reused or
+-- restored keeps every archived version. Existing rows are numbered in the order they were deleted.
+-- Files in this directory are not run by the postgres image on startup, run it with
+--   psql -v ON_ERROR_STOP=1 -f 002_urls_archive_id.sql
+BEGIN;
+
+LOCK TABLE urls_archive IN ACCESS EXCLUSIVE MODE;
+
+ALTER TABLE urls_archive DROP CONSTRAINT urls_archive_pkey;
+ALTER TABLE urls_archive ADD COLUMN id BIGINT;
+
+CREATE SEQUENCE urls_archive_id_seq OWNED BY urls_archive.id;
+UPDATE urls_archive a SET id = n.id
+FROM (SELECT short_path, nextval('urls_archive_id_seq') AS id
+      FROM (SELECT short_path FROM urls_archive ORDER BY deleted_at NULLS FIRST, short_path) ordered) n
+WHERE a.short_path = n.short_path;
+
+ALTER TABLE urls_archive ALTER COLUMN id SET DEFAULT nextval('urls_archive_id_seq');
+ALTER TABLE urls_archive ALTER COLUMN id SET NOT NULL;
+ALTER TABLE urls_archive ADD PRIMARY KEY (id);
+
+CREATE INDEX IF NOT EXISTS idx_urls_archive_short_path ON urls_archive(short_path, id);
+
+COMMIT;
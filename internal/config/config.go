@@ -4,18 +4,42 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	Redis       RedisConfig       `mapstructure:"redis"`
+	Cache       CacheConfig       `mapstructure:"cache"`
+	Clicks      ClicksConfig      `mapstructure:"clicks"`
+	GeoIP       GeoIPConfig       `mapstructure:"geoip"`
+	Rollups     RollupsConfig     `mapstructure:"rollups"`
+	Stats       StatsConfig       `mapstructure:"stats"`
+	Partitions  PartitionsConfig  `mapstructure:"partitions"`
+	Visitors    VisitorsConfig    `mapstructure:"visitors"`
+	Bots        BotsConfig        `mapstructure:"bots"`
+	Counters    CountersConfig    `mapstructure:"counters"`
+	Exports     ExportsConfig     `mapstructure:"exports"`
+	Streams     StreamsConfig     `mapstructure:"streams"`
+	Alerts      AlertsConfig      `mapstructure:"alerts"`
+	Conversions ConversionsConfig `mapstructure:"conversions"`
+	Privacy     PrivacyConfig     `mapstructure:"privacy"`
+	Webhooks    WebhooksConfig    `mapstructure:"webhooks"`
+	Outbox      OutboxConfig      `mapstructure:"outbox"`
+	Audit       AuditConfig       `mapstructure:"audit"`
 }
 
 type ServerConfig struct {
 	Port string `mapstructure:"port"`
+	// ShutdownTimeout bounds how long in-flight requests and queued clicks are given to finish on shutdown.
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	// TrustedProxies lists the proxy IPs or CIDRs whose X-Forwarded-For and X-Real-IP headers are believed
+	// when recording a client IP. Empty trusts none and records the connecting address.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type DatabaseConfig struct {
@@ -24,11 +48,260 @@ type DatabaseConfig struct {
 	User     string `mapstructure:"user"`
 	Password string `mapstructure:"password"`
 	DBName   string `mapstructure:"dbname"`
+	// SSLMode is passed to lib/pq as is (disable, require, verify-ca or verify-full), SSLRootCert is the CA used to verify the server.
+	SSLMode          string        `mapstructure:"sslmode"`
+	SSLRootCert      string        `mapstructure:"sslrootcert"`
+	ApplicationName  string        `mapstructure:"application_name"`
+	ConnectTimeout   time.Duration `mapstructure:"connect_timeout"`
+	StatementTimeout time.Duration `mapstructure:"statement_timeout"`
+	MaxOpenConns     int           `mapstructure:"max_open_conns"`
+	MaxIdleConns     int           `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime  time.Duration `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime  time.Duration `mapstructure:"conn_max_idle_time"`
+	// ConnectRetries is how many more times to try reaching the primary at startup, waiting ConnectBackoff
+	// before the first retry, at least 100ms, and doubling it up to ConnectMaxBackoff after every failure.
+	ConnectRetries    int           `mapstructure:"connect_retries"`
+	ConnectBackoff    time.Duration `mapstructure:"connect_backoff"`
+	ConnectMaxBackoff time.Duration `mapstructure:"connect_max_backoff"`
+	// Replicas are DSNs of read replicas, lookups are spread across them while writes always go to the primary.
+	Replicas []string `mapstructure:"replicas"`
+	// Replicas failing the health check or lagging behind by more than MaxReplicaLag are skipped until they recover.
+	ReplicaCheckInterval time.Duration `mapstructure:"replica_check_interval"`
+	MaxReplicaLag        time.Duration `mapstructure:"max_replica_lag"`
+	// ReadYourWritesWindow is how long lookups of a link written by this instance keep going to the primary.
+	ReadYourWritesWindow time.Duration `mapstructure:"read_your_writes_window"`
 }
 
 type RedisConfig struct {
+	// Mode is one of standalone (default), sentinel or cluster.
+	Mode string `mapstructure:"mode"`
 	Host string `mapstructure:"host"`
 	Port string `mapstructure:"port"`
+	// Addrs lists the sentinel addresses in sentinel mode and the seed nodes in cluster mode.
+	Addrs            []string       `mapstructure:"addrs"`
+	MasterName       string         `mapstructure:"master_name"`
+	SentinelUsername string         `mapstructure:"sentinel_username"`
+	SentinelPassword string         `mapstructure:"sentinel_password"`
+	Username         string         `mapstructure:"username"`
+	Password         string         `mapstructure:"password"`
+	DB               int            `mapstructure:"db"`
+	TLS              RedisTLSConfig `mapstructure:"tls"`
+	PoolSize         int            `mapstructure:"pool_size"`
+	MinIdleConns     int            `mapstructure:"min_idle_conns"`
+	MaxRetries       int            `mapstructure:"max_retries"`
+	DialTimeout      time.Duration  `mapstructure:"dial_timeout"`
+	ReadTimeout      time.Duration  `mapstructure:"read_timeout"`
+	WriteTimeout     time.Duration  `mapstructure:"write_timeout"`
+	PoolTimeout      time.Duration  `mapstructure:"pool_timeout"`
+}
+
+type RedisTLSConfig struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	CACertFile         string `mapstructure:"ca_cert_file"`
+	CertFile           string `mapstructure:"cert_file"`
+	KeyFile            string `mapstructure:"key_file"`
+	ServerName         string `mapstructure:"server_name"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+}
+
+// CacheConfig controls how long urls stay in Redis.
+type CacheConfig struct {
+	// BaseTTL is the expiry given to a url when it is cached.
+	BaseTTL time.Duration `mapstructure:"base_ttl"`
+	// Jitter randomises every TTL by up to this fraction (0.1 is +/-10%) so keys cached together do not expire together.
+	Jitter float64 `mapstructure:"jitter"`
+	// Keys hit at least HotThreshold times within HotWindow have their expiry pushed out to HotTTL. Zero in any of them
+	// disables sliding expiry.
+	HotThreshold int           `mapstructure:"hot_threshold"`
+	HotWindow    time.Duration `mapstructure:"hot_window"`
+	HotTTL       time.Duration `mapstructure:"hot_ttl"`
+	// MaxTTL caps every TTL. A url is never cached past its own expiry either.
+	MaxTTL time.Duration `mapstructure:"max_ttl"`
+}
+
+// ClicksConfig controls the in-process queue that batches redirect clicks into url_access_logs. Batches over the 4369
+// rows one insert can bind are split into several inserts in a single transaction.
+type ClicksConfig struct {
+	QueueSize     int           `mapstructure:"queue_size"`
+	Workers       int           `mapstructure:"workers"`
+	BatchSize     int           `mapstructure:"batch_size"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+	// EnqueueTimeout is how long a redirect waits for room in a full queue before the click is dropped. Zero drops straight away.
+	EnqueueTimeout time.Duration `mapstructure:"enqueue_timeout"`
+	FlushTimeout   time.Duration `mapstructure:"flush_timeout"`
+	FlushRetries   int           `mapstructure:"flush_retries"`
+	// DedupWindow is how long repeated clicks of a link by the same visitor are marked as duplicates, zero marks none.
+	DedupWindow time.Duration `mapstructure:"dedup_window"`
+}
+
+// GeoIPConfig points at a local MaxMind (.mmdb) city database used to add the location to clicks.
+type GeoIPConfig struct {
+	// DatabasePath is empty to store clicks without a location.
+	DatabasePath string `mapstructure:"database_path"`
+	// ReloadInterval is how often the file is checked for changes, so it can be replaced without a restart.
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+}
+
+// RollupsConfig controls the job that aggregates url_access_logs into hourly and daily rollups for the stats API.
+type RollupsConfig struct {
+	// Interval is how often the job runs, zero disables it and stats are computed from raw clicks only.
+	Interval time.Duration `mapstructure:"interval"`
+	// Lateness is how long after an hour ends its clicks are rolled up. It has to cover the click queue's flush delay.
+	Lateness time.Duration `mapstructure:"lateness"`
+	// MaxSpan limits how much raw data a single rollup transaction covers while catching up, in whole hours.
+	MaxSpan time.Duration `mapstructure:"max_span"`
+}
+
+// StatsConfig controls the link stats computed from raw clicks.
+type StatsConfig struct {
+	// BreakdownWindow is how far back the referrer, browser, device and location breakdowns look. They are counted from
+	// raw clicks on every request, so it bounds their cost. Zero leaves them out.
+	BreakdownWindow time.Duration `mapstructure:"breakdown_window"`
+}
+
+// PartitionsConfig controls the job maintaining the monthly partitions of url_access_logs.
+type PartitionsConfig struct {
+	Interval time.Duration `mapstructure:"interval"`
+	// PremakeMonths is how many months after the current one get a partition ahead of time.
+	PremakeMonths int `mapstructure:"premake_months"`
+	// Retention is how long raw clicks are kept, zero keeps them forever. Breakdowns and time series are computed
+	// from raw clicks and only cover this period, the all time and weekly counts come from the rollups.
+	Retention time.Duration `mapstructure:"retention"`
+	// Archive detaches expired partitions and keeps them as url_access_logs_archive_pYYYYMM instead of dropping them.
+	Archive bool `mapstructure:"archive"`
+}
+
+// VisitorsConfig controls unique visitor counting.
+type VisitorsConfig struct {
+	// Secret keys the hash of client IP and user agent that identifies a visitor. Changing it makes every visitor
+	// count as new. It is required, a guessable secret would let visitor IDs be matched to IP addresses.
+	Secret string `mapstructure:"secret"`
+}
+
+// BotsConfig controls how clicks by crawlers and link unfurlers are recognised.
+type BotsConfig struct {
+	// RulesPath is a file of User-Agent substrings, one per line, used instead of the list built into the binary.
+	RulesPath string `mapstructure:"rules_path"`
+}
+
+// CountersConfig controls the job reconciling the Redis click counters behind the recent stats windows with Postgres.
+type CountersConfig struct {
+	// ReconcileInterval is how often rolled up hours are copied to the counters, zero disables it.
+	ReconcileInterval time.Duration `mapstructure:"reconcile_interval"`
+	// MaxSpan limits how many hours of rollups a single query reads while catching up.
+	MaxSpan time.Duration `mapstructure:"max_span"`
+}
+
+// ExportsConfig controls raw click exports.
+type ExportsConfig struct {
+	// PageSize is how many clicks are read per query, and per Parquet row group.
+	PageSize int `mapstructure:"page_size"`
+}
+
+// StreamsConfig controls the live click streams sent as Server-Sent Events.
+type StreamsConfig struct {
+	// QueueSize bounds the clicks waiting to be published, more are dropped from the streams only.
+	QueueSize int `mapstructure:"queue_size"`
+	// HistorySize is how many events per stream are kept for clients reconnecting with Last-Event-ID, for up to
+	// HistoryTTL after the last click.
+	HistorySize int           `mapstructure:"history_size"`
+	HistoryTTL  time.Duration `mapstructure:"history_ttl"`
+	// MaxConnections and MaxConnectionsPerClient limit the open streams of a replica, in total and per client IP.
+	// Zero is unlimited.
+	MaxConnections          int `mapstructure:"max_connections"`
+	MaxConnectionsPerClient int `mapstructure:"max_connections_per_client"`
+	// BufferSize is how many events a connection may fall behind before it is closed.
+	BufferSize int `mapstructure:"buffer_size"`
+	// MaxDuration closes a connection after this long so clients spread out over replicas again, zero keeps it open.
+	MaxDuration time.Duration `mapstructure:"max_duration"`
+	// HeartbeatInterval is how often a comment is sent on an idle connection to keep proxies from closing it.
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
+}
+
+// AlertsConfig controls the detection of click spikes and drops and the webhooks alerts are sent to.
+type AlertsConfig struct {
+	// Interval is how often finished buckets are checked, zero disables detection.
+	Interval time.Duration `mapstructure:"interval"`
+	// Bucket is the period clicks are counted in, a bucket is checked Lateness after it ends.
+	Bucket   time.Duration `mapstructure:"bucket"`
+	Lateness time.Duration `mapstructure:"lateness"`
+	// HalfLife is how long until a bucket counts half as much in a link's baseline.
+	HalfLife time.Duration `mapstructure:"half_life"`
+	// WarmupBuckets is how many buckets a link's baseline is learned for before it can alert.
+	WarmupBuckets int `mapstructure:"warmup_buckets"`
+	// SpikeZ and DropZ are the default number of standard deviations above or below the baseline that alert.
+	SpikeZ float64 `mapstructure:"spike_z"`
+	DropZ  float64 `mapstructure:"drop_z"`
+	// MinClicks is the default least clicks of a spike, or baseline of a drop, so quiet links do not alert.
+	MinClicks int `mapstructure:"min_clicks"`
+	// Cooldown is the least time between two alerts of the same link.
+	Cooldown time.Duration `mapstructure:"cooldown"`
+	// Webhooks receive every alert as a JSON POST, signed with WebhookSecret when it is set.
+	Webhooks       []string      `mapstructure:"webhooks"`
+	WebhookSecret  string        `mapstructure:"webhook_secret"`
+	WebhookTimeout time.Duration `mapstructure:"webhook_timeout"`
+}
+
+// ConversionsConfig controls the click IDs added to redirects and the conversions recorded against them.
+type ConversionsConfig struct {
+	// ClickIDParam is the query parameter the click ID is appended to the destination as, empty leaves destinations as they are.
+	ClickIDParam string `mapstructure:"click_id_param"`
+	// AttributionWindow is how long after the click a conversion is still accepted.
+	AttributionWindow time.Duration `mapstructure:"attribution_window"`
+}
+
+// PrivacyConfig controls what is stored about the people clicking links.
+type PrivacyConfig struct {
+	// IPMode is how client IPs are stored: "full", "truncate" to the /24 (IPv4) or /48 (IPv6) network, "hash" with a
+	// salt that rotates every SaltRotation, or "none". The location is looked up from the full IP before it is changed.
+	IPMode       string        `mapstructure:"ip_mode"`
+	SaltRotation time.Duration `mapstructure:"salt_rotation"`
+	// HonorDNT leaves out the client IP, visitor ID, query string, region and city of clicks sent with DNT: 1 or Sec-GPC: 1.
+	HonorDNT bool `mapstructure:"honor_dnt"`
+	// Retention is how long each listed column of url_access_logs is kept before the purge job clears it, e.g.
+	// {"client_ip": "720h"}. Columns that are not listed are kept as long as the click.
+	Retention      map[string]time.Duration `mapstructure:"retention"`
+	PurgeInterval  time.Duration            `mapstructure:"purge_interval"`
+	PurgeBatchSize int                      `mapstructure:"purge_batch_size"`
+}
+
+// WebhooksConfig controls the delivery of link events to webhook subscriptions.
+type WebhooksConfig struct {
+	// Interval is how often new events are fanned out and due deliveries sent, zero disables delivery.
+	Interval time.Duration `mapstructure:"interval"`
+	// BatchSize is the most events fanned out, and deliveries sent, per run.
+	BatchSize int `mapstructure:"batch_size"`
+	// Timeout bounds each delivery attempt, claimed deliveries are held for twice as long. Zero or less is 10s.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// A failed delivery is retried after RetryBase, doubling every attempt up to RetryMax, until MaxAttempts.
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	RetryBase   time.Duration `mapstructure:"retry_base"`
+	RetryMax    time.Duration `mapstructure:"retry_max"`
+}
+
+// OutboxConfig controls the relay of link events from the outbox to the cache and the event sinks.
+type OutboxConfig struct {
+	// RelayInterval is how often new events are relayed, zero disables the relay and leaves cache invalidation to
+	// the requests that change links.
+	RelayInterval time.Duration `mapstructure:"relay_interval"`
+	BatchSize     int           `mapstructure:"batch_size"`
+	// RedisStream is the stream events are appended to, capped at about RedisStreamMaxLen entries. Empty disables it.
+	RedisStream       string `mapstructure:"redis_stream"`
+	RedisStreamMaxLen int64  `mapstructure:"redis_stream_max_len"`
+	// NATSURL is the server events are published to, on NATSSubject.<event type>. Empty disables it.
+	NATSURL     string `mapstructure:"nats_url"`
+	NATSSubject string `mapstructure:"nats_subject"`
+	// FilePath is a file events are appended to as JSON lines, "-" writes them to stdout. Empty disables it.
+	FilePath string `mapstructure:"file_path"`
+	// Retention is how long events read by every consumer are kept before the relay deletes them, zero keeps them.
+	Retention time.Duration `mapstructure:"retention"`
+}
+
+// AuditConfig controls how changes to links are attributed in the audit log.
+type AuditConfig struct {
+	// ActorHeader is the request header naming who made a change, requests without it are recorded as "system".
+	// The header is trusted as sent, it should be set by an authenticating proxy in front of the service.
+	ActorHeader string `mapstructure:"actor_header"`
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -37,6 +310,85 @@ func LoadConfig(path string) (*Config, error) {
 	viper.SetEnvPrefix("URL_SHORTENER")
 
 	viper.SetDefault("server.port", "8080")
+	viper.SetDefault("server.shutdown_timeout", "30s")
+	viper.SetDefault("database.sslmode", "disable")
+	viper.SetDefault("database.application_name", "url-shortener")
+	viper.SetDefault("database.connect_timeout", "5s")
+	viper.SetDefault("database.statement_timeout", "10s")
+	viper.SetDefault("database.max_open_conns", 25)
+	viper.SetDefault("database.max_idle_conns", 10)
+	viper.SetDefault("database.conn_max_lifetime", "30m")
+	viper.SetDefault("database.conn_max_idle_time", "5m")
+	viper.SetDefault("database.connect_retries", 10)
+	viper.SetDefault("database.connect_backoff", "1s")
+	viper.SetDefault("database.connect_max_backoff", "30s")
+	viper.SetDefault("database.replica_check_interval", "5s")
+	viper.SetDefault("database.max_replica_lag", "2s")
+	viper.SetDefault("database.read_your_writes_window", "10s")
+	viper.SetDefault("redis.mode", "standalone")
+	viper.SetDefault("clicks.queue_size", 10000)
+	viper.SetDefault("clicks.workers", 2)
+	viper.SetDefault("clicks.batch_size", 500)
+	viper.SetDefault("clicks.flush_interval", "1s")
+	viper.SetDefault("clicks.enqueue_timeout", "0s")
+	viper.SetDefault("clicks.flush_timeout", "10s")
+	viper.SetDefault("clicks.flush_retries", 3)
+	viper.SetDefault("clicks.dedup_window", "30s")
+	viper.SetDefault("geoip.reload_interval", "1m")
+	viper.SetDefault("rollups.interval", "1m")
+	viper.SetDefault("rollups.lateness", "5m")
+	viper.SetDefault("rollups.max_span", "24h")
+	viper.SetDefault("stats.breakdown_window", "720h")
+	viper.SetDefault("partitions.interval", "1h")
+	viper.SetDefault("partitions.premake_months", 3)
+	viper.SetDefault("partitions.retention", "0s")
+	viper.SetDefault("partitions.archive", false)
+	viper.SetDefault("counters.reconcile_interval", "5m")
+	viper.SetDefault("counters.max_span", "24h")
+	viper.SetDefault("exports.page_size", 5000)
+	viper.SetDefault("streams.queue_size", 10000)
+	viper.SetDefault("streams.history_size", 500)
+	viper.SetDefault("streams.history_ttl", "1h")
+	viper.SetDefault("streams.max_connections", 1000)
+	viper.SetDefault("streams.max_connections_per_client", 5)
+	viper.SetDefault("streams.buffer_size", 64)
+	viper.SetDefault("streams.max_duration", "30m")
+	viper.SetDefault("streams.heartbeat_interval", "15s")
+	viper.SetDefault("alerts.interval", "1m")
+	viper.SetDefault("alerts.bucket", "5m")
+	viper.SetDefault("alerts.lateness", "1m")
+	viper.SetDefault("alerts.half_life", "6h")
+	viper.SetDefault("alerts.warmup_buckets", 24)
+	viper.SetDefault("alerts.spike_z", 4.0)
+	viper.SetDefault("alerts.drop_z", 3.0)
+	viper.SetDefault("alerts.min_clicks", 20)
+	viper.SetDefault("alerts.cooldown", "1h")
+	viper.SetDefault("alerts.webhook_timeout", "5s")
+	viper.SetDefault("conversions.click_id_param", "click_id")
+	viper.SetDefault("conversions.attribution_window", "720h")
+	viper.SetDefault("privacy.ip_mode", "truncate")
+	viper.SetDefault("privacy.salt_rotation", "24h")
+	viper.SetDefault("privacy.honor_dnt", true)
+	viper.SetDefault("privacy.purge_interval", "1h")
+	viper.SetDefault("privacy.purge_batch_size", 10000)
+	viper.SetDefault("webhooks.interval", "5s")
+	viper.SetDefault("webhooks.batch_size", 100)
+	viper.SetDefault("webhooks.timeout", "10s")
+	viper.SetDefault("webhooks.max_attempts", 10)
+	viper.SetDefault("webhooks.retry_base", "30s")
+	viper.SetDefault("webhooks.retry_max", "6h")
+	viper.SetDefault("outbox.relay_interval", "1s")
+	viper.SetDefault("outbox.batch_size", 500)
+	viper.SetDefault("outbox.redis_stream_max_len", 100000)
+	viper.SetDefault("outbox.nats_subject", "links")
+	viper.SetDefault("outbox.retention", "168h")
+	viper.SetDefault("audit.actor_header", "X-Actor")
+	viper.SetDefault("cache.base_ttl", "1h")
+	viper.SetDefault("cache.jitter", 0.1)
+	viper.SetDefault("cache.hot_threshold", 20)
+	viper.SetDefault("cache.hot_window", "1m")
+	viper.SetDefault("cache.hot_ttl", "6h")
+	viper.SetDefault("cache.max_ttl", "24h")
 
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
@@ -46,6 +398,28 @@ func LoadConfig(path string) (*Config, error) {
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("error unmarshalling config: %w", err)
 	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
 
 	return &cfg, nil
 }
+
+// validate rejects settings the jobs cannot work with, so they fail at startup instead of in the background.
+func (c *Config) validate() error {
+	if c.Visitors.Secret == "" || (strings.HasPrefix(c.Visitors.Secret, "{{") && strings.HasSuffix(c.Visitors.Secret, "}}")) {
+		return fmt.Errorf("visitors.secret must be set to a random value")
+	}
+	if err := validateHourSpan("rollups.max_span", c.Rollups.MaxSpan); err != nil {
+		return err
+	}
+	return validateHourSpan("counters.max_span", c.Counters.MaxSpan)
+}
+
+// validateHourSpan checks a span that is applied to hourly rollups, zero means no limit.
+func validateHourSpan(name string, span time.Duration) error {
+	if span < 0 || (span > 0 && (span < time.Hour || span%time.Hour != 0)) {
+		return fmt.Errorf("%s must be a whole number of hours, got %s", name, span)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateHourSpan(t *testing.T) {
+	tests := []struct {
+		span  time.Duration
+		valid bool
+	}{
+		{span: 0, valid: true},
+		{span: time.Hour, valid: true},
+		{span: 24 * time.Hour, valid: true},
+		{span: 30 * time.Minute, valid: false},
+		{span: 90 * time.Minute, valid: false},
+		{span: -time.Hour, valid: false},
+	}
+	for _, tt := range tests {
+		err := validateHourSpan("rollups.max_span", tt.span)
+		if tt.valid {
+			assert.NoError(t, err, tt.span.String())
+		} else {
+			assert.Error(t, err, tt.span.String())
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{
+		Visitors: VisitorsConfig{Secret: "s3cr3t"},
+		Rollups:  RollupsConfig{MaxSpan: 24 * time.Hour},
+		Counters: CountersConfig{MaxSpan: 24 * time.Hour},
+	}
+	assert.NoError(t, valid.validate())
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{name: "empty visitor secret", modify: func(c *Config) { c.Visitors.Secret = "" }},
+		{name: "visitor secret placeholder", modify: func(c *Config) { c.Visitors.Secret = "{{VISITOR_SECRET}}" }},
+		{name: "partial hour rollup span", modify: func(c *Config) { c.Rollups.MaxSpan = 90 * time.Minute }},
+		{name: "partial hour counter span", modify: func(c *Config) { c.Counters.MaxSpan = 30 * time.Minute }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.modify(&config)
+			assert.Error(t, config.validate())
+		})
+	}
+}
@@ -4,19 +4,93 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
 	"url-shortener/internal/config"
 
 	_ "github.com/lib/pq"
 )
 
+// minConnectBackoff keeps a zero or tiny database.connect_backoff from retrying in a busy loop.
+const minConnectBackoff = 100 * time.Millisecond
+
+// NewPostgresConnection connects to the primary, retrying with exponential backoff so the server
+// can start before the database is accepting connections.
 func NewPostgresConnection(config *config.DatabaseConfig) (*sql.DB, error) {
-	db, err := sql.Open("postgres", fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", config.Host, config.Port, config.User, config.Password, config.DBName))
+	db, err := sql.Open("postgres", postgresDSN(config))
 	if err != nil {
 		return nil, err
 	}
+	configurePool(db, config)
 
-	if err := db.PingContext(context.Background()); err != nil {
-		return nil, err
+	backoff := max(config.ConnectBackoff, minConnectBackoff)
+	for attempt := 0; ; attempt++ {
+		err = pingWithTimeout(db, config.ConnectTimeout)
+		if err == nil {
+			return db, nil
+		}
+		if attempt >= config.ConnectRetries {
+			db.Close()
+			return nil, err
+		}
+		log.Printf("Database not ready (attempt %d of %d): %v, retrying in %s", attempt+1, config.ConnectRetries+1, err, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+		if config.ConnectMaxBackoff > 0 && backoff > config.ConnectMaxBackoff {
+			backoff = config.ConnectMaxBackoff
+		}
+	}
+}
+
+func postgresDSN(config *config.DatabaseConfig) string {
+	params := []string{
+		"host=" + quoteDSNValue(config.Host),
+		"port=" + quoteDSNValue(config.Port),
+		"user=" + quoteDSNValue(config.User),
+		"password=" + quoteDSNValue(config.Password),
+		"dbname=" + quoteDSNValue(config.DBName),
+		"sslmode=" + quoteDSNValue(config.SSLMode),
+	}
+	if config.SSLRootCert != "" {
+		params = append(params, "sslrootcert="+quoteDSNValue(config.SSLRootCert))
+	}
+	if config.ApplicationName != "" {
+		params = append(params, "application_name="+quoteDSNValue(config.ApplicationName))
+	}
+	// connect_timeout is in whole seconds and 0 waits forever, so anything shorter than a second is rounded up.
+	if config.ConnectTimeout > 0 {
+		params = append(params, fmt.Sprintf("connect_timeout=%d", int(math.Ceil(config.ConnectTimeout.Seconds()))))
+	}
+	// lib/pq sends parameters it does not know about to the server as run-time settings.
+	if config.StatementTimeout > 0 {
+		params = append(params, fmt.Sprintf("statement_timeout=%d", config.StatementTimeout.Milliseconds()))
+	}
+	return strings.Join(params, " ")
+}
+
+// quoteDSNValue quotes values so passwords and paths containing spaces or quotes survive the key=value format.
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+func configurePool(db *sql.DB, config *config.DatabaseConfig) {
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+}
+
+func pingWithTimeout(db *sql.DB, timeout time.Duration) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
-	return db, nil
+	return db.PingContext(ctx)
 }
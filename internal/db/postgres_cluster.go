@@ -0,0 +1,186 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+	"url-shortener/internal/config"
+)
+
+// Replication lag is zero while the replica has replayed everything it received, otherwise the age of the last replayed transaction.
+const replicaLagQuery = `SELECT CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+								ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0) END`
+
+// PostgresCluster routes writes to the primary and lookups to healthy read replicas,
+// falling back to the primary when no replica is usable.
+type PostgresCluster struct {
+	primary              *sql.DB
+	replicas             []*replica
+	maxLag               time.Duration
+	readYourWritesWindow time.Duration
+	next                 atomic.Uint64
+
+	// recentWrites pins keys written by this instance to the primary until the replicas have caught up.
+	mu           sync.Mutex
+	recentWrites map[string]time.Time
+}
+
+type replica struct {
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+func NewPostgresCluster(primary *sql.DB, replicas []*sql.DB, config *config.DatabaseConfig) *PostgresCluster {
+	cluster := &PostgresCluster{
+		primary:              primary,
+		maxLag:               config.MaxReplicaLag,
+		readYourWritesWindow: config.ReadYourWritesWindow,
+		recentWrites:         map[string]time.Time{},
+	}
+	for _, db := range replicas {
+		cluster.replicas = append(cluster.replicas, &replica{db: db})
+	}
+	return cluster
+}
+
+// NewPostgresClusterFromConfig connects to the primary and every configured replica. Replicas share the
+// primary's pool settings, TLS and timeouts have to be set in their DSNs. Replicas that cannot be reached
+// yet are kept and picked up by the health checks once they come up.
+func NewPostgresClusterFromConfig(config *config.DatabaseConfig) (*PostgresCluster, error) {
+	primary, err := NewPostgresConnection(config)
+	if err != nil {
+		return nil, err
+	}
+	replicas := []*sql.DB{}
+	for i, dsn := range config.Replicas {
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			primary.Close()
+			return nil, err
+		}
+		configurePool(db, config)
+		if err := pingWithTimeout(db, config.ConnectTimeout); err != nil {
+			log.Printf("Read replica %d is not reachable yet: %v", i, err)
+		}
+		replicas = append(replicas, db)
+	}
+	return NewPostgresCluster(primary, replicas, config), nil
+}
+
+// Primary returns the primary connection pool.
+func (c *PostgresCluster) Primary() *sql.DB {
+	return c.primary
+}
+
+// Writer returns the primary and pins the given keys to it for the read-your-writes window.
+func (c *PostgresCluster) Writer(keys ...string) *sql.DB {
+	if c.readYourWritesWindow > 0 && len(c.replicas) > 0 {
+		until := time.Now().Add(c.readYourWritesWindow)
+		c.mu.Lock()
+		for _, key := range keys {
+			c.recentWrites[key] = until
+		}
+		c.mu.Unlock()
+	}
+	return c.primary
+}
+
+// Reader returns a healthy replica in round robin order, or the primary if none is healthy
+// or one of the keys was recently written by this instance.
+func (c *PostgresCluster) Reader(ctx context.Context, keys ...string) *sql.DB {
+	if len(c.replicas) == 0 || c.recentlyWritten(keys) {
+		return c.primary
+	}
+	start := c.next.Add(1)
+	for i := range c.replicas {
+		r := c.replicas[(start+uint64(i))%uint64(len(c.replicas))]
+		if r.healthy.Load() {
+			return r.db
+		}
+	}
+	return c.primary
+}
+
+func (c *PostgresCluster) recentlyWritten(keys []string) bool {
+	if len(keys) == 0 {
+		return false
+	}
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		until, ok := c.recentWrites[key]
+		if !ok {
+			continue
+		}
+		if now.Before(until) {
+			return true
+		}
+		delete(c.recentWrites, key)
+	}
+	return false
+}
+
+// StartHealthChecks checks every replica right away and then every interval until ctx is cancelled.
+func (c *PostgresCluster) StartHealthChecks(ctx context.Context, interval time.Duration) {
+	if len(c.replicas) == 0 {
+		return
+	}
+	c.checkReplicas(ctx)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.checkReplicas(ctx)
+				c.pruneRecentWrites()
+			}
+		}
+	}()
+}
+
+func (c *PostgresCluster) checkReplicas(ctx context.Context) {
+	for i, r := range c.replicas {
+		healthy := c.checkReplica(ctx, r.db)
+		if r.healthy.Swap(healthy) != healthy {
+			log.Printf("Read replica %d healthy: %t", i, healthy)
+		}
+	}
+}
+
+func (c *PostgresCluster) checkReplica(ctx context.Context, db *sql.DB) bool {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	var lagSeconds float64
+	if err := db.QueryRowContext(ctx, replicaLagQuery).Scan(&lagSeconds); err != nil {
+		return false
+	}
+	return c.maxLag <= 0 || time.Duration(lagSeconds*float64(time.Second)) <= c.maxLag
+}
+
+func (c *PostgresCluster) pruneRecentWrites() {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, until := range c.recentWrites {
+		if now.After(until) {
+			delete(c.recentWrites, key)
+		}
+	}
+}
+
+// Close closes the primary and every replica.
+func (c *PostgresCluster) Close() error {
+	errs := []error{c.primary.Close()}
+	for _, r := range c.replicas {
+		errs = append(errs, r.db.Close())
+	}
+	return errors.Join(errs...)
+}
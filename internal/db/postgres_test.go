@@ -0,0 +1,85 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteDSNValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "localhost", want: "localhost"},
+		{value: "p@ss=word", want: "p@ss=word"},
+		{value: "", want: "''"},
+		{value: "my secret", want: "'my secret'"},
+		{value: "it's", want: `'it\'s'`},
+		{value: `back\slash`, want: `'back\\slash'`},
+		{value: `a 'quoted' \ mix`, want: `'a \'quoted\' \\ mix'`},
+		{value: "/etc/ssl/My Certs/root.crt", want: "'/etc/ssl/My Certs/root.crt'"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, quoteDSNValue(tt.value), tt.value)
+	}
+}
+
+func TestPostgresDSN(t *testing.T) {
+	base := config.DatabaseConfig{Host: "db", Port: "5432", User: "app", Password: "secret", DBName: "urls", SSLMode: "disable"}
+	tests := []struct {
+		name   string
+		modify func(*config.DatabaseConfig)
+		want   string
+	}{
+		{
+			name:   "required settings only",
+			modify: func(*config.DatabaseConfig) {},
+			want:   "host=db port=5432 user=app password=secret dbname=urls sslmode=disable",
+		},
+		{
+			name: "quoted values",
+			modify: func(c *config.DatabaseConfig) {
+				c.Password = `it's a \secret`
+				c.SSLMode = "verify-full"
+				c.SSLRootCert = "/etc/ssl/My Certs/root.crt"
+				c.ApplicationName = "url shortener"
+			},
+			want: `host=db port=5432 user=app password='it\'s a \\secret' dbname=urls sslmode=verify-full ` +
+				`sslrootcert='/etc/ssl/My Certs/root.crt' application_name='url shortener'`,
+		},
+		{
+			name:   "empty password",
+			modify: func(c *config.DatabaseConfig) { c.Password = "" },
+			want:   "host=db port=5432 user=app password='' dbname=urls sslmode=disable",
+		},
+		{
+			name: "timeouts",
+			modify: func(c *config.DatabaseConfig) {
+				c.ConnectTimeout = 5 * time.Second
+				c.StatementTimeout = 1500 * time.Millisecond
+			},
+			want: "host=db port=5432 user=app password=secret dbname=urls sslmode=disable connect_timeout=5 statement_timeout=1500",
+		},
+		{
+			name:   "connect timeout below a second is rounded up",
+			modify: func(c *config.DatabaseConfig) { c.ConnectTimeout = 300 * time.Millisecond },
+			want:   "host=db port=5432 user=app password=secret dbname=urls sslmode=disable connect_timeout=1",
+		},
+		{
+			name:   "connect timeout rounded up to the next second",
+			modify: func(c *config.DatabaseConfig) { c.ConnectTimeout = 2500 * time.Millisecond },
+			want:   "host=db port=5432 user=app password=secret dbname=urls sslmode=disable connect_timeout=3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			databaseConfig := base
+			tt.modify(&databaseConfig)
+			assert.Equal(t, tt.want, postgresDSN(&databaseConfig))
+		})
+	}
+}
@@ -2,6 +2,10 @@ package db
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
 	"time"
 	"url-shortener/internal/config"
 
@@ -12,19 +16,119 @@ import (
 type RedisClient interface {
 	Get(ctx context.Context, key string) *redis.StringCmd
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd
 	Del(ctx context.Context, keys ...string) *redis.IntCmd
+	MGet(ctx context.Context, keys ...string) *redis.SliceCmd
+	Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd
+	Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
+	PFAdd(ctx context.Context, key string, els ...interface{}) *redis.IntCmd
+	PFCount(ctx context.Context, keys ...string) *redis.IntCmd
+	LRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
 }
 
 type redisClientImpl struct {
-	client *redis.Client
+	client redis.UniversalClient
 }
 
-func NewRedisClient(redisConfig *config.RedisConfig) RedisClient {
-	client := redis.NewClient(&redis.Options{
-		Addr: redisConfig.Host + ":" + redisConfig.Port,
-		DB:   0,
-	})
-	return &redisClientImpl{client: client}
+// NewRedisClient connects to a standalone server, a Sentinel managed master or a Cluster depending on redisConfig.Mode.
+func NewRedisClient(redisConfig *config.RedisConfig) (RedisClient, error) {
+	tlsConfig, err := newRedisTLSConfig(&redisConfig.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	var client redis.UniversalClient
+	switch redisConfig.Mode {
+	case "", "standalone":
+		client = redis.NewClient(&redis.Options{
+			Addr:         redisConfig.Host + ":" + redisConfig.Port,
+			Username:     redisConfig.Username,
+			Password:     redisConfig.Password,
+			DB:           redisConfig.DB,
+			TLSConfig:    tlsConfig,
+			MaxRetries:   redisConfig.MaxRetries,
+			DialTimeout:  redisConfig.DialTimeout,
+			ReadTimeout:  redisConfig.ReadTimeout,
+			WriteTimeout: redisConfig.WriteTimeout,
+			PoolSize:     redisConfig.PoolSize,
+			MinIdleConns: redisConfig.MinIdleConns,
+			PoolTimeout:  redisConfig.PoolTimeout,
+		})
+	case "sentinel":
+		if redisConfig.MasterName == "" || len(redisConfig.Addrs) == 0 {
+			return nil, fmt.Errorf("redis sentinel mode requires master_name and addrs")
+		}
+		client = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       redisConfig.MasterName,
+			SentinelAddrs:    redisConfig.Addrs,
+			SentinelUsername: redisConfig.SentinelUsername,
+			SentinelPassword: redisConfig.SentinelPassword,
+			Username:         redisConfig.Username,
+			Password:         redisConfig.Password,
+			DB:               redisConfig.DB,
+			TLSConfig:        tlsConfig,
+			MaxRetries:       redisConfig.MaxRetries,
+			DialTimeout:      redisConfig.DialTimeout,
+			ReadTimeout:      redisConfig.ReadTimeout,
+			WriteTimeout:     redisConfig.WriteTimeout,
+			PoolSize:         redisConfig.PoolSize,
+			MinIdleConns:     redisConfig.MinIdleConns,
+			PoolTimeout:      redisConfig.PoolTimeout,
+		})
+	case "cluster":
+		if len(redisConfig.Addrs) == 0 {
+			return nil, fmt.Errorf("redis cluster mode requires addrs")
+		}
+		if redisConfig.DB != 0 {
+			return nil, fmt.Errorf("redis cluster mode only supports db 0")
+		}
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        redisConfig.Addrs,
+			Username:     redisConfig.Username,
+			Password:     redisConfig.Password,
+			TLSConfig:    tlsConfig,
+			MaxRetries:   redisConfig.MaxRetries,
+			DialTimeout:  redisConfig.DialTimeout,
+			ReadTimeout:  redisConfig.ReadTimeout,
+			WriteTimeout: redisConfig.WriteTimeout,
+			PoolSize:     redisConfig.PoolSize,
+			MinIdleConns: redisConfig.MinIdleConns,
+			PoolTimeout:  redisConfig.PoolTimeout,
+		})
+	default:
+		return nil, fmt.Errorf("unknown redis mode %q", redisConfig.Mode)
+	}
+	return &redisClientImpl{client: client}, nil
+}
+
+func newRedisTLSConfig(tlsConfig *config.RedisTLSConfig) (*tls.Config, error) {
+	if !tlsConfig.Enabled {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         tlsConfig.ServerName,
+		InsecureSkipVerify: tlsConfig.InsecureSkipVerify,
+	}
+	if tlsConfig.CACertFile != "" {
+		caCert, err := os.ReadFile(tlsConfig.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading redis ca cert: %w", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in redis ca cert %s", tlsConfig.CACertFile)
+		}
+	}
+	if tlsConfig.CertFile != "" || tlsConfig.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(tlsConfig.CertFile, tlsConfig.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading redis client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
 }
 
 func (r *redisClientImpl) Get(ctx context.Context, key string) *redis.StringCmd {
@@ -35,6 +139,38 @@ func (r *redisClientImpl) Set(ctx context.Context, key string, value interface{}
 	return r.client.Set(ctx, key, value, expiration)
 }
 
+func (r *redisClientImpl) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd {
+	return r.client.SetNX(ctx, key, value, expiration)
+}
+
 func (r *redisClientImpl) Del(ctx context.Context, keys ...string) *redis.IntCmd {
 	return r.client.Del(ctx, keys...)
 }
+
+func (r *redisClientImpl) MGet(ctx context.Context, keys ...string) *redis.SliceCmd {
+	return r.client.MGet(ctx, keys...)
+}
+
+func (r *redisClientImpl) Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd {
+	return r.client.Expire(ctx, key, expiration)
+}
+
+func (r *redisClientImpl) Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	return r.client.Pipelined(ctx, fn)
+}
+
+func (r *redisClientImpl) PFAdd(ctx context.Context, key string, els ...interface{}) *redis.IntCmd {
+	return r.client.PFAdd(ctx, key, els...)
+}
+
+func (r *redisClientImpl) PFCount(ctx context.Context, keys ...string) *redis.IntCmd {
+	return r.client.PFCount(ctx, keys...)
+}
+
+func (r *redisClientImpl) LRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd {
+	return r.client.LRange(ctx, key, start, stop)
+}
+
+func (r *redisClientImpl) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return r.client.Subscribe(ctx, channels...)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"strings"
+
+	"url-shortener/internal/models"
+	"url-shortener/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxReferrerHostLength = 255
+	maxLanguageLength     = 35
+	maxQueryStringLength  = 2048
+	maxActorLength        = 255
+	maxRequestIDLength    = 128
+	requestIDHeader       = "X-Request-ID"
+)
+
+// accessLogFromRequest captures where a redirect came from. ShortPath and AccessedAt are filled in by the service.
+// With honorDNT, requests that opt out of tracking get no visitor ID and no query string. Their client IP is only
+// kept to look up the country and dropped before the click is stored.
+func accessLogFromRequest(ctx *gin.Context, fingerprinter *utils.VisitorFingerprinter, botClassifier *utils.BotClassifier, honorDNT bool) *models.AccessLog {
+	userAgent := utils.ParseUserAgent(ctx.Request.UserAgent(), botClassifier)
+	clientIP := ctx.ClientIP()
+	if honorDNT && doNotTrack(ctx) {
+		return &models.AccessLog{
+			ReferrerHost: referrerHost(ctx.Request.Referer()),
+			Browser:      userAgent.Browser,
+			OS:           userAgent.OS,
+			DeviceClass:  userAgent.DeviceClass,
+			ClientIP:     clientIP,
+			Language:     primaryLanguage(ctx.GetHeader("Accept-Language")),
+			IsBot:        userAgent.IsBot,
+			DoNotTrack:   true,
+		}
+	}
+	return &models.AccessLog{
+		ReferrerHost: referrerHost(ctx.Request.Referer()),
+		Browser:      userAgent.Browser,
+		OS:           userAgent.OS,
+		DeviceClass:  userAgent.DeviceClass,
+		// ClientIP only believes forwarding headers from the trusted proxies set on the router.
+		ClientIP:    clientIP,
+		Language:    primaryLanguage(ctx.GetHeader("Accept-Language")),
+		QueryString: truncate(ctx.Request.URL.RawQuery, maxQueryStringLength),
+		IsBot:       userAgent.IsBot,
+		VisitorID:   fingerprinter.Fingerprint(clientIP, ctx.Request.UserAgent()),
+	}
+}
+
+// auditActorFromRequest captures who changes a link, named by actorHeader. The request ID is taken from X-Request-ID,
+// or generated when the request has none, and echoed in the response so the change can be found in the audit log.
+func auditActorFromRequest(ctx *gin.Context, actorHeader string) *models.AuditActor {
+	requestID := truncate(ctx.GetHeader(requestIDHeader), maxRequestIDLength)
+	if requestID == "" {
+		requestID = newRequestID()
+	}
+	ctx.Header(requestIDHeader, requestID)
+	return &models.AuditActor{
+		Actor:     truncate(strings.TrimSpace(ctx.GetHeader(actorHeader)), maxActorLength),
+		SourceIP:  ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+		RequestID: requestID,
+	}
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	// crypto/rand does not fail on supported platforms, a zero ID would still be recorded.
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// doNotTrack reports whether the request carries DNT: 1 or the Global Privacy Control signal Sec-GPC: 1.
+func doNotTrack(ctx *gin.Context) bool {
+	return ctx.GetHeader("DNT") == "1" || ctx.GetHeader("Sec-GPC") == "1"
+}
+
+// referrerHost keeps only the host of the Referer header so full referring URLs, which may carry personal data, are not stored.
+func referrerHost(referrer string) string {
+	if referrer == "" {
+		return ""
+	}
+	parsed, err := url.Parse(referrer)
+	if err != nil {
+		return ""
+	}
+	return truncate(strings.ToLower(parsed.Hostname()), maxReferrerHostLength)
+}
+
+// primaryLanguage returns the first language tag of an Accept-Language header, e.g. "en-us" for "en-US,en;q=0.9".
+func primaryLanguage(acceptLanguage string) string {
+	tag, _, _ := strings.Cut(acceptLanguage, ",")
+	tag, _, _ = strings.Cut(tag, ";")
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "*" {
+		return ""
+	}
+	return truncate(tag, maxLanguageLength)
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return value[:length]
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	api "url-shortener/generated"
+	"url-shortener/internal/models"
+	"url-shortener/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	cacheWarmupService services.CacheWarmupService
+	accessLogger       services.AccessLogger
+}
+
+func NewAdminHandler(cacheWarmupService services.CacheWarmupService, accessLogger services.AccessLogger) *AdminHandler {
+	return &AdminHandler{cacheWarmupService: cacheWarmupService, accessLogger: accessLogger}
+}
+
+func (h *AdminHandler) StartCacheWarmup(ctx *gin.Context) {
+	// The body is optional, an empty request warms up every link with the defaults.
+	var req api.StartCacheWarmupJSONRequestBody
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request payload"})
+			return
+		}
+	}
+
+	if isNegative(req.TopN) || isNegative(req.BatchSize) || isNegative(req.RatePerSecond) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "topN, batchSize and ratePerSecond cannot be negative"})
+		return
+	}
+	opts := models.CacheWarmupOptions{
+		TopN:          valueOrZero(req.TopN),
+		BatchSize:     valueOrZero(req.BatchSize),
+		RatePerSecond: valueOrZero(req.RatePerSecond),
+	}
+	if req.WindowHours != nil {
+		if *req.WindowHours <= 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "windowHours must be positive"})
+			return
+		}
+		opts.Window = time.Duration(*req.WindowHours) * time.Hour
+	}
+
+	err := h.cacheWarmupService.Start(opts)
+	if errors.Is(err, services.ErrCacheWarmupInProgress) {
+		ctx.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusAccepted, toCacheWarmupStatus(h.cacheWarmupService.Status()))
+}
+
+func (h *AdminHandler) GetCacheWarmupStatus(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, toCacheWarmupStatus(h.cacheWarmupService.Status()))
+}
+
+func (h *AdminHandler) GetClickIngestionStats(ctx *gin.Context) {
+	stats := h.accessLogger.Stats()
+	queued, enqueued, dropped, flushed, failed := int(stats.Queued), int(stats.Enqueued), int(stats.Dropped), int(stats.Flushed), int(stats.Failed)
+	ctx.JSON(http.StatusOK, &api.ClickIngestionStats{
+		Queued:   &queued,
+		Enqueued: &enqueued,
+		Dropped:  &dropped,
+		Flushed:  &flushed,
+		Failed:   &failed,
+	})
+}
+
+func toCacheWarmupStatus(progress models.CacheWarmupProgress) *api.CacheWarmupStatus {
+	scanned := int(progress.Scanned)
+	loaded := int(progress.Loaded)
+	skipped := int(progress.Skipped)
+	status := &api.CacheWarmupStatus{
+		Running:    &progress.Running,
+		Scanned:    &scanned,
+		Loaded:     &loaded,
+		Skipped:    &skipped,
+		StartedAt:  progress.StartedAt,
+		FinishedAt: progress.FinishedAt,
+	}
+	if progress.Error != "" {
+		status.Error = &progress.Error
+	}
+	return status
+}
+
+func isNegative(value *int) bool {
+	return value != nil && *value < 0
+}
+
+func valueOrZero[T any](value *T) T {
+	if value == nil {
+		var zero T
+		return zero
+	}
+	return *value
+}
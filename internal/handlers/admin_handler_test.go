@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	api "url-shortener/generated"
+	"url-shortener/internal/models"
+	"url-shortener/internal/services"
+	mocks "url-shortener/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAdminHandler() (*mocks.CacheWarmupService, *mocks.AccessLogger, *AdminHandler) {
+	mockCacheWarmupService := mocks.CacheWarmupService{}
+	mockAccessLogger := mocks.AccessLogger{}
+	return &mockCacheWarmupService, &mockAccessLogger, NewAdminHandler(&mockCacheWarmupService, &mockAccessLogger)
+}
+
+func TestStartCacheWarmup_Success(t *testing.T) {
+	mockCacheWarmupService, _, handler := setupAdminHandler()
+	startedAt := time.Now()
+	mockCacheWarmupService.On("Start", models.CacheWarmupOptions{TopN: 100, Window: 6 * time.Hour}).Return(nil).Once()
+	mockCacheWarmupService.On("Status").Return(models.CacheWarmupProgress{Running: true, StartedAt: &startedAt}).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/admin/cache/warmup", bytes.NewBufferString(`{"topN": 100, "windowHours": 6}`))
+
+	handler.StartCacheWarmup(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var status api.CacheWarmupStatus
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.True(t, *status.Running)
+	mockCacheWarmupService.AssertExpectations(t)
+}
+
+func TestStartCacheWarmup_EmptyBody(t *testing.T) {
+	mockCacheWarmupService, _, handler := setupAdminHandler()
+	mockCacheWarmupService.On("Start", models.CacheWarmupOptions{}).Return(nil).Once()
+	mockCacheWarmupService.On("Status").Return(models.CacheWarmupProgress{Running: true}).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/admin/cache/warmup", nil)
+
+	handler.StartCacheWarmup(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	mockCacheWarmupService.AssertExpectations(t)
+}
+
+func TestStartCacheWarmup_InvalidOptions(t *testing.T) {
+	_, _, handler := setupAdminHandler()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/admin/cache/warmup", bytes.NewBufferString(`{"batchSize": -1}`))
+
+	handler.StartCacheWarmup(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestStartCacheWarmup_AlreadyRunning(t *testing.T) {
+	mockCacheWarmupService, _, handler := setupAdminHandler()
+	mockCacheWarmupService.On("Start", models.CacheWarmupOptions{}).Return(services.ErrCacheWarmupInProgress).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/admin/cache/warmup", bytes.NewBufferString(`{}`))
+
+	handler.StartCacheWarmup(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockCacheWarmupService.AssertExpectations(t)
+}
+
+func TestGetCacheWarmupStatus(t *testing.T) {
+	mockCacheWarmupService, _, handler := setupAdminHandler()
+	mockCacheWarmupService.On("Status").Return(models.CacheWarmupProgress{Scanned: 10, Loaded: 8, Skipped: 2}).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/admin/cache/warmup", nil)
+
+	handler.GetCacheWarmupStatus(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var status api.CacheWarmupStatus
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(t, 8, *status.Loaded)
+	mockCacheWarmupService.AssertExpectations(t)
+}
+
+func TestGetClickIngestionStats(t *testing.T) {
+	_, mockAccessLogger, handler := setupAdminHandler()
+	mockAccessLogger.On("Stats").Return(models.AccessLogPipelineStats{Enqueued: 10, Dropped: 2, Flushed: 8}).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/admin/clicks/stats", nil)
+
+	handler.GetClickIngestionStats(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var stats api.ClickIngestionStats
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, 2, *stats.Dropped)
+	assert.Equal(t, 8, *stats.Flushed)
+	mockAccessLogger.AssertExpectations(t)
+}
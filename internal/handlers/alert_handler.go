@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	api "url-shortener/generated"
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AlertHandler struct {
+	alertSettingsService services.AlertSettingsService
+}
+
+func NewAlertHandler(alertSettingsService services.AlertSettingsService) *AlertHandler {
+	return &AlertHandler{alertSettingsService: alertSettingsService}
+}
+
+func (h *AlertHandler) GetShortUrlAlertSettings(ctx *gin.Context, shortPath string) {
+	settings, err := h.alertSettingsService.GetAlertSettings(ctx, shortPath)
+	if errors.Is(err, repositories.ErrShortURLNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Short URL not found"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, toAlertSettings(settings))
+}
+
+func (h *AlertHandler) SetShortUrlAlertSettings(ctx *gin.Context, shortPath string) {
+	var req api.SetShortUrlAlertSettingsJSONRequestBody
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request payload"})
+		return
+	}
+	settings := &models.AlertSettings{
+		ShortPath: shortPath,
+		SpikeZ:    req.SpikeZ,
+		DropZ:     req.DropZ,
+		MinClicks: req.MinClicks,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+	}
+	err := h.alertSettingsService.SetAlertSettings(ctx, settings)
+	if errors.Is(err, services.ErrInvalidAlertSettings) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if errors.Is(err, repositories.ErrShortURLNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Short URL not found"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	h.GetShortUrlAlertSettings(ctx, shortPath)
+}
+
+func toAlertSettings(settings *models.AlertSettings) *api.AlertSettings {
+	return &api.AlertSettings{
+		SpikeZ:    settings.SpikeZ,
+		DropZ:     settings.DropZ,
+		MinClicks: settings.MinClicks,
+		Enabled:   &settings.Enabled,
+	}
+}
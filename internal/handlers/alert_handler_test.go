@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/services"
+	mocks "url-shortener/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupAlertHandler() (*mocks.AlertSettingsService, *AlertHandler) {
+	mockAlertSettingsService := mocks.AlertSettingsService{}
+	return &mockAlertSettingsService, NewAlertHandler(&mockAlertSettingsService)
+}
+
+func TestGetShortUrlAlertSettings_Success(t *testing.T) {
+	mockAlertSettingsService, handler := setupAlertHandler()
+	spikeZ, dropZ, minClicks := 4.0, 3.0, 20
+	mockAlertSettingsService.On("GetAlertSettings", mock.Anything, "abc").
+		Return(&models.AlertSettings{ShortPath: "abc", SpikeZ: &spikeZ, DropZ: &dropZ, MinClicks: &minClicks, Enabled: true}, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/urls/abc/alerts", nil)
+
+	handler.GetShortUrlAlertSettings(c, "abc")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"spikeZ":4,"dropZ":3,"minClicks":20,"enabled":true}`, w.Body.String())
+	mockAlertSettingsService.AssertExpectations(t)
+}
+
+func TestGetShortUrlAlertSettings_NotFound(t *testing.T) {
+	mockAlertSettingsService, handler := setupAlertHandler()
+	mockAlertSettingsService.On("GetAlertSettings", mock.Anything, "missing").Return(nil, repositories.ErrShortURLNotFound).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/urls/missing/alerts", nil)
+
+	handler.GetShortUrlAlertSettings(c, "missing")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSetShortUrlAlertSettings_Success(t *testing.T) {
+	mockAlertSettingsService, handler := setupAlertHandler()
+	spikeZ, dropZ, minClicks := 6.0, 3.0, 20
+	mockAlertSettingsService.On("SetAlertSettings", mock.Anything, &models.AlertSettings{ShortPath: "abc", SpikeZ: &spikeZ, Enabled: true}).Return(nil).Once()
+	mockAlertSettingsService.On("GetAlertSettings", mock.Anything, "abc").
+		Return(&models.AlertSettings{ShortPath: "abc", SpikeZ: &spikeZ, DropZ: &dropZ, MinClicks: &minClicks, Enabled: true}, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPut, "/urls/abc/alerts", strings.NewReader(`{"spikeZ":6}`))
+
+	handler.SetShortUrlAlertSettings(c, "abc")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"spikeZ":6,"dropZ":3,"minClicks":20,"enabled":true}`, w.Body.String())
+	mockAlertSettingsService.AssertExpectations(t)
+}
+
+func TestSetShortUrlAlertSettings_Invalid(t *testing.T) {
+	mockAlertSettingsService, handler := setupAlertHandler()
+	mockAlertSettingsService.On("SetAlertSettings", mock.Anything, mock.Anything).Return(services.ErrInvalidAlertSettings).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPut, "/urls/abc/alerts", strings.NewReader(`{"spikeZ":-1,"enabled":false}`))
+
+	handler.SetShortUrlAlertSettings(c, "abc")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockAlertSettingsService.AssertNotCalled(t, "GetAlertSettings", mock.Anything, mock.Anything)
+}
+
+func TestSetShortUrlAlertSettings_InvalidPayload(t *testing.T) {
+	mockAlertSettingsService, handler := setupAlertHandler()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPut, "/urls/abc/alerts", strings.NewReader(`{"spikeZ":"high"}`))
+
+	handler.SetShortUrlAlertSettings(c, "abc")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockAlertSettingsService.AssertNotCalled(t, "SetAlertSettings", mock.Anything, mock.Anything)
+}
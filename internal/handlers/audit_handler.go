@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	api "url-shortener/generated"
+	"url-shortener/internal/models"
+	"url-shortener/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditLogService services.AuditLogService
+}
+
+func NewAuditHandler(auditLogService services.AuditLogService) *AuditHandler {
+	return &AuditHandler{auditLogService: auditLogService}
+}
+
+func (h *AuditHandler) ListAuditEntries(ctx *gin.Context, params api.ListAuditEntriesParams) {
+	entries, err := h.auditLogService.ListEntries(ctx, models.AuditLogFilter{
+		Actor:     valueOrZero(params.Actor),
+		ShortPath: valueOrZero(params.ShortPath),
+		From:      params.From,
+		To:        params.To,
+		Before:    valueOrZero(params.Before),
+		Limit:     valueOrZero(params.Limit),
+	})
+	if errors.Is(err, services.ErrInvalidAuditRange) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	response := make([]*api.AuditEntry, 0, len(entries))
+	for i := range entries {
+		response = append(response, toAuditEntry(&entries[i]))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+func toAuditEntry(entry *models.AuditEntry) *api.AuditEntry {
+	action := api.AuditEntryAction(entry.Action)
+	createdAt := entry.CreatedAt.UTC()
+	response := &api.AuditEntry{
+		Id:        &entry.ID,
+		Action:    &action,
+		ShortPath: &entry.ShortPath,
+		Actor:     &entry.Actor,
+		Before:    jsonObject(entry.Before),
+		After:     jsonObject(entry.After),
+		Changes:   jsonObject(entry.Changes),
+		CreatedAt: &createdAt,
+	}
+	if entry.SourceIP != "" {
+		response.SourceIp = &entry.SourceIP
+	}
+	if entry.UserAgent != "" {
+		response.UserAgent = &entry.UserAgent
+	}
+	if entry.RequestID != "" {
+		response.RequestId = &entry.RequestID
+	}
+	return response
+}
+
+// jsonObject decodes a stored JSON document, nil when there is none.
+func jsonObject(raw json.RawMessage) *map[string]interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(raw, &object); err != nil || object == nil {
+		return nil
+	}
+	return &object
+}
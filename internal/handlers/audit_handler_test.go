@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	api "url-shortener/generated"
+	"url-shortener/internal/models"
+	"url-shortener/internal/services"
+	mocks "url-shortener/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListAuditEntries(t *testing.T) {
+	mockAuditLogService := &mocks.AuditLogService{}
+	handler := NewAuditHandler(mockAuditLogService)
+	from := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	actor, shortPath, before, limit := "alice", "abc", int64(40), 2
+	mockAuditLogService.On("ListEntries", mock.Anything, models.AuditLogFilter{Actor: actor, ShortPath: shortPath, From: &from, Before: before, Limit: limit}).
+		Return([]models.AuditEntry{{
+			ID: 39, Action: models.AuditActionUpdate, ShortPath: "abc", Actor: "alice", RequestID: "req-1",
+			Before:    json.RawMessage(`{"originalUrl":"https://old.example.com","expiry":null}`),
+			After:     json.RawMessage(`{"originalUrl":"https://example.com","expiry":null}`),
+			Changes:   json.RawMessage(`{"originalUrl":{"from":"https://old.example.com","to":"https://example.com"}}`),
+			CreatedAt: time.Date(2026, 5, 2, 9, 30, 0, 0, time.UTC),
+		}}, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/audit", nil)
+
+	handler.ListAuditEntries(c, api.ListAuditEntriesParams{Actor: &actor, ShortPath: &shortPath, From: &from, Before: &before, Limit: &limit})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"id":39,"action":"update","shortPath":"abc","actor":"alice","requestId":"req-1",
+		"before":{"originalUrl":"https://old.example.com","expiry":null},"after":{"originalUrl":"https://example.com","expiry":null},
+		"changes":{"originalUrl":{"from":"https://old.example.com","to":"https://example.com"}},"createdAt":"2026-05-02T09:30:00Z"}]`, w.Body.String())
+	mockAuditLogService.AssertExpectations(t)
+}
+
+func TestListAuditEntries_InvalidRange(t *testing.T) {
+	mockAuditLogService := &mocks.AuditLogService{}
+	handler := NewAuditHandler(mockAuditLogService)
+	mockAuditLogService.On("ListEntries", mock.Anything, mock.Anything).Return(nil, services.ErrInvalidAuditRange).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/audit", nil)
+
+	handler.ListAuditEntries(c, api.ListAuditEntriesParams{})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockAuditLogService.AssertExpectations(t)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	api "url-shortener/generated"
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	"url-shortener/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// clickStreamRetry is how long browsers wait before reconnecting a stream that ended.
+	clickStreamRetry            = 3 * time.Second
+	defaultClickStreamHeartbeat = 15 * time.Second
+)
+
+type ClickStreamHandler struct {
+	urlService   services.URLService
+	clickStreams services.ClickStreamService
+	config       config.StreamsConfig
+}
+
+func NewClickStreamHandler(urlService services.URLService, clickStreams services.ClickStreamService, streamsConfig config.StreamsConfig) *ClickStreamHandler {
+	if streamsConfig.HeartbeatInterval <= 0 {
+		streamsConfig.HeartbeatInterval = defaultClickStreamHeartbeat
+	}
+	return &ClickStreamHandler{urlService: urlService, clickStreams: clickStreams, config: streamsConfig}
+}
+
+func (h *ClickStreamHandler) StreamShortUrlEvents(ctx *gin.Context, shortPath string, params api.StreamShortUrlEventsParams) {
+	url, err := h.urlService.GetURLDetails(ctx, shortPath)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	if url == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Short URL not found"})
+		return
+	}
+	h.stream(ctx, services.LinkClickStream(shortPath), valueOrZero(params.IncludeBots), valueOrZero(params.LastEventID))
+}
+
+func (h *ClickStreamHandler) StreamOwnerEvents(ctx *gin.Context, owner string, params api.StreamOwnerEventsParams) {
+	h.stream(ctx, services.OwnerClickStream(owner), valueOrZero(params.IncludeBots), valueOrZero(params.LastEventID))
+}
+
+// stream sends the events of stream until the client goes away, the subscription ends or the connection has been
+// open for the maximum duration. Clients reconnect with the ID of the last event they received in all three cases.
+func (h *ClickStreamHandler) stream(ctx *gin.Context, stream string, includeBots bool, lastEventID string) {
+	subscription, err := h.clickStreams.Watch(ctx, stream, ctx.ClientIP(), lastEventID)
+	if errors.Is(err, services.ErrTooManyClickStreams) {
+		ctx.JSON(http.StatusTooManyRequests, gin.H{"message": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrClickStreamsClosed) {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	defer subscription.Close()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	// Stops nginx from buffering the stream.
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	if _, err := fmt.Fprintf(ctx.Writer, "retry: %d\n\n", clickStreamRetry.Milliseconds()); err != nil {
+		return
+	}
+	replayed := make(map[string]struct{}, len(subscription.Replay))
+	for _, event := range subscription.Replay {
+		replayed[event.ID] = struct{}{}
+		if (includeBots || !event.IsBot) && writeClickEvent(ctx, event) != nil {
+			return
+		}
+	}
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.config.HeartbeatInterval)
+	defer heartbeat.Stop()
+	var deadline <-chan time.Time
+	if h.config.MaxDuration > 0 {
+		timer := time.NewTimer(h.config.MaxDuration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	for {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return
+			}
+			if _, ok := replayed[event.ID]; ok || (!includeBots && event.IsBot) {
+				continue
+			}
+			if writeClickEvent(ctx, event) != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(ctx.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case <-deadline:
+			return
+		case <-ctx.Request.Context().Done():
+			return
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+func writeClickEvent(ctx *gin.Context, event models.ClickStreamEvent) error {
+	data, err := json.Marshal(toClickStreamEvent(event))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(ctx.Writer, "id: %s\nevent: click\ndata: %s\n\n", event.ID, data)
+	return err
+}
+
+func toClickStreamEvent(event models.ClickStreamEvent) *api.ClickStreamEvent {
+	accessedAt := event.AccessedAt.UTC()
+	return &api.ClickStreamEvent{
+		Id:           &event.ID,
+		ShortPath:    &event.ShortPath,
+		AccessedAt:   &accessedAt,
+		ReferrerHost: &event.ReferrerHost,
+		Browser:      &event.Browser,
+		Os:           &event.OS,
+		DeviceClass:  &event.DeviceClass,
+		Country:      &event.Country,
+		Region:       &event.Region,
+		City:         &event.City,
+		IsBot:        &event.IsBot,
+	}
+}
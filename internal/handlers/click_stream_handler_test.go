@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	api "url-shortener/generated"
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	"url-shortener/internal/services"
+	mocks "url-shortener/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupClickStreamHandler() (*mocks.URLService, *mocks.ClickStreamService, *ClickStreamHandler) {
+	mockURLService := mocks.URLService{}
+	mockClickStreamService := mocks.ClickStreamService{}
+	handler := NewClickStreamHandler(&mockURLService, &mockClickStreamService, config.StreamsConfig{HeartbeatInterval: time.Hour})
+	return &mockURLService, &mockClickStreamService, handler
+}
+
+// endedSubscription delivers events and then ends, as when the subscriber fell behind.
+func endedSubscription(replay []models.ClickStreamEvent, events ...models.ClickStreamEvent) *models.ClickStreamSubscription {
+	ch := make(chan models.ClickStreamEvent, len(events))
+	for _, event := range events {
+		ch <- event
+	}
+	close(ch)
+	return &models.ClickStreamSubscription{Replay: replay, Events: ch, Close: func() {}}
+}
+
+func TestStreamShortUrlEvents_Success(t *testing.T) {
+	mockURLService, mockClickStreamService, handler := setupClickStreamHandler()
+	mockURLService.On("GetURLDetails", mock.Anything, "shortPath").Return(&models.URL{ShortPath: "shortPath"}, nil).Once()
+	subscription := endedSubscription(
+		[]models.ClickStreamEvent{{ID: "1", ShortPath: "shortPath"}},
+		models.ClickStreamEvent{ID: "1", ShortPath: "shortPath"},
+		models.ClickStreamEvent{ID: "2", ShortPath: "shortPath", IsBot: true},
+		models.ClickStreamEvent{ID: "3", ShortPath: "shortPath", Browser: "Firefox"},
+	)
+	mockClickStreamService.On("Watch", mock.Anything, services.LinkClickStream("shortPath"), mock.Anything, "0").Return(subscription, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/urls/shortPath/events", nil)
+	lastEventID := "0"
+
+	handler.StreamShortUrlEvents(c, "shortPath", api.StreamShortUrlEventsParams{LastEventID: &lastEventID})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.True(t, strings.HasPrefix(body, "retry: 3000\n\n"))
+	assert.Equal(t, 1, strings.Count(body, "id: 1\nevent: click\n"))
+	assert.NotContains(t, body, "id: 2\n")
+	assert.Contains(t, body, "id: 3\nevent: click\ndata: {")
+	assert.Contains(t, body, `"browser":"Firefox"`)
+	mockClickStreamService.AssertExpectations(t)
+}
+
+func TestStreamShortUrlEvents_NotFound(t *testing.T) {
+	mockURLService, mockClickStreamService, handler := setupClickStreamHandler()
+	mockURLService.On("GetURLDetails", mock.Anything, "missing").Return(nil, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/urls/missing/events", nil)
+
+	handler.StreamShortUrlEvents(c, "missing", api.StreamShortUrlEventsParams{})
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockClickStreamService.AssertNotCalled(t, "Watch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStreamOwnerEvents_IncludeBots(t *testing.T) {
+	_, mockClickStreamService, handler := setupClickStreamHandler()
+	subscription := endedSubscription(nil, models.ClickStreamEvent{ID: "1", IsBot: true})
+	mockClickStreamService.On("Watch", mock.Anything, services.OwnerClickStream("alice"), mock.Anything, "").Return(subscription, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/owners/alice/events", nil)
+	includeBots := true
+
+	handler.StreamOwnerEvents(c, "alice", api.StreamOwnerEventsParams{IncludeBots: &includeBots})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"isBot":true`)
+}
+
+func TestStreamOwnerEvents_TooManyStreams(t *testing.T) {
+	_, mockClickStreamService, handler := setupClickStreamHandler()
+	mockClickStreamService.On("Watch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrTooManyClickStreams).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/owners/alice/events", nil)
+
+	handler.StreamOwnerEvents(c, "alice", api.StreamOwnerEventsParams{})
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	api "url-shortener/generated"
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConversionHandler struct {
+	conversionService services.ConversionService
+}
+
+func NewConversionHandler(conversionService services.ConversionService) *ConversionHandler {
+	return &ConversionHandler{conversionService: conversionService}
+}
+
+func (h *ConversionHandler) RecordConversion(ctx *gin.Context) {
+	var req api.ConversionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request payload"})
+		return
+	}
+	conversion := &models.Conversion{
+		ClickID:       req.ClickId,
+		Type:          req.Type,
+		Value:         valueOrZero(req.Value),
+		TransactionID: valueOrZero(req.TransactionId),
+	}
+	created, err := h.conversionService.RecordConversion(ctx, conversion)
+	if errors.Is(err, services.ErrInvalidConversion) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if errors.Is(err, repositories.ErrClickNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Click not found"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+	ctx.JSON(status, toConversion(conversion))
+}
+
+func toConversion(conversion *models.Conversion) *api.Conversion {
+	convertedAt := conversion.ConvertedAt.UTC()
+	response := &api.Conversion{
+		ClickId:     &conversion.ClickID,
+		ShortPath:   &conversion.ShortPath,
+		Type:        &conversion.Type,
+		Value:       &conversion.Value,
+		ConvertedAt: &convertedAt,
+	}
+	if conversion.TransactionID != "" {
+		response.TransactionId = &conversion.TransactionID
+	}
+	return response
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	mocks "url-shortener/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupConversionHandler() (*mocks.ConversionService, *ConversionHandler) {
+	mockConversionService := mocks.ConversionService{}
+	return &mockConversionService, NewConversionHandler(&mockConversionService)
+}
+
+func TestRecordConversion_Created(t *testing.T) {
+	mockConversionService, handler := setupConversionHandler()
+	convertedAt := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	mockConversionService.On("RecordConversion", mock.Anything, &models.Conversion{ClickID: "clk_123", Type: "purchase", Value: 19.5, TransactionID: "order-7"}).
+		Run(func(args mock.Arguments) {
+			conversion := args.Get(1).(*models.Conversion)
+			conversion.ShortPath = "abc"
+			conversion.ConvertedAt = convertedAt
+		}).Return(true, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/conversions", strings.NewReader(`{"clickId":"clk_123","type":"purchase","value":19.5,"transactionId":"order-7"}`))
+
+	handler.RecordConversion(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"clickId":"clk_123","shortPath":"abc","type":"purchase","value":19.5,"transactionId":"order-7","convertedAt":"2025-06-12T10:00:00Z"}`, w.Body.String())
+	mockConversionService.AssertExpectations(t)
+}
+
+func TestRecordConversion_Duplicate(t *testing.T) {
+	mockConversionService, handler := setupConversionHandler()
+	mockConversionService.On("RecordConversion", mock.Anything, mock.Anything).Return(false, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/conversions", strings.NewReader(`{"clickId":"clk_123","type":"purchase","transactionId":"order-7"}`))
+
+	handler.RecordConversion(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRecordConversion_UnknownClick(t *testing.T) {
+	mockConversionService, handler := setupConversionHandler()
+	mockConversionService.On("RecordConversion", mock.Anything, mock.Anything).Return(false, repositories.ErrClickNotFound).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/conversions", strings.NewReader(`{"clickId":"missing","type":"signup"}`))
+
+	handler.RecordConversion(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRecordConversion_InvalidPayload(t *testing.T) {
+	mockConversionService, handler := setupConversionHandler()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/conversions", strings.NewReader(`{"clickId":"clk_123","value":"a lot"}`))
+
+	handler.RecordConversion(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockConversionService.AssertNotCalled(t, "RecordConversion", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	api "url-shortener/generated"
+	"url-shortener/internal/models"
+	"url-shortener/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	clickExportService services.ClickExportService
+}
+
+func NewExportHandler(clickExportService services.ClickExportService) *ExportHandler {
+	return &ExportHandler{clickExportService: clickExportService}
+}
+
+func (h *ExportHandler) ExportClicks(ctx *gin.Context, params api.ExportClicksParams) {
+	format := services.ExportFormatCSV
+	if params.Format != nil {
+		format = string(*params.Format)
+	}
+	contentType, extension, err := services.ExportContentType(format)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	filter := models.ClickEventFilter{ShortPath: valueOrZero(params.ShortPath), Owner: valueOrZero(params.Owner)}
+	if params.From != nil {
+		filter.From = *params.From
+	}
+	if params.To != nil {
+		filter.To = *params.To
+	}
+
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", `attachment; filename="clicks.`+extension+`"`)
+	ctx.Status(http.StatusOK)
+	_, err = h.clickExportService.Export(ctx, filter, format, ctx.Writer)
+	if err == nil {
+		return
+	}
+	if !ctx.Writer.Written() {
+		ctx.Header("Content-Type", "")
+		ctx.Header("Content-Disposition", "")
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidExportRange) {
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{"message": err.Error()})
+		return
+	}
+	// The status and part of the file are already sent, breaking the connection tells the client the file is
+	// incomplete instead of letting a truncated export look finished.
+	log.Printf("Click export failed after the response started: %v", err)
+	panic(http.ErrAbortHandler)
+}
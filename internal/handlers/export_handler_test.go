@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	api "url-shortener/generated"
+	"url-shortener/internal/models"
+	"url-shortener/internal/services"
+	mocks "url-shortener/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupExportHandler() (*mocks.ClickExportService, *ExportHandler) {
+	mockClickExportService := mocks.ClickExportService{}
+	return &mockClickExportService, NewExportHandler(&mockClickExportService)
+}
+
+func TestExportClicks_Success(t *testing.T) {
+	mockClickExportService, handler := setupExportHandler()
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	owner := "alice"
+	format := api.ExportClicksParamsFormat("jsonl")
+	mockClickExportService.On("Export", mock.Anything, models.ClickEventFilter{Owner: owner, From: from}, services.ExportFormatJSONL, mock.Anything).
+		Run(func(args mock.Arguments) {
+			args.Get(3).(gin.ResponseWriter).WriteString(`{"id":1}` + "\n")
+		}).Return(1, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/exports/clicks", nil)
+
+	handler.ExportClicks(c, api.ExportClicksParams{Format: &format, Owner: &owner, From: &from})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="clicks.jsonl"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, `{"id":1}`+"\n", w.Body.String())
+	mockClickExportService.AssertExpectations(t)
+}
+
+func TestExportClicks_InvalidFormat(t *testing.T) {
+	mockClickExportService, handler := setupExportHandler()
+	format := api.ExportClicksParamsFormat("xml")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/exports/clicks", nil)
+
+	handler.ExportClicks(c, api.ExportClicksParams{Format: &format})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockClickExportService.AssertNotCalled(t, "Export")
+}
+
+func TestExportClicks_ErrorBeforeFirstRow(t *testing.T) {
+	mockClickExportService, handler := setupExportHandler()
+	mockClickExportService.On("Export", mock.Anything, mock.Anything, services.ExportFormatCSV, mock.Anything).Return(0, services.ErrInvalidExportRange).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/exports/clicks", nil)
+
+	handler.ExportClicks(c, api.ExportClicksParams{})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+}
+
+func TestExportClicks_ErrorAfterFirstRowAbortsResponse(t *testing.T) {
+	mockClickExportService, handler := setupExportHandler()
+	mockClickExportService.On("Export", mock.Anything, mock.Anything, services.ExportFormatCSV, mock.Anything).
+		Run(func(args mock.Arguments) {
+			args.Get(3).(gin.ResponseWriter).WriteString("id\n")
+		}).Return(0, assert.AnError).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/exports/clicks", nil)
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { handler.ExportClicks(c, api.ExportClicksParams{}) })
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	api "url-shortener/generated"
+)
+
+// Server combines the handlers into a single api.ServerInterface.
+type Server struct {
+	*URLHandler
+	*AdminHandler
+	*ExportHandler
+	*ClickStreamHandler
+	*AlertHandler
+	*ConversionHandler
+	*WebhookHandler
+	*AuditHandler
+}
+
+var _ api.ServerInterface = (*Server)(nil)
+
+func NewServer(urlHandler *URLHandler, adminHandler *AdminHandler, exportHandler *ExportHandler, clickStreamHandler *ClickStreamHandler, alertHandler *AlertHandler, conversionHandler *ConversionHandler, webhookHandler *WebhookHandler, auditHandler *AuditHandler) *Server {
+	return &Server{URLHandler: urlHandler, AdminHandler: adminHandler, ExportHandler: exportHandler, ClickStreamHandler: clickStreamHandler, AlertHandler: alertHandler, ConversionHandler: conversionHandler, WebhookHandler: webhookHandler, AuditHandler: auditHandler}
+}
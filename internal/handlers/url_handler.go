@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	api "url-shortener/generated"
+	"url-shortener/internal/models"
 	"url-shortener/internal/repositories"
 	"url-shortener/internal/services"
 	"url-shortener/internal/utils"
@@ -16,11 +19,17 @@ type URLHandler struct {
 	service        services.URLService
 	urlStatService services.URLStatsService
 	timeProvider   utils.TimeProvider
+	fingerprinter  *utils.VisitorFingerprinter
+	botClassifier  *utils.BotClassifier
+	// honorDNT leaves out identifying fields of clicks sent with DNT or Sec-GPC.
+	honorDNT bool
+	// actorHeader names who creates, updates or deletes a link in the audit log.
+	actorHeader string
 	api.ServerInterface
 }
 
-func NewURLHandler(service services.URLService, urlStatService services.URLStatsService, timeProvider utils.TimeProvider) *URLHandler {
-	return &URLHandler{service: service, urlStatService: urlStatService, timeProvider: timeProvider}
+func NewURLHandler(service services.URLService, urlStatService services.URLStatsService, timeProvider utils.TimeProvider, fingerprinter *utils.VisitorFingerprinter, botClassifier *utils.BotClassifier, honorDNT bool, actorHeader string) *URLHandler {
+	return &URLHandler{service: service, urlStatService: urlStatService, timeProvider: timeProvider, fingerprinter: fingerprinter, botClassifier: botClassifier, honorDNT: honorDNT, actorHeader: actorHeader}
 }
 
 func (h *URLHandler) CreateShortUrl(ctx *gin.Context) {
@@ -40,18 +49,14 @@ func (h *URLHandler) CreateShortUrl(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Expiry date cannot be in the past"})
 		return
 	}
-	shortPath, err := h.service.CreateShortURL(ctx, req.OriginalUrl, req.Expiry)
+	shortPath, err := h.service.CreateShortURL(ctx, req.OriginalUrl, req.Expiry, auditActorFromRequest(ctx, h.actorHeader))
 	if err != nil {
 		log.Print(err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
-	scheme := "http"
-	if ctx.Request.TLS != nil {
-		scheme = "https"
-	}
-	shortUrl := scheme + "://" + ctx.Request.Host + "/" + shortPath
+	shortUrl := shortURL(ctx, shortPath)
 
 	response := &api.ShortenedUrlDetails{
 		OriginalUrl: &req.OriginalUrl,
@@ -62,7 +67,7 @@ func (h *URLHandler) CreateShortUrl(ctx *gin.Context) {
 }
 
 func (h *URLHandler) RedirectToOriginalUrl(ctx *gin.Context, shortPath string) {
-	longURL, err := h.service.GetLongURL(ctx, shortPath)
+	longURL, err := h.service.GetLongURL(ctx, shortPath, accessLogFromRequest(ctx, h.fingerprinter, h.botClassifier, h.honorDNT))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -77,7 +82,7 @@ func (h *URLHandler) RedirectToOriginalUrl(ctx *gin.Context, shortPath string) {
 
 // DeleteShortURL implements URLService
 func (h *URLHandler) DeleteShortUrl(ctx *gin.Context, shortPath string) {
-	err := h.service.DeleteURL(ctx, shortPath)
+	err := h.service.DeleteURL(ctx, shortPath, auditActorFromRequest(ctx, h.actorHeader))
 	if err == repositories.ErrShortURLNotFound {
 		ctx.JSON(http.StatusNotFound, gin.H{"message": "Short URL not found"})
 		return
@@ -108,7 +113,7 @@ func (h *URLHandler) UpdateShortUrl(ctx *gin.Context, shortPath string) {
 		return
 	}
 
-	err := h.service.UpdateShortURL(ctx, req.OriginalUrl, shortPath, req.Expiry)
+	err := h.service.UpdateShortURL(ctx, req.OriginalUrl, shortPath, req.Expiry, auditActorFromRequest(ctx, h.actorHeader))
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -117,6 +122,46 @@ func (h *URLHandler) UpdateShortUrl(ctx *gin.Context, shortPath string) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Short URL updated successfully"})
 }
 
+func (h *URLHandler) RestoreShortUrl(ctx *gin.Context, shortPath string) {
+	url, err := h.service.RestoreURL(ctx, shortPath, auditActorFromRequest(ctx, h.actorHeader))
+	if errors.Is(err, repositories.ErrURLNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "No archived version of " + shortPath})
+		return
+	}
+	if errors.Is(err, repositories.ErrShortURLAlreadyExists) {
+		ctx.JSON(http.StatusConflict, gin.H{"message": shortPath + " is in use again"})
+		return
+	}
+	if errors.Is(err, repositories.ErrURLExpired) {
+		ctx.JSON(http.StatusConflict, gin.H{"message": "The latest archived version of " + shortPath + " has expired"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	shortUrl := shortURL(ctx, url.ShortPath)
+	ctx.JSON(http.StatusOK, &api.ShortenedUrlDetails{
+		ShortPath:   &url.ShortPath,
+		OriginalUrl: &url.OriginalURL,
+		ShortUrl:    &shortUrl,
+		Expiry:      url.Expiry,
+	})
+}
+
+func (h *URLHandler) ListArchivedUrls(ctx *gin.Context, params api.ListArchivedUrlsParams) {
+	archived, err := h.service.ListArchivedURLs(ctx, valueOrZero(params.ShortPath), valueOrZero(params.Before), valueOrZero(params.Limit))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	response := make([]*api.ArchivedUrl, 0, len(archived))
+	for i := range archived {
+		response = append(response, toArchivedURL(&archived[i]))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
 // GetURLDetails implements URLService
 func (h *URLHandler) GetShortUrlDetails(ctx *gin.Context, shortPath string) {
 	urlDetails, err := h.service.GetURLDetails(ctx, shortPath)
@@ -131,8 +176,9 @@ func (h *URLHandler) GetShortUrlDetails(ctx *gin.Context, shortPath string) {
 	ctx.JSON(http.StatusOK, urlDetails)
 }
 
-func (h *URLHandler) GetShortUrlStats(ctx *gin.Context, shortPath string) {
-	urlStats, err := h.urlStatService.GetURLStatistics(ctx, shortPath)
+func (h *URLHandler) GetShortUrlStats(ctx *gin.Context, shortPath string, params api.GetShortUrlStatsParams) {
+	includeBots := params.IncludeBots != nil && *params.IncludeBots
+	urlStats, err := h.urlStatService.GetURLStatistics(ctx, shortPath, includeBots)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -143,3 +189,165 @@ func (h *URLHandler) GetShortUrlStats(ctx *gin.Context, shortPath string) {
 	}
 	ctx.JSON(http.StatusOK, urlStats)
 }
+
+func (h *URLHandler) GetShortUrlStatsTimeseries(ctx *gin.Context, shortPath string, params api.GetShortUrlStatsTimeseriesParams) {
+	interval := services.TimeseriesDay
+	if params.Interval != nil {
+		interval = string(*params.Interval)
+	}
+	if interval != services.TimeseriesHour && interval != services.TimeseriesDay && interval != services.TimeseriesWeek {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "interval must be hour, day or week"})
+		return
+	}
+	location := time.UTC
+	if params.Tz != nil && *params.Tz != "" {
+		var err error
+		// Local would silently mean the server's time zone.
+		location, err = time.LoadLocation(*params.Tz)
+		if err != nil || *params.Tz == "Local" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Unknown time zone " + *params.Tz})
+			return
+		}
+	}
+
+	var to time.Time
+	if params.To != nil {
+		to = *params.To
+	} else {
+		to = h.timeProvider.Now()
+	}
+	from := defaultTimeseriesFrom(to, interval)
+	if params.From != nil {
+		from = *params.From
+	}
+
+	includeBots := params.IncludeBots != nil && *params.IncludeBots
+	timeseries, err := h.urlStatService.GetURLTimeseries(ctx, shortPath, from, to, interval, location, includeBots)
+	if errors.Is(err, services.ErrInvalidTimeseriesRange) || errors.Is(err, services.ErrTooManyBuckets) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, toURLTimeseries(timeseries))
+}
+
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+func (h *URLHandler) GetTopLinks(ctx *gin.Context, params api.GetTopLinksParams) {
+	window, limit, ok := leaderboardParams(ctx, (*string)(params.Window), params.Limit)
+	if !ok {
+		return
+	}
+	links, err := h.urlStatService.GetTopLinks(ctx, window, limit)
+	if errors.Is(err, services.ErrInvalidStatsWindow) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	topLinks := make([]api.TopLink, 0, len(links))
+	for _, link := range links {
+		shortPath, originalURL, clicks := link.ShortPath, link.OriginalURL, int(link.Clicks)
+		topLinks = append(topLinks, api.TopLink{ShortPath: &shortPath, OriginalUrl: &originalURL, Clicks: &clicks})
+	}
+	ctx.JSON(http.StatusOK, api.TopLinks{Window: &window, Links: &topLinks})
+}
+
+func (h *URLHandler) GetDomainStats(ctx *gin.Context, params api.GetDomainStatsParams) {
+	window, limit, ok := leaderboardParams(ctx, (*string)(params.Window), params.Limit)
+	if !ok {
+		return
+	}
+	domains, err := h.urlStatService.GetDomainClicks(ctx, window, limit)
+	if errors.Is(err, services.ErrInvalidStatsWindow) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	domainStats := make([]api.DomainStat, 0, len(domains))
+	for _, domain := range domains {
+		name, links, clicks := domain.Domain, int(domain.Links), int(domain.Clicks)
+		domainStats = append(domainStats, api.DomainStat{Domain: &name, Links: &links, Clicks: &clicks})
+	}
+	ctx.JSON(http.StatusOK, api.DomainStats{Window: &window, Domains: &domainStats})
+}
+
+// leaderboardParams applies the defaults of the top links and domain endpoints and answers with 400 when the limit
+// is out of range. The window is checked by the service.
+func leaderboardParams(ctx *gin.Context, window *string, limit *int) (string, int, bool) {
+	selectedWindow := services.StatsWindowDay
+	if window != nil {
+		selectedWindow = *window
+	}
+	selectedLimit := defaultLeaderboardLimit
+	if limit != nil {
+		selectedLimit = *limit
+	}
+	if selectedLimit < 1 || selectedLimit > maxLeaderboardLimit {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "limit must be between 1 and 100"})
+		return "", 0, false
+	}
+	return selectedWindow, selectedLimit, true
+}
+
+// shortURL is the address shortPath is served at by this server.
+func shortURL(ctx *gin.Context, shortPath string) string {
+	scheme := "http"
+	if ctx.Request.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + ctx.Request.Host + "/" + shortPath
+}
+
+func defaultTimeseriesFrom(to time.Time, interval string) time.Time {
+	switch interval {
+	case services.TimeseriesHour:
+		return to.Add(-24 * time.Hour)
+	case services.TimeseriesWeek:
+		return to.AddDate(0, 0, -12*7)
+	default:
+		return to.AddDate(0, 0, -30)
+	}
+}
+
+func toURLTimeseries(timeseries *models.URLTimeseries) *api.URLTimeseries {
+	buckets := make([]api.TimeseriesBucket, 0, len(timeseries.Buckets))
+	for _, bucket := range timeseries.Buckets {
+		start := bucket.Start
+		count := int(bucket.Count)
+		buckets = append(buckets, api.TimeseriesBucket{Start: &start, Count: &count})
+	}
+	return &api.URLTimeseries{
+		Interval: &timeseries.Interval,
+		Timezone: &timeseries.TimeZone,
+		From:     &timeseries.From,
+		To:       &timeseries.To,
+		Buckets:  &buckets,
+	}
+}
+
+func toArchivedURL(url *models.URLArchive) *api.ArchivedUrl {
+	return &api.ArchivedUrl{
+		Id:          &url.ID,
+		ShortPath:   &url.ShortPath,
+		OriginalUrl: &url.OriginalURL,
+		Expiry:      utcTimePtr(url.Expiry),
+		CreatedAt:   utcTimePtr(url.CreatedAt),
+		CreatedBy:   url.CreatedBy,
+		ModifiedAt:  utcTimePtr(url.ModifiedAt),
+		ModifiedBy:  url.ModifiedBy,
+		DeletedAt:   utcTimePtr(url.DeletedAt),
+		DeletedBy:   url.DeletedBy,
+	}
+}
@@ -13,7 +13,10 @@ import (
 
 	api "url-shortener/generated"
 	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/services"
 	mocks "url-shortener/internal/services/mocks"
+	"url-shortener/internal/utils"
 
 	utilMocks "url-shortener/internal/utils/mocks"
 
@@ -29,12 +32,17 @@ func setupHandler() (*mocks.URLService, *mocks.URLStatsService, *utilMocks.TimeP
 	mockURLService := mocks.URLService{}
 	mockURLStatsService := mocks.URLStatsService{}
 	mockTimeProvier := utilMocks.TimeProvider{}
-	return &mockURLService, &mockURLStatsService, &mockTimeProvier, NewURLHandler(&mockURLService, &mockURLStatsService, &mockTimeProvier)
+	return &mockURLService, &mockURLStatsService, &mockTimeProvier, NewURLHandler(&mockURLService, &mockURLStatsService, &mockTimeProvier, utils.NewVisitorFingerprinter(""), testBotClassifier(), true, "X-Actor")
+}
+
+func testBotClassifier() *utils.BotClassifier {
+	botClassifier, _ := utils.NewBotClassifier("")
+	return botClassifier
 }
 
 func TestCreateShortURL_Success(t *testing.T) {
 	mockURLService, mockURLStatsService, timeProvider, handler := setupHandler()
-	mockURLService.On("CreateShortURL", mock.Anything, "https://www.example.com", &mockExpiryTime).Return("shortpath", nil).Once()
+	mockURLService.On("CreateShortURL", mock.Anything, "https://www.example.com", &mockExpiryTime, mock.Anything).Return("shortpath", nil).Once()
 	timeProvider.On("Now").Return(time.Now()).Once()
 
 	w := httptest.NewRecorder()
@@ -66,7 +74,7 @@ func TestCreateShortURL_InvalidURL(t *testing.T) {
 
 func TestRedirectToOriginalURL_Success(t *testing.T) {
 	mockURLService, mockURLStatsService, _, handler := setupHandler()
-	mockURLService.On("GetLongURL", mock.Anything, "shortpath").Return("https://www.example.com", nil).Once()
+	mockURLService.On("GetLongURL", mock.Anything, "shortpath", mock.Anything).Return("https://www.example.com", nil).Once()
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -82,9 +90,140 @@ func TestRedirectToOriginalURL_Success(t *testing.T) {
 	mockURLStatsService.AssertExpectations(t)
 }
 
+func TestRedirectToOriginalURL_CapturesAccessMetadata(t *testing.T) {
+	mockURLService, _, _, handler := setupHandler()
+	expected := &models.AccessLog{
+		ReferrerHost: "news.example.org",
+		Browser:      "Chrome",
+		OS:           "Android",
+		DeviceClass:  "mobile",
+		ClientIP:     "203.0.113.7",
+		Language:     "de-de",
+		QueryString:  "utm_source=newsletter",
+	}
+	mockURLService.On("GetLongURL", mock.Anything, "shortpath", expected).Return("https://www.example.com", nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/shortpath?utm_source=newsletter", nil)
+	c.Request.RemoteAddr = "203.0.113.7:51234"
+	c.Request.Header.Set("Referer", "https://News.example.org/articles/1?ref=home")
+	c.Request.Header.Set("User-Agent", "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36")
+	c.Request.Header.Set("Accept-Language", "de-DE,de;q=0.9,en;q=0.8")
+
+	handler.RedirectToOriginalUrl(c, "shortpath")
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	mockURLService.AssertExpectations(t)
+}
+
+func TestRedirectToOriginalURL_SetsVisitorID(t *testing.T) {
+	mockURLService := &mocks.URLService{}
+	fingerprinter := utils.NewVisitorFingerprinter("secret")
+	handler := NewURLHandler(mockURLService, &mocks.URLStatsService{}, &utilMocks.TimeProvider{}, fingerprinter, testBotClassifier(), true, "X-Actor")
+	userAgent := "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0"
+	visitorID := fingerprinter.Fingerprint("203.0.113.7", userAgent)
+	mockURLService.On("GetLongURL", mock.Anything, "shortpath", mock.MatchedBy(func(accessLog *models.AccessLog) bool {
+		return accessLog.VisitorID == visitorID
+	})).Return("https://www.example.com", nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/shortpath", nil)
+	c.Request.RemoteAddr = "203.0.113.7:51234"
+	c.Request.Header.Set("User-Agent", userAgent)
+
+	handler.RedirectToOriginalUrl(c, "shortpath")
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.NotEmpty(t, visitorID)
+	mockURLService.AssertExpectations(t)
+}
+
+func TestRedirectToOriginalURL_HonorsDoNotTrack(t *testing.T) {
+	mockURLService := &mocks.URLService{}
+	handler := NewURLHandler(mockURLService, &mocks.URLStatsService{}, &utilMocks.TimeProvider{}, utils.NewVisitorFingerprinter("secret"), testBotClassifier(), true, "X-Actor")
+	expected := &models.AccessLog{
+		Browser:     "Firefox",
+		OS:          "Linux",
+		DeviceClass: "desktop",
+		ClientIP:    "203.0.113.7",
+		DoNotTrack:  true,
+	}
+	mockURLService.On("GetLongURL", mock.Anything, "shortpath", expected).Return("https://www.example.com", nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/shortpath?email=someone@example.com", nil)
+	c.Request.RemoteAddr = "203.0.113.7:51234"
+	c.Request.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0")
+	c.Request.Header.Set("Sec-GPC", "1")
+
+	handler.RedirectToOriginalUrl(c, "shortpath")
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	mockURLService.AssertExpectations(t)
+}
+
+func TestRedirectToOriginalURL_IgnoresDoNotTrackWhenOff(t *testing.T) {
+	mockURLService := &mocks.URLService{}
+	handler := NewURLHandler(mockURLService, &mocks.URLStatsService{}, &utilMocks.TimeProvider{}, utils.NewVisitorFingerprinter("secret"), testBotClassifier(), false, "X-Actor")
+	mockURLService.On("GetLongURL", mock.Anything, "shortpath", mock.MatchedBy(func(accessLog *models.AccessLog) bool {
+		return !accessLog.DoNotTrack && accessLog.VisitorID != ""
+	})).Return("https://www.example.com", nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/shortpath", nil)
+	c.Request.RemoteAddr = "203.0.113.7:51234"
+	c.Request.Header.Set("DNT", "1")
+
+	handler.RedirectToOriginalUrl(c, "shortpath")
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	mockURLService.AssertExpectations(t)
+}
+
+func TestRedirectToOriginalURL_MarksLinkUnfurlersAsBots(t *testing.T) {
+	mockURLService, _, _, handler := setupHandler()
+	mockURLService.On("GetLongURL", mock.Anything, "shortpath", mock.MatchedBy(func(accessLog *models.AccessLog) bool {
+		return accessLog.IsBot
+	})).Return("https://www.example.com", nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/shortpath", nil)
+	c.Request.Header.Set("User-Agent", "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)")
+
+	handler.RedirectToOriginalUrl(c, "shortpath")
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	mockURLService.AssertExpectations(t)
+}
+
+func TestRedirectToOriginalURL_IgnoresUntrustedForwardedFor(t *testing.T) {
+	mockURLService, _, _, handler := setupHandler()
+	mockURLService.On("GetLongURL", mock.Anything, "shortpath", mock.MatchedBy(func(accessLog *models.AccessLog) bool {
+		return accessLog.ClientIP == "203.0.113.7"
+	})).Return("https://www.example.com", nil).Once()
+
+	router := gin.New()
+	assert.NoError(t, router.SetTrustedProxies(nil))
+	router.GET("/:shortPath", func(c *gin.Context) { handler.RedirectToOriginalUrl(c, c.Param("shortPath")) })
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/shortpath", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	mockURLService.AssertExpectations(t)
+}
+
 func TestDeleteShortURL_Success(t *testing.T) {
 	mockURLService, _, _, handler := setupHandler()
-	mockURLService.On("DeleteURL", mock.Anything, "shortpath").Return(nil).Once()
+	mockURLService.On("DeleteURL", mock.Anything, "shortpath", mock.Anything).Return(nil).Once()
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -97,9 +236,46 @@ func TestDeleteShortURL_Success(t *testing.T) {
 	mockURLService.AssertExpectations(t)
 }
 
+func TestDeleteShortURL_PassesAuditActor(t *testing.T) {
+	mockURLService, _, _, handler := setupHandler()
+	mockURLService.On("DeleteURL", mock.Anything, "shortpath", &models.AuditActor{Actor: "alice", SourceIP: "203.0.113.7",
+		UserAgent: "curl/8.0", RequestID: "req-1"}).Return(nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodDelete, "/shortpath", nil)
+	c.Request.RemoteAddr = "203.0.113.7:4321"
+	c.Request.Header.Set("X-Actor", " alice ")
+	c.Request.Header.Set("User-Agent", "curl/8.0")
+	c.Request.Header.Set("X-Request-ID", "req-1")
+
+	handler.DeleteShortUrl(c, "shortpath")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "req-1", w.Header().Get("X-Request-ID"))
+	mockURLService.AssertExpectations(t)
+}
+
+func TestDeleteShortURL_GeneratesRequestID(t *testing.T) {
+	mockURLService, _, _, handler := setupHandler()
+	mockURLService.On("DeleteURL", mock.Anything, "shortpath", mock.MatchedBy(func(actor *models.AuditActor) bool {
+		return actor.Actor == "" && len(actor.RequestID) == 32
+	})).Return(nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodDelete, "/shortpath", nil)
+
+	handler.DeleteShortUrl(c, "shortpath")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, w.Header().Get("X-Request-ID"), 32)
+	mockURLService.AssertExpectations(t)
+}
+
 func TestUpdateShortURL_Success(t *testing.T) {
 	mockURLService, _, timeProvider, handler := setupHandler()
-	mockURLService.On("UpdateShortURL", mock.Anything, "https://www.updated-example.com", "shortpath", &mockExpiryTime).Return(nil).Once()
+	mockURLService.On("UpdateShortURL", mock.Anything, "https://www.updated-example.com", "shortpath", &mockExpiryTime, mock.Anything).Return(nil).Once()
 	timeProvider.On("Now").Return(time.Now()).Once()
 
 	w := httptest.NewRecorder()
@@ -118,14 +294,30 @@ func TestUpdateShortURL_Success(t *testing.T) {
 func TestGetShortURLStats_Success(t *testing.T) {
 	_, mockURLStatsService, _, handler := setupHandler()
 	mockStats := &models.URLStatistics{ShortPath: "shortpath", Last24Hours: 5, PastWeek: 5, AllTime: 5}
-	mockURLStatsService.On("GetURLStatistics", mock.Anything, "shortpath").Return(mockStats, nil).Once()
+	mockURLStatsService.On("GetURLStatistics", mock.Anything, "shortpath", false).Return(mockStats, nil).Once()
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest(http.MethodGet, "/shortpath/stats", nil)
 	c.Params = gin.Params{{Key: "shortPath", Value: "shortpath"}}
 
-	handler.GetShortUrlStats(c, "shortpath")
+	handler.GetShortUrlStats(c, "shortpath", api.GetShortUrlStatsParams{})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockURLStatsService.AssertExpectations(t)
+}
+
+func TestGetShortURLStats_IncludeBots(t *testing.T) {
+	_, mockURLStatsService, _, handler := setupHandler()
+	mockStats := &models.URLStatistics{ShortPath: "shortpath", Last24Hours: 7, PastWeek: 7, AllTime: 7}
+	mockURLStatsService.On("GetURLStatistics", mock.Anything, "shortpath", true).Return(mockStats, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/shortpath/stats?includeBots=true", nil)
+	includeBots := true
+
+	handler.GetShortUrlStats(c, "shortpath", api.GetShortUrlStatsParams{IncludeBots: &includeBots})
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	mockURLStatsService.AssertExpectations(t)
@@ -163,14 +355,14 @@ func TestGetShortURLDetails_NotFound(t *testing.T) {
 
 func TestGetShortURLStats_NotFound(t *testing.T) {
 	_, mockURLStatsService, _, handler := setupHandler()
-	mockURLStatsService.On("GetURLStatistics", mock.Anything, "shortpath").Return(nil, nil).Once()
+	mockURLStatsService.On("GetURLStatistics", mock.Anything, "shortpath", false).Return(nil, nil).Once()
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest(http.MethodGet, "/shortpath/stats", nil)
 	c.Params = gin.Params{{Key: "shortPath", Value: "shortpath"}}
 
-	handler.GetShortUrlStats(c, "shortpath")
+	handler.GetShortUrlStats(c, "shortpath", api.GetShortUrlStatsParams{})
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 	mockURLStatsService.AssertExpectations(t)
@@ -178,7 +370,7 @@ func TestGetShortURLStats_NotFound(t *testing.T) {
 
 func TestRedirectToOriginalURL_NotFound(t *testing.T) {
 	mockURLService, mockURLStatsService, _, handler := setupHandler()
-	mockURLService.On("GetLongURL", mock.Anything, "shortpath").Return("", nil).Once()
+	mockURLService.On("GetLongURL", mock.Anything, "shortpath", mock.Anything).Return("", nil).Once()
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -193,7 +385,7 @@ func TestRedirectToOriginalURL_NotFound(t *testing.T) {
 }
 func TestDeleteShortURL_Failure(t *testing.T) {
 	mockURLService, _, _, handler := setupHandler()
-	mockURLService.On("DeleteURL", mock.Anything, "shortpath").Return(errors.New("failed to delete")).Once()
+	mockURLService.On("DeleteURL", mock.Anything, "shortpath", mock.Anything).Return(errors.New("failed to delete")).Once()
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -208,7 +400,7 @@ func TestDeleteShortURL_Failure(t *testing.T) {
 
 func TestUpdateShortURL_Failure(t *testing.T) {
 	mockURLService, _, timeProvider, handler := setupHandler()
-	mockURLService.On("UpdateShortURL", mock.Anything, "https://www.updated-example.com", "shortpath", &mockExpiryTime).Return(errors.New("failed to update")).Once()
+	mockURLService.On("UpdateShortURL", mock.Anything, "https://www.updated-example.com", "shortpath", &mockExpiryTime, mock.Anything).Return(errors.New("failed to update")).Once()
 	timeProvider.On("Now").Return(time.Now()).Once()
 
 	w := httptest.NewRecorder()
@@ -226,14 +418,14 @@ func TestUpdateShortURL_Failure(t *testing.T) {
 
 func TestGetShortURLStats_Failure(t *testing.T) {
 	_, mockURLStatsService, _, handler := setupHandler()
-	mockURLStatsService.On("GetURLStatistics", mock.Anything, "shortpath").Return(nil, errors.New("failed to get stats")).Once()
+	mockURLStatsService.On("GetURLStatistics", mock.Anything, "shortpath", false).Return(nil, errors.New("failed to get stats")).Once()
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest(http.MethodGet, "/shortpath/stats", nil)
 	c.Params = gin.Params{{Key: "shortPath", Value: "shortpath"}}
 
-	handler.GetShortUrlStats(c, "shortpath")
+	handler.GetShortUrlStats(c, "shortpath", api.GetShortUrlStatsParams{})
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	mockURLStatsService.AssertExpectations(t)
@@ -294,7 +486,7 @@ func TestCreateShortURL_InvalidRequest(t *testing.T) {
 
 func TestRedirectToOriginalURL_Failure(t *testing.T) {
 	mockURLService, mockURLStatsService, _, handler := setupHandler()
-	mockURLService.On("GetLongURL", mock.Anything, "shortpath").Return("", errors.New("failed to get long URL")).Once()
+	mockURLService.On("GetLongURL", mock.Anything, "shortpath", mock.Anything).Return("", errors.New("failed to get long URL")).Once()
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -310,7 +502,7 @@ func TestRedirectToOriginalURL_Failure(t *testing.T) {
 
 func TestCreateShortURL_InternalError(t *testing.T) {
 	mockURLService, mockURLStatsService, timeProvider, handler := setupHandler()
-	mockURLService.On("CreateShortURL", mock.Anything, "https://www.example.com", &mockExpiryTime).Return("", errors.New("failed to create short URL")).Once()
+	mockURLService.On("CreateShortURL", mock.Anything, "https://www.example.com", &mockExpiryTime, mock.Anything).Return("", errors.New("failed to create short URL")).Once()
 	timeProvider.On("Now").Return(time.Now()).Once()
 
 	w := httptest.NewRecorder()
@@ -328,7 +520,7 @@ func TestCreateShortURL_InternalError(t *testing.T) {
 
 func TestCreateShortURL_SuccessHTTPS(t *testing.T) {
 	mockURLService, mockURLStatsService, timeProvider, handler := setupHandler()
-	mockURLService.On("CreateShortURL", mock.Anything, "https://www.example.com", &mockExpiryTime).Return("shortpath", nil).Once()
+	mockURLService.On("CreateShortURL", mock.Anything, "https://www.example.com", &mockExpiryTime, mock.Anything).Return("shortpath", nil).Once()
 	timeProvider.On("Now").Return(time.Now()).Once()
 
 	w := httptest.NewRecorder()
@@ -353,3 +545,229 @@ func TestValidateURL_InvalidScheme(t *testing.T) {
 	err := validateURL("ftp://example.com")
 	assert.Equal(t, ErrInvalidURLScheme, err)
 }
+
+func TestGetShortURLStatsTimeseries_Success(t *testing.T) {
+	_, mockURLStatsService, _, handler := setupHandler()
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	from := time.Date(2025, 6, 9, 0, 0, 0, 0, berlin)
+	to := time.Date(2025, 6, 10, 0, 0, 0, 0, berlin)
+	interval := api.Hour
+	tz := "Europe/Berlin"
+	timeseries := &models.URLTimeseries{ShortPath: "shortpath", Interval: "hour", TimeZone: tz, From: from, To: to,
+		Buckets: []models.TimeseriesBucket{{Start: from, Count: 2}}}
+	mockURLStatsService.On("GetURLTimeseries", mock.Anything, "shortpath", from, to, "hour", berlin, false).Return(timeseries, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/urls/shortpath/stats/timeseries", nil)
+
+	handler.GetShortUrlStatsTimeseries(c, "shortpath", api.GetShortUrlStatsTimeseriesParams{From: &from, To: &to, Interval: &interval, Tz: &tz})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response api.URLTimeseries
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, *response.Buckets, 1)
+	assert.Equal(t, 2, *(*response.Buckets)[0].Count)
+	assert.Contains(t, w.Body.String(), "2025-06-09T00:00:00+02:00")
+	mockURLStatsService.AssertExpectations(t)
+}
+
+func TestGetShortURLStatsTimeseries_Defaults(t *testing.T) {
+	_, mockURLStatsService, timeProvider, handler := setupHandler()
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now).Once()
+	mockURLStatsService.On("GetURLTimeseries", mock.Anything, "shortpath", now.AddDate(0, 0, -30), now, "day", time.UTC, false).
+		Return(&models.URLTimeseries{}, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/urls/shortpath/stats/timeseries", nil)
+
+	handler.GetShortUrlStatsTimeseries(c, "shortpath", api.GetShortUrlStatsTimeseriesParams{})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockURLStatsService.AssertExpectations(t)
+}
+
+func TestGetShortURLStatsTimeseries_InvalidParams(t *testing.T) {
+	badInterval := api.GetShortUrlStatsTimeseriesParamsInterval("month")
+	badTz := "Mars/Olympus"
+	local := "Local"
+	for name, params := range map[string]api.GetShortUrlStatsTimeseriesParams{
+		"interval":   {Interval: &badInterval},
+		"time zone":  {Tz: &badTz},
+		"local zone": {Tz: &local},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, mockURLStatsService, _, handler := setupHandler()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/urls/shortpath/stats/timeseries", nil)
+
+			handler.GetShortUrlStatsTimeseries(c, "shortpath", params)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockURLStatsService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetShortURLStatsTimeseries_InvalidRange(t *testing.T) {
+	_, mockURLStatsService, _, handler := setupHandler()
+	from := time.Now()
+	to := from.Add(-time.Hour)
+	mockURLStatsService.On("GetURLTimeseries", mock.Anything, "shortpath", from, to, "day", time.UTC, false).
+		Return(nil, services.ErrInvalidTimeseriesRange).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/urls/shortpath/stats/timeseries", nil)
+
+	handler.GetShortUrlStatsTimeseries(c, "shortpath", api.GetShortUrlStatsTimeseriesParams{From: &from, To: &to})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockURLStatsService.AssertExpectations(t)
+}
+
+func TestGetTopLinks_Defaults(t *testing.T) {
+	_, mockURLStatsService, _, handler := setupHandler()
+	mockURLStatsService.On("GetTopLinks", mock.Anything, "24h", 10).Return([]models.LinkClicks{
+		{ShortPath: "path1", OriginalURL: "https://www.example.com", Clicks: 42},
+	}, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/stats/top", nil)
+
+	handler.GetTopLinks(c, api.GetTopLinksParams{})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"window":"24h","links":[{"short-path":"path1","originalUrl":"https://www.example.com","clicks":42}]}`, w.Body.String())
+	mockURLStatsService.AssertExpectations(t)
+}
+
+func TestGetTopLinks_InvalidLimit(t *testing.T) {
+	_, mockURLStatsService, _, handler := setupHandler()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/stats/top?limit=1000", nil)
+	limit := 1000
+
+	handler.GetTopLinks(c, api.GetTopLinksParams{Limit: &limit})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockURLStatsService.AssertNotCalled(t, "GetTopLinks", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetTopLinks_InvalidWindow(t *testing.T) {
+	_, mockURLStatsService, _, handler := setupHandler()
+	mockURLStatsService.On("GetTopLinks", mock.Anything, "1y", 10).Return(nil, services.ErrInvalidStatsWindow).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/stats/top?window=1y", nil)
+	window := api.GetTopLinksParamsWindow("1y")
+
+	handler.GetTopLinks(c, api.GetTopLinksParams{Window: &window})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockURLStatsService.AssertExpectations(t)
+}
+
+func TestGetDomainStats_Success(t *testing.T) {
+	_, mockURLStatsService, _, handler := setupHandler()
+	mockURLStatsService.On("GetDomainClicks", mock.Anything, "7d", 5).Return([]models.DomainClicks{
+		{Domain: "example.com", Links: 3, Clicks: 10},
+		{Domain: "example.org", Links: 1, Clicks: 0},
+	}, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/stats/domains?window=7d&limit=5", nil)
+	window := api.GetDomainStatsParamsWindowN7d
+	limit := 5
+
+	handler.GetDomainStats(c, api.GetDomainStatsParams{Window: &window, Limit: &limit})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"window":"7d","domains":[{"domain":"example.com","links":3,"clicks":10},{"domain":"example.org","links":1,"clicks":0}]}`, w.Body.String())
+	mockURLStatsService.AssertExpectations(t)
+}
+
+func TestGetDomainStats_Error(t *testing.T) {
+	_, mockURLStatsService, _, handler := setupHandler()
+	mockURLStatsService.On("GetDomainClicks", mock.Anything, "24h", 10).Return(nil, errors.New("failed")).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/stats/domains", nil)
+
+	handler.GetDomainStats(c, api.GetDomainStatsParams{})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestRestoreShortURL_Success(t *testing.T) {
+	mockURLService, _, _, handler := setupHandler()
+	mockURLService.On("RestoreURL", mock.Anything, "shortpath", mock.MatchedBy(func(actor *models.AuditActor) bool { return actor.Actor == "alice" })).
+		Return(&models.URL{ShortPath: "shortpath", OriginalURL: "https://www.example.com"}, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/urls/shortpath/restore", nil)
+	c.Request.Host = "sho.rt"
+	c.Request.Header.Set("X-Actor", "alice")
+
+	handler.RestoreShortUrl(c, "shortpath")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"short-path":"shortpath","originalUrl":"https://www.example.com","shortUrl":"http://sho.rt/shortpath"}`, w.Body.String())
+	mockURLService.AssertExpectations(t)
+}
+
+func TestRestoreShortURL_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		code int
+	}{
+		{repositories.ErrURLNotFound, http.StatusNotFound},
+		{repositories.ErrShortURLAlreadyExists, http.StatusConflict},
+		{repositories.ErrURLExpired, http.StatusConflict},
+		{errors.New("db down"), http.StatusInternalServerError},
+	} {
+		mockURLService, _, _, handler := setupHandler()
+		mockURLService.On("RestoreURL", mock.Anything, "shortpath", mock.Anything).Return(nil, tc.err).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/urls/shortpath/restore", nil)
+
+		handler.RestoreShortUrl(c, "shortpath")
+
+		assert.Equal(t, tc.code, w.Code, tc.err.Error())
+	}
+}
+
+func TestListArchivedURLs(t *testing.T) {
+	mockURLService, _, _, handler := setupHandler()
+	deletedAt := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	createdBy, deletedBy := "alice", "bob"
+	shortPath, limit := "shortpath", 10
+	mockURLService.On("ListArchivedURLs", mock.Anything, shortPath, int64(0), limit).Return([]models.URLArchive{{
+		ID: 4, ShortPath: shortPath, OriginalURL: "https://www.example.com", CreatedAt: &deletedAt, CreatedBy: &createdBy,
+		DeletedAt: &deletedAt, DeletedBy: &deletedBy,
+	}}, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/archived-urls", nil)
+
+	handler.ListArchivedUrls(c, api.ListArchivedUrlsParams{ShortPath: &shortPath, Limit: &limit})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"id":4,"shortPath":"shortpath","originalUrl":"https://www.example.com","createdAt":"2025-06-12T10:00:00Z",
+		"createdBy":"alice","deletedAt":"2025-06-12T10:00:00Z","deletedBy":"bob"}]`, w.Body.String())
+	mockURLService.AssertExpectations(t)
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	api "url-shortener/generated"
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService services.WebhookService
+}
+
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+func (h *WebhookHandler) ListWebhooks(ctx *gin.Context) {
+	subscriptions, err := h.webhookService.ListWebhooks(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	response := make([]*api.WebhookSubscription, 0, len(subscriptions))
+	for i := range subscriptions {
+		response = append(response, toWebhookSubscription(&subscriptions[i], false))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (h *WebhookHandler) CreateWebhook(ctx *gin.Context) {
+	subscription, ok := bindWebhookSubscription(ctx)
+	if !ok {
+		return
+	}
+	err := h.webhookService.CreateWebhook(ctx, subscription)
+	if errors.Is(err, services.ErrInvalidWebhook) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, toWebhookSubscription(subscription, true))
+}
+
+func (h *WebhookHandler) GetWebhook(ctx *gin.Context, id int64) {
+	subscription, err := h.webhookService.GetWebhook(ctx, id)
+	if errors.Is(err, repositories.ErrWebhookNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Webhook not found"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, toWebhookSubscription(subscription, false))
+}
+
+func (h *WebhookHandler) UpdateWebhook(ctx *gin.Context, id int64) {
+	subscription, ok := bindWebhookSubscription(ctx)
+	if !ok {
+		return
+	}
+	subscription.ID = id
+	err := h.webhookService.UpdateWebhook(ctx, subscription)
+	if errors.Is(err, services.ErrInvalidWebhook) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if errors.Is(err, repositories.ErrWebhookNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Webhook not found"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, toWebhookSubscription(subscription, false))
+}
+
+func (h *WebhookHandler) DeleteWebhook(ctx *gin.Context, id int64) {
+	err := h.webhookService.DeleteWebhook(ctx, id)
+	if errors.Is(err, repositories.ErrWebhookNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Webhook not found"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) ListWebhookDeliveries(ctx *gin.Context, id int64, params api.ListWebhookDeliveriesParams) {
+	deliveries, err := h.webhookService.ListDeliveries(ctx, id, valueOrZero(params.Before), valueOrZero(params.Limit))
+	if errors.Is(err, repositories.ErrWebhookNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Webhook not found"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	response := make([]*api.WebhookDelivery, 0, len(deliveries))
+	for i := range deliveries {
+		response = append(response, toWebhookDelivery(&deliveries[i]))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// bindWebhookSubscription reads the subscription of a create or update request, writing the response when it is
+// not valid JSON.
+func bindWebhookSubscription(ctx *gin.Context) (*models.WebhookSubscription, bool) {
+	var req api.WebhookSubscriptionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request payload"})
+		return nil, false
+	}
+	subscription := &models.WebhookSubscription{
+		URL:        req.Url,
+		Secret:     valueOrZero(req.Secret),
+		EventTypes: []string{},
+		Active:     req.Active == nil || *req.Active,
+	}
+	if req.EventTypes != nil {
+		for _, eventType := range *req.EventTypes {
+			subscription.EventTypes = append(subscription.EventTypes, string(eventType))
+		}
+	}
+	return subscription, true
+}
+
+// toWebhookSubscription leaves the secret out unless withSecret, it is only shown once when the subscription is
+// created.
+func toWebhookSubscription(subscription *models.WebhookSubscription, withSecret bool) *api.WebhookSubscription {
+	createdAt := subscription.CreatedAt.UTC()
+	updatedAt := subscription.UpdatedAt.UTC()
+	response := &api.WebhookSubscription{
+		Id:         &subscription.ID,
+		Url:        &subscription.URL,
+		EventTypes: &subscription.EventTypes,
+		Active:     &subscription.Active,
+		CreatedAt:  &createdAt,
+		UpdatedAt:  &updatedAt,
+	}
+	if withSecret {
+		response.Secret = &subscription.Secret
+	}
+	return response
+}
+
+func toWebhookDelivery(delivery *models.WebhookDelivery) *api.WebhookDelivery {
+	createdAt := delivery.CreatedAt.UTC()
+	status := api.WebhookDeliveryStatus(delivery.Status)
+	response := &api.WebhookDelivery{
+		Id:             &delivery.ID,
+		EventId:        &delivery.EventID,
+		EventType:      &delivery.EventType,
+		Status:         &status,
+		Attempts:       &delivery.Attempts,
+		NextAttemptAt:  utcTimePtr(delivery.NextAttemptAt),
+		LastStatusCode: delivery.LastStatusCode,
+		CreatedAt:      &createdAt,
+		DeliveredAt:    utcTimePtr(delivery.DeliveredAt),
+	}
+	if delivery.LastError != "" {
+		response.LastError = &delivery.LastError
+	}
+	return response
+}
+
+func utcTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
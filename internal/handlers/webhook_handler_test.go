@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	api "url-shortener/generated"
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/services"
+	mocks "url-shortener/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var webhookCreatedAt = time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+
+func setupWebhookHandler() (*mocks.WebhookService, *WebhookHandler) {
+	mockWebhookService := mocks.WebhookService{}
+	return &mockWebhookService, NewWebhookHandler(&mockWebhookService)
+}
+
+func TestCreateWebhook_ReturnsSecret(t *testing.T) {
+	mockWebhookService, handler := setupWebhookHandler()
+	mockWebhookService.On("CreateWebhook", mock.Anything, &models.WebhookSubscription{URL: "https://example.com/hook",
+		EventTypes: []string{models.LinkEventCreated, models.LinkEventExpired}, Active: true}).
+		Run(func(args mock.Arguments) {
+			subscription := args.Get(1).(*models.WebhookSubscription)
+			subscription.ID = 7
+			subscription.Secret = "generated"
+			subscription.CreatedAt = webhookCreatedAt
+			subscription.UpdatedAt = webhookCreatedAt
+		}).Return(nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"url":"https://example.com/hook","eventTypes":["link.created","link.expired"]}`))
+
+	handler.CreateWebhook(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"id":7,"url":"https://example.com/hook","secret":"generated","eventTypes":["link.created","link.expired"],
+		"active":true,"createdAt":"2025-06-12T10:00:00Z","updatedAt":"2025-06-12T10:00:00Z"}`, w.Body.String())
+	mockWebhookService.AssertExpectations(t)
+}
+
+func TestCreateWebhook_Invalid(t *testing.T) {
+	mockWebhookService, handler := setupWebhookHandler()
+	mockWebhookService.On("CreateWebhook", mock.Anything, mock.Anything).Return(services.ErrInvalidWebhook).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"url":"ftp://example.com"}`))
+
+	handler.CreateWebhook(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetWebhook_HidesSecret(t *testing.T) {
+	mockWebhookService, handler := setupWebhookHandler()
+	mockWebhookService.On("GetWebhook", mock.Anything, int64(7)).Return(&models.WebhookSubscription{ID: 7, URL: "https://example.com/hook",
+		Secret: "s3cret", EventTypes: []string{}, Active: false, CreatedAt: webhookCreatedAt, UpdatedAt: webhookCreatedAt}, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	handler.GetWebhook(c, 7)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":7,"url":"https://example.com/hook","eventTypes":[],"active":false,
+		"createdAt":"2025-06-12T10:00:00Z","updatedAt":"2025-06-12T10:00:00Z"}`, w.Body.String())
+}
+
+func TestUpdateWebhook_NotFound(t *testing.T) {
+	mockWebhookService, handler := setupWebhookHandler()
+	mockWebhookService.On("UpdateWebhook", mock.Anything, &models.WebhookSubscription{ID: 9, URL: "https://example.com/hook",
+		EventTypes: []string{}, Active: false}).Return(repositories.ErrWebhookNotFound).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPut, "/webhooks/9", strings.NewReader(`{"url":"https://example.com/hook","active":false}`))
+
+	handler.UpdateWebhook(c, 9)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockWebhookService.AssertExpectations(t)
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	mockWebhookService, handler := setupWebhookHandler()
+	mockWebhookService.On("DeleteWebhook", mock.Anything, int64(7)).Return(nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	handler.DeleteWebhook(c, 7)
+	c.Writer.WriteHeaderNow()
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestListWebhookDeliveries(t *testing.T) {
+	mockWebhookService, handler := setupWebhookHandler()
+	statusCode := 503
+	nextAttemptAt := webhookCreatedAt.Add(time.Minute)
+	before := int64(30)
+	mockWebhookService.On("ListDeliveries", mock.Anything, int64(7), int64(30), 0).Return([]models.WebhookDelivery{{
+		ID: 12, SubscriptionID: 7, EventID: 40, EventType: models.LinkEventDeleted, Status: models.WebhookDeliveryPending,
+		Attempts: 2, NextAttemptAt: &nextAttemptAt, LastStatusCode: &statusCode, LastError: "unexpected status 503 Service Unavailable",
+		CreatedAt: webhookCreatedAt,
+	}}, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	handler.ListWebhookDeliveries(c, 7, api.ListWebhookDeliveriesParams{Before: &before})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"id":12,"eventId":40,"eventType":"link.deleted","status":"pending","attempts":2,
+		"nextAttemptAt":"2025-06-12T10:01:00Z","lastStatusCode":503,"lastError":"unexpected status 503 Service Unavailable",
+		"createdAt":"2025-06-12T10:00:00Z"}]`, w.Body.String())
+}
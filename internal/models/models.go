@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type URL struct {
 	ShortPath   string     `json:"short_path"`
@@ -12,7 +15,9 @@ type URL struct {
 	ModifiedBy  *string    `json:"modified_by"`
 }
 
+// URLArchive is a deleted or expired version of a link, ID orders the versions of a short path.
 type URLArchive struct {
+	ID          int64      `json:"id"`
 	ShortPath   string     `json:"short_path"`
 	OriginalURL string     `json:"original_url"`
 	Expiry      *time.Time `json:"expiry"`
@@ -29,4 +34,413 @@ type URLStatistics struct {
 	Last24Hours int64  `json:"last_24_hours"`
 	PastWeek    int64  `json:"past_week"`
 	AllTime     int64  `json:"all_time"`
+	// Unique visitor counts are HyperLogLog estimates, accurate to about 1%.
+	UniqueLast24Hours int64            `json:"unique_last_24_hours"`
+	UniquePastWeek    int64            `json:"unique_past_week"`
+	UniqueAllTime     int64            `json:"unique_all_time"`
+	Referrers         []BreakdownEntry `json:"referrers"`
+	Browsers          []BreakdownEntry `json:"browsers"`
+	OperatingSystems  []BreakdownEntry `json:"operating_systems"`
+	Devices           []BreakdownEntry `json:"devices"`
+	Languages         []BreakdownEntry `json:"languages"`
+	Countries         []BreakdownEntry `json:"countries"`
+	Regions           []BreakdownEntry `json:"regions"`
+	Cities            []BreakdownEntry `json:"cities"`
+	// ConversionRate is the share of clicks with at least one conversion.
+	Conversions     int64                 `json:"conversions"`
+	ConversionValue float64               `json:"conversion_value"`
+	ConversionRate  float64               `json:"conversion_rate"`
+	ConversionTypes []ConversionTypeStats `json:"conversion_types"`
+}
+
+type URLTimeseries struct {
+	ShortPath string             `json:"short_path"`
+	Interval  string             `json:"interval"`
+	TimeZone  string             `json:"timezone"`
+	From      time.Time          `json:"from"`
+	To        time.Time          `json:"to"`
+	Buckets   []TimeseriesBucket `json:"buckets"`
+}
+
+type TimeseriesBucket struct {
+	Start time.Time `json:"start"`
+	Count int64     `json:"count"`
+}
+
+type UniqueVisitorCounts struct {
+	Last24Hours int64 `json:"last_24_hours"`
+	PastWeek    int64 `json:"past_week"`
+	AllTime     int64 `json:"all_time"`
+}
+
+// RecentClickCounts are the clicks of a link, bots excluded, counted in Redis as they happen.
+type RecentClickCounts struct {
+	Last24Hours int64 `json:"last_24_hours"`
+	PastWeek    int64 `json:"past_week"`
+}
+
+// HourlyClicks is the number of clicks of a link, bots excluded, in the hour starting at Bucket.
+type HourlyClicks struct {
+	ShortPath string    `json:"short_path"`
+	Bucket    time.Time `json:"bucket"`
+	Clicks    int64     `json:"clicks"`
+}
+
+// MinuteClicks is the number of clicks of a link, bots excluded, in the minute starting at Bucket.
+type MinuteClicks struct {
+	ShortPath string    `json:"short_path"`
+	Bucket    time.Time `json:"bucket"`
+	Clicks    int64     `json:"clicks"`
+}
+
+// LinkClicks is a link of the top links leaderboard.
+type LinkClicks struct {
+	ShortPath   string `json:"short_path"`
+	OriginalURL string `json:"original_url"`
+	Clicks      int64  `json:"clicks"`
+}
+
+// DomainClicks are the links pointing at a destination host and their clicks.
+type DomainClicks struct {
+	Domain string `json:"domain"`
+	Links  int64  `json:"links"`
+	Clicks int64  `json:"clicks"`
+}
+
+type BreakdownEntry struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type AccessLog struct {
+	ShortPath    string    `json:"short_path"`
+	AccessedAt   time.Time `json:"accessed_at"`
+	ReferrerHost string    `json:"referrer_host"`
+	Browser      string    `json:"browser"`
+	OS           string    `json:"os"`
+	DeviceClass  string    `json:"device_class"`
+	ClientIP     string    `json:"client_ip"`
+	Language     string    `json:"language"`
+	QueryString  string    `json:"query_string"`
+	Country      string    `json:"country"`
+	Region       string    `json:"region"`
+	City         string    `json:"city"`
+	// IsBot marks clicks by crawlers and link unfurlers, which are left out of stats unless asked for.
+	IsBot bool `json:"is_bot"`
+	// VisitorID is a pseudonymous fingerprint used for unique visitor counts. It is not stored with the click.
+	VisitorID string `json:"visitor_id"`
+	// ClickID is handed to the destination so it can report conversions of the click, empty when click IDs are off.
+	ClickID string `json:"click_id"`
+	// IsDuplicate marks repeated clicks of a visitor within the de-dup window, which are kept but left out of stats.
+	IsDuplicate bool `json:"is_duplicate"`
+	// DoNotTrack is set for clicks sent with DNT or Sec-GPC, which are stored without identifying fields.
+	DoNotTrack bool `json:"do_not_track"`
+}
+
+// Conversion is an outcome, such as a sign-up or purchase, reported by the destination for a click.
+type Conversion struct {
+	ClickID   string  `json:"click_id"`
+	ShortPath string  `json:"short_path"`
+	Type      string  `json:"type"`
+	Value     float64 `json:"value"`
+	// TransactionID makes reporting the same conversion again a no-op, empty conversions are never deduplicated.
+	TransactionID string    `json:"transaction_id"`
+	ConvertedAt   time.Time `json:"converted_at"`
+}
+
+// IssuedClick is a click ID handed out on a redirect, kept until the click itself is stored.
+type IssuedClick struct {
+	ClickID   string    `json:"-"`
+	ShortPath string    `json:"short_path"`
+	ClickedAt time.Time `json:"clicked_at"`
+}
+
+// ConversionStatistics sums up the conversions of a short path.
+type ConversionStatistics struct {
+	Conversions     int64                 `json:"conversions"`
+	ConvertedClicks int64                 `json:"converted_clicks"`
+	Value           float64               `json:"value"`
+	Types           []ConversionTypeStats `json:"types"`
+}
+
+type ConversionTypeStats struct {
+	Type        string  `json:"type"`
+	Conversions int64   `json:"conversions"`
+	Value       float64 `json:"value"`
+}
+
+// ClickEvent is a stored click as exported for analysis. The parquet tags define the columns of Parquet exports.
+type ClickEvent struct {
+	ID           int64     `json:"id" parquet:"id"`
+	ShortPath    string    `json:"short_path" parquet:"short_path"`
+	AccessedAt   time.Time `json:"accessed_at" parquet:"accessed_at,timestamp(microsecond:utc)"`
+	ReferrerHost string    `json:"referrer_host" parquet:"referrer_host"`
+	Browser      string    `json:"browser" parquet:"browser"`
+	OS           string    `json:"os" parquet:"os"`
+	DeviceClass  string    `json:"device_class" parquet:"device_class"`
+	ClientIP     string    `json:"client_ip" parquet:"client_ip"`
+	Language     string    `json:"language" parquet:"language"`
+	QueryString  string    `json:"query_string" parquet:"query_string"`
+	Country      string    `json:"country" parquet:"country"`
+	Region       string    `json:"region" parquet:"region"`
+	City         string    `json:"city" parquet:"city"`
+	IsBot        bool      `json:"is_bot" parquet:"is_bot"`
+	IsDuplicate  bool      `json:"is_duplicate" parquet:"is_duplicate"`
+	ClickID      string    `json:"click_id" parquet:"click_id"`
+}
+
+// ClickStreamEvent is a click as sent to live click streams. ClientIP is only used to look up the location and is
+// never published.
+type ClickStreamEvent struct {
+	ID           string    `json:"id"`
+	ShortPath    string    `json:"short_path"`
+	Owner        string    `json:"owner"`
+	AccessedAt   time.Time `json:"accessed_at"`
+	ReferrerHost string    `json:"referrer_host,omitempty"`
+	Browser      string    `json:"browser,omitempty"`
+	OS           string    `json:"os,omitempty"`
+	DeviceClass  string    `json:"device_class,omitempty"`
+	Country      string    `json:"country,omitempty"`
+	Region       string    `json:"region,omitempty"`
+	City         string    `json:"city,omitempty"`
+	IsBot        bool      `json:"is_bot,omitempty"`
+	ClientIP     string    `json:"-"`
+	// DoNotTrack limits the location looked up from ClientIP to the country.
+	DoNotTrack bool `json:"-"`
+}
+
+// ClickStreamMessage is a click received for one of the streams a replica listens to.
+type ClickStreamMessage struct {
+	Stream string
+	Event  ClickStreamEvent
+}
+
+// ClickStreamSubscription is an open live click stream.
+type ClickStreamSubscription struct {
+	// Replay holds the events missed since the Last-Event-ID, oldest first. Events may repeat some of them when
+	// they were published while the replay was read.
+	Replay []ClickStreamEvent
+	// Events delivers live events. It is closed when the subscriber falls too far behind or the streams shut down,
+	// the client is then expected to reconnect with the last event ID it received.
+	Events <-chan ClickStreamEvent
+	// Close ends the subscription, it is safe to call more than once.
+	Close func()
+}
+
+// ClickEventFilter selects the clicks to export in [From, To). Empty ShortPath or Owner match every link.
+type ClickEventFilter struct {
+	ShortPath string
+	// Owner matches links by created_by, including deleted ones.
+	Owner string
+	From  time.Time
+	To    time.Time
+}
+
+// ClickEventCursor is the position after which the next page of an export starts.
+type ClickEventCursor struct {
+	AccessedAt time.Time
+	ID         int64
+}
+
+const (
+	TrafficStateNormal = "normal"
+	TrafficStateSpike  = "spike"
+	TrafficStateDrop   = "drop"
+)
+
+// TrafficBaseline is the exponentially weighted mean and variance of a link's clicks per detection bucket. State is
+// the anomaly the link was last found in, one of the TrafficState values, so an ongoing spike or drop is only
+// alerted once.
+type TrafficBaseline struct {
+	ShortPath string
+	Mean      float64
+	Variance  float64
+	Samples   int
+	State     string
+	AlertedAt *time.Time
+}
+
+// TrafficBucket is one detection bucket [From, To) with the clicks of every link clicked in it and the baselines
+// of every link from before it.
+type TrafficBucket struct {
+	From      time.Time
+	To        time.Time
+	Clicks    map[string]int64
+	Baselines []TrafficBaseline
+}
+
+// AlertSettings overrides the default anomaly thresholds of a link. Nil thresholds use the defaults.
+type AlertSettings struct {
+	ShortPath string
+	SpikeZ    *float64
+	DropZ     *float64
+	MinClicks *int
+	Enabled   bool
+}
+
+// TrafficAlert reports a link whose clicks in a bucket spiked above or dropped below its baseline.
+type TrafficAlert struct {
+	// ID and Attempts are set once the alert is queued.
+	ID       int64
+	Attempts int
+	// Kind is TrafficStateSpike or TrafficStateDrop.
+	Kind        string
+	ShortPath   string
+	BucketStart time.Time
+	BucketEnd   time.Time
+	Clicks      int64
+	Baseline    float64
+	ZScore      float64
+	// DedupKey is the same for retries of the same alert.
+	DedupKey string
+}
+
+// AccessLogPartition is a monthly partition of url_access_logs holding clicks in [From, To).
+type AccessLogPartition struct {
+	Name string    `json:"name"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+type AccessLogPipelineStats struct {
+	Queued   int64 `json:"queued"`
+	Enqueued int64 `json:"enqueued"`
+	Dropped  int64 `json:"dropped"`
+	Flushed  int64 `json:"flushed"`
+	Failed   int64 `json:"failed"`
+}
+
+type CacheWarmupOptions struct {
+	TopN          int           `json:"top_n"`
+	Window        time.Duration `json:"window"`
+	BatchSize     int           `json:"batch_size"`
+	RatePerSecond int           `json:"rate_per_second"`
+}
+
+type CacheWarmupProgress struct {
+	Running    bool       `json:"running"`
+	Scanned    int64      `json:"scanned"`
+	Loaded     int64      `json:"loaded"`
+	Skipped    int64      `json:"skipped"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// Link lifecycle event types. Links moved to the archive by move_expired_urls_to_archive() send link.expired, links
+// moved back from it send link.restored.
+const (
+	LinkEventCreated  = "link.created"
+	LinkEventUpdated  = "link.updated"
+	LinkEventDeleted  = "link.deleted"
+	LinkEventExpired  = "link.expired"
+	LinkEventRestored = "link.restored"
+)
+
+var LinkEventTypes = []string{LinkEventCreated, LinkEventUpdated, LinkEventDeleted, LinkEventExpired, LinkEventRestored}
+
+// LinkEventData is the state of a link after the change, as stored in the outbox and sent as the data of an event.
+type LinkEventData struct {
+	ShortPath   string     `json:"shortPath"`
+	OriginalURL string     `json:"originalUrl,omitempty"`
+	Expiry      *time.Time `json:"expiry,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	CreatedBy   string     `json:"createdBy,omitempty"`
+	ModifiedAt  *time.Time `json:"modifiedAt,omitempty"`
+	ModifiedBy  *string    `json:"modifiedBy,omitempty"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+	DeletedBy   *string    `json:"deletedBy,omitempty"`
+}
+
+// OutboxEvent is a link change recorded in the transaction that made it.
+type OutboxEvent struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	ShortPath string          `json:"short_path"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// WebhookSubscription receives the link events of EventTypes, or every event when it is empty, at URL. Secret
+// signs the payloads.
+type WebhookSubscription struct {
+	ID         int64     `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is an event sent, or still to be sent, to a subscription.
+type WebhookDelivery struct {
+	ID             int64      `json:"id"`
+	SubscriptionID int64      `json:"subscription_id"`
+	EventID        int64      `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	LastStatusCode *int       `json:"last_status_code"`
+	LastError      string     `json:"last_error"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+}
+
+// WebhookDispatch is a delivery claimed for an attempt, with everything needed to send it.
+type WebhookDispatch struct {
+	Delivery WebhookDelivery
+	URL      string
+	Secret   string
+	Event    OutboxEvent
+}
+
+// Audited actions on links.
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+)
+
+// AuditActor is who made a change and from where, as seen by the API.
+type AuditActor struct {
+	Actor     string
+	SourceIP  string
+	UserAgent string
+	RequestID string
+}
+
+// AuditEntry records a change of a link. Before is empty for created links and After for deleted ones. Changes maps
+// every field that differs to its old and new value, {"originalUrl": {"from": ..., "to": ...}}.
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	Action    string          `json:"action"`
+	ShortPath string          `json:"short_path"`
+	Actor     string          `json:"actor"`
+	SourceIP  string          `json:"source_ip"`
+	UserAgent string          `json:"user_agent"`
+	RequestID string          `json:"request_id"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	Changes   json.RawMessage `json:"changes"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditLogFilter selects audit entries. Empty fields match every entry, From is inclusive and To exclusive. Entries
+// are returned latest first, older than the entry Before unless it is 0.
+type AuditLogFilter struct {
+	Actor     string
+	ShortPath string
+	From      *time.Time
+	To        *time.Time
+	Before    int64
+	Limit     int
 }
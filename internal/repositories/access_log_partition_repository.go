@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=AccessLogPartitionRepository --output=./mocks
+type AccessLogPartitionRepository interface {
+	// CreateMonthlyPartition creates the partition of url_access_logs for the month starting at month unless it exists.
+	CreateMonthlyPartition(ctx context.Context, month time.Time) error
+	// ListMonthlyPartitions returns the monthly partitions attached to url_access_logs, oldest first.
+	ListMonthlyPartitions(ctx context.Context) ([]models.AccessLogPartition, error)
+	DropPartition(ctx context.Context, partition models.AccessLogPartition) error
+	// ArchivePartition detaches the partition and renames it so its raw clicks are kept out of the stats and can be
+	// exported before being dropped by hand.
+	ArchivePartition(ctx context.Context, partition models.AccessLogPartition) error
+}
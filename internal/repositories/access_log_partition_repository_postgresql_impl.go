@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+)
+
+const (
+	accessLogPartitionPrefix = "url_access_logs_p"
+	accessLogArchivePrefix   = "url_access_logs_archive_p"
+	partitionMonthLayout     = "200601"
+)
+
+type accessLogPartitionRepositoryPostgresqlImpl struct {
+	cluster *db.PostgresCluster
+}
+
+func NewAccessLogPartitionRepositoryPostgresql(cluster *db.PostgresCluster) AccessLogPartitionRepository {
+	return &accessLogPartitionRepositoryPostgresqlImpl{cluster: cluster}
+}
+
+// CreateMonthlyPartition implements AccessLogPartitionRepository. Clicks of the month in the default partition are
+// moved into the new partition, which can take longer than the statement timeout. Table names and bounds cannot be bind parameters, they are generated from month and
+// never come from user input.
+func (r *accessLogPartitionRepositoryPostgresqlImpl) CreateMonthlyPartition(ctx context.Context, month time.Time) error {
+	partition := monthlyPartition(month)
+	tx, err := r.cluster.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return ErrDBError
+	}
+	defer tx.Rollback()
+	if err := liftStatementTimeout(ctx, tx); err != nil {
+		return err
+	}
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, PG_ACCESS_LOG_PARTITION_EXISTS, partition.Name).Scan(&exists); err != nil {
+		log.Printf("Error looking up access log partition %s: %v", partition.Name, err)
+		return ErrDBError
+	}
+	if exists {
+		return nil
+	}
+
+	steps := []struct {
+		query string
+		args  []interface{}
+	}{
+		{query: PG_LOCK_ACCESS_LOGS},
+		{query: PG_CREATE_MOVED_ACCESS_LOGS},
+		{query: PG_MOVE_OUT_OF_DEFAULT_PARTITION, args: []interface{}{partition.From, partition.To}},
+		{query: fmt.Sprintf(PG_CREATE_ACCESS_LOG_PARTITION, partition.Name,
+			partition.From.Format(time.DateOnly), partition.To.Format(time.DateOnly))},
+		{query: PG_MOVE_INTO_ACCESS_LOG_PARTITIONS},
+	}
+	for _, step := range steps {
+		if _, err := tx.ExecContext(ctx, step.query, step.args...); err != nil {
+			log.Printf("Error creating access log partition %s: %v", partition.Name, err)
+			return ErrDBError
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error creating access log partition %s: %v", partition.Name, err)
+		return ErrDBError
+	}
+	return nil
+}
+
+// ListMonthlyPartitions implements AccessLogPartitionRepository. Partitions not named by CreateMonthlyPartition,
+// such as the default partition, are left out.
+func (r *accessLogPartitionRepositoryPostgresqlImpl) ListMonthlyPartitions(ctx context.Context) ([]models.AccessLogPartition, error) {
+	rows, err := r.cluster.Primary().QueryContext(ctx, PG_LIST_ACCESS_LOG_PARTITIONS)
+	if err != nil {
+		log.Printf("Error listing access log partitions: %v", err)
+		return nil, ErrDBError
+	}
+	defer rows.Close()
+
+	partitions := []models.AccessLogPartition{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			log.Printf("Error scanning access log partition: %v", err)
+			return nil, ErrDBError
+		}
+		suffix, ok := strings.CutPrefix(name, accessLogPartitionPrefix)
+		if !ok {
+			continue
+		}
+		month, err := time.Parse(partitionMonthLayout, suffix)
+		if err != nil {
+			continue
+		}
+		partitions = append(partitions, monthlyPartition(month))
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error reading access log partitions: %v", err)
+		return nil, ErrDBError
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].From.Before(partitions[j].From) })
+	return partitions, nil
+}
+
+// DropPartition implements AccessLogPartitionRepository.
+func (r *accessLogPartitionRepositoryPostgresqlImpl) DropPartition(ctx context.Context, partition models.AccessLogPartition) error {
+	if _, err := r.cluster.Primary().ExecContext(ctx, fmt.Sprintf(PG_DROP_ACCESS_LOG_PARTITION, monthlyPartition(partition.From).Name)); err != nil {
+		log.Printf("Error dropping access log partition %s: %v", partition.Name, err)
+		return ErrDBError
+	}
+	return nil
+}
+
+// ArchivePartition implements AccessLogPartitionRepository.
+func (r *accessLogPartitionRepositoryPostgresqlImpl) ArchivePartition(ctx context.Context, partition models.AccessLogPartition) error {
+	name := monthlyPartition(partition.From).Name
+	archiveName := accessLogArchivePrefix + partition.From.Format(partitionMonthLayout)
+	tx, err := r.cluster.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return ErrDBError
+	}
+	defer tx.Rollback()
+	if err := liftStatementTimeout(ctx, tx); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(PG_DETACH_ACCESS_LOG_PARTITION, name)); err != nil {
+		log.Printf("Error detaching access log partition %s: %v", name, err)
+		return ErrDBError
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(PG_RENAME_ACCESS_LOG_PARTITION, name, archiveName)); err != nil {
+		log.Printf("Error renaming access log partition %s to %s: %v", name, archiveName, err)
+		return ErrDBError
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error archiving access log partition %s: %v", name, err)
+		return ErrDBError
+	}
+	return nil
+}
+
+func monthlyPartition(month time.Time) models.AccessLogPartition {
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return models.AccessLogPartition{
+		Name: accessLogPartitionPrefix + from.Format(partitionMonthLayout),
+		From: from,
+		To:   from.AddDate(0, 1, 0),
+	}
+}
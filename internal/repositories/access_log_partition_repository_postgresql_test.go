@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+	"url-shortener/internal/models"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLogPartitionRepositoryPostgresqlImpl_CreateMonthlyPartition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAccessLogPartitionRepositoryPostgresql(newTestCluster(db))
+	from, to := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL statement_timeout = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT to_regclass\\(\\$1\\) IS NOT NULL").WithArgs("url_access_logs_p202512").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("LOCK TABLE url_access_logs IN ACCESS EXCLUSIVE MODE").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TEMPORARY TABLE url_access_logs_moved").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM url_access_logs_default WHERE accessed_at >= \\$1 AND accessed_at < \\$2").WithArgs(from, to).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS url_access_logs_p202512 PARTITION OF url_access_logs FOR VALUES FROM \\('2025-12-01'\\) TO \\('2026-01-01'\\)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO url_access_logs SELECT \\* FROM url_access_logs_moved").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err = repo.CreateMonthlyPartition(context.Background(), time.Date(2025, 12, 17, 8, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccessLogPartitionRepositoryPostgresqlImpl_CreateMonthlyPartition_Exists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAccessLogPartitionRepositoryPostgresql(newTestCluster(db))
+	// An existing partition is left alone without locking the clicks.
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL statement_timeout = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT to_regclass").WithArgs("url_access_logs_p202512").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	err = repo.CreateMonthlyPartition(context.Background(), time.Date(2025, 12, 17, 8, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccessLogPartitionRepositoryPostgresqlImpl_CreateMonthlyPartition_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAccessLogPartitionRepositoryPostgresql(newTestCluster(db))
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL statement_timeout = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT to_regclass").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("LOCK TABLE").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TEMPORARY TABLE").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM url_access_logs_default").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS").WillReturnError(fmt.Errorf("some error"))
+	mock.ExpectRollback()
+
+	err = repo.CreateMonthlyPartition(context.Background(), time.Now())
+
+	assert.ErrorIs(t, err, ErrDBError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccessLogPartitionRepositoryPostgresqlImpl_ListMonthlyPartitions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAccessLogPartitionRepositoryPostgresql(newTestCluster(db))
+	mock.ExpectQuery("SELECT c.relname FROM pg_inherits").WillReturnRows(sqlmock.NewRows([]string{"relname"}).
+		AddRow("url_access_logs_p202602").
+		AddRow("url_access_logs_default").
+		AddRow("url_access_logs_p202601").
+		AddRow("url_access_logs_pbroken"))
+
+	partitions, err := repo.ListMonthlyPartitions(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AccessLogPartition{
+		{Name: "url_access_logs_p202601", From: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "url_access_logs_p202602", From: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}, partitions)
+}
+
+func TestAccessLogPartitionRepositoryPostgresqlImpl_DropPartition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAccessLogPartitionRepositoryPostgresql(newTestCluster(db))
+	mock.ExpectExec("DROP TABLE IF EXISTS url_access_logs_p202601").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.DropPartition(context.Background(), monthlyPartition(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccessLogPartitionRepositoryPostgresqlImpl_ArchivePartition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAccessLogPartitionRepositoryPostgresql(newTestCluster(db))
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL statement_timeout = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ALTER TABLE url_access_logs DETACH PARTITION url_access_logs_p202601").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ALTER TABLE url_access_logs_p202601 RENAME TO url_access_logs_archive_p202601").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = repo.ArchivePartition(context.Background(), monthlyPartition(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
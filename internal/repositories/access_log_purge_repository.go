@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockery --name=AccessLogPurgeRepository --output=./mocks
+type AccessLogPurgeRepository interface {
+	// PurgeColumn clears column of every click before the given time, batchSize clicks at a time, and returns how many
+	// clicks were changed. It returns ErrUnknownAccessLogColumn for columns that cannot be cleared.
+	PurgeColumn(ctx context.Context, column string, before time.Time, batchSize int) (int64, error)
+}
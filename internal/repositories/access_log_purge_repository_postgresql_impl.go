@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"url-shortener/internal/db"
+)
+
+// purgeableAccessLogColumns are the nullable click metadata columns a retention can be set for. Column names cannot
+// be bind parameters, so only these are ever put into a statement.
+var purgeableAccessLogColumns = map[string]bool{
+	"referrer_host": true,
+	"browser":       true,
+	"os":            true,
+	"device_class":  true,
+	"client_ip":     true,
+	"language":      true,
+	"query_string":  true,
+	"country":       true,
+	"region":        true,
+	"city":          true,
+	"click_id":      true,
+}
+
+type accessLogPurgeRepositoryPostgresqlImpl struct {
+	cluster *db.PostgresCluster
+}
+
+func NewAccessLogPurgeRepositoryPostgresql(cluster *db.PostgresCluster) AccessLogPurgeRepository {
+	return &accessLogPurgeRepositoryPostgresqlImpl{cluster: cluster}
+}
+
+// PurgeColumn implements AccessLogPurgeRepository. Only clicks after the watermark of the column are scanned, so
+// every run only goes through the clicks that passed the retention since the previous one. Batches keep the row
+// locks short, a purge that fails half way is picked up again by the next run.
+func (r *accessLogPurgeRepositoryPostgresqlImpl) PurgeColumn(ctx context.Context, column string, before time.Time, batchSize int) (int64, error) {
+	if !purgeableAccessLogColumns[column] {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownAccessLogColumn, column)
+	}
+	watermarkName := "purge_" + column
+	var from time.Time
+	err := r.cluster.Primary().QueryRowContext(ctx, PG_GET_PURGE_WATERMARK, watermarkName).Scan(&from)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error reading purge watermark of %s: %v", column, err)
+		return 0, ErrDBError
+	}
+	before = before.UTC()
+	if !from.Before(before) {
+		return 0, nil
+	}
+
+	query := fmt.Sprintf(PG_PURGE_ACCESS_LOG_COLUMN, column)
+	var purged int64
+	for {
+		result, err := r.cluster.Primary().ExecContext(ctx, query, from, before, batchSize)
+		if err != nil {
+			log.Printf("Error purging %s of access logs before %s: %v", column, before, err)
+			return purged, ErrDBError
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			log.Printf("Error purging %s of access logs before %s: %v", column, before, err)
+			return purged, ErrDBError
+		}
+		purged += rows
+		if rows < int64(batchSize) {
+			break
+		}
+	}
+
+	if _, err := r.cluster.Primary().ExecContext(ctx, PG_UPDATE_PURGE_WATERMARK, watermarkName, before); err != nil {
+		log.Printf("Error updating purge watermark of %s: %v", column, err)
+		return purged, ErrDBError
+	}
+	return purged, nil
+}
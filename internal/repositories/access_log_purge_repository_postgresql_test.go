@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLogPurgeRepositoryPostgresqlImpl_PurgeColumn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAccessLogPurgeRepositoryPostgresql(newTestCluster(db))
+	watermark := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT rolled_up_to FROM click_rollup_watermarks WHERE name = \\$1").WithArgs("purge_client_ip").
+		WillReturnRows(sqlmock.NewRows([]string{"rolled_up_to"}).AddRow(watermark))
+	mock.ExpectExec("UPDATE url_access_logs SET client_ip = NULL WHERE \\(id, accessed_at\\) IN \\(SELECT id, accessed_at FROM url_access_logs "+
+		"WHERE accessed_at >= \\$1 AND accessed_at < \\$2 AND client_ip IS NOT NULL LIMIT \\$3\\)").
+		WithArgs(watermark, before, 2).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE url_access_logs SET client_ip = NULL").WithArgs(watermark, before, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO click_rollup_watermarks \\(name, rolled_up_to\\) VALUES \\(\\$1, \\$2\\) ON CONFLICT").
+		WithArgs("purge_client_ip", before).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	purged, err := repo.PurgeColumn(context.Background(), "client_ip", before, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccessLogPurgeRepositoryPostgresqlImpl_PurgeColumn_FirstRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAccessLogPurgeRepositoryPostgresql(newTestCluster(db))
+	before := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT rolled_up_to FROM click_rollup_watermarks").WithArgs("purge_query_string").WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("UPDATE url_access_logs SET query_string = NULL").WithArgs(time.Time{}, before, 100).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO click_rollup_watermarks").WithArgs("purge_query_string", before).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	purged, err := repo.PurgeColumn(context.Background(), "query_string", before, 100)
+
+	assert.NoError(t, err)
+	assert.Zero(t, purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccessLogPurgeRepositoryPostgresqlImpl_PurgeColumn_UpToDate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAccessLogPurgeRepositoryPostgresql(newTestCluster(db))
+	before := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// A longer retention than before moves the cutoff back, the clicks in between are purged already.
+	mock.ExpectQuery("SELECT rolled_up_to FROM click_rollup_watermarks").WithArgs("purge_city").
+		WillReturnRows(sqlmock.NewRows([]string{"rolled_up_to"}).AddRow(before.Add(time.Hour)))
+
+	purged, err := repo.PurgeColumn(context.Background(), "city", before, 100)
+
+	assert.NoError(t, err)
+	assert.Zero(t, purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccessLogPurgeRepositoryPostgresqlImpl_PurgeColumn_UnknownColumn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAccessLogPurgeRepositoryPostgresql(newTestCluster(db))
+
+	_, err = repo.PurgeColumn(context.Background(), "is_bot; DROP TABLE urls", time.Now(), 100)
+
+	assert.ErrorIs(t, err, ErrUnknownAccessLogColumn)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccessLogPurgeRepositoryPostgresqlImpl_PurgeColumn_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAccessLogPurgeRepositoryPostgresql(newTestCluster(db))
+	mock.ExpectQuery("SELECT rolled_up_to FROM click_rollup_watermarks").WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("UPDATE url_access_logs SET client_ip = NULL").WillReturnError(fmt.Errorf("some error"))
+
+	_, err = repo.PurgeColumn(context.Background(), "client_ip", time.Now(), 100)
+
+	assert.ErrorIs(t, err, ErrDBError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"url-shortener/internal/models"
+)
+
+// insertAuditEntry records a change of a link by actor in the audit log as part of tx, so the entry exists if and
+// only if the change commits. before is the link as locked by tx, nil when it did not exist, and after nil when it
+// no longer exists. A nil actor is the system.
+func insertAuditEntry(ctx context.Context, tx *sql.Tx, action string, shortPath string, actor *models.AuditActor, before *models.URL, after *models.URL, at time.Time) error {
+	entry := &models.AuditEntry{Action: action, ShortPath: shortPath, Actor: "system", CreatedAt: at.UTC()}
+	if actor != nil {
+		if actor.Actor != "" {
+			entry.Actor = actor.Actor
+		}
+		entry.SourceIP = actor.SourceIP
+		entry.UserAgent = actor.UserAgent
+		entry.RequestID = actor.RequestID
+	}
+	var err error
+	if entry.Before, entry.After, entry.Changes, err = auditDiff(before, after); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, PG_INSERT_AUDIT_ENTRY, entry.Action, entry.ShortPath, entry.Actor, entry.SourceIP,
+		entry.UserAgent, entry.RequestID, nullJSON(entry.Before), nullJSON(entry.After), []byte(entry.Changes), entry.CreatedAt)
+	if err != nil {
+		log.Printf("Error recording %s of %s in the audit log: %v", action, shortPath, err)
+		return ErrDBError
+	}
+	return nil
+}
+
+// auditedFields are the fields of a link the audit log tracks.
+type auditedFields struct {
+	OriginalURL string     `json:"originalUrl"`
+	Expiry      *time.Time `json:"expiry"`
+}
+
+// auditDiff returns the audited fields of a link before and after a change, and every field that differs as
+// {"field": {"from": old, "to": new}}. A link that does not exist on one side has no document there and its fields
+// are compared as null.
+func auditDiff(before *models.URL, after *models.URL) (json.RawMessage, json.RawMessage, json.RawMessage, error) {
+	beforeDoc, beforeFields, err := auditDocument(before)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	afterDoc, afterFields, err := auditDocument(after)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	type change struct {
+		From json.RawMessage `json:"from"`
+		To   json.RawMessage `json:"to"`
+	}
+	changes := map[string]change{}
+	for _, fields := range []map[string]json.RawMessage{beforeFields, afterFields} {
+		for field := range fields {
+			from, to := jsonOrNull(beforeFields[field]), jsonOrNull(afterFields[field])
+			if !bytes.Equal(from, to) {
+				changes[field] = change{From: from, To: to}
+			}
+		}
+	}
+	changesDoc, err := json.Marshal(changes)
+	return beforeDoc, afterDoc, changesDoc, err
+}
+
+func auditDocument(url *models.URL) (json.RawMessage, map[string]json.RawMessage, error) {
+	if url == nil {
+		return nil, nil, nil
+	}
+	doc, err := json.Marshal(auditedFields{OriginalURL: url.OriginalURL, Expiry: utcTime(url.Expiry)})
+	if err != nil {
+		return nil, nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		return nil, nil, err
+	}
+	return doc, fields, nil
+}
+
+func jsonOrNull(value json.RawMessage) json.RawMessage {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return value
+}
+
+// nullJSON stores an empty document as NULL.
+func nullJSON(document json.RawMessage) interface{} {
+	if len(document) == 0 {
+		return nil
+	}
+	return []byte(document)
+}
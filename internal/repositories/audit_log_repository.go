@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=AuditLogRepository --output=./mocks
+type AuditLogRepository interface {
+	// ListEntries returns the entries written with the changes of links, see insertAuditEntry. Entries are never
+	// changed or removed.
+	ListEntries(ctx context.Context, filter models.AuditLogFilter) ([]models.AuditEntry, error)
+}
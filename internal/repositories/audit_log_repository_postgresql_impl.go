@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+)
+
+type auditLogRepositoryPostgresqlImpl struct {
+	cluster *db.PostgresCluster
+}
+
+func NewAuditLogRepositoryPostgresql(cluster *db.PostgresCluster) AuditLogRepository {
+	return &auditLogRepositoryPostgresqlImpl{cluster: cluster}
+}
+
+// ListEntries implements AuditLogRepository.
+func (r *auditLogRepositoryPostgresqlImpl) ListEntries(ctx context.Context, filter models.AuditLogFilter) ([]models.AuditEntry, error) {
+	var from, to sql.NullTime
+	if filter.From != nil {
+		from = sql.NullTime{Time: filter.From.UTC(), Valid: true}
+	}
+	if filter.To != nil {
+		to = sql.NullTime{Time: filter.To.UTC(), Valid: true}
+	}
+	rows, err := r.cluster.Reader(ctx).QueryContext(ctx, PG_LIST_AUDIT_ENTRIES, filter.Actor, filter.ShortPath, from, to, filter.Before, filter.Limit)
+	if err != nil {
+		log.Printf("Error listing audit entries: %v, filter: %+v", err, filter)
+		return nil, ErrDBError
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var entry models.AuditEntry
+		var before, after, changes []byte
+		if err := rows.Scan(&entry.ID, &entry.Action, &entry.ShortPath, &entry.Actor, &entry.SourceIP, &entry.UserAgent,
+			&entry.RequestID, &before, &after, &changes, &entry.CreatedAt); err != nil {
+			log.Printf("Error scanning audit entry: %v", err)
+			return nil, ErrDBError
+		}
+		entry.Before, entry.After, entry.Changes = before, after, changes
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error listing audit entries: %v, filter: %+v", err, filter)
+		return nil, ErrDBError
+	}
+	return entries, nil
+}
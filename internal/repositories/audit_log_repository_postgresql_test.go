@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLogRepositoryPostgresqlImpl_ListEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuditLogRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	from := now.Add(-time.Hour)
+
+	mock.ExpectQuery("FROM audit_log WHERE \\(\\$1 = '' OR actor = \\$1\\) AND \\(\\$2 = '' OR short_path = \\$2\\)").
+		WithArgs("alice", "", from, nil, int64(0), 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "action", "short_path", "actor", "source_ip", "user_agent", "request_id",
+			"before", "after", "changes", "created_at"}).
+			AddRow(4, models.AuditActionDelete, "abc", "alice", "203.0.113.7", "curl/8.0", "req-2",
+				[]byte(`{"originalUrl":"https://example.com"}`), nil, []byte(`{"originalUrl":{"from":"https://example.com","to":null}}`), now))
+
+	entries, err := repo.ListEntries(context.Background(), models.AuditLogFilter{Actor: "alice", From: &from, Limit: 50})
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "curl/8.0", entries[0].UserAgent)
+	assert.JSONEq(t, `{"originalUrl":"https://example.com"}`, string(entries[0].Before))
+	assert.Nil(t, entries[0].After)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	"url-shortener/internal/utils"
+)
+
+//go:generate mockery --name=CachePolicy --output=./mocks
+type CachePolicy interface {
+	// TTL returns the expiry for a url being cached. Zero or less means the url must not be cached.
+	TTL(url *models.URL) time.Duration
+	// OnHit records a cache hit and returns the expiry the key should be extended to, or zero to leave it as is.
+	OnHit(url *models.URL) time.Duration
+}
+
+type cachePolicyImpl struct {
+	config       config.CacheConfig
+	timeProvider utils.TimeProvider
+
+	mu          sync.Mutex
+	windowStart time.Time
+	hits        map[string]int
+}
+
+func NewCachePolicy(cacheConfig config.CacheConfig, timeProvider utils.TimeProvider) CachePolicy {
+	return &cachePolicyImpl{config: cacheConfig, timeProvider: timeProvider, hits: map[string]int{}}
+}
+
+// TTL implements CachePolicy.
+func (p *cachePolicyImpl) TTL(url *models.URL) time.Duration {
+	return p.capped(url, p.jittered(p.config.BaseTTL))
+}
+
+// OnHit implements CachePolicy. Hits are counted in fixed windows of HotWindow and a key is extended
+// every HotThreshold hits, so a hot key keeps sliding forward without an EXPIRE on every request. Without a
+// window every hit would start a new one and no key would ever count as hot, so that disables it too.
+func (p *cachePolicyImpl) OnHit(url *models.URL) time.Duration {
+	if p.config.HotThreshold <= 0 || p.config.HotWindow <= 0 || p.config.HotTTL <= 0 {
+		return 0
+	}
+
+	p.mu.Lock()
+	now := p.timeProvider.Now()
+	if now.Sub(p.windowStart) >= p.config.HotWindow {
+		p.windowStart = now
+		p.hits = map[string]int{}
+	}
+	p.hits[url.ShortPath]++
+	hits := p.hits[url.ShortPath]
+	p.mu.Unlock()
+
+	if hits%p.config.HotThreshold != 0 {
+		return 0
+	}
+	return p.capped(url, p.jittered(p.config.HotTTL))
+}
+
+func (p *cachePolicyImpl) jittered(ttl time.Duration) time.Duration {
+	if p.config.Jitter <= 0 {
+		return ttl
+	}
+	return time.Duration(float64(ttl) * (1 + p.config.Jitter*(2*rand.Float64()-1)))
+}
+
+func (p *cachePolicyImpl) capped(url *models.URL, ttl time.Duration) time.Duration {
+	if p.config.MaxTTL > 0 && ttl > p.config.MaxTTL {
+		ttl = p.config.MaxTTL
+	}
+	if url.Expiry != nil {
+		if untilExpiry := url.Expiry.Sub(p.timeProvider.Now()); untilExpiry < ttl {
+			ttl = untilExpiry
+		}
+	}
+	return ttl
+}
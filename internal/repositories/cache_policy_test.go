@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	utilMocks "url-shortener/internal/utils/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCachePolicy(cacheConfig config.CacheConfig, now time.Time) (*utilMocks.TimeProvider, CachePolicy) {
+	timeProvider := &utilMocks.TimeProvider{}
+	timeProvider.On("Now").Return(now)
+	return timeProvider, NewCachePolicy(cacheConfig, timeProvider)
+}
+
+func TestCachePolicy_TTL(t *testing.T) {
+	_, policy := setupCachePolicy(config.CacheConfig{BaseTTL: time.Hour}, time.Now())
+	assert.Equal(t, time.Hour, policy.TTL(&models.URL{ShortPath: "path"}))
+}
+
+func TestCachePolicy_TTL_CappedByExpiry(t *testing.T) {
+	now := time.Now()
+	expiry := now.Add(10 * time.Minute)
+	_, policy := setupCachePolicy(config.CacheConfig{BaseTTL: time.Hour}, now)
+	assert.Equal(t, 10*time.Minute, policy.TTL(&models.URL{ShortPath: "path", Expiry: &expiry}))
+}
+
+func TestCachePolicy_TTL_CappedByMaxTTL(t *testing.T) {
+	_, policy := setupCachePolicy(config.CacheConfig{BaseTTL: 2 * time.Hour, MaxTTL: time.Hour}, time.Now())
+	assert.Equal(t, time.Hour, policy.TTL(&models.URL{ShortPath: "path"}))
+}
+
+func TestCachePolicy_TTL_Jitter(t *testing.T) {
+	_, policy := setupCachePolicy(config.CacheConfig{BaseTTL: time.Hour, Jitter: 0.1}, time.Now())
+	for i := 0; i < 100; i++ {
+		ttl := policy.TTL(&models.URL{ShortPath: "path"})
+		assert.GreaterOrEqual(t, ttl, 54*time.Minute)
+		assert.LessOrEqual(t, ttl, 66*time.Minute)
+	}
+}
+
+func TestCachePolicy_OnHit(t *testing.T) {
+	_, policy := setupCachePolicy(config.CacheConfig{BaseTTL: time.Hour, HotThreshold: 3, HotWindow: time.Minute, HotTTL: 6 * time.Hour}, time.Now())
+	url := &models.URL{ShortPath: "path"}
+
+	assert.Zero(t, policy.OnHit(url))
+	assert.Zero(t, policy.OnHit(url))
+	assert.Equal(t, 6*time.Hour, policy.OnHit(url))
+	assert.Zero(t, policy.OnHit(&models.URL{ShortPath: "other"}))
+}
+
+func TestCachePolicy_OnHit_WindowResets(t *testing.T) {
+	now := time.Now()
+	timeProvider := &utilMocks.TimeProvider{}
+	policy := NewCachePolicy(config.CacheConfig{HotThreshold: 2, HotWindow: time.Minute, HotTTL: time.Hour}, timeProvider)
+	url := &models.URL{ShortPath: "path"}
+
+	timeProvider.On("Now").Return(now).Once()
+	assert.Zero(t, policy.OnHit(url))
+	timeProvider.On("Now").Return(now.Add(2 * time.Minute)).Once()
+	assert.Zero(t, policy.OnHit(url))
+}
+
+func TestCachePolicy_OnHit_Disabled(t *testing.T) {
+	_, policy := setupCachePolicy(config.CacheConfig{BaseTTL: time.Hour}, time.Now())
+	assert.Zero(t, policy.OnHit(&models.URL{ShortPath: "path"}))
+}
+
+func TestCachePolicy_OnHit_DisabledWithoutWindow(t *testing.T) {
+	timeProvider, policy := setupCachePolicy(config.CacheConfig{BaseTTL: time.Hour, HotThreshold: 1, HotTTL: 6 * time.Hour}, time.Now())
+
+	assert.Zero(t, policy.OnHit(&models.URL{ShortPath: "path"}))
+	timeProvider.AssertNotCalled(t, "Now")
+}
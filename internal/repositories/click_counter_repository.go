@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=ClickCounterRepository --output=./mocks
+type ClickCounterRepository interface {
+	// Increment counts a click of shortPath at the given time.
+	Increment(ctx context.Context, shortPath string, at time.Time) error
+	// CountRecentClicks returns the clicks of shortPath in the last 24 hours and the past week before now.
+	CountRecentClicks(ctx context.Context, shortPath string, now time.Time) (*models.RecentClickCounts, error)
+	// SetHourlyClicks overwrites the counters of the given hours, e.g. with the rolled up counts from Postgres.
+	SetHourlyClicks(ctx context.Context, clicks []models.HourlyClicks, now time.Time) error
+	// SetMinuteClicks overwrites the counters of the given minutes like SetHourlyClicks.
+	SetMinuteClicks(ctx context.Context, clicks []models.MinuteClicks, now time.Time) error
+	// Reconciled reports whether the counters were reconciled since Redis last lost its data, MarkReconciled records
+	// that they were.
+	Reconciled(ctx context.Context) (bool, error)
+	MarkReconciled(ctx context.Context) error
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// Minute counters are only read for the hour the last 24 hours start in, hourly ones for the past week.
+	minuteClicksTTL = 25 * time.Hour
+	hourlyClicksTTL = 8 * 24 * time.Hour
+	// clickCountersReconciledKey never expires, it is only gone when Redis lost the counters with it.
+	clickCountersReconciledKey = "clicks:reconciled"
+)
+
+// clickCounterRepositoryRedisImpl counts clicks per link and minute and per link and hour. The last 24 hours add
+// up the minutes of the hour the window starts in and every hour after it, the past week adds up the whole hours
+// after the one it starts in. Every key of a link shares the {shortPath} hash tag so they can be read with a
+// single MGET on a cluster.
+type clickCounterRepositoryRedisImpl struct {
+	client db.RedisClient
+}
+
+func NewClickCounterRepositoryRedis(client db.RedisClient) ClickCounterRepository {
+	return &clickCounterRepositoryRedisImpl{client: client}
+}
+
+// Increment implements ClickCounterRepository.
+func (r *clickCounterRepositoryRedisImpl) Increment(ctx context.Context, shortPath string, at time.Time) error {
+	minuteKey := minuteClicksKey(shortPath, at)
+	hourKey := hourlyClicksKey(shortPath, at)
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, minuteKey)
+		pipe.Expire(ctx, minuteKey, minuteClicksTTL)
+		pipe.Incr(ctx, hourKey)
+		pipe.Expire(ctx, hourKey, hourlyClicksTTL)
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error counting click of %s: %v", shortPath, err)
+		return ErrRedisError
+	}
+	return nil
+}
+
+// CountRecentClicks implements ClickCounterRepository.
+func (r *clickCounterRepositoryRedisImpl) CountRecentClicks(ctx context.Context, shortPath string, now time.Time) (*models.RecentClickCounts, error) {
+	now = now.UTC()
+	dayStart := now.Add(-24 * time.Hour)
+	dayHoursFrom := dayStart.Truncate(time.Hour).Add(time.Hour)
+	weekHoursFrom := now.Add(-7 * 24 * time.Hour).Truncate(time.Hour).Add(time.Hour)
+
+	keys := []string{}
+	for minute := dayStart.Truncate(time.Minute); minute.Before(dayHoursFrom); minute = minute.Add(time.Minute) {
+		keys = append(keys, minuteClicksKey(shortPath, minute))
+	}
+	minuteKeys := len(keys)
+	for hour := weekHoursFrom; !hour.After(now); hour = hour.Add(time.Hour) {
+		keys = append(keys, hourlyClicksKey(shortPath, hour))
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Printf("Error getting click counters of %s: %v", shortPath, err)
+		return nil, ErrRedisError
+	}
+	counts := &models.RecentClickCounts{}
+	for i, value := range values {
+		clicks := counterValue(value)
+		if i < minuteKeys {
+			counts.Last24Hours += clicks
+			continue
+		}
+		counts.PastWeek += clicks
+		if hour := weekHoursFrom.Add(time.Duration(i-minuteKeys) * time.Hour); !hour.Before(dayHoursFrom) {
+			counts.Last24Hours += clicks
+		}
+	}
+	return counts, nil
+}
+
+// SetHourlyClicks implements ClickCounterRepository. Hours that would already have expired are skipped.
+func (r *clickCounterRepositoryRedisImpl) SetHourlyClicks(ctx context.Context, clicks []models.HourlyClicks, now time.Time) error {
+	if len(clicks) == 0 {
+		return nil
+	}
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, hour := range clicks {
+			ttl := hour.Bucket.Add(hourlyClicksTTL).Sub(now)
+			if ttl <= 0 {
+				continue
+			}
+			pipe.Set(ctx, hourlyClicksKey(hour.ShortPath, hour.Bucket), hour.Clicks, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error setting %d hourly click counters: %v", len(clicks), err)
+		return ErrRedisError
+	}
+	return nil
+}
+
+// SetMinuteClicks implements ClickCounterRepository. Minutes that would already have expired are skipped.
+func (r *clickCounterRepositoryRedisImpl) SetMinuteClicks(ctx context.Context, clicks []models.MinuteClicks, now time.Time) error {
+	if len(clicks) == 0 {
+		return nil
+	}
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, minute := range clicks {
+			ttl := minute.Bucket.Add(minuteClicksTTL).Sub(now)
+			if ttl <= 0 {
+				continue
+			}
+			pipe.Set(ctx, minuteClicksKey(minute.ShortPath, minute.Bucket), minute.Clicks, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error setting %d minute click counters: %v", len(clicks), err)
+		return ErrRedisError
+	}
+	return nil
+}
+
+// Reconciled implements ClickCounterRepository.
+func (r *clickCounterRepositoryRedisImpl) Reconciled(ctx context.Context) (bool, error) {
+	err := r.client.Get(ctx, clickCountersReconciledKey).Err()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		log.Printf("Error checking click counter reconcile marker: %v", err)
+		return false, ErrRedisError
+	}
+	return true, nil
+}
+
+// MarkReconciled implements ClickCounterRepository.
+func (r *clickCounterRepositoryRedisImpl) MarkReconciled(ctx context.Context) error {
+	if err := r.client.Set(ctx, clickCountersReconciledKey, 1, 0).Err(); err != nil {
+		log.Printf("Error setting click counter reconcile marker: %v", err)
+		return ErrRedisError
+	}
+	return nil
+}
+
+// counterValue reads an MGET result, missing keys count as no clicks.
+func counterValue(value interface{}) int64 {
+	s, ok := value.(string)
+	if !ok {
+		return 0
+	}
+	clicks, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return clicks
+}
+
+func minuteClicksKey(shortPath string, t time.Time) string {
+	return "clicks:{" + shortPath + "}:m:" + t.UTC().Format("200601021504")
+}
+
+func hourlyClicksKey(shortPath string, t time.Time) string {
+	return "clicks:{" + shortPath + "}:h:" + t.UTC().Format("2006010215")
+}
@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+
+	dbMocks "url-shortener/internal/db/mocks"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRedisIncrementClicks(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewClickCounterRepositoryRedis(mockClient)
+	pipe := redis.NewClient(&redis.Options{}).Pipeline()
+	mockClient.On("Pipelined", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(redis.Pipeliner) error)
+		assert.NoError(t, fn(pipe))
+	}).Return(nil, nil).Once()
+
+	err := repo.Increment(context.Background(), "path1", time.Now())
+
+	assert.NoError(t, err)
+	// INCR and EXPIRE of the minute and the hour counter.
+	assert.Equal(t, 4, pipe.Len())
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisIncrementClicks_Error(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewClickCounterRepositoryRedis(mockClient)
+	mockClient.On("Pipelined", mock.Anything, mock.Anything).Return(nil, errors.New("redis error")).Once()
+
+	err := repo.Increment(context.Background(), "path1", time.Now())
+
+	assert.ErrorIs(t, err, ErrRedisError)
+}
+
+// anyKeys matches a context and n keys of a variadic RedisClient call.
+func anyKeys(n int) []interface{} {
+	args := make([]interface{}, n+1)
+	for i := range args {
+		args[i] = mock.Anything
+	}
+	return args
+}
+
+func TestRedisCountRecentClicks(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewClickCounterRepositoryRedis(mockClient)
+	now := time.Date(2025, 6, 12, 10, 30, 0, 0, time.UTC)
+	var keys []string
+	mockClient.On("MGet", anyKeys(30+168)...).Run(func(args mock.Arguments) {
+		for _, key := range args[1:] {
+			keys = append(keys, key.(string))
+		}
+	}).Return(func(ctx context.Context, keys ...string) *redis.SliceCmd {
+		values := make([]interface{}, len(keys))
+		for i, key := range keys {
+			switch key {
+			// The last minute of the hour the day window starts in, and the minute before the window.
+			case "clicks:{path1}:m:202506111059", "clicks:{path1}:m:202506111029":
+				values[i] = "1"
+			// An hour inside the day window, one only inside the week and the current one.
+			case "clicks:{path1}:h:2025061200", "clicks:{path1}:h:2025060612", "clicks:{path1}:h:2025061210":
+				values[i] = "2"
+			}
+		}
+		cmd := redis.NewSliceCmd(ctx)
+		cmd.SetVal(values)
+		return cmd
+	}).Once()
+
+	counts, err := repo.CountRecentClicks(context.Background(), "path1", now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.RecentClickCounts{Last24Hours: 5, PastWeek: 6}, counts)
+	// 30 minutes of the hour the day window starts in and the 168 hours after the one the week starts in.
+	assert.Len(t, keys, 30+168)
+	assert.Equal(t, "clicks:{path1}:m:202506111030", keys[0])
+	assert.Equal(t, "clicks:{path1}:h:2025060511", keys[30])
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisCountRecentClicks_Error(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewClickCounterRepositoryRedis(mockClient)
+	cmd := redis.NewSliceCmd(context.Background())
+	cmd.SetErr(errors.New("redis error"))
+	mockClient.On("MGet", anyKeys(60+168)...).Return(cmd).Once()
+
+	counts, err := repo.CountRecentClicks(context.Background(), "path1", time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC))
+
+	assert.ErrorIs(t, err, ErrRedisError)
+	assert.Nil(t, counts)
+}
+
+func TestRedisSetHourlyClicks_SkipsExpiredHours(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewClickCounterRepositoryRedis(mockClient)
+	now := time.Date(2025, 6, 12, 10, 30, 0, 0, time.UTC)
+	clicks := []models.HourlyClicks{
+		{ShortPath: "path1", Bucket: time.Date(2025, 6, 12, 9, 0, 0, 0, time.UTC), Clicks: 3},
+		{ShortPath: "path1", Bucket: time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC), Clicks: 5},
+	}
+	pipe := redis.NewClient(&redis.Options{}).Pipeline()
+	mockClient.On("Pipelined", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(redis.Pipeliner) error)
+		assert.NoError(t, fn(pipe))
+	}).Return(nil, nil).Once()
+
+	err := repo.SetHourlyClicks(context.Background(), clicks, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pipe.Len())
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisSetMinuteClicks_SkipsExpiredMinutes(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewClickCounterRepositoryRedis(mockClient)
+	now := time.Date(2025, 6, 12, 10, 30, 0, 0, time.UTC)
+	clicks := []models.MinuteClicks{
+		{ShortPath: "path1", Bucket: time.Date(2025, 6, 11, 10, 45, 0, 0, time.UTC), Clicks: 2},
+		{ShortPath: "path1", Bucket: time.Date(2025, 6, 11, 9, 0, 0, 0, time.UTC), Clicks: 5},
+	}
+	pipe := redis.NewClient(&redis.Options{}).Pipeline()
+	mockClient.On("Pipelined", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(redis.Pipeliner) error)
+		assert.NoError(t, fn(pipe))
+	}).Return(nil, nil).Once()
+
+	err := repo.SetMinuteClicks(context.Background(), clicks, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pipe.Len())
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisClickCountersReconciled(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewClickCounterRepositoryRedis(mockClient)
+	missing := &redis.StringCmd{}
+	missing.SetErr(redis.Nil)
+	present := &redis.StringCmd{}
+	present.SetVal("1")
+	failed := &redis.StringCmd{}
+	failed.SetErr(errors.New("redis error"))
+	mockClient.On("Get", mock.Anything, "clicks:reconciled").Return(missing).Once()
+	mockClient.On("Get", mock.Anything, "clicks:reconciled").Return(present).Once()
+	mockClient.On("Get", mock.Anything, "clicks:reconciled").Return(failed).Once()
+
+	reconciled, err := repo.Reconciled(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, reconciled)
+
+	reconciled, err = repo.Reconciled(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, reconciled)
+
+	_, err = repo.Reconciled(context.Background())
+	assert.ErrorIs(t, err, ErrRedisError)
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisMarkClickCountersReconciled(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewClickCounterRepositoryRedis(mockClient)
+	mockClient.On("Set", mock.Anything, "clicks:reconciled", 1, time.Duration(0)).Return(&redis.StatusCmd{}).Once()
+
+	assert.NoError(t, repo.MarkReconciled(context.Background()))
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockery --name=ClickDedupRepository --output=./mocks
+type ClickDedupRepository interface {
+	// MarkClick reports whether this is the first click of visitorID on shortPath within window. Every first click
+	// starts a new window, later clicks in it do not extend it.
+	MarkClick(ctx context.Context, shortPath string, visitorID string, window time.Duration) (bool, error)
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"url-shortener/internal/db"
+)
+
+// clickDedupRepositoryRedisImpl keeps a key per link and visitor that expires with the de-duplication window.
+// SET NX makes claiming the window atomic, so concurrent clicks on different replicas see a single first click.
+type clickDedupRepositoryRedisImpl struct {
+	client db.RedisClient
+}
+
+func NewClickDedupRepositoryRedis(client db.RedisClient) ClickDedupRepository {
+	return &clickDedupRepositoryRedisImpl{client: client}
+}
+
+// MarkClick implements ClickDedupRepository.
+func (r *clickDedupRepositoryRedisImpl) MarkClick(ctx context.Context, shortPath string, visitorID string, window time.Duration) (bool, error) {
+	first, err := r.client.SetNX(ctx, clickDedupKey(shortPath, visitorID), 1, window).Result()
+	if err != nil {
+		log.Printf("Error de-duplicating click of %s: %v", shortPath, err)
+		return false, ErrRedisError
+	}
+	return first, nil
+}
+
+func clickDedupKey(shortPath string, visitorID string) string {
+	return "dedup:{" + shortPath + "}:" + visitorID
+}
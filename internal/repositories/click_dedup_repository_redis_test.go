@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dbMocks "url-shortener/internal/db/mocks"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisMarkClick(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewClickDedupRepositoryRedis(mockClient)
+	ctx := context.Background()
+	mockClient.On("SetNX", ctx, "dedup:{path1}:visitor", 1, 30*time.Second).Return(redis.NewBoolResult(true, nil)).Once()
+	mockClient.On("SetNX", ctx, "dedup:{path1}:visitor", 1, 30*time.Second).Return(redis.NewBoolResult(false, nil)).Once()
+
+	first, err := repo.MarkClick(ctx, "path1", "visitor", 30*time.Second)
+	assert.NoError(t, err)
+	assert.True(t, first)
+
+	first, err = repo.MarkClick(ctx, "path1", "visitor", 30*time.Second)
+	assert.NoError(t, err)
+	assert.False(t, first)
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisMarkClick_Error(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewClickDedupRepositoryRedis(mockClient)
+	ctx := context.Background()
+	mockClient.On("SetNX", ctx, "dedup:{path1}:visitor", 1, time.Minute).Return(redis.NewBoolResult(false, errors.New("redis error"))).Once()
+
+	_, err := repo.MarkClick(ctx, "path1", "visitor", time.Minute)
+
+	assert.ErrorIs(t, err, ErrRedisError)
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=ClickEventRepository --output=./mocks
+type ClickEventRepository interface {
+	// ListClickEvents returns up to limit clicks matching filter after the cursor, ordered by access time and id.
+	ListClickEvents(ctx context.Context, filter models.ClickEventFilter, after models.ClickEventCursor, limit int) ([]models.ClickEvent, error)
+}
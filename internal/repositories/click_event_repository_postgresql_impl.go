@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+)
+
+type clickEventRepositoryPostgresqlImpl struct {
+	cluster *db.PostgresCluster
+}
+
+func NewClickEventRepositoryPostgresql(cluster *db.PostgresCluster) ClickEventRepository {
+	return &clickEventRepositoryPostgresqlImpl{cluster: cluster}
+}
+
+// ListClickEvents implements ClickEventRepository. Every page is a separate transaction on a replica, so an export
+// never holds one open for longer than a page. A page of a large export can take longer than the statement timeout.
+func (r *clickEventRepositoryPostgresqlImpl) ListClickEvents(ctx context.Context, filter models.ClickEventFilter, after models.ClickEventCursor, limit int) ([]models.ClickEvent, error) {
+	tx, err := r.cluster.Reader(ctx).BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		log.Printf("Error starting transaction to list click events: %v", err)
+		return nil, ErrDBError
+	}
+	defer tx.Rollback()
+	if err := liftStatementTimeout(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, PG_LIST_CLICK_EVENTS, filter.From.UTC(), filter.To.UTC(), filter.ShortPath, filter.Owner,
+		after.AccessedAt.UTC(), after.ID, limit)
+	if err != nil {
+		log.Printf("Error listing click events after %s/%d: %v", after.AccessedAt, after.ID, err)
+		return nil, ErrDBError
+	}
+	defer rows.Close()
+
+	events := make([]models.ClickEvent, 0, limit)
+	for rows.Next() {
+		var event models.ClickEvent
+		if err := rows.Scan(&event.ID, &event.ShortPath, &event.AccessedAt, &event.ReferrerHost, &event.Browser, &event.OS, &event.DeviceClass,
+			&event.ClientIP, &event.Language, &event.QueryString, &event.Country, &event.Region, &event.City, &event.IsBot, &event.IsDuplicate,
+			&event.ClickID); err != nil {
+			log.Printf("Error scanning click event: %v", err)
+			return nil, ErrDBError
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error reading click events: %v", err)
+		return nil, ErrDBError
+	}
+	return events, nil
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var clickEventColumns = []string{"id", "short_path", "accessed_at", "referrer_host", "browser", "os", "device_class", "client_ip",
+	"language", "query_string", "country", "region", "city", "is_bot", "is_duplicate", "click_id"}
+
+func TestClickEventRepositoryPostgresqlImpl_ListClickEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewClickEventRepositoryPostgresql(newTestCluster(db))
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	after := models.ClickEventCursor{AccessedAt: from.Add(time.Hour), ID: 41}
+	accessedAt := from.Add(2 * time.Hour)
+	rows := sqlmock.NewRows(clickEventColumns).
+		AddRow(42, "shortPath", accessedAt, "example.com", "Chrome", "Linux", "desktop", "203.0.113.7", "de", "utm_source=x", "DE", "DE-BY", "Munich", false, true, "clk_123")
+	// The page runs without the statement timeout, large exports can take longer.
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL statement_timeout = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT (.+) FROM url_access_logs l WHERE (.+) ORDER BY l.accessed_at, l.id").
+		WithArgs(from, to, "shortPath", "alice", after.AccessedAt, int64(41), 100).WillReturnRows(rows)
+	mock.ExpectRollback()
+
+	events, err := repo.ListClickEvents(context.Background(), models.ClickEventFilter{ShortPath: "shortPath", Owner: "alice", From: from, To: to}, after, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ClickEvent{{
+		ID: 42, ShortPath: "shortPath", AccessedAt: accessedAt, ReferrerHost: "example.com", Browser: "Chrome", OS: "Linux",
+		DeviceClass: "desktop", ClientIP: "203.0.113.7", Language: "de", QueryString: "utm_source=x", Country: "DE", Region: "DE-BY", City: "Munich", IsDuplicate: true,
+		ClickID: "clk_123",
+	}}, events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClickEventRepositoryPostgresqlImpl_ListClickEvents_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewClickEventRepositoryPostgresql(newTestCluster(db))
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL statement_timeout = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT (.+) FROM url_access_logs l").WillReturnError(fmt.Errorf("some error"))
+
+	events, err := repo.ListClickEvents(context.Background(), models.ClickEventFilter{To: time.Now()}, models.ClickEventCursor{}, 10)
+
+	assert.ErrorIs(t, err, ErrDBError)
+	assert.Nil(t, events)
+}
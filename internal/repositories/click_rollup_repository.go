@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=ClickRollupRepository --output=./mocks
+type ClickRollupRepository interface {
+	// RollUp aggregates raw clicks from the watermark up to until, at most maxSpan at a time, into the hourly
+	// and daily rollup tables and returns the new watermark. until has to be on the hour.
+	RollUp(ctx context.Context, until time.Time, maxSpan time.Duration) (time.Time, error)
+	// Watermark returns the time up to which clicks have been rolled up, zero before the first rollup.
+	Watermark(ctx context.Context) (time.Time, error)
+	// HourlyClicks returns the rolled up clicks of every link in the hours starting in [from, to), bots excluded.
+	HourlyClicks(ctx context.Context, from time.Time, to time.Time) ([]models.HourlyClicks, error)
+	// CounterWatermark returns the time up to which the Redis click counters have been reconciled, zero before the
+	// first reconcile.
+	CounterWatermark(ctx context.Context) (time.Time, error)
+	SetCounterWatermark(ctx context.Context, reconciledTo time.Time) error
+	// MinuteClicks counts the raw clicks of every link per minute in [from, to), bots and duplicates excluded.
+	MinuteClicks(ctx context.Context, from time.Time, to time.Time) ([]models.MinuteClicks, error)
+}
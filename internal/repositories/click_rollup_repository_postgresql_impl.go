@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+)
+
+type clickRollupRepositoryPostgresqlImpl struct {
+	cluster *db.PostgresCluster
+}
+
+func NewClickRollupRepositoryPostgresql(cluster *db.PostgresCluster) ClickRollupRepository {
+	return &clickRollupRepositoryPostgresqlImpl{cluster: cluster}
+}
+
+// RollUp implements ClickRollupRepository. The watermark row is locked for the whole transaction so instances
+// running the job at the same time take turns, and each hour is only ever computed from complete raw data, so
+// running it again after a failure gives the same rollups.
+func (r *clickRollupRepositoryPostgresqlImpl) RollUp(ctx context.Context, until time.Time, maxSpan time.Duration) (time.Time, error) {
+	until = until.UTC()
+	tx, err := r.cluster.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting click rollup transaction: %v", err)
+		return time.Time{}, ErrDBError
+	}
+	defer tx.Rollback()
+	// Catching up on a backlog of clicks can take longer than the statement timeout.
+	if err := liftStatementTimeout(ctx, tx); err != nil {
+		return time.Time{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, PG_INIT_CLICK_ROLLUP_WATERMARK, until); err != nil {
+		log.Printf("Error initialising click rollup watermark: %v", err)
+		return time.Time{}, ErrDBError
+	}
+	var watermark time.Time
+	if err := tx.QueryRowContext(ctx, PG_LOCK_CLICK_ROLLUP_WATERMARK).Scan(&watermark); err != nil {
+		log.Printf("Error locking click rollup watermark: %v", err)
+		return time.Time{}, ErrDBError
+	}
+	end := until
+	if maxSpan > 0 && watermark.Add(maxSpan).Before(end) {
+		// Only whole hours are rolled up, a partial hour would be counted again by the next run.
+		end = watermark.Add(maxSpan).Truncate(time.Hour)
+	}
+	if !watermark.Before(end) {
+		return watermark, tx.Commit()
+	}
+
+	if _, err := tx.ExecContext(ctx, PG_ROLL_UP_HOURLY_CLICKS, watermark, end); err != nil {
+		log.Printf("Error rolling up hourly clicks from %s to %s: %v", watermark, end, err)
+		return time.Time{}, ErrDBError
+	}
+	if _, err := tx.ExecContext(ctx, PG_ROLL_UP_DAILY_CLICKS, watermark, end); err != nil {
+		log.Printf("Error rolling up daily clicks from %s to %s: %v", watermark, end, err)
+		return time.Time{}, ErrDBError
+	}
+	if _, err := tx.ExecContext(ctx, PG_UPDATE_CLICK_ROLLUP_WATERMARK, end); err != nil {
+		log.Printf("Error moving click rollup watermark to %s: %v", end, err)
+		return time.Time{}, ErrDBError
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing click rollup: %v", err)
+		return time.Time{}, ErrDBError
+	}
+	return end, nil
+}
+
+// Watermark implements ClickRollupRepository.
+func (r *clickRollupRepositoryPostgresqlImpl) Watermark(ctx context.Context) (time.Time, error) {
+	var watermark time.Time
+	err := r.cluster.Primary().QueryRowContext(ctx, PG_GET_CLICK_ROLLUP_WATERMARK).Scan(&watermark)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		log.Printf("Error getting click rollup watermark: %v", err)
+		return time.Time{}, ErrDBError
+	}
+	return watermark, nil
+}
+
+// CounterWatermark implements ClickRollupRepository.
+func (r *clickRollupRepositoryPostgresqlImpl) CounterWatermark(ctx context.Context) (time.Time, error) {
+	var watermark time.Time
+	err := r.cluster.Primary().QueryRowContext(ctx, PG_GET_COUNTER_RECONCILE_WATERMARK).Scan(&watermark)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		log.Printf("Error getting click counter watermark: %v", err)
+		return time.Time{}, ErrDBError
+	}
+	return watermark, nil
+}
+
+// SetCounterWatermark implements ClickRollupRepository.
+func (r *clickRollupRepositoryPostgresqlImpl) SetCounterWatermark(ctx context.Context, reconciledTo time.Time) error {
+	if _, err := r.cluster.Primary().ExecContext(ctx, PG_UPDATE_COUNTER_RECONCILE_WATERMARK, reconciledTo.UTC()); err != nil {
+		log.Printf("Error moving click counter watermark to %s: %v", reconciledTo, err)
+		return ErrDBError
+	}
+	return nil
+}
+
+// HourlyClicks implements ClickRollupRepository.
+func (r *clickRollupRepositoryPostgresqlImpl) HourlyClicks(ctx context.Context, from time.Time, to time.Time) ([]models.HourlyClicks, error) {
+	rows, err := r.cluster.Primary().QueryContext(ctx, PG_GET_HOURLY_CLICK_ROLLUPS, from.UTC(), to.UTC())
+	if err != nil {
+		log.Printf("Error getting hourly clicks from %s to %s: %v", from, to, err)
+		return nil, ErrDBError
+	}
+	defer rows.Close()
+
+	clicks := []models.HourlyClicks{}
+	for rows.Next() {
+		var hour models.HourlyClicks
+		if err := rows.Scan(&hour.ShortPath, &hour.Bucket, &hour.Clicks); err != nil {
+			log.Printf("Error scanning hourly clicks: %v", err)
+			return nil, ErrDBError
+		}
+		clicks = append(clicks, hour)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error reading hourly clicks: %v", err)
+		return nil, ErrDBError
+	}
+	return clicks, nil
+}
+
+// MinuteClicks implements ClickRollupRepository.
+func (r *clickRollupRepositoryPostgresqlImpl) MinuteClicks(ctx context.Context, from time.Time, to time.Time) ([]models.MinuteClicks, error) {
+	rows, err := r.cluster.Primary().QueryContext(ctx, PG_GET_MINUTE_CLICKS, from.UTC(), to.UTC())
+	if err != nil {
+		log.Printf("Error getting minute clicks from %s to %s: %v", from, to, err)
+		return nil, ErrDBError
+	}
+	defer rows.Close()
+
+	clicks := []models.MinuteClicks{}
+	for rows.Next() {
+		var minute models.MinuteClicks
+		if err := rows.Scan(&minute.ShortPath, &minute.Bucket, &minute.Clicks); err != nil {
+			log.Printf("Error scanning minute clicks: %v", err)
+			return nil, ErrDBError
+		}
+		clicks = append(clicks, minute)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error reading minute clicks: %v", err)
+		return nil, ErrDBError
+	}
+	return clicks, nil
+}
@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClickRollupRepositoryPostgresqlImpl_RollUp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewClickRollupRepositoryPostgresql(newTestCluster(db))
+	watermark := time.Date(2025, 6, 9, 10, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC)
+	end := watermark.Add(24 * time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL statement_timeout = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO click_rollup_watermarks").WithArgs(until).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT rolled_up_to FROM click_rollup_watermarks WHERE name = 'url_clicks' FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"rolled_up_to"}).AddRow(watermark))
+	mock.ExpectExec("INSERT INTO url_click_rollups_hourly").WithArgs(watermark, end).WillReturnResult(sqlmock.NewResult(0, 10))
+	mock.ExpectExec("INSERT INTO url_click_rollups_daily").WithArgs(watermark, end).WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec("UPDATE click_rollup_watermarks SET rolled_up_to = \\$1").WithArgs(end).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rolledUpTo, err := repo.RollUp(context.Background(), until, 24*time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, end, rolledUpTo)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClickRollupRepositoryPostgresqlImpl_RollUp_PartialHourSpan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewClickRollupRepositoryPostgresql(newTestCluster(db))
+	watermark := time.Date(2025, 6, 9, 10, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC)
+	// 90 minutes only covers one whole hour, the half hour is left for the next run.
+	end := watermark.Add(time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL statement_timeout = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO click_rollup_watermarks").WithArgs(until).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT rolled_up_to FROM click_rollup_watermarks").
+		WillReturnRows(sqlmock.NewRows([]string{"rolled_up_to"}).AddRow(watermark))
+	mock.ExpectExec("INSERT INTO url_click_rollups_hourly").WithArgs(watermark, end).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO url_click_rollups_daily").WithArgs(watermark, end).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE click_rollup_watermarks SET rolled_up_to = \\$1").WithArgs(end).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rolledUpTo, err := repo.RollUp(context.Background(), until, 90*time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, end, rolledUpTo)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClickRollupRepositoryPostgresqlImpl_RollUp_CaughtUp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewClickRollupRepositoryPostgresql(newTestCluster(db))
+	until := time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL statement_timeout = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO click_rollup_watermarks").WithArgs(until).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT rolled_up_to FROM click_rollup_watermarks").
+		WillReturnRows(sqlmock.NewRows([]string{"rolled_up_to"}).AddRow(until))
+	mock.ExpectCommit()
+
+	rolledUpTo, err := repo.RollUp(context.Background(), until, 24*time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, until, rolledUpTo)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClickRollupRepositoryPostgresqlImpl_RollUp_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewClickRollupRepositoryPostgresql(newTestCluster(db))
+	watermark := time.Date(2025, 6, 11, 22, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL statement_timeout = 0").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO click_rollup_watermarks").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT rolled_up_to FROM click_rollup_watermarks").
+		WillReturnRows(sqlmock.NewRows([]string{"rolled_up_to"}).AddRow(watermark))
+	mock.ExpectExec("INSERT INTO url_click_rollups_hourly").WithArgs(watermark, until).WillReturnError(fmt.Errorf("some error"))
+	mock.ExpectRollback()
+
+	_, err = repo.RollUp(context.Background(), until, 24*time.Hour)
+
+	assert.ErrorIs(t, err, ErrDBError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClickRollupRepositoryPostgresqlImpl_HourlyClicks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewClickRollupRepositoryPostgresql(newTestCluster(db))
+	from := time.Date(2025, 6, 12, 9, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	mock.ExpectQuery("SELECT short_path, bucket, clicks FROM url_click_rollups_hourly").WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"short_path", "bucket", "clicks"}).AddRow("path1", from, 3).AddRow("path2", from, 1))
+
+	clicks, err := repo.HourlyClicks(context.Background(), from, to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.HourlyClicks{{ShortPath: "path1", Bucket: from, Clicks: 3}, {ShortPath: "path2", Bucket: from, Clicks: 1}}, clicks)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClickRollupRepositoryPostgresqlImpl_HourlyClicks_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewClickRollupRepositoryPostgresql(newTestCluster(db))
+	mock.ExpectQuery("SELECT short_path, bucket, clicks FROM url_click_rollups_hourly").WillReturnError(fmt.Errorf("some error"))
+
+	_, err = repo.HourlyClicks(context.Background(), time.Now().Add(-time.Hour), time.Now())
+
+	assert.ErrorIs(t, err, ErrDBError)
+}
+
+func TestClickRollupRepositoryPostgresqlImpl_MinuteClicks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewClickRollupRepositoryPostgresql(newTestCluster(db))
+	from := time.Date(2025, 6, 12, 9, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	mock.ExpectQuery("SELECT short_path, date_trunc\\('minute', accessed_at\\) AS bucket, COUNT\\(\\*\\) FROM url_access_logs").WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"short_path", "bucket", "count"}).AddRow("path1", from.Add(time.Minute), 2))
+
+	clicks, err := repo.MinuteClicks(context.Background(), from, to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.MinuteClicks{{ShortPath: "path1", Bucket: from.Add(time.Minute), Clicks: 2}}, clicks)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClickRollupRepositoryPostgresqlImpl_CounterWatermark(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewClickRollupRepositoryPostgresql(newTestCluster(db))
+	reconciledTo := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT rolled_up_to FROM click_rollup_watermarks WHERE name = 'click_counters'").WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("INSERT INTO click_rollup_watermarks \\(name, rolled_up_to\\) VALUES \\('click_counters', \\$1\\)").WithArgs(reconciledTo).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT rolled_up_to FROM click_rollup_watermarks WHERE name = 'click_counters'").
+		WillReturnRows(sqlmock.NewRows([]string{"rolled_up_to"}).AddRow(reconciledTo))
+
+	watermark, err := repo.CounterWatermark(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, watermark.IsZero())
+
+	assert.NoError(t, repo.SetCounterWatermark(context.Background(), reconciledTo))
+
+	watermark, err = repo.CounterWatermark(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, reconciledTo, watermark)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=ClickStreamRepository --output=./mocks
+type ClickStreamRepository interface {
+	// Publish sends every message to the replicas subscribed to its stream and keeps it for replay.
+	Publish(ctx context.Context, messages []models.ClickStreamMessage) error
+	// Recent returns the events kept for stream, oldest first.
+	Recent(ctx context.Context, stream string) ([]models.ClickStreamEvent, error)
+	// Subscribe and Unsubscribe change the streams whose events this replica receives on Messages.
+	Subscribe(ctx context.Context, streams ...string) error
+	Unsubscribe(ctx context.Context, streams ...string) error
+	// Messages delivers the events published to the subscribed streams until Close.
+	Messages() <-chan models.ClickStreamMessage
+	Close() error
+}
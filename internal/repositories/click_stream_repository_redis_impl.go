@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	clickStreamChannelPrefix = "live:"
+	clickStreamHistoryPrefix = "live:history:"
+)
+
+// clickStreamRepositoryRedisImpl fans clicks out over Redis pub/sub, one channel per stream, and keeps the last
+// historySize events of every stream in a capped list so reconnecting clients can catch up. Pub/sub messages are
+// not stored, a replica only sees the events published while it is subscribed. Every stream a replica serves
+// shares one pub/sub connection, which go-redis reconnects and subscribes again when it breaks.
+type clickStreamRepositoryRedisImpl struct {
+	client      db.RedisClient
+	historySize int
+	historyTTL  time.Duration
+
+	pubsub   *redis.PubSub
+	listen   sync.Once
+	messages chan models.ClickStreamMessage
+}
+
+func NewClickStreamRepositoryRedis(client db.RedisClient, historySize int, historyTTL time.Duration) ClickStreamRepository {
+	return &clickStreamRepositoryRedisImpl{
+		client:      client,
+		historySize: historySize,
+		historyTTL:  historyTTL,
+		// Without channels the connection is only opened by the first Subscribe.
+		pubsub:   client.Subscribe(context.Background()),
+		messages: make(chan models.ClickStreamMessage),
+	}
+}
+
+// Publish implements ClickStreamRepository.
+func (r *clickStreamRepositoryRedisImpl) Publish(ctx context.Context, messages []models.ClickStreamMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, message := range messages {
+			data, err := json.Marshal(message.Event)
+			if err != nil {
+				return err
+			}
+			pipe.Publish(ctx, clickStreamChannelPrefix+message.Stream, data)
+			if r.historySize > 0 {
+				historyKey := clickStreamHistoryPrefix + message.Stream
+				pipe.LPush(ctx, historyKey, data)
+				pipe.LTrim(ctx, historyKey, 0, int64(r.historySize-1))
+				pipe.Expire(ctx, historyKey, r.historyTTL)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error publishing %d click stream events: %v", len(messages), err)
+		return ErrRedisError
+	}
+	return nil
+}
+
+// Recent implements ClickStreamRepository.
+func (r *clickStreamRepositoryRedisImpl) Recent(ctx context.Context, stream string) ([]models.ClickStreamEvent, error) {
+	values, err := r.client.LRange(ctx, clickStreamHistoryPrefix+stream, 0, -1).Result()
+	if err != nil {
+		log.Printf("Error reading recent events of click stream %s: %v", stream, err)
+		return nil, ErrRedisError
+	}
+	// The list is newest first.
+	events := make([]models.ClickStreamEvent, 0, len(values))
+	for i := len(values) - 1; i >= 0; i-- {
+		var event models.ClickStreamEvent
+		if err := json.Unmarshal([]byte(values[i]), &event); err != nil {
+			log.Printf("Error decoding event of click stream %s: %v", stream, err)
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// Subscribe implements ClickStreamRepository.
+func (r *clickStreamRepositoryRedisImpl) Subscribe(ctx context.Context, streams ...string) error {
+	if err := r.pubsub.Subscribe(ctx, clickStreamChannels(streams)...); err != nil {
+		log.Printf("Error subscribing to click streams %v: %v", streams, err)
+		return ErrRedisError
+	}
+	return nil
+}
+
+// Unsubscribe implements ClickStreamRepository.
+func (r *clickStreamRepositoryRedisImpl) Unsubscribe(ctx context.Context, streams ...string) error {
+	if err := r.pubsub.Unsubscribe(ctx, clickStreamChannels(streams)...); err != nil {
+		log.Printf("Error unsubscribing from click streams %v: %v", streams, err)
+		return ErrRedisError
+	}
+	return nil
+}
+
+// Messages implements ClickStreamRepository.
+func (r *clickStreamRepositoryRedisImpl) Messages() <-chan models.ClickStreamMessage {
+	r.listen.Do(func() { go r.receive() })
+	return r.messages
+}
+
+// Close implements ClickStreamRepository.
+func (r *clickStreamRepositoryRedisImpl) Close() error {
+	return r.pubsub.Close()
+}
+
+func (r *clickStreamRepositoryRedisImpl) receive() {
+	defer close(r.messages)
+	for message := range r.pubsub.Channel() {
+		var event models.ClickStreamEvent
+		if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+			log.Printf("Error decoding event of click stream channel %s: %v", message.Channel, err)
+			continue
+		}
+		r.messages <- models.ClickStreamMessage{Stream: strings.TrimPrefix(message.Channel, clickStreamChannelPrefix), Event: event}
+	}
+}
+
+func clickStreamChannels(streams []string) []string {
+	channels := make([]string, len(streams))
+	for i, stream := range streams {
+		channels[i] = clickStreamChannelPrefix + stream
+	}
+	return channels
+}
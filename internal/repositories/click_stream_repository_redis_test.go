@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+
+	dbMocks "url-shortener/internal/db/mocks"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newClickStreamMockClient() *dbMocks.RedisClient {
+	mockClient := &dbMocks.RedisClient{}
+	// A pub/sub without channels does not connect.
+	mockClient.On("Subscribe", mock.Anything).Return(redis.NewClient(&redis.Options{}).Subscribe(context.Background())).Once()
+	return mockClient
+}
+
+func TestRedisPublishClickStreamEvents(t *testing.T) {
+	mockClient := newClickStreamMockClient()
+	repo := NewClickStreamRepositoryRedis(mockClient, 100, time.Hour)
+	pipe := redis.NewClient(&redis.Options{}).Pipeline()
+	mockClient.On("Pipelined", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(redis.Pipeliner) error)
+		assert.NoError(t, fn(pipe))
+	}).Return(nil, nil).Once()
+
+	event := models.ClickStreamEvent{ID: "1", ShortPath: "path1", Owner: "alice"}
+	err := repo.Publish(context.Background(), []models.ClickStreamMessage{{Stream: "link:path1", Event: event}, {Stream: "owner:alice", Event: event}})
+
+	assert.NoError(t, err)
+	// PUBLISH, LPUSH, LTRIM and EXPIRE per stream.
+	assert.Equal(t, 8, pipe.Len())
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisPublishClickStreamEvents_Error(t *testing.T) {
+	mockClient := newClickStreamMockClient()
+	repo := NewClickStreamRepositoryRedis(mockClient, 100, time.Hour)
+	mockClient.On("Pipelined", mock.Anything, mock.Anything).Return(nil, errors.New("redis error")).Once()
+
+	err := repo.Publish(context.Background(), []models.ClickStreamMessage{{Stream: "link:path1"}})
+
+	assert.ErrorIs(t, err, ErrRedisError)
+}
+
+func TestRedisRecentClickStreamEvents(t *testing.T) {
+	mockClient := newClickStreamMockClient()
+	repo := NewClickStreamRepositoryRedis(mockClient, 100, time.Hour)
+	newest, _ := json.Marshal(models.ClickStreamEvent{ID: "2", ShortPath: "path1"})
+	oldest, _ := json.Marshal(models.ClickStreamEvent{ID: "1", ShortPath: "path1"})
+	mockClient.On("LRange", mock.Anything, "live:history:link:path1", int64(0), int64(-1)).
+		Return(redis.NewStringSliceResult([]string{string(newest), "not json", string(oldest)}, nil)).Once()
+
+	events, err := repo.Recent(context.Background(), "link:path1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ClickStreamEvent{{ID: "1", ShortPath: "path1"}, {ID: "2", ShortPath: "path1"}}, events)
+}
+
+func TestRedisRecentClickStreamEvents_Error(t *testing.T) {
+	mockClient := newClickStreamMockClient()
+	repo := NewClickStreamRepositoryRedis(mockClient, 100, time.Hour)
+	mockClient.On("LRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(redis.NewStringSliceResult(nil, errors.New("redis error"))).Once()
+
+	events, err := repo.Recent(context.Background(), "link:path1")
+
+	assert.ErrorIs(t, err, ErrRedisError)
+	assert.Nil(t, events)
+}
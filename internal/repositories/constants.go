@@ -4,18 +4,340 @@ import "errors"
 
 const (
 	PG_GET_BY_SHORT_URL    = `SELECT short_path, original_url, expiry, created_at, created_by, modified_at, modified_by FROM urls WHERE short_path = $1`
+	PG_LOCK_SHORT_URL      = PG_GET_BY_SHORT_URL + ` FOR UPDATE`
 	PG_GET_BY_ORIGINAL_URL = `SELECT short_path, original_url, expiry, created_at, created_by, modified_at, modified_by FROM urls WHERE original_url = $1`
 	PG_INSERT_SHORT_URL    = `INSERT INTO urls (short_path, original_url, expiry, created_at, created_by) VALUES ($1, $2, $3, $4, $5)`
 	PG_UPDATE_SHORT_URL    = `UPDATE urls SET original_url = $1, expiry = $2, modified_at = $3, modified_by = $4 WHERE short_path = $5`
 	PG_DELETE_SHORT_URL    = `DELETE FROM urls WHERE short_path = $1`
+	PG_INSERT_OUTBOX_EVENT = `INSERT INTO outbox_events (type, short_path, payload) VALUES ($1, $2, $3)`
 	PG_INSERT_URL_ARCHIVE  = `INSERT INTO urls_archive (short_path, original_url, expiry, created_at, created_by, modified_at, modified_by, deleted_at, deleted_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
 
-	PG_GET_URL_STATISTICS = `SELECT COUNT(*) FILTER (WHERE accessed_at >= NOW() - INTERVAL '24 hours') AS last_24_hours,
-    								COUNT(*) FILTER (WHERE accessed_at >= NOW() - INTERVAL '7 days') AS past_week,
-    								COUNT(*) AS all_time
+	// PG_GET_URL_STATISTICS adds up the rollups before the watermark and the raw clicks after it. Hourly buckets are
+	// only counted when they start inside a window, the clicks of the hour a window starts in are counted from the raw rows.
+	// Bot clicks are only counted when $2 is true, duplicate clicks never are.
+	PG_GET_URL_STATISTICS = `WITH bounds AS (
+								SELECT NOW() AT TIME ZONE 'UTC' - INTERVAL '24 hours' AS day_start,
+										NOW() AT TIME ZONE 'UTC' - INTERVAL '7 days' AS week_start,
+										COALESCE((SELECT rolled_up_to FROM click_rollup_watermarks WHERE name = 'url_clicks'), '-infinity'::timestamp) AS rolled_up_to
+							), raw AS (
+								SELECT COUNT(*) FILTER (WHERE l.accessed_at >= b.day_start AND (l.accessed_at >= b.rolled_up_to OR date_trunc('hour', l.accessed_at) < b.day_start)) AS last_24_hours,
+										COUNT(*) FILTER (WHERE l.accessed_at >= b.week_start AND (l.accessed_at >= b.rolled_up_to OR date_trunc('hour', l.accessed_at) < b.week_start)) AS past_week,
+										COUNT(*) FILTER (WHERE l.accessed_at >= b.rolled_up_to) AS all_time
+								FROM bounds b
+								JOIN url_access_logs l ON l.short_path = $1 AND NOT l.is_duplicate AND ($2 OR NOT l.is_bot)
+									AND (l.accessed_at >= b.rolled_up_to
+										OR (l.accessed_at >= b.day_start AND l.accessed_at < date_trunc('hour', b.day_start) + INTERVAL '1 hour')
+										OR (l.accessed_at >= b.week_start AND l.accessed_at < date_trunc('hour', b.week_start) + INTERVAL '1 hour'))
+							), hourly AS (
+								SELECT COALESCE(SUM(h.clicks + CASE WHEN $2 THEN h.bot_clicks ELSE 0 END) FILTER (WHERE h.bucket >= b.day_start), 0) AS last_24_hours,
+										COALESCE(SUM(h.clicks + CASE WHEN $2 THEN h.bot_clicks ELSE 0 END) FILTER (WHERE h.bucket >= b.week_start), 0) AS past_week,
+										COALESCE(SUM(h.clicks + CASE WHEN $2 THEN h.bot_clicks ELSE 0 END) FILTER (WHERE h.bucket >= date_trunc('day', b.rolled_up_to)), 0) AS all_time
+								FROM bounds b
+								JOIN url_click_rollups_hourly h ON h.short_path = $1 AND h.bucket < b.rolled_up_to
+									AND h.bucket >= LEAST(b.week_start, date_trunc('day', b.rolled_up_to))
+							), daily AS (
+								SELECT COALESCE(SUM(d.clicks + CASE WHEN $2 THEN d.bot_clicks ELSE 0 END), 0) AS all_time
+								FROM bounds b
+								JOIN url_click_rollups_daily d ON d.short_path = $1 AND d.bucket < date_trunc('day', b.rolled_up_to)
+							)
+							SELECT raw.last_24_hours + hourly.last_24_hours AS last_24_hours,
+									raw.past_week + hourly.past_week AS past_week,
+									raw.all_time + hourly.all_time + daily.all_time AS all_time
+							FROM raw, hourly, daily;`
+	// PG_GET_URL_TIMESERIES counts accesses in [$2, $3) per $4 (hour, day or week) of the wall clock in time zone $5,
+	// including bots when $6 is true. accessed_at is stored in UTC.
+	PG_GET_URL_TIMESERIES = `SELECT date_trunc($4, accessed_at AT TIME ZONE 'UTC' AT TIME ZONE $5) AS bucket, COUNT(*) AS clicks
 							FROM url_access_logs
-							WHERE short_path = $1;`
-	PG_INSERT_ACCESS_LOG = `INSERT INTO url_access_logs (short_path,accessed_at) VALUES ($1,$2);`
+							WHERE short_path = $1 AND accessed_at >= $2 AND accessed_at < $3 AND NOT is_duplicate AND ($6 OR NOT is_bot)
+							GROUP BY bucket
+							ORDER BY bucket`
+	// pgClicksSince adds up the clicks of every link since $1, bots excluded. Whole days and hours come from the rollups
+	// up to the watermark, the hour $1 falls in and everything after the watermark from the raw rows.
+	pgClicksSince = `WITH bounds AS (
+								SELECT $1::timestamp AS window_start,
+										date_trunc('hour', $1::timestamp + INTERVAL '1 hour' - INTERVAL '1 microsecond') AS hour_start,
+										date_trunc('day', $1::timestamp + INTERVAL '1 day' - INTERVAL '1 microsecond') AS day_start,
+										COALESCE((SELECT rolled_up_to FROM click_rollup_watermarks WHERE name = 'url_clicks'), '-infinity'::timestamp) AS rolled_up_to
+							), clicks AS (
+								SELECT short_path, SUM(clicks) AS clicks
+								FROM (SELECT l.short_path, COUNT(*) AS clicks
+										FROM bounds b
+										JOIN url_access_logs l ON NOT l.is_bot AND NOT l.is_duplicate AND l.accessed_at >= b.window_start
+											AND (l.accessed_at >= b.rolled_up_to OR l.accessed_at < b.hour_start)
+										GROUP BY l.short_path
+									UNION ALL
+									SELECT h.short_path, SUM(h.clicks)
+										FROM bounds b
+										JOIN url_click_rollups_hourly h ON h.bucket >= b.hour_start AND h.bucket < b.rolled_up_to
+											AND (h.bucket < b.day_start OR h.bucket >= date_trunc('day', b.rolled_up_to))
+										GROUP BY h.short_path
+									UNION ALL
+									SELECT d.short_path, SUM(d.clicks)
+										FROM bounds b
+										JOIN url_click_rollups_daily d ON d.bucket >= b.day_start AND d.bucket < date_trunc('day', b.rolled_up_to)
+										GROUP BY d.short_path) parts
+								GROUP BY short_path
+							)`
+	// PG_GET_TOP_LINKS returns the $2 links with the most clicks since $1.
+	PG_GET_TOP_LINKS = pgClicksSince + `
+							SELECT u.short_path, u.original_url, c.clicks
+							FROM clicks c
+							JOIN urls u ON u.short_path = c.short_path
+							ORDER BY c.clicks DESC, u.short_path
+							LIMIT $2`
+	// PG_GET_DOMAIN_CLICKS groups every link by the lower cased host of its original url and returns the $2 hosts with
+	// the most clicks since $1.
+	PG_GET_DOMAIN_CLICKS = pgClicksSince + `
+							SELECT u.domain, COUNT(*) AS links, COALESCE(SUM(c.clicks), 0) AS clicks
+							FROM (SELECT short_path, COALESCE(lower(substring(original_url FROM '^[^:]+://(?:[^/?#@]*@)?([^/?#:]+)')), '') AS domain
+									FROM urls) u
+							LEFT JOIN clicks c ON c.short_path = u.short_path
+							GROUP BY u.domain
+							ORDER BY clicks DESC, links DESC, u.domain
+							LIMIT $2`
+	// PG_INSERT_ACCESS_LOGS is completed with one ($1, $2, ...) group per row.
+	PG_INSERT_ACCESS_LOGS = `INSERT INTO url_access_logs (short_path, accessed_at, referrer_host, browser, os, device_class, client_ip, language, query_string, country, region, city, is_bot, click_id, is_duplicate) VALUES `
+	// PG_GET_URL_STATISTICS_BREAKDOWN returns the $2 most frequent values of every dimension over the last $4 seconds,
+	// including bots when $3 is true. The window bounds the raw rows read through idx_accessed_at, as the dimensions are
+	// not rolled up. Clicks logged before the metadata columns existed are counted as unknown.
+	PG_GET_URL_STATISTICS_BREAKDOWN = `SELECT dimension, value, clicks
+							FROM (SELECT d.dimension, d.value, COUNT(*) AS clicks,
+										ROW_NUMBER() OVER (PARTITION BY d.dimension ORDER BY COUNT(*) DESC, d.value) AS rank
+									FROM url_access_logs,
+										LATERAL (VALUES ('referrer', COALESCE(NULLIF(referrer_host, ''), 'direct')),
+														('browser', COALESCE(NULLIF(browser, ''), 'unknown')),
+														('os', COALESCE(NULLIF(os, ''), 'unknown')),
+														('device', COALESCE(NULLIF(device_class, ''), 'unknown')),
+														('language', COALESCE(NULLIF(language, ''), 'unknown')),
+														('country', COALESCE(NULLIF(country, ''), 'unknown')),
+														('region', COALESCE(NULLIF(region, ''), 'unknown')),
+														('city', COALESCE(NULLIF(city, ''), 'unknown'))) AS d(dimension, value)
+									WHERE short_path = $1 AND accessed_at >= NOW() AT TIME ZONE 'UTC' - $4 * INTERVAL '1 second'
+										AND NOT is_duplicate AND ($3 OR NOT is_bot)
+									GROUP BY d.dimension, d.value) ranked
+							WHERE rank <= $2
+							ORDER BY dimension, clicks DESC, value`
+
+	// The watermark starts at the first hour with clicks, or at $1 when there are none yet.
+	PG_INIT_CLICK_ROLLUP_WATERMARK = `INSERT INTO click_rollup_watermarks (name, rolled_up_to)
+							SELECT 'url_clicks', COALESCE(date_trunc('hour', MIN(accessed_at)), $1) FROM url_access_logs
+							ON CONFLICT (name) DO NOTHING`
+	PG_GET_CLICK_ROLLUP_WATERMARK    = `SELECT rolled_up_to FROM click_rollup_watermarks WHERE name = 'url_clicks'`
+	PG_GET_HOURLY_CLICK_ROLLUPS      = `SELECT short_path, bucket, clicks FROM url_click_rollups_hourly WHERE bucket >= $1 AND bucket < $2`
+	PG_LOCK_CLICK_ROLLUP_WATERMARK   = `SELECT rolled_up_to FROM click_rollup_watermarks WHERE name = 'url_clicks' FOR UPDATE`
+	PG_UPDATE_CLICK_ROLLUP_WATERMARK = `UPDATE click_rollup_watermarks SET rolled_up_to = $1 WHERE name = 'url_clicks'`
+	PG_ROLL_UP_HOURLY_CLICKS         = `INSERT INTO url_click_rollups_hourly (short_path, bucket, clicks, bot_clicks)
+							SELECT short_path, date_trunc('hour', accessed_at), COUNT(*) FILTER (WHERE NOT is_bot AND NOT is_duplicate), COUNT(*) FILTER (WHERE is_bot AND NOT is_duplicate)
+							FROM url_access_logs
+							WHERE accessed_at >= $1 AND accessed_at < $2
+							GROUP BY 1, 2
+							ON CONFLICT (short_path, bucket) DO UPDATE SET clicks = EXCLUDED.clicks, bot_clicks = EXCLUDED.bot_clicks`
+	// PG_ROLL_UP_DAILY_CLICKS recomputes every day touched by the hours just rolled up from the hourly rollups.
+	PG_ROLL_UP_DAILY_CLICKS = `INSERT INTO url_click_rollups_daily (short_path, bucket, clicks, bot_clicks)
+							SELECT short_path, date_trunc('day', bucket)::date, SUM(clicks), SUM(bot_clicks)
+							FROM url_click_rollups_hourly
+							WHERE bucket >= date_trunc('day', $1::timestamp) AND bucket < $2
+							GROUP BY 1, 2
+							ON CONFLICT (short_path, bucket) DO UPDATE SET clicks = EXCLUDED.clicks, bot_clicks = EXCLUDED.bot_clicks`
+	// The Redis click counters are reconciled up to the click_counters watermark.
+	PG_GET_COUNTER_RECONCILE_WATERMARK    = `SELECT rolled_up_to FROM click_rollup_watermarks WHERE name = 'click_counters'`
+	PG_UPDATE_COUNTER_RECONCILE_WATERMARK = `INSERT INTO click_rollup_watermarks (name, rolled_up_to) VALUES ('click_counters', $1)
+							ON CONFLICT (name) DO UPDATE SET rolled_up_to = EXCLUDED.rolled_up_to`
+	// PG_GET_MINUTE_CLICKS counts raw clicks per link and minute for the Redis minute counters, as they are not rolled up.
+	PG_GET_MINUTE_CLICKS = `SELECT short_path, date_trunc('minute', accessed_at) AS bucket, COUNT(*)
+							FROM url_access_logs
+							WHERE accessed_at >= $1 AND accessed_at < $2 AND NOT is_bot AND NOT is_duplicate
+							GROUP BY short_path, bucket`
+
+	// The anomaly detection watermark starts at $1, there is no baseline to compare older clicks with.
+	PG_INIT_TRAFFIC_ANOMALY_WATERMARK = `INSERT INTO click_rollup_watermarks (name, rolled_up_to) VALUES ('traffic_anomalies', $1)
+							ON CONFLICT (name) DO NOTHING`
+	PG_LOCK_TRAFFIC_ANOMALY_WATERMARK   = `SELECT rolled_up_to FROM click_rollup_watermarks WHERE name = 'traffic_anomalies' FOR UPDATE`
+	PG_UPDATE_TRAFFIC_ANOMALY_WATERMARK = `UPDATE click_rollup_watermarks SET rolled_up_to = $1 WHERE name = 'traffic_anomalies'`
+	PG_GET_BUCKET_CLICKS                = `SELECT short_path, COUNT(*) FROM url_access_logs
+							WHERE accessed_at >= $1 AND accessed_at < $2 AND NOT is_bot AND NOT is_duplicate
+							GROUP BY short_path`
+	PG_LIST_TRAFFIC_BASELINES = `SELECT short_path, mean, variance, samples, state, alerted_at FROM link_traffic_baselines`
+	// PG_UPSERT_TRAFFIC_BASELINES is completed with one ($1, $2, ...) group per row.
+	PG_UPSERT_TRAFFIC_BASELINES          = `INSERT INTO link_traffic_baselines (short_path, mean, variance, samples, state, alerted_at) VALUES `
+	PG_UPSERT_TRAFFIC_BASELINES_CONFLICT = ` ON CONFLICT (short_path) DO UPDATE SET mean = EXCLUDED.mean, variance = EXCLUDED.variance,
+							samples = EXCLUDED.samples, state = EXCLUDED.state, alerted_at = EXCLUDED.alerted_at`
+	PG_DELETE_TRAFFIC_BASELINES = `DELETE FROM link_traffic_baselines WHERE short_path = ANY($1)`
+	PG_LIST_ALERT_SETTINGS      = `SELECT short_path, spike_z, drop_z, min_clicks, enabled FROM link_alert_settings`
+	PG_GET_ALERT_SETTINGS       = `SELECT short_path, spike_z, drop_z, min_clicks, enabled FROM link_alert_settings WHERE short_path = $1`
+	PG_UPSERT_ALERT_SETTINGS    = `INSERT INTO link_alert_settings (short_path, spike_z, drop_z, min_clicks, enabled) VALUES ($1, $2, $3, $4, $5)
+							ON CONFLICT (short_path) DO UPDATE SET spike_z = EXCLUDED.spike_z, drop_z = EXCLUDED.drop_z,
+								min_clicks = EXCLUDED.min_clicks, enabled = EXCLUDED.enabled`
+
+	// PG_INSERT_TRAFFIC_ALERT queues an alert, due at the end of its bucket.
+	PG_INSERT_TRAFFIC_ALERT = `INSERT INTO traffic_alerts (dedup_key, kind, short_path, bucket_start, bucket_end, clicks, baseline, z_score, next_attempt_at)
+							VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $5)
+							ON CONFLICT (dedup_key) DO NOTHING`
+	// PG_CLAIM_TRAFFIC_ALERT leases the oldest alert due at $1 until $2, so other replicas skip it meanwhile.
+	PG_CLAIM_TRAFFIC_ALERT = `WITH due AS (
+								SELECT id FROM traffic_alerts WHERE next_attempt_at <= $1
+								ORDER BY next_attempt_at, id
+								LIMIT 1
+								FOR UPDATE SKIP LOCKED
+							)
+							UPDATE traffic_alerts a SET next_attempt_at = $2, attempts = a.attempts + 1
+							FROM due WHERE a.id = due.id
+							RETURNING a.id, a.dedup_key, a.kind, a.short_path, a.bucket_start, a.bucket_end, a.clicks, a.baseline, a.z_score, a.attempts`
+	PG_RETRY_TRAFFIC_ALERT  = `UPDATE traffic_alerts SET next_attempt_at = $2 WHERE id = $1`
+	PG_DELETE_TRAFFIC_ALERT = `DELETE FROM traffic_alerts WHERE id = $1`
+
+	// PG_INSERT_CONVERSION records a conversion of click $1 made on or after $6. It returns the short path of the
+	// click, NULL when there is no such click, and whether the conversion was new.
+	PG_INSERT_CONVERSION = `WITH click AS (
+								SELECT short_path, accessed_at FROM url_access_logs WHERE click_id = $1 AND accessed_at >= $6 LIMIT 1
+							), inserted AS (
+								INSERT INTO conversions (click_id, short_path, clicked_at, type, value, transaction_id, converted_at)
+								SELECT $1, short_path, accessed_at, $2, $3, NULLIF($4, ''), $5 FROM click
+								ON CONFLICT DO NOTHING
+								RETURNING 1
+							)
+							SELECT (SELECT short_path FROM click), EXISTS (SELECT 1 FROM inserted)`
+	// PG_INSERT_ISSUED_CLICK_CONVERSION records a conversion of click $1 of $2 made at $3 that is not stored yet.
+	PG_INSERT_ISSUED_CLICK_CONVERSION = `INSERT INTO conversions (click_id, short_path, clicked_at, type, value, transaction_id, converted_at)
+							VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+							ON CONFLICT DO NOTHING`
+	// PG_GET_CONVERSION_STATISTICS returns a row per conversion type of $1 and a total row with a NULL type.
+	PG_GET_CONVERSION_STATISTICS = `SELECT type, COUNT(*), COUNT(DISTINCT click_id), COALESCE(SUM(value), 0)
+							FROM conversions
+							WHERE short_path = $1
+							GROUP BY GROUPING SETS ((type), ())
+							ORDER BY GROUPING(type) DESC, COUNT(*) DESC, type`
+
+	// PG_LIST_CLICK_EVENTS returns the page of $7 clicks after the cursor ($5, $6) in [$1, $2) of link $3 and links
+	// created by $4, either is ignored when empty.
+	PG_LIST_CLICK_EVENTS = `SELECT l.id, l.short_path, l.accessed_at, COALESCE(l.referrer_host, ''), COALESCE(l.browser, ''), COALESCE(l.os, ''),
+								COALESCE(l.device_class, ''), COALESCE(l.client_ip, ''), COALESCE(l.language, ''), COALESCE(l.query_string, ''),
+								COALESCE(l.country, ''), COALESCE(l.region, ''), COALESCE(l.city, ''), l.is_bot, l.is_duplicate, COALESCE(l.click_id, '')
+							FROM url_access_logs l
+							WHERE l.accessed_at >= $1 AND l.accessed_at < $2 AND (l.accessed_at, l.id) > ($5, $6)
+								AND ($3 = '' OR l.short_path = $3)
+								AND ($4 = '' OR l.short_path IN (SELECT short_path FROM urls WHERE created_by = $4
+																UNION ALL SELECT short_path FROM urls_archive WHERE created_by = $4))
+							ORDER BY l.accessed_at, l.id
+							LIMIT $7`
+
+	// The partition statements are completed with fmt.Sprintf since identifiers cannot be bind parameters.
+	PG_CREATE_ACCESS_LOG_PARTITION = `CREATE TABLE IF NOT EXISTS %s PARTITION OF url_access_logs FOR VALUES FROM ('%s') TO ('%s')`
+	PG_LIST_ACCESS_LOG_PARTITIONS  = `SELECT c.relname FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid WHERE i.inhparent = 'url_access_logs'::regclass`
+	PG_DROP_ACCESS_LOG_PARTITION   = `DROP TABLE IF EXISTS %s`
+	PG_DETACH_ACCESS_LOG_PARTITION = `ALTER TABLE url_access_logs DETACH PARTITION %s`
+	PG_RENAME_ACCESS_LOG_PARTITION = `ALTER TABLE %s RENAME TO %s`
+
+	PG_ACCESS_LOG_PARTITION_EXISTS = `SELECT to_regclass($1) IS NOT NULL`
+	PG_LIFT_STATEMENT_TIMEOUT      = `SET LOCAL statement_timeout = 0`
+	// Clicks logged while the partition of their month was missing are in the default partition, Postgres refuses to
+	// create the partition over them. They are set aside, under a lock so no more arrive, and logged again once it exists.
+	PG_LOCK_ACCESS_LOGS              = `LOCK TABLE url_access_logs IN ACCESS EXCLUSIVE MODE`
+	PG_CREATE_MOVED_ACCESS_LOGS      = `CREATE TEMPORARY TABLE url_access_logs_moved (LIKE url_access_logs) ON COMMIT DROP`
+	PG_MOVE_OUT_OF_DEFAULT_PARTITION = `WITH moved AS (DELETE FROM url_access_logs_default WHERE accessed_at >= $1 AND accessed_at < $2 RETURNING *)
+							INSERT INTO url_access_logs_moved SELECT * FROM moved`
+	PG_MOVE_INTO_ACCESS_LOG_PARTITIONS = `INSERT INTO url_access_logs SELECT * FROM url_access_logs_moved`
+
+	// The purge watermarks share the rollup watermark table, named purge_<column>. Clicks before one are purged already.
+	PG_GET_PURGE_WATERMARK    = `SELECT rolled_up_to FROM click_rollup_watermarks WHERE name = $1`
+	PG_UPDATE_PURGE_WATERMARK = `INSERT INTO click_rollup_watermarks (name, rolled_up_to) VALUES ($1, $2)
+							ON CONFLICT (name) DO UPDATE SET rolled_up_to = GREATEST(click_rollup_watermarks.rolled_up_to, EXCLUDED.rolled_up_to)`
+	// PG_PURGE_ACCESS_LOG_COLUMN clears the column, filled in with fmt.Sprintf, of up to $3 clicks in [$1, $2).
+	PG_PURGE_ACCESS_LOG_COLUMN = `UPDATE url_access_logs SET %[1]s = NULL
+							WHERE (id, accessed_at) IN (SELECT id, accessed_at FROM url_access_logs
+														WHERE accessed_at >= $1 AND accessed_at < $2 AND %[1]s IS NOT NULL
+														LIMIT $3)`
+
+	PG_INSERT_WEBHOOK_SUBSCRIPTION = `INSERT INTO webhook_subscriptions (url, secret, event_types, active, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $5) RETURNING id`
+	PG_GET_WEBHOOK_SUBSCRIPTION    = `SELECT id, url, secret, event_types, active, created_at, updated_at FROM webhook_subscriptions WHERE id = $1`
+	PG_LIST_WEBHOOK_SUBSCRIPTIONS  = `SELECT id, url, secret, event_types, active, created_at, updated_at FROM webhook_subscriptions ORDER BY id`
+	PG_UPDATE_WEBHOOK_SUBSCRIPTION = `UPDATE webhook_subscriptions SET url = $2, secret = $3, event_types = $4, active = $5, updated_at = $6 WHERE id = $1`
+	PG_DELETE_WEBHOOK_SUBSCRIPTION = `DELETE FROM webhook_subscriptions WHERE id = $1`
+	// PG_LIST_WEBHOOK_DELIVERIES returns the $3 latest deliveries of subscription $1 before delivery $2, 0 starts at the latest.
+	PG_LIST_WEBHOOK_DELIVERIES = `SELECT d.id, d.subscription_id, d.event_id, e.type, d.status, d.attempts, d.next_attempt_at, d.last_status_code,
+									COALESCE(d.last_error, ''), d.created_at, d.delivered_at
+							FROM webhook_deliveries d
+							JOIN outbox_events e ON e.id = d.event_id
+							WHERE d.subscription_id = $1 AND ($2 = 0 OR d.id < $2)
+							ORDER BY d.id DESC
+							LIMIT $3`
+
+	// The outbox is read in (txid, id) order, only up to the oldest transaction still running so no event can commit
+	// behind the offset.
+	PG_INIT_OUTBOX_OFFSET = `INSERT INTO outbox_offsets (consumer, txid, event_id) VALUES ($1, '0', 0) ON CONFLICT (consumer) DO NOTHING`
+	PG_LOCK_OUTBOX_OFFSET = `SELECT txid::text, event_id FROM outbox_offsets WHERE consumer = $1 FOR UPDATE`
+	PG_LIST_OUTBOX_EVENTS = `SELECT id, txid::text, type, short_path, payload, created_at FROM outbox_events
+							WHERE (txid, id) > ($1::xid8, $2) AND txid < pg_snapshot_xmin(pg_current_snapshot())
+							ORDER BY txid, id
+							LIMIT $3`
+	PG_UPDATE_OUTBOX_OFFSET = `UPDATE outbox_offsets SET txid = $2::xid8, event_id = $3 WHERE consumer = $1`
+	// PG_PRUNE_OUTBOX_EVENTS deletes up to $2 events created before $1 that every consumer has read, with their
+	// finished webhook deliveries. Events still being delivered are kept.
+	PG_PRUNE_OUTBOX_EVENTS = `WITH pruned AS (
+								SELECT e.id FROM outbox_events e
+								WHERE e.created_at < $1
+								AND (e.txid, e.id) <= ALL (SELECT txid, event_id FROM outbox_offsets)
+								AND NOT EXISTS (SELECT 1 FROM webhook_deliveries d WHERE d.event_id = e.id AND d.status = 'pending')
+								ORDER BY e.id
+								LIMIT $2
+							), deliveries AS (
+								DELETE FROM webhook_deliveries d USING pruned WHERE d.event_id = pruned.id
+							)
+							DELETE FROM outbox_events e USING pruned WHERE e.id = pruned.id`
+	// PG_INSERT_WEBHOOK_DELIVERIES fans the events $1 out to every active subscription of their type.
+	PG_INSERT_WEBHOOK_DELIVERIES = `INSERT INTO webhook_deliveries (subscription_id, event_id, next_attempt_at, created_at)
+							SELECT s.id, e.id, $2, $2
+							FROM outbox_events e
+							JOIN webhook_subscriptions s ON s.active AND (cardinality(s.event_types) = 0 OR e.type = ANY(s.event_types))
+							WHERE e.id = ANY($1)
+							ON CONFLICT (subscription_id, event_id) DO NOTHING`
+	// PG_CLAIM_WEBHOOK_DELIVERIES leases up to $3 deliveries due at $1 until $2, so other replicas skip them meanwhile.
+	PG_CLAIM_WEBHOOK_DELIVERIES = `WITH due AS (
+								SELECT d.id FROM webhook_deliveries d
+								JOIN webhook_subscriptions s ON s.id = d.subscription_id AND s.active
+								WHERE d.status = 'pending' AND d.next_attempt_at <= $1
+								ORDER BY d.next_attempt_at
+								LIMIT $3
+								FOR UPDATE OF d SKIP LOCKED
+							)
+							UPDATE webhook_deliveries d SET next_attempt_at = $2, attempts = d.attempts + 1
+							FROM due, webhook_subscriptions s, outbox_events e
+							WHERE d.id = due.id AND s.id = d.subscription_id AND e.id = d.event_id
+							RETURNING d.id, d.subscription_id, d.event_id, d.attempts, d.created_at, s.url, s.secret, e.type, e.short_path, e.payload, e.created_at`
+	PG_COMPLETE_WEBHOOK_DELIVERY = `UPDATE webhook_deliveries SET status = $2, next_attempt_at = COALESCE($3, next_attempt_at), last_status_code = $4, last_error = $5, delivered_at = $6 WHERE id = $1`
+
+	// PG_LOCK_LATEST_URL_ARCHIVE locks the latest archived version of a path, so concurrent restores of it take turns.
+	PG_LOCK_LATEST_URL_ARCHIVE = `SELECT id, short_path, original_url, expiry, created_at, created_by, modified_at, modified_by, deleted_at, deleted_by
+								FROM urls_archive WHERE short_path = $1 ORDER BY id DESC LIMIT 1 FOR UPDATE`
+	// PG_RESTORE_SHORT_URL inserts nothing when the path was taken by another link in the meantime.
+	PG_RESTORE_SHORT_URL  = `INSERT INTO urls (short_path, original_url, expiry, created_at, created_by, modified_at, modified_by) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (short_path) DO NOTHING`
+	PG_DELETE_URL_ARCHIVE = `DELETE FROM urls_archive WHERE id = $1`
+	// PG_LIST_URL_ARCHIVE returns the $3 latest archived versions of path $1, or of every path when it is empty, before version $2.
+	PG_LIST_URL_ARCHIVE = `SELECT id, short_path, original_url, expiry, created_at, created_by, modified_at, modified_by, deleted_at, deleted_by
+							FROM urls_archive WHERE ($1 = '' OR short_path = $1) AND ($2 = 0 OR id < $2) ORDER BY id DESC LIMIT $3`
+
+	PG_INSERT_AUDIT_ENTRY = `INSERT INTO audit_log (action, short_path, actor, source_ip, user_agent, request_id, before, after, changes, created_at)
+							VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9, $10)`
+	PG_LIST_AUDIT_ENTRIES = `SELECT id, action, short_path, actor, COALESCE(source_ip, ''), COALESCE(user_agent, ''), COALESCE(request_id, ''),
+									before, after, changes, created_at
+							FROM audit_log
+							WHERE ($1 = '' OR actor = $1) AND ($2 = '' OR short_path = $2)
+								AND ($3::timestamp IS NULL OR created_at >= $3) AND ($4::timestamp IS NULL OR created_at < $4)
+								AND ($5 = 0 OR id < $5)
+							ORDER BY id DESC
+							LIMIT $6`
+
+	PG_LIST_URLS     = `SELECT short_path, original_url, expiry, created_at, created_by, modified_at, modified_by FROM urls WHERE short_path > $1 AND (expiry IS NULL OR expiry > $2) ORDER BY short_path LIMIT $3`
+	PG_LIST_TOP_URLS = `SELECT u.short_path, u.original_url, u.expiry, u.created_at, u.created_by, u.modified_at, u.modified_by
+							FROM urls u
+							JOIN (SELECT short_path, COUNT(*) AS clicks
+									FROM url_access_logs
+									WHERE accessed_at >= $1 AND NOT is_bot AND NOT is_duplicate
+									GROUP BY short_path
+									ORDER BY clicks DESC
+									LIMIT $2) top ON top.short_path = u.short_path
+							WHERE u.expiry IS NULL OR u.expiry > $3
+							ORDER BY top.clicks DESC`
 )
 
 var (
@@ -23,6 +345,7 @@ var (
 	ErrShortURLAlreadyExists    = errors.New("short url already exists")
 	ErrOriginalURLAlreadyExists = errors.New("original url already exists")
 	ErrURLNotFound              = errors.New("url not found")
+	ErrClickNotFound            = errors.New("click not found")
 	ErrInternalServerError      = errors.New("internal server error")
 	ErrRequestTimeout           = errors.New("request timeout")
 	ErrInvalidPayload           = errors.New("invalid payload")
@@ -32,4 +355,6 @@ var (
 	ErrShortURLNotFound         = errors.New("short url not found")
 	ErrURLStatisticsNotFound    = errors.New("url statistics not found")
 	ErrURLExpired               = errors.New("url expired")
+	ErrUnknownAccessLogColumn   = errors.New("unknown access log column")
+	ErrWebhookNotFound          = errors.New("webhook not found")
 )
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=ConversionRepository --output=./mocks
+type ConversionRepository interface {
+	// InsertConversion records conversion against its click if the click was made at or after clickedAfter, and
+	// fills in the short path of the click. It reports whether the conversion was new, a conversion with a
+	// transaction ID already recorded for the click is not added again. It returns ErrClickNotFound when there is
+	// no such click.
+	InsertConversion(ctx context.Context, conversion *models.Conversion, clickedAfter time.Time) (bool, error)
+	// InsertIssuedClickConversion records conversion against an issued click that is not stored yet, with the same
+	// transaction ID handling as InsertConversion. conversion takes the short path of the click.
+	InsertIssuedClickConversion(ctx context.Context, conversion *models.Conversion, click *models.IssuedClick) (bool, error)
+	GetConversionStatistics(ctx context.Context, shortPath string) (*models.ConversionStatistics, error)
+}
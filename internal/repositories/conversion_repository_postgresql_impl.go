@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+)
+
+type conversionRepositoryPostgresqlImpl struct {
+	cluster *db.PostgresCluster
+}
+
+func NewConversionRepositoryPostgresql(cluster *db.PostgresCluster) ConversionRepository {
+	return &conversionRepositoryPostgresqlImpl{cluster: cluster}
+}
+
+// InsertConversion implements ConversionRepository.
+func (r *conversionRepositoryPostgresqlImpl) InsertConversion(ctx context.Context, conversion *models.Conversion, clickedAfter time.Time) (bool, error) {
+	row := r.cluster.Primary().QueryRowContext(ctx, PG_INSERT_CONVERSION, conversion.ClickID, conversion.Type, conversion.Value,
+		conversion.TransactionID, conversion.ConvertedAt.UTC(), clickedAfter.UTC())
+	var shortPath sql.NullString
+	var inserted bool
+	if err := row.Scan(&shortPath, &inserted); err != nil {
+		log.Printf("Error inserting conversion of click %s: %v", conversion.ClickID, err)
+		return false, ErrDBError
+	}
+	if !shortPath.Valid {
+		return false, ErrClickNotFound
+	}
+	conversion.ShortPath = shortPath.String
+	return inserted, nil
+}
+
+// InsertIssuedClickConversion implements ConversionRepository.
+func (r *conversionRepositoryPostgresqlImpl) InsertIssuedClickConversion(ctx context.Context, conversion *models.Conversion, click *models.IssuedClick) (bool, error) {
+	result, err := r.cluster.Primary().ExecContext(ctx, PG_INSERT_ISSUED_CLICK_CONVERSION, conversion.ClickID, click.ShortPath,
+		click.ClickedAt.UTC(), conversion.Type, conversion.Value, conversion.TransactionID, conversion.ConvertedAt.UTC())
+	if err != nil {
+		log.Printf("Error inserting conversion of issued click %s: %v", conversion.ClickID, err)
+		return false, ErrDBError
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error inserting conversion of issued click %s: %v", conversion.ClickID, err)
+		return false, ErrDBError
+	}
+	conversion.ShortPath = click.ShortPath
+	return inserted > 0, nil
+}
+
+// GetConversionStatistics implements ConversionRepository.
+func (r *conversionRepositoryPostgresqlImpl) GetConversionStatistics(ctx context.Context, shortPath string) (*models.ConversionStatistics, error) {
+	rows, err := r.cluster.Reader(ctx).QueryContext(ctx, PG_GET_CONVERSION_STATISTICS, shortPath)
+	if err != nil {
+		log.Printf("Error getting conversion statistics of %s: %v", shortPath, err)
+		return nil, ErrDBError
+	}
+	defer rows.Close()
+
+	statistics := &models.ConversionStatistics{Types: []models.ConversionTypeStats{}}
+	for rows.Next() {
+		var conversionType sql.NullString
+		var stats models.ConversionTypeStats
+		var convertedClicks int64
+		if err := rows.Scan(&conversionType, &stats.Conversions, &convertedClicks, &stats.Value); err != nil {
+			log.Printf("Error scanning conversion statistics of %s: %v", shortPath, err)
+			return nil, ErrDBError
+		}
+		if !conversionType.Valid {
+			statistics.Conversions = stats.Conversions
+			statistics.ConvertedClicks = convertedClicks
+			statistics.Value = stats.Value
+			continue
+		}
+		stats.Type = conversionType.String
+		statistics.Types = append(statistics.Types, stats)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error reading conversion statistics of %s: %v", shortPath, err)
+		return nil, ErrDBError
+	}
+	return statistics, nil
+}
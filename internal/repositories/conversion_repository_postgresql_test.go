@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversionRepositoryPostgresqlImpl_InsertConversion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewConversionRepositoryPostgresql(newTestCluster(db))
+	convertedAt := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	clickedAfter := convertedAt.Add(-720 * time.Hour)
+	conversion := &models.Conversion{ClickID: "clk_123", Type: "purchase", Value: 19.5, TransactionID: "order-7", ConvertedAt: convertedAt}
+
+	mock.ExpectQuery("WITH click AS \\( SELECT short_path, accessed_at FROM url_access_logs WHERE click_id = \\$1").
+		WithArgs("clk_123", "purchase", 19.5, "order-7", convertedAt, clickedAfter).
+		WillReturnRows(sqlmock.NewRows([]string{"short_path", "inserted"}).AddRow("abc", true))
+
+	created, err := repo.InsertConversion(context.Background(), conversion, clickedAfter)
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "abc", conversion.ShortPath)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConversionRepositoryPostgresqlImpl_InsertConversion_UnknownClick(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewConversionRepositoryPostgresql(newTestCluster(db))
+	mock.ExpectQuery("WITH click AS").WillReturnRows(sqlmock.NewRows([]string{"short_path", "inserted"}).AddRow(nil, false))
+
+	_, err = repo.InsertConversion(context.Background(), &models.Conversion{ClickID: "missing", Type: "signup"}, time.Time{})
+
+	assert.ErrorIs(t, err, ErrClickNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConversionRepositoryPostgresqlImpl_InsertIssuedClickConversion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewConversionRepositoryPostgresql(newTestCluster(db))
+	clickedAt := time.Date(2025, 6, 12, 9, 59, 58, 0, time.UTC)
+	convertedAt := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	conversion := &models.Conversion{ClickID: "clk_123", Type: "purchase", Value: 19.5, TransactionID: "order-7", ConvertedAt: convertedAt}
+
+	mock.ExpectExec("INSERT INTO conversions").
+		WithArgs("clk_123", "abc", clickedAt, "purchase", 19.5, "order-7", convertedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO conversions").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	created, err := repo.InsertIssuedClickConversion(context.Background(), conversion, &models.IssuedClick{ClickID: "clk_123", ShortPath: "abc", ClickedAt: clickedAt})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "abc", conversion.ShortPath)
+
+	// The same transaction ID again is not a new conversion.
+	created, err = repo.InsertIssuedClickConversion(context.Background(), conversion, &models.IssuedClick{ClickID: "clk_123", ShortPath: "abc", ClickedAt: clickedAt})
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConversionRepositoryPostgresqlImpl_GetConversionStatistics(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewConversionRepositoryPostgresql(newTestCluster(db))
+	mock.ExpectQuery("SELECT type, COUNT\\(\\*\\), COUNT\\(DISTINCT click_id\\), COALESCE\\(SUM\\(value\\), 0\\) FROM conversions").WithArgs("abc").
+		WillReturnRows(sqlmock.NewRows([]string{"type", "count", "clicks", "value"}).
+			AddRow(nil, 5, 3, "120.0000").
+			AddRow("purchase", 3, 2, "120.0000").
+			AddRow("signup", 2, 2, "0.0000"))
+
+	statistics, err := repo.GetConversionStatistics(context.Background(), "abc")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.ConversionStatistics{Conversions: 5, ConvertedClicks: 3, Value: 120, Types: []models.ConversionTypeStats{
+		{Type: "purchase", Conversions: 3, Value: 120},
+		{Type: "signup", Conversions: 2},
+	}}, statistics)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/db"
+)
+
+// newTestCluster wraps a single connection, so every query goes to it.
+func newTestCluster(sqlDB *sql.DB) *db.PostgresCluster {
+	return db.NewPostgresCluster(sqlDB, nil, &config.DatabaseConfig{})
+}
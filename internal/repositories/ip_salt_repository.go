@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockery --name=IPSaltRepository --output=./mocks
+type IPSaltRepository interface {
+	// GetSalt returns the salt of the period starting at periodStart, creating it with the given salt when there is
+	// none yet. The salt is deleted after ttl, so hashes made with it can no longer be reproduced.
+	GetSalt(ctx context.Context, periodStart time.Time, salt []byte, ttl time.Duration) ([]byte, error)
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"url-shortener/internal/db"
+)
+
+// ipSaltRepositoryRedisImpl shares the IP hashing salts between replicas, so the same IP hashes the same everywhere
+// within a period. SET NX lets the first replica to need a salt pick it.
+type ipSaltRepositoryRedisImpl struct {
+	client db.RedisClient
+}
+
+func NewIPSaltRepositoryRedis(client db.RedisClient) IPSaltRepository {
+	return &ipSaltRepositoryRedisImpl{client: client}
+}
+
+// GetSalt implements IPSaltRepository.
+func (r *ipSaltRepositoryRedisImpl) GetSalt(ctx context.Context, periodStart time.Time, salt []byte, ttl time.Duration) ([]byte, error) {
+	key := ipSaltKey(periodStart)
+	created, err := r.client.SetNX(ctx, key, salt, ttl).Result()
+	if err != nil {
+		log.Printf("Error creating IP salt %s: %v", key, err)
+		return nil, ErrRedisError
+	}
+	if created {
+		return salt, nil
+	}
+	existing, err := r.client.Get(ctx, key).Bytes()
+	if err != nil {
+		log.Printf("Error reading IP salt %s: %v", key, err)
+		return nil, ErrRedisError
+	}
+	return existing, nil
+}
+
+func ipSaltKey(periodStart time.Time) string {
+	return "privacy:ip_salt:" + strconv.FormatInt(periodStart.Unix(), 10)
+}
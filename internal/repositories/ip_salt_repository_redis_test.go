@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dbMocks "url-shortener/internal/db/mocks"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisGetSalt_Created(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewIPSaltRepositoryRedis(mockClient)
+	ctx := context.Background()
+	period := time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC)
+	mockClient.On("SetNX", ctx, "privacy:ip_salt:1749686400", []byte("new"), 48*time.Hour).Return(redis.NewBoolResult(true, nil)).Once()
+
+	salt, err := repo.GetSalt(ctx, period, []byte("new"), 48*time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("new"), salt)
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisGetSalt_Existing(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewIPSaltRepositoryRedis(mockClient)
+	ctx := context.Background()
+	period := time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC)
+	mockClient.On("SetNX", ctx, "privacy:ip_salt:1749686400", []byte("new"), 48*time.Hour).Return(redis.NewBoolResult(false, nil)).Once()
+	mockClient.On("Get", ctx, "privacy:ip_salt:1749686400").Return(redis.NewStringResult("existing", nil)).Once()
+
+	salt, err := repo.GetSalt(ctx, period, []byte("new"), 48*time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("existing"), salt)
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisGetSalt_Error(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewIPSaltRepositoryRedis(mockClient)
+	mockClient.On("SetNX", context.Background(), "privacy:ip_salt:1749686400", []byte("new"), time.Hour).Return(redis.NewBoolResult(false, errors.New("redis error"))).Once()
+
+	_, err := repo.GetSalt(context.Background(), time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC), []byte("new"), time.Hour)
+
+	assert.ErrorIs(t, err, ErrRedisError)
+}
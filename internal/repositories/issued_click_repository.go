@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=IssuedClickRepository --output=./mocks
+type IssuedClickRepository interface {
+	// Issue records the click ID handed out on a redirect, so a conversion can be reported before the click is
+	// stored in Postgres.
+	Issue(ctx context.Context, click *models.IssuedClick) error
+	// Get returns the issued click, ErrClickNotFound when it is unknown or has expired.
+	Get(ctx context.Context, clickID string) (*models.IssuedClick, error)
+}
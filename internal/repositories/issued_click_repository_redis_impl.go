@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// issuedClickDefaultTTL keeps issued clicks when conversions have no attribution window. Postgres then holds the
+// clicks for good, the keys only have to outlive the time clicks spend in the ingestion queue.
+const issuedClickDefaultTTL = 24 * time.Hour
+
+// issuedClickRepositoryRedisImpl keeps a key per issued click ID that expires with the attribution window.
+type issuedClickRepositoryRedisImpl struct {
+	client db.RedisClient
+	ttl    time.Duration
+}
+
+// NewIssuedClickRepositoryRedis keeps issued clicks for ttl, a day when it is zero.
+func NewIssuedClickRepositoryRedis(client db.RedisClient, ttl time.Duration) IssuedClickRepository {
+	if ttl <= 0 {
+		ttl = issuedClickDefaultTTL
+	}
+	return &issuedClickRepositoryRedisImpl{client: client, ttl: ttl}
+}
+
+// Issue implements IssuedClickRepository.
+func (r *issuedClickRepositoryRedisImpl) Issue(ctx context.Context, click *models.IssuedClick) error {
+	data, err := json.Marshal(click)
+	if err != nil {
+		log.Printf("Error encoding issued click %s: %v", click.ClickID, err)
+		return ErrRedisError
+	}
+	if err := r.client.Set(ctx, issuedClickKey(click.ClickID), data, r.ttl).Err(); err != nil {
+		log.Printf("Error storing issued click %s: %v", click.ClickID, err)
+		return ErrRedisError
+	}
+	return nil
+}
+
+// Get implements IssuedClickRepository.
+func (r *issuedClickRepositoryRedisImpl) Get(ctx context.Context, clickID string) (*models.IssuedClick, error) {
+	data, err := r.client.Get(ctx, issuedClickKey(clickID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrClickNotFound
+	}
+	if err != nil {
+		log.Printf("Error reading issued click %s: %v", clickID, err)
+		return nil, ErrRedisError
+	}
+	click := &models.IssuedClick{}
+	if err := json.Unmarshal(data, click); err != nil {
+		log.Printf("Error decoding issued click %s: %v", clickID, err)
+		return nil, ErrRedisError
+	}
+	click.ClickID = clickID
+	return click, nil
+}
+
+func issuedClickKey(clickID string) string {
+	return "clickid:" + clickID
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dbMocks "url-shortener/internal/db/mocks"
+	"url-shortener/internal/models"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRedisIssueClick(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewIssuedClickRepositoryRedis(mockClient, 720*time.Hour)
+	ctx := context.Background()
+	click := &models.IssuedClick{ClickID: "clk_123", ShortPath: "abc", ClickedAt: time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)}
+	mockClient.On("Set", ctx, "clickid:clk_123", []byte(`{"short_path":"abc","clicked_at":"2025-06-12T10:00:00Z"}`), 720*time.Hour).
+		Return(redis.NewStatusResult("OK", nil)).Once()
+
+	assert.NoError(t, repo.Issue(ctx, click))
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisIssueClick_DefaultTTL(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewIssuedClickRepositoryRedis(mockClient, 0)
+	ctx := context.Background()
+	mockClient.On("Set", ctx, "clickid:clk_123", mock.Anything, 24*time.Hour).Return(redis.NewStatusResult("", errors.New("redis error"))).Once()
+
+	err := repo.Issue(ctx, &models.IssuedClick{ClickID: "clk_123", ShortPath: "abc"})
+
+	assert.ErrorIs(t, err, ErrRedisError)
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisGetIssuedClick(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewIssuedClickRepositoryRedis(mockClient, time.Hour)
+	ctx := context.Background()
+	mockClient.On("Get", ctx, "clickid:clk_123").Return(redis.NewStringResult(`{"short_path":"abc","clicked_at":"2025-06-12T10:00:00Z"}`, nil)).Once()
+	mockClient.On("Get", ctx, "clickid:missing").Return(redis.NewStringResult("", redis.Nil)).Once()
+	mockClient.On("Get", ctx, "clickid:broken").Return(redis.NewStringResult("", errors.New("redis error"))).Once()
+
+	click, err := repo.Get(ctx, "clk_123")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.IssuedClick{ClickID: "clk_123", ShortPath: "abc", ClickedAt: time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)}, click)
+
+	_, err = repo.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrClickNotFound)
+
+	_, err = repo.Get(ctx, "broken")
+	assert.ErrorIs(t, err, ErrRedisError)
+}
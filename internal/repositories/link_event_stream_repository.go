@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=LinkEventStreamRepository --output=./mocks
+type LinkEventStreamRepository interface {
+	// AppendEvents adds events to the end of stream, trimming it to about maxLen entries when maxLen is above 0.
+	AppendEvents(ctx context.Context, stream string, maxLen int64, events []models.OutboxEvent) error
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type linkEventStreamRepositoryRedisImpl struct {
+	client db.RedisClient
+}
+
+func NewLinkEventStreamRepositoryRedis(client db.RedisClient) LinkEventStreamRepository {
+	return &linkEventStreamRepositoryRedisImpl{client: client}
+}
+
+// AppendEvents implements LinkEventStreamRepository. Every event is one entry with the fields id, type, shortPath,
+// occurredAt and data, the entry IDs are left to Redis. Consumers can use id to drop an event added twice.
+func (r *linkEventStreamRepositoryRedisImpl) AppendEvents(ctx context.Context, stream string, maxLen int64, events []models.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, event := range events {
+			pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: stream,
+				MaxLen: maxLen,
+				Approx: true,
+				Values: []interface{}{
+					"id", strconv.FormatInt(event.ID, 10),
+					"type", event.Type,
+					"shortPath", event.ShortPath,
+					"occurredAt", event.CreatedAt.UTC().Format(time.RFC3339Nano),
+					"data", string(event.Payload),
+				},
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error appending %d link events to stream %s: %v", len(events), stream, err)
+		return ErrRedisError
+	}
+	return nil
+}
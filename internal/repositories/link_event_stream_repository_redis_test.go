@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+
+	dbMocks "url-shortener/internal/db/mocks"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRedisAppendLinkEvents(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewLinkEventStreamRepositoryRedis(mockClient)
+	pipe := redis.NewClient(&redis.Options{}).Pipeline()
+	mockClient.On("Pipelined", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(redis.Pipeliner) error)
+		assert.NoError(t, fn(pipe))
+	}).Return(nil, nil).Once()
+
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	err := repo.AppendEvents(context.Background(), "link-events", 1000, []models.OutboxEvent{
+		{ID: 21, Type: models.LinkEventCreated, ShortPath: "abc", Payload: []byte(`{"shortPath":"abc"}`), CreatedAt: now},
+		{ID: 22, Type: models.LinkEventDeleted, ShortPath: "abc", Payload: []byte(`{"shortPath":"abc"}`), CreatedAt: now},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pipe.Len())
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisAppendLinkEvents_Error(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewLinkEventStreamRepositoryRedis(mockClient)
+	mockClient.On("Pipelined", mock.Anything, mock.Anything).Return(nil, errors.New("redis error")).Once()
+
+	err := repo.AppendEvents(context.Background(), "link-events", 0, []models.OutboxEvent{{ID: 21}})
+
+	assert.ErrorIs(t, err, ErrRedisError)
+}
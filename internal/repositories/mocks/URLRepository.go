@@ -16,17 +16,17 @@ type URLRepository struct {
 	mock.Mock
 }
 
-// DeleteShortURL provides a mock function with given fields: ctx, shortPath, currentTime, deletedBy
-func (_m *URLRepository) DeleteShortURL(ctx context.Context, shortPath string, currentTime time.Time, deletedBy string) error {
-	ret := _m.Called(ctx, shortPath, currentTime, deletedBy)
+// DeleteShortURL provides a mock function with given fields: ctx, shortPath, currentTime, deletedBy, actor
+func (_m *URLRepository) DeleteShortURL(ctx context.Context, shortPath string, currentTime time.Time, deletedBy string, actor *models.AuditActor) error {
+	ret := _m.Called(ctx, shortPath, currentTime, deletedBy, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteShortURL")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, string) error); ok {
-		r0 = rf(ctx, shortPath, currentTime, deletedBy)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, string, *models.AuditActor) error); ok {
+		r0 = rf(ctx, shortPath, currentTime, deletedBy, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -94,17 +94,17 @@ func (_m *URLRepository) GetShortURL(ctx context.Context, originalURL string) (*
 	return r0, r1
 }
 
-// InsertShortURL provides a mock function with given fields: ctx, url
-func (_m *URLRepository) InsertShortURL(ctx context.Context, url *models.URL) error {
-	ret := _m.Called(ctx, url)
+// InsertShortURL provides a mock function with given fields: ctx, url, actor
+func (_m *URLRepository) InsertShortURL(ctx context.Context, url *models.URL, actor *models.AuditActor) error {
+	ret := _m.Called(ctx, url, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InsertShortURL")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.URL) error); ok {
-		r0 = rf(ctx, url)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.URL, *models.AuditActor) error); ok {
+		r0 = rf(ctx, url, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -112,17 +112,17 @@ func (_m *URLRepository) InsertShortURL(ctx context.Context, url *models.URL) er
 	return r0
 }
 
-// UpdateShortURL provides a mock function with given fields: ctx, url
-func (_m *URLRepository) UpdateShortURL(ctx context.Context, url *models.URL) error {
-	ret := _m.Called(ctx, url)
+// UpdateShortURL provides a mock function with given fields: ctx, url, actor
+func (_m *URLRepository) UpdateShortURL(ctx context.Context, url *models.URL, actor *models.AuditActor) error {
+	ret := _m.Called(ctx, url, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateShortURL")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.URL) error); ok {
-		r0 = rf(ctx, url)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.URL, *models.AuditActor) error); ok {
+		r0 = rf(ctx, url, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -16,9 +16,99 @@ type URLStatisticsRepository struct {
 	mock.Mock
 }
 
-// GetURLStatistics provides a mock function with given fields: ctx, shortPath
-func (_m *URLStatisticsRepository) GetURLStatistics(ctx context.Context, shortPath string) (*models.URLStatistics, error) {
-	ret := _m.Called(ctx, shortPath)
+// GetAccessCounts provides a mock function with given fields: ctx, shortPath, from, to, interval, timeZone, includeBots
+func (_m *URLStatisticsRepository) GetAccessCounts(ctx context.Context, shortPath string, from time.Time, to time.Time, interval string, timeZone string, includeBots bool) ([]models.TimeseriesBucket, error) {
+	ret := _m.Called(ctx, shortPath, from, to, interval, timeZone, includeBots)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccessCounts")
+	}
+
+	var r0 []models.TimeseriesBucket
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, string, string, bool) ([]models.TimeseriesBucket, error)); ok {
+		return rf(ctx, shortPath, from, to, interval, timeZone, includeBots)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, string, string, bool) []models.TimeseriesBucket); ok {
+		r0 = rf(ctx, shortPath, from, to, interval, timeZone, includeBots)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.TimeseriesBucket)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time, string, string, bool) error); ok {
+		r1 = rf(ctx, shortPath, from, to, interval, timeZone, includeBots)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDomainClicks provides a mock function with given fields: ctx, since, limit
+func (_m *URLStatisticsRepository) GetDomainClicks(ctx context.Context, since time.Time, limit int) ([]models.DomainClicks, error) {
+	ret := _m.Called(ctx, since, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDomainClicks")
+	}
+
+	var r0 []models.DomainClicks
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]models.DomainClicks, error)); ok {
+		return rf(ctx, since, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []models.DomainClicks); ok {
+		r0 = rf(ctx, since, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.DomainClicks)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, since, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTopLinks provides a mock function with given fields: ctx, since, limit
+func (_m *URLStatisticsRepository) GetTopLinks(ctx context.Context, since time.Time, limit int) ([]models.LinkClicks, error) {
+	ret := _m.Called(ctx, since, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTopLinks")
+	}
+
+	var r0 []models.LinkClicks
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]models.LinkClicks, error)); ok {
+		return rf(ctx, since, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []models.LinkClicks); ok {
+		r0 = rf(ctx, since, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.LinkClicks)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, since, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetURLStatistics provides a mock function with given fields: ctx, shortPath, includeBots
+func (_m *URLStatisticsRepository) GetURLStatistics(ctx context.Context, shortPath string, includeBots bool) (*models.URLStatistics, error) {
+	ret := _m.Called(ctx, shortPath, includeBots)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetURLStatistics")
@@ -26,19 +116,19 @@ func (_m *URLStatisticsRepository) GetURLStatistics(ctx context.Context, shortPa
 
 	var r0 *models.URLStatistics
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.URLStatistics, error)); ok {
-		return rf(ctx, shortPath)
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) (*models.URLStatistics, error)); ok {
+		return rf(ctx, shortPath, includeBots)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.URLStatistics); ok {
-		r0 = rf(ctx, shortPath)
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) *models.URLStatistics); ok {
+		r0 = rf(ctx, shortPath, includeBots)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.URLStatistics)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, shortPath)
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
+		r1 = rf(ctx, shortPath, includeBots)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -46,17 +136,17 @@ func (_m *URLStatisticsRepository) GetURLStatistics(ctx context.Context, shortPa
 	return r0, r1
 }
 
-// InsertAccessLog provides a mock function with given fields: ctx, shortPath, accessedAt
-func (_m *URLStatisticsRepository) InsertAccessLog(ctx context.Context, shortPath string, accessedAt time.Time) error {
-	ret := _m.Called(ctx, shortPath, accessedAt)
+// InsertAccessLogs provides a mock function with given fields: ctx, logs
+func (_m *URLStatisticsRepository) InsertAccessLogs(ctx context.Context, logs []*models.AccessLog) error {
+	ret := _m.Called(ctx, logs)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InsertAccessLog")
+		panic("no return value specified for InsertAccessLogs")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
-		r0 = rf(ctx, shortPath, accessedAt)
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.AccessLog) error); ok {
+		r0 = rf(ctx, logs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"url-shortener/internal/models"
+)
+
+// insertLinkEvent records a link change in the outbox as part of tx, so the event exists if and only if the
+// change commits.
+func insertLinkEvent(ctx context.Context, tx *sql.Tx, eventType string, data models.LinkEventData) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, PG_INSERT_OUTBOX_EVENT, eventType, data.ShortPath, payload); err != nil {
+		log.Printf("Error recording %s event of %s: %v", eventType, data.ShortPath, err)
+		return ErrDBError
+	}
+	return nil
+}
+
+// outboxPosition is where a consumer is in the outbox. Events are ordered by the transaction that wrote them, then by
+// ID.
+type outboxPosition struct {
+	txid    string
+	eventID int64
+}
+
+// readOutbox locks the offset of consumer for the rest of tx and returns up to limit events after it, with the
+// position of the last one. Only events of transactions older than every running one are returned, a transaction
+// still running could otherwise commit an event behind the offset.
+func readOutbox(ctx context.Context, tx *sql.Tx, consumer string, limit int) ([]models.OutboxEvent, outboxPosition, error) {
+	var position outboxPosition
+	if _, err := tx.ExecContext(ctx, PG_INIT_OUTBOX_OFFSET, consumer); err != nil {
+		log.Printf("Error creating outbox offset of %s: %v", consumer, err)
+		return nil, position, ErrDBError
+	}
+	if err := tx.QueryRowContext(ctx, PG_LOCK_OUTBOX_OFFSET, consumer).Scan(&position.txid, &position.eventID); err != nil {
+		log.Printf("Error reading outbox offset of %s: %v", consumer, err)
+		return nil, position, ErrDBError
+	}
+
+	rows, err := tx.QueryContext(ctx, PG_LIST_OUTBOX_EVENTS, position.txid, position.eventID, limit)
+	if err != nil {
+		log.Printf("Error reading outbox events of %s: %v", consumer, err)
+		return nil, position, ErrDBError
+	}
+	defer rows.Close()
+
+	events := []models.OutboxEvent{}
+	for rows.Next() {
+		var event models.OutboxEvent
+		if err := rows.Scan(&event.ID, &position.txid, &event.Type, &event.ShortPath, &event.Payload, &event.CreatedAt); err != nil {
+			log.Printf("Error scanning outbox event of %s: %v", consumer, err)
+			return nil, position, ErrDBError
+		}
+		position.eventID = event.ID
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error reading outbox events of %s: %v", consumer, err)
+		return nil, position, ErrDBError
+	}
+	return events, position, nil
+}
+
+// advanceOutbox moves the offset of consumer to position as part of tx.
+func advanceOutbox(ctx context.Context, tx *sql.Tx, consumer string, position outboxPosition) error {
+	if _, err := tx.ExecContext(ctx, PG_UPDATE_OUTBOX_OFFSET, consumer, position.txid, position.eventID); err != nil {
+		log.Printf("Error updating outbox offset of %s: %v", consumer, err)
+		return ErrDBError
+	}
+	return nil
+}
+
+func linkEventData(url *models.URL) models.LinkEventData {
+	return models.LinkEventData{
+		ShortPath:   url.ShortPath,
+		OriginalURL: url.OriginalURL,
+		Expiry:      utcTime(url.Expiry),
+		CreatedAt:   utcTime(url.CreatedAt),
+		CreatedBy:   url.CreatedBy,
+		ModifiedAt:  utcTime(url.ModifiedAt),
+		ModifiedBy:  url.ModifiedBy,
+	}
+}
+
+func archivedLinkEventData(url *models.URLArchive) models.LinkEventData {
+	data := models.LinkEventData{
+		ShortPath:   url.ShortPath,
+		OriginalURL: url.OriginalURL,
+		Expiry:      utcTime(url.Expiry),
+		CreatedAt:   utcTime(url.CreatedAt),
+		ModifiedAt:  utcTime(url.ModifiedAt),
+		ModifiedBy:  url.ModifiedBy,
+		DeletedAt:   utcTime(url.DeletedAt),
+		DeletedBy:   url.DeletedBy,
+	}
+	if url.CreatedBy != nil {
+		data.CreatedBy = *url.CreatedBy
+	}
+	return data
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
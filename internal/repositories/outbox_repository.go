@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=OutboxRepository --output=./mocks
+type OutboxRepository interface {
+	// ConsumeEvents passes up to limit events after the offset of consumer to handle, in commit order, and moves the
+	// offset past them only when handle succeeds, so every consumer gets every event once per offset. It returns how
+	// many events were handled. Callers of the same consumer wait for each other.
+	ConsumeEvents(ctx context.Context, consumer string, limit int, handle func(ctx context.Context, events []models.OutboxEvent) error) (int, error)
+	// PruneEvents deletes up to limit events created before before that every consumer has read and returns how many
+	// it deleted.
+	PruneEvents(ctx context.Context, before time.Time, limit int) (int, error)
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+)
+
+type outboxRepositoryPostgresqlImpl struct {
+	cluster *db.PostgresCluster
+}
+
+func NewOutboxRepositoryPostgresql(cluster *db.PostgresCluster) OutboxRepository {
+	return &outboxRepositoryPostgresqlImpl{cluster: cluster}
+}
+
+// ConsumeEvents implements OutboxRepository. The offset stays locked while handle runs, events handled just before
+// the commit fails are passed again on the next call.
+func (r *outboxRepositoryPostgresqlImpl) ConsumeEvents(ctx context.Context, consumer string, limit int, handle func(ctx context.Context, events []models.OutboxEvent) error) (int, error) {
+	tx, err := r.cluster.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction to consume outbox events of %s: %v", consumer, err)
+		return 0, ErrDBError
+	}
+	defer tx.Rollback()
+
+	events, position, err := readOutbox(ctx, tx, consumer, limit)
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+	if err := handle(ctx, events); err != nil {
+		return 0, err
+	}
+	if err := advanceOutbox(ctx, tx, consumer, position); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing outbox offset of %s: %v", consumer, err)
+		return 0, ErrDBError
+	}
+	return len(events), nil
+}
+
+// PruneEvents implements OutboxRepository. A consumer whose offset stopped moving holds every later event back.
+func (r *outboxRepositoryPostgresqlImpl) PruneEvents(ctx context.Context, before time.Time, limit int) (int, error) {
+	result, err := r.cluster.Primary().ExecContext(ctx, PG_PRUNE_OUTBOX_EVENTS, before.UTC(), limit)
+	if err != nil {
+		log.Printf("Error pruning outbox events before %s: %v", before, err)
+		return 0, ErrDBError
+	}
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error counting pruned outbox events: %v", err)
+		return 0, ErrDBError
+	}
+	return int(pruned), nil
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func outboxEventRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "txid", "type", "short_path", "payload", "created_at"})
+}
+
+func TestOutboxRepositoryPostgresqlImpl_ConsumeEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOutboxRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO outbox_offsets").WithArgs("cache").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT txid::text, event_id FROM outbox_offsets WHERE consumer = \\$1 FOR UPDATE").WithArgs("cache").
+		WillReturnRows(sqlmock.NewRows([]string{"txid", "event_id"}).AddRow("750", 20))
+	mock.ExpectQuery("FROM outbox_events").WithArgs("750", int64(20), 100).
+		WillReturnRows(outboxEventRows().AddRow(21, "751", models.LinkEventUpdated, "abc", []byte(`{"shortPath":"abc"}`), now))
+	mock.ExpectExec("UPDATE outbox_offsets SET txid = \\$2::xid8, event_id = \\$3 WHERE consumer = \\$1").
+		WithArgs("cache", "751", int64(21)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var handled []models.OutboxEvent
+	count, err := repo.ConsumeEvents(context.Background(), "cache", 100, func(ctx context.Context, events []models.OutboxEvent) error {
+		handled = events
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []models.OutboxEvent{{ID: 21, Type: models.LinkEventUpdated, ShortPath: "abc",
+		Payload: []byte(`{"shortPath":"abc"}`), CreatedAt: now}}, handled)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepositoryPostgresqlImpl_ConsumeEvents_HandleError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOutboxRepositoryPostgresql(newTestCluster(db))
+	handleErr := errors.New("sink unavailable")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO outbox_offsets").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("FROM outbox_offsets").WillReturnRows(sqlmock.NewRows([]string{"txid", "event_id"}).AddRow("750", 20))
+	mock.ExpectQuery("FROM outbox_events").
+		WillReturnRows(outboxEventRows().AddRow(21, "751", models.LinkEventUpdated, "abc", []byte(`{}`), time.Now()))
+	mock.ExpectRollback()
+
+	_, err = repo.ConsumeEvents(context.Background(), "nats", 100, func(ctx context.Context, events []models.OutboxEvent) error {
+		return handleErr
+	})
+
+	assert.ErrorIs(t, err, handleErr)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepositoryPostgresqlImpl_ConsumeEvents_NoEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOutboxRepositoryPostgresql(newTestCluster(db))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO outbox_offsets").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("FROM outbox_offsets").WillReturnRows(sqlmock.NewRows([]string{"txid", "event_id"}).AddRow("0", 0))
+	mock.ExpectQuery("FROM outbox_events").WillReturnRows(outboxEventRows())
+	mock.ExpectRollback()
+
+	count, err := repo.ConsumeEvents(context.Background(), "cache", 100, func(ctx context.Context, events []models.OutboxEvent) error {
+		t.Fatal("handle must not be called without events")
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepositoryPostgresqlImpl_PruneEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOutboxRepositoryPostgresql(newTestCluster(db))
+	before := time.Date(2025, 6, 5, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("DELETE FROM outbox_events e USING pruned").WithArgs(before, 500).WillReturnResult(sqlmock.NewResult(0, 42))
+	mock.ExpectExec("DELETE FROM outbox_events").WillReturnError(errors.New("connection reset"))
+
+	pruned, err := repo.PruneEvents(context.Background(), before, 500)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, pruned)
+
+	_, err = repo.PruneEvents(context.Background(), before, 500)
+
+	assert.ErrorIs(t, err, ErrDBError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+)
+
+// liftStatementTimeout turns database.statement_timeout off for the rest of tx. It is meant for requests, while
+// maintenance such as moving clicks between partitions or catching up on rollups can rightly take much longer.
+func liftStatementTimeout(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, PG_LIFT_STATEMENT_TIMEOUT); err != nil {
+		log.Printf("Error lifting the statement timeout: %v", err)
+		return ErrDBError
+	}
+	return nil
+}
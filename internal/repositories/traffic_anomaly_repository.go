@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=TrafficAnomalyRepository --output=./mocks
+type TrafficAnomalyRepository interface {
+	// ProcessNextBucket hands the bucket of the given size after the detection watermark to process when it ends
+	// by until, then saves the baselines process returns, deletes those of the links it lists and queues its
+	// alerts. It reports whether a bucket was processed.
+	ProcessNextBucket(ctx context.Context, until time.Time, size time.Duration, process func(models.TrafficBucket) ([]models.TrafficBaseline, []string, []models.TrafficAlert, error)) (bool, error)
+	// ClaimAlert returns the oldest queued alert due at now with its attempts counted and holds it until until, nil
+	// when none is due.
+	ClaimAlert(ctx context.Context, now time.Time, until time.Time) (*models.TrafficAlert, error)
+	RetryAlert(ctx context.Context, id int64, at time.Time) error
+	DeleteAlert(ctx context.Context, id int64) error
+	ListAlertSettings(ctx context.Context) ([]models.AlertSettings, error)
+	// GetAlertSettings returns nil when the link uses the defaults.
+	GetAlertSettings(ctx context.Context, shortPath string) (*models.AlertSettings, error)
+	SaveAlertSettings(ctx context.Context, settings *models.AlertSettings) error
+}
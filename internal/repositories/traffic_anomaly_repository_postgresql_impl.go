@@ -0,0 +1,268 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// maxBaselinesPerInsert keeps an upsert of baselines below the limit of 65535 parameters per statement.
+const maxBaselinesPerInsert = 5000
+
+type trafficAnomalyRepositoryPostgresqlImpl struct {
+	cluster *db.PostgresCluster
+}
+
+func NewTrafficAnomalyRepositoryPostgresql(cluster *db.PostgresCluster) TrafficAnomalyRepository {
+	return &trafficAnomalyRepositoryPostgresqlImpl{cluster: cluster}
+}
+
+// ProcessNextBucket implements TrafficAnomalyRepository. The watermark row is locked for the whole transaction, so
+// instances running the detection at the same time take turns and every bucket updates the baselines once. Alerts
+// are queued in the same transaction, so they exist exactly when the bucket is saved.
+func (r *trafficAnomalyRepositoryPostgresqlImpl) ProcessNextBucket(ctx context.Context, until time.Time, size time.Duration, process func(models.TrafficBucket) ([]models.TrafficBaseline, []string, []models.TrafficAlert, error)) (bool, error) {
+	until = until.UTC()
+	tx, err := r.cluster.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting anomaly detection transaction: %v", err)
+		return false, ErrDBError
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, PG_INIT_TRAFFIC_ANOMALY_WATERMARK, until.Truncate(size)); err != nil {
+		log.Printf("Error initialising anomaly detection watermark: %v", err)
+		return false, ErrDBError
+	}
+	var watermark time.Time
+	if err := tx.QueryRowContext(ctx, PG_LOCK_TRAFFIC_ANOMALY_WATERMARK).Scan(&watermark); err != nil {
+		log.Printf("Error locking anomaly detection watermark: %v", err)
+		return false, ErrDBError
+	}
+	bucket := models.TrafficBucket{From: watermark, To: watermark.Add(size)}
+	if bucket.To.After(until) {
+		return false, tx.Commit()
+	}
+
+	if bucket.Clicks, err = r.bucketClicks(ctx, tx, bucket.From, bucket.To); err != nil {
+		return false, err
+	}
+	if bucket.Baselines, err = r.listBaselines(ctx, tx); err != nil {
+		return false, err
+	}
+	updated, deleted, alerts, err := process(bucket)
+	if err != nil {
+		return false, err
+	}
+	if err := r.saveBaselines(ctx, tx, updated); err != nil {
+		return false, err
+	}
+	if len(deleted) > 0 {
+		if _, err := tx.ExecContext(ctx, PG_DELETE_TRAFFIC_BASELINES, pq.Array(deleted)); err != nil {
+			log.Printf("Error deleting %d traffic baselines: %v", len(deleted), err)
+			return false, ErrDBError
+		}
+	}
+	for _, alert := range alerts {
+		if _, err := tx.ExecContext(ctx, PG_INSERT_TRAFFIC_ALERT, alert.DedupKey, alert.Kind, alert.ShortPath, alert.BucketStart.UTC(),
+			alert.BucketEnd.UTC(), alert.Clicks, alert.Baseline, alert.ZScore); err != nil {
+			log.Printf("Error queueing %s alert of %s: %v", alert.Kind, alert.ShortPath, err)
+			return false, ErrDBError
+		}
+	}
+	if _, err := tx.ExecContext(ctx, PG_UPDATE_TRAFFIC_ANOMALY_WATERMARK, bucket.To); err != nil {
+		log.Printf("Error moving anomaly detection watermark to %s: %v", bucket.To, err)
+		return false, ErrDBError
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing anomaly detection: %v", err)
+		return false, ErrDBError
+	}
+	return true, nil
+}
+
+func (r *trafficAnomalyRepositoryPostgresqlImpl) bucketClicks(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time) (map[string]int64, error) {
+	rows, err := tx.QueryContext(ctx, PG_GET_BUCKET_CLICKS, from, to)
+	if err != nil {
+		log.Printf("Error counting clicks from %s to %s: %v", from, to, err)
+		return nil, ErrDBError
+	}
+	defer rows.Close()
+
+	clicks := map[string]int64{}
+	for rows.Next() {
+		var shortPath string
+		var count int64
+		if err := rows.Scan(&shortPath, &count); err != nil {
+			log.Printf("Error scanning bucket clicks: %v", err)
+			return nil, ErrDBError
+		}
+		clicks[shortPath] = count
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error reading bucket clicks: %v", err)
+		return nil, ErrDBError
+	}
+	return clicks, nil
+}
+
+func (r *trafficAnomalyRepositoryPostgresqlImpl) listBaselines(ctx context.Context, tx *sql.Tx) ([]models.TrafficBaseline, error) {
+	rows, err := tx.QueryContext(ctx, PG_LIST_TRAFFIC_BASELINES)
+	if err != nil {
+		log.Printf("Error listing traffic baselines: %v", err)
+		return nil, ErrDBError
+	}
+	defer rows.Close()
+
+	baselines := []models.TrafficBaseline{}
+	for rows.Next() {
+		var baseline models.TrafficBaseline
+		if err := rows.Scan(&baseline.ShortPath, &baseline.Mean, &baseline.Variance, &baseline.Samples, &baseline.State, &baseline.AlertedAt); err != nil {
+			log.Printf("Error scanning traffic baseline: %v", err)
+			return nil, ErrDBError
+		}
+		baselines = append(baselines, baseline)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error reading traffic baselines: %v", err)
+		return nil, ErrDBError
+	}
+	return baselines, nil
+}
+
+func (r *trafficAnomalyRepositoryPostgresqlImpl) saveBaselines(ctx context.Context, tx *sql.Tx, baselines []models.TrafficBaseline) error {
+	const columns = 6
+	for start := 0; start < len(baselines); start += maxBaselinesPerInsert {
+		chunk := baselines[start:min(start+maxBaselinesPerInsert, len(baselines))]
+		query := strings.Builder{}
+		query.WriteString(PG_UPSERT_TRAFFIC_BASELINES)
+		args := make([]interface{}, 0, len(chunk)*columns)
+		for i, baseline := range chunk {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			query.WriteString("(")
+			for column := 1; column <= columns; column++ {
+				if column > 1 {
+					query.WriteString(", ")
+				}
+				fmt.Fprintf(&query, "$%d", i*columns+column)
+			}
+			query.WriteString(")")
+			var alertedAt *time.Time
+			if baseline.AlertedAt != nil {
+				utc := baseline.AlertedAt.UTC()
+				alertedAt = &utc
+			}
+			args = append(args, baseline.ShortPath, baseline.Mean, baseline.Variance, baseline.Samples, baseline.State, alertedAt)
+		}
+		query.WriteString(PG_UPSERT_TRAFFIC_BASELINES_CONFLICT)
+		if _, err := tx.ExecContext(ctx, query.String(), args...); err != nil {
+			log.Printf("Error saving %d traffic baselines: %v", len(chunk), err)
+			return ErrDBError
+		}
+	}
+	return nil
+}
+
+// ClaimAlert implements TrafficAnomalyRepository.
+func (r *trafficAnomalyRepositoryPostgresqlImpl) ClaimAlert(ctx context.Context, now time.Time, until time.Time) (*models.TrafficAlert, error) {
+	var alert models.TrafficAlert
+	err := r.cluster.Primary().QueryRowContext(ctx, PG_CLAIM_TRAFFIC_ALERT, now.UTC(), until.UTC()).Scan(&alert.ID, &alert.DedupKey,
+		&alert.Kind, &alert.ShortPath, &alert.BucketStart, &alert.BucketEnd, &alert.Clicks, &alert.Baseline, &alert.ZScore, &alert.Attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error claiming traffic alert: %v", err)
+		return nil, ErrDBError
+	}
+	return &alert, nil
+}
+
+// RetryAlert implements TrafficAnomalyRepository.
+func (r *trafficAnomalyRepositoryPostgresqlImpl) RetryAlert(ctx context.Context, id int64, at time.Time) error {
+	if _, err := r.cluster.Primary().ExecContext(ctx, PG_RETRY_TRAFFIC_ALERT, id, at.UTC()); err != nil {
+		log.Printf("Error rescheduling traffic alert %d: %v", id, err)
+		return ErrDBError
+	}
+	return nil
+}
+
+// DeleteAlert implements TrafficAnomalyRepository.
+func (r *trafficAnomalyRepositoryPostgresqlImpl) DeleteAlert(ctx context.Context, id int64) error {
+	if _, err := r.cluster.Primary().ExecContext(ctx, PG_DELETE_TRAFFIC_ALERT, id); err != nil {
+		log.Printf("Error deleting traffic alert %d: %v", id, err)
+		return ErrDBError
+	}
+	return nil
+}
+
+// ListAlertSettings implements TrafficAnomalyRepository.
+func (r *trafficAnomalyRepositoryPostgresqlImpl) ListAlertSettings(ctx context.Context) ([]models.AlertSettings, error) {
+	rows, err := r.cluster.Primary().QueryContext(ctx, PG_LIST_ALERT_SETTINGS)
+	if err != nil {
+		log.Printf("Error listing alert settings: %v", err)
+		return nil, ErrDBError
+	}
+	defer rows.Close()
+
+	settings := []models.AlertSettings{}
+	for rows.Next() {
+		linkSettings, err := scanAlertSettings(rows)
+		if err != nil {
+			log.Printf("Error scanning alert settings: %v", err)
+			return nil, ErrDBError
+		}
+		settings = append(settings, *linkSettings)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error reading alert settings: %v", err)
+		return nil, ErrDBError
+	}
+	return settings, nil
+}
+
+// GetAlertSettings implements TrafficAnomalyRepository.
+func (r *trafficAnomalyRepositoryPostgresqlImpl) GetAlertSettings(ctx context.Context, shortPath string) (*models.AlertSettings, error) {
+	settings, err := scanAlertSettings(r.cluster.Primary().QueryRowContext(ctx, PG_GET_ALERT_SETTINGS, shortPath))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error getting alert settings of %s: %v", shortPath, err)
+		return nil, ErrDBError
+	}
+	return settings, nil
+}
+
+// SaveAlertSettings implements TrafficAnomalyRepository.
+func (r *trafficAnomalyRepositoryPostgresqlImpl) SaveAlertSettings(ctx context.Context, settings *models.AlertSettings) error {
+	_, err := r.cluster.Primary().ExecContext(ctx, PG_UPSERT_ALERT_SETTINGS, settings.ShortPath, settings.SpikeZ, settings.DropZ, settings.MinClicks, settings.Enabled)
+	if err != nil {
+		log.Printf("Error saving alert settings of %s: %v", settings.ShortPath, err)
+		return ErrDBError
+	}
+	return nil
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAlertSettings(row scanner) (*models.AlertSettings, error) {
+	var settings models.AlertSettings
+	if err := row.Scan(&settings.ShortPath, &settings.SpikeZ, &settings.DropZ, &settings.MinClicks, &settings.Enabled); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
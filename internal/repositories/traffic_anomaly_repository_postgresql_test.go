@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrafficAnomalyRepositoryPostgresqlImpl_ProcessNextBucket(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTrafficAnomalyRepositoryPostgresql(newTestCluster(db))
+	watermark := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 6, 12, 10, 6, 0, 0, time.UTC)
+	end := watermark.Add(5 * time.Minute)
+	alertedAt := time.Date(2025, 6, 12, 8, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO click_rollup_watermarks").WithArgs(until.Truncate(5 * time.Minute)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT rolled_up_to FROM click_rollup_watermarks WHERE name = 'traffic_anomalies' FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"rolled_up_to"}).AddRow(watermark))
+	mock.ExpectQuery("SELECT short_path, COUNT\\(\\*\\) FROM url_access_logs").WithArgs(watermark, end).
+		WillReturnRows(sqlmock.NewRows([]string{"short_path", "count"}).AddRow("abc", 42).AddRow("new", 3))
+	mock.ExpectQuery("SELECT short_path, mean, variance, samples, state, alerted_at FROM link_traffic_baselines").
+		WillReturnRows(sqlmock.NewRows([]string{"short_path", "mean", "variance", "samples", "state", "alerted_at"}).
+			AddRow("abc", 10.0, 4.0, 50, models.TrafficStateNormal, alertedAt).
+			AddRow("idle", 0.001, 0.0, 900, models.TrafficStateNormal, nil))
+	mock.ExpectExec("INSERT INTO link_traffic_baselines \\(short_path, mean, variance, samples, state, alerted_at\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6\\) ON CONFLICT").
+		WithArgs("abc", 12.0, 5.0, 51, models.TrafficStateSpike, end).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM link_traffic_baselines WHERE short_path = ANY").WithArgs(pq.Array([]string{"idle"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO traffic_alerts").WithArgs("abc:spike:2025-06-12T10:00:00Z", models.TrafficStateSpike, "abc", watermark, end,
+		int64(42), 10.0, 8.0).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE click_rollup_watermarks SET rolled_up_to = \\$1 WHERE name = 'traffic_anomalies'").WithArgs(end).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var bucket models.TrafficBucket
+	processed, err := repo.ProcessNextBucket(context.Background(), until, 5*time.Minute, func(b models.TrafficBucket) ([]models.TrafficBaseline, []string, []models.TrafficAlert, error) {
+		bucket = b
+		return []models.TrafficBaseline{{ShortPath: "abc", Mean: 12, Variance: 5, Samples: 51, State: models.TrafficStateSpike, AlertedAt: &end}}, []string{"idle"},
+			[]models.TrafficAlert{{Kind: models.TrafficStateSpike, ShortPath: "abc", BucketStart: watermark, BucketEnd: end, Clicks: 42, Baseline: 10,
+				ZScore: 8, DedupKey: "abc:spike:2025-06-12T10:00:00Z"}}, nil
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	assert.Equal(t, watermark, bucket.From)
+	assert.Equal(t, end, bucket.To)
+	assert.Equal(t, map[string]int64{"abc": 42, "new": 3}, bucket.Clicks)
+	assert.Len(t, bucket.Baselines, 2)
+	assert.Equal(t, alertedAt, *bucket.Baselines[0].AlertedAt)
+	assert.Nil(t, bucket.Baselines[1].AlertedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTrafficAnomalyRepositoryPostgresqlImpl_ProcessNextBucket_CaughtUp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTrafficAnomalyRepositoryPostgresql(newTestCluster(db))
+	until := time.Date(2025, 6, 12, 10, 6, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO click_rollup_watermarks").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT rolled_up_to FROM click_rollup_watermarks").
+		WillReturnRows(sqlmock.NewRows([]string{"rolled_up_to"}).AddRow(time.Date(2025, 6, 12, 10, 5, 0, 0, time.UTC)))
+	mock.ExpectCommit()
+
+	processed, err := repo.ProcessNextBucket(context.Background(), until, 5*time.Minute, func(models.TrafficBucket) ([]models.TrafficBaseline, []string, []models.TrafficAlert, error) {
+		t.Fatal("the bucket has not ended yet")
+		return nil, nil, nil, nil
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, processed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTrafficAnomalyRepositoryPostgresqlImpl_ProcessNextBucket_ProcessError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTrafficAnomalyRepositoryPostgresql(newTestCluster(db))
+	watermark := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO click_rollup_watermarks").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT rolled_up_to FROM click_rollup_watermarks").
+		WillReturnRows(sqlmock.NewRows([]string{"rolled_up_to"}).AddRow(watermark))
+	mock.ExpectQuery("SELECT short_path, COUNT").WillReturnRows(sqlmock.NewRows([]string{"short_path", "count"}))
+	mock.ExpectQuery("SELECT short_path, mean").WillReturnRows(sqlmock.NewRows([]string{"short_path", "mean", "variance", "samples", "state", "alerted_at"}))
+	mock.ExpectRollback()
+
+	processed, err := repo.ProcessNextBucket(context.Background(), watermark.Add(time.Hour), 5*time.Minute, func(models.TrafficBucket) ([]models.TrafficBaseline, []string, []models.TrafficAlert, error) {
+		return nil, nil, nil, assert.AnError
+	})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.False(t, processed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTrafficAnomalyRepositoryPostgresqlImpl_ClaimAlert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTrafficAnomalyRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 7, 0, 0, time.UTC)
+	start := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("UPDATE traffic_alerts a SET next_attempt_at = \\$2, attempts = a.attempts \\+ 1").WithArgs(now, now.Add(time.Minute)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "dedup_key", "kind", "short_path", "bucket_start", "bucket_end", "clicks", "baseline", "z_score", "attempts"}).
+			AddRow(3, "abc:drop:2025-06-12T10:00:00Z", models.TrafficStateDrop, "abc", start, start.Add(5*time.Minute), 2, 40.0, -5.5, 2))
+	mock.ExpectQuery("UPDATE traffic_alerts").WillReturnError(sql.ErrNoRows)
+
+	alert, err := repo.ClaimAlert(context.Background(), now, now.Add(time.Minute))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.TrafficAlert{ID: 3, Attempts: 2, Kind: models.TrafficStateDrop, ShortPath: "abc", BucketStart: start,
+		BucketEnd: start.Add(5 * time.Minute), Clicks: 2, Baseline: 40, ZScore: -5.5, DedupKey: "abc:drop:2025-06-12T10:00:00Z"}, alert)
+
+	alert, err = repo.ClaimAlert(context.Background(), now, now.Add(time.Minute))
+
+	assert.NoError(t, err)
+	assert.Nil(t, alert)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTrafficAnomalyRepositoryPostgresqlImpl_RetryAndDeleteAlert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTrafficAnomalyRepositoryPostgresql(newTestCluster(db))
+	at := time.Date(2025, 6, 12, 10, 9, 0, 0, time.UTC)
+
+	mock.ExpectExec("UPDATE traffic_alerts SET next_attempt_at = \\$2 WHERE id = \\$1").WithArgs(int64(3), at).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM traffic_alerts WHERE id = \\$1").WithArgs(int64(3)).WillReturnError(assert.AnError)
+
+	assert.NoError(t, repo.RetryAlert(context.Background(), 3, at))
+	assert.ErrorIs(t, repo.DeleteAlert(context.Background(), 3), ErrDBError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTrafficAnomalyRepositoryPostgresqlImpl_AlertSettings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTrafficAnomalyRepositoryPostgresql(newTestCluster(db))
+	spikeZ := 6.5
+
+	mock.ExpectQuery("SELECT short_path, spike_z, drop_z, min_clicks, enabled FROM link_alert_settings WHERE short_path = \\$1").WithArgs("abc").
+		WillReturnRows(sqlmock.NewRows([]string{"short_path", "spike_z", "drop_z", "min_clicks", "enabled"}).AddRow("abc", spikeZ, nil, nil, false))
+	mock.ExpectQuery("SELECT short_path, spike_z, drop_z, min_clicks, enabled FROM link_alert_settings WHERE short_path = \\$1").WithArgs("none").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("INSERT INTO link_alert_settings").WithArgs("abc", &spikeZ, nil, nil, true).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	settings, err := repo.GetAlertSettings(context.Background(), "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.AlertSettings{ShortPath: "abc", SpikeZ: &spikeZ, Enabled: false}, settings)
+
+	settings, err = repo.GetAlertSettings(context.Background(), "none")
+	assert.NoError(t, err)
+	assert.Nil(t, settings)
+
+	err = repo.SaveAlertSettings(context.Background(), &models.AlertSettings{ShortPath: "abc", SpikeZ: &spikeZ, Enabled: true})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=UniqueVisitorRepository --output=./mocks
+type UniqueVisitorRepository interface {
+	// AddVisitors records the visitor ID of every access that has one.
+	AddVisitors(ctx context.Context, logs []*models.AccessLog) error
+	// CountUniqueVisitors estimates the distinct visitors of a link in the 24 hours and 7 days up to now and overall.
+	CountUniqueVisitors(ctx context.Context, shortPath string, now time.Time) (*models.UniqueVisitorCounts, error)
+}
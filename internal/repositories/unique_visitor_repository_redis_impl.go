@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// Hourly and daily sets are kept a little longer than the windows they are counted in.
+	hourlyVisitorsTTL = 25 * time.Hour
+	dailyVisitorsTTL  = 8 * 24 * time.Hour
+)
+
+// uniqueVisitorRepositoryRedisImpl keeps a HyperLogLog of visitor IDs per link and hour, day and all time. The
+// last 24 hours are the union of the current and previous 23 hourly sets, the past week the union of the last
+// 7 daily sets. Every key of a link shares the {shortPath} hash tag so they can be counted together on a cluster.
+type uniqueVisitorRepositoryRedisImpl struct {
+	client db.RedisClient
+}
+
+func NewUniqueVisitorRepositoryRedis(client db.RedisClient) UniqueVisitorRepository {
+	return &uniqueVisitorRepositoryRedisImpl{client: client}
+}
+
+// AddVisitors implements UniqueVisitorRepository.
+func (r *uniqueVisitorRepositoryRedisImpl) AddVisitors(ctx context.Context, logs []*models.AccessLog) error {
+	visitors := map[string][]interface{}{}
+	ttls := map[string]time.Duration{}
+	for _, accessLog := range logs {
+		if accessLog.VisitorID == "" || accessLog.IsBot {
+			continue
+		}
+		hourKey := hourlyVisitorsKey(accessLog.ShortPath, accessLog.AccessedAt)
+		dayKey := dailyVisitorsKey(accessLog.ShortPath, accessLog.AccessedAt)
+		allKey := allTimeVisitorsKey(accessLog.ShortPath)
+		visitors[hourKey] = append(visitors[hourKey], accessLog.VisitorID)
+		visitors[dayKey] = append(visitors[dayKey], accessLog.VisitorID)
+		visitors[allKey] = append(visitors[allKey], accessLog.VisitorID)
+		ttls[hourKey] = hourlyVisitorsTTL
+		ttls[dayKey] = dailyVisitorsTTL
+	}
+	if len(visitors) == 0 {
+		return nil
+	}
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, ids := range visitors {
+			pipe.PFAdd(ctx, key, ids...)
+			if ttl, ok := ttls[key]; ok {
+				pipe.Expire(ctx, key, ttl)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error adding unique visitors: %v", err)
+		return err
+	}
+	return nil
+}
+
+// CountUniqueVisitors implements UniqueVisitorRepository.
+func (r *uniqueVisitorRepositoryRedisImpl) CountUniqueVisitors(ctx context.Context, shortPath string, now time.Time) (*models.UniqueVisitorCounts, error) {
+	hourKeys := make([]string, 0, 24)
+	for i := 0; i < 24; i++ {
+		hourKeys = append(hourKeys, hourlyVisitorsKey(shortPath, now.Add(-time.Duration(i)*time.Hour)))
+	}
+	dayKeys := make([]string, 0, 7)
+	for i := 0; i < 7; i++ {
+		dayKeys = append(dayKeys, dailyVisitorsKey(shortPath, now.AddDate(0, 0, -i)))
+	}
+
+	var last24Hours, pastWeek, allTime *redis.IntCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		last24Hours = pipe.PFCount(ctx, hourKeys...)
+		pastWeek = pipe.PFCount(ctx, dayKeys...)
+		allTime = pipe.PFCount(ctx, allTimeVisitorsKey(shortPath))
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error counting unique visitors of %s: %v", shortPath, err)
+		return nil, err
+	}
+	return &models.UniqueVisitorCounts{
+		Last24Hours: last24Hours.Val(),
+		PastWeek:    pastWeek.Val(),
+		AllTime:     allTime.Val(),
+	}, nil
+}
+
+func hourlyVisitorsKey(shortPath string, t time.Time) string {
+	return "uv:{" + shortPath + "}:h:" + t.UTC().Format("2006010215")
+}
+
+func dailyVisitorsKey(shortPath string, t time.Time) string {
+	return "uv:{" + shortPath + "}:d:" + t.UTC().Format("20060102")
+}
+
+func allTimeVisitorsKey(shortPath string) string {
+	return "uv:{" + shortPath + "}:all"
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+
+	dbMocks "url-shortener/internal/db/mocks"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRedisAddVisitors(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewUniqueVisitorRepositoryRedis(mockClient)
+	accessedAt := time.Date(2024, 5, 1, 13, 30, 0, 0, time.UTC)
+	logs := []*models.AccessLog{
+		{ShortPath: "path1", AccessedAt: accessedAt, VisitorID: "a"},
+		{ShortPath: "path1", AccessedAt: accessedAt, VisitorID: "b"},
+		{ShortPath: "path2", AccessedAt: accessedAt},
+		{ShortPath: "path3", AccessedAt: accessedAt, VisitorID: "c", IsBot: true},
+	}
+	pipe := redis.NewClient(&redis.Options{}).Pipeline()
+	mockClient.On("Pipelined", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(redis.Pipeliner) error)
+		assert.NoError(t, fn(pipe))
+	}).Return(nil, nil).Once()
+
+	err := repo.AddVisitors(context.Background(), logs)
+
+	assert.NoError(t, err)
+	// PFADD for the hourly, daily and all time keys of path1, EXPIRE for the hourly and daily ones. The bot on
+	// path3 is not counted.
+	assert.Equal(t, 5, pipe.Len())
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisAddVisitors_NoVisitorIDs(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewUniqueVisitorRepositoryRedis(mockClient)
+
+	err := repo.AddVisitors(context.Background(), []*models.AccessLog{{ShortPath: "path1", AccessedAt: time.Now()}})
+
+	assert.NoError(t, err)
+	mockClient.AssertNotCalled(t, "Pipelined", mock.Anything, mock.Anything)
+}
+
+func TestRedisCountUniqueVisitors(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewUniqueVisitorRepositoryRedis(mockClient)
+	now := time.Date(2024, 5, 1, 13, 30, 0, 0, time.UTC)
+	pipe := redis.NewClient(&redis.Options{}).Pipeline()
+	mockClient.On("Pipelined", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(redis.Pipeliner) error)
+		assert.NoError(t, fn(pipe))
+	}).Return(nil, nil).Once()
+
+	counts, err := repo.CountUniqueVisitors(context.Background(), "path1", now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.UniqueVisitorCounts{}, counts)
+	// One PFCOUNT each over the hourly keys, the daily keys and the all time key.
+	assert.Equal(t, 3, pipe.Len())
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisCountUniqueVisitors_Error(t *testing.T) {
+	mockClient := &dbMocks.RedisClient{}
+	repo := NewUniqueVisitorRepositoryRedis(mockClient)
+	mockClient.On("Pipelined", mock.Anything, mock.Anything).Return(nil, errors.New("redis error")).Once()
+
+	counts, err := repo.CountUniqueVisitors(context.Background(), "path1", time.Now())
+
+	assert.Error(t, err)
+	assert.Nil(t, counts)
+	mockClient.AssertExpectations(t)
+}
+
+func TestUniqueVisitorKeys(t *testing.T) {
+	at := time.Date(2024, 5, 1, 13, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	assert.Equal(t, "uv:{path1}:h:2024050111", hourlyVisitorsKey("path1", at))
+	assert.Equal(t, "uv:{path1}:d:20240501", dailyVisitorsKey("path1", at))
+	assert.Equal(t, "uv:{path1}:all", allTimeVisitorsKey("path1"))
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=URLArchiveRepository --output=./mocks
+type URLArchiveRepository interface {
+	// RestoreShortURL moves the latest archived version of shortPath back into urls as modified by restoredBy at
+	// restoredAt, and records a link.restored event and an audit entry by actor in the same transaction. It returns ErrURLNotFound when the path
+	// has no archived version, ErrURLExpired when that version expired by restoredAt and ErrShortURLAlreadyExists
+	// when the path is in use again.
+	RestoreShortURL(ctx context.Context, shortPath string, restoredAt time.Time, restoredBy string, actor *models.AuditActor) (*models.URL, error)
+	// ListArchivedURLs returns the latest archived versions of shortPath, or of every path when it is empty, older
+	// than the version before unless it is 0.
+	ListArchivedURLs(ctx context.Context, shortPath string, before int64, limit int) ([]models.URLArchive, error)
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+)
+
+type urlArchiveRepositoryPostgresqlImpl struct {
+	cluster *db.PostgresCluster
+}
+
+func NewURLArchiveRepositoryPostgresql(cluster *db.PostgresCluster) URLArchiveRepository {
+	return &urlArchiveRepositoryPostgresqlImpl{cluster: cluster}
+}
+
+// RestoreShortURL implements URLArchiveRepository.
+func (r *urlArchiveRepositoryPostgresqlImpl) RestoreShortURL(ctx context.Context, shortPath string, restoredAt time.Time, restoredBy string, actor *models.AuditActor) (*models.URL, error) {
+	tx, err := r.cluster.Writer(shortPath).BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction: %v, shortPath: %s", err, shortPath)
+		return nil, ErrDBError
+	}
+	defer tx.Rollback()
+
+	archived, err := scanURLArchive(tx.QueryRowContext(ctx, PG_LOCK_LATEST_URL_ARCHIVE, shortPath))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrURLNotFound
+	}
+	if err != nil {
+		log.Printf("Error reading archived URL: %v, shortPath: %s", err, shortPath)
+		return nil, ErrDBError
+	}
+	// An expired link would only be archived again by the next cleanup.
+	if archived.Expiry != nil && !archived.Expiry.After(restoredAt) {
+		return nil, ErrURLExpired
+	}
+
+	url := &models.URL{
+		ShortPath:   archived.ShortPath,
+		OriginalURL: archived.OriginalURL,
+		Expiry:      archived.Expiry,
+		CreatedAt:   archived.CreatedAt,
+		ModifiedAt:  &restoredAt,
+		ModifiedBy:  &restoredBy,
+	}
+	if archived.CreatedBy != nil {
+		url.CreatedBy = *archived.CreatedBy
+	}
+	result, err := tx.ExecContext(ctx, PG_RESTORE_SHORT_URL, url.ShortPath, url.OriginalURL, url.Expiry, url.CreatedAt, url.CreatedBy, url.ModifiedAt, url.ModifiedBy)
+	if err != nil {
+		log.Printf("Error restoring short URL: %v, url: %+v", err, url)
+		return nil, ErrDBError
+	}
+	if restored, err := result.RowsAffected(); err != nil || restored == 0 {
+		return nil, ErrShortURLAlreadyExists
+	}
+	if _, err := tx.ExecContext(ctx, PG_DELETE_URL_ARCHIVE, archived.ID); err != nil {
+		log.Printf("Error deleting archived URL: %v, id: %d", err, archived.ID)
+		return nil, ErrDBError
+	}
+	if err := insertLinkEvent(ctx, tx, models.LinkEventRestored, linkEventData(url)); err != nil {
+		return nil, err
+	}
+	if err := insertAuditEntry(ctx, tx, models.AuditActionRestore, shortPath, actor, nil, url, restoredAt); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing restore of short URL: %v, shortPath: %s", err, shortPath)
+		return nil, ErrDBError
+	}
+	return url, nil
+}
+
+// ListArchivedURLs implements URLArchiveRepository.
+func (r *urlArchiveRepositoryPostgresqlImpl) ListArchivedURLs(ctx context.Context, shortPath string, before int64, limit int) ([]models.URLArchive, error) {
+	rows, err := r.cluster.Reader(ctx).QueryContext(ctx, PG_LIST_URL_ARCHIVE, shortPath, before, limit)
+	if err != nil {
+		log.Printf("Error listing archived URLs: %v, shortPath: %s", err, shortPath)
+		return nil, ErrDBError
+	}
+	defer rows.Close()
+
+	archived := []models.URLArchive{}
+	for rows.Next() {
+		url, err := scanURLArchive(rows)
+		if err != nil {
+			log.Printf("Error scanning archived URL: %v", err)
+			return nil, ErrDBError
+		}
+		archived = append(archived, *url)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error listing archived URLs: %v, shortPath: %s", err, shortPath)
+		return nil, ErrDBError
+	}
+	return archived, nil
+}
+
+func scanURLArchive(row scanner) (*models.URLArchive, error) {
+	url := &models.URLArchive{}
+	err := row.Scan(&url.ID, &url.ShortPath, &url.OriginalURL, &url.Expiry, &url.CreatedAt, &url.CreatedBy, &url.ModifiedAt,
+		&url.ModifiedBy, &url.DeletedAt, &url.DeletedBy)
+	if err != nil {
+		return nil, err
+	}
+	return url, nil
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var urlArchiveColumns = []string{"id", "short_path", "original_url", "expiry", "created_at", "created_by", "modified_at", "modified_by", "deleted_at", "deleted_by"}
+
+func TestURLArchiveRepositoryPostgresqlImpl_RestoreShortURL(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLArchiveRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	createdAt, deletedAt, expiry := now.Add(-48*time.Hour), now.Add(-time.Hour), now.Add(24*time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM urls_archive WHERE short_path = \\$1 ORDER BY id DESC LIMIT 1 FOR UPDATE").WithArgs("abc").
+		WillReturnRows(sqlmock.NewRows(urlArchiveColumns).AddRow(7, "abc", "https://example.com", expiry, createdAt, "alice", nil, nil, deletedAt, "bob"))
+	mock.ExpectExec("INSERT INTO urls (.+) ON CONFLICT \\(short_path\\) DO NOTHING").
+		WithArgs("abc", "https://example.com", &expiry, &createdAt, "alice", &now, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM urls_archive WHERE id = \\$1").WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO outbox_events").WithArgs(models.LinkEventRestored, "abc", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO audit_log").
+		WithArgs(models.AuditActionRestore, "abc", "carol", "203.0.113.7", "", "req-1", nil, sqlmock.AnyArg(),
+			[]byte(`{"expiry":{"from":null,"to":"2025-06-13T10:00:00Z"},"originalUrl":{"from":null,"to":"https://example.com"}}`), now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	url, err := repo.RestoreShortURL(context.Background(), "abc", now, "carol", &models.AuditActor{Actor: "carol", SourceIP: "203.0.113.7", RequestID: "req-1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com", url.OriginalURL)
+	assert.Equal(t, "alice", url.CreatedBy)
+	assert.Equal(t, "carol", *url.ModifiedBy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestURLArchiveRepositoryPostgresqlImpl_RestoreShortURL_PathReused(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLArchiveRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM urls_archive WHERE short_path = \\$1").WithArgs("abc").
+		WillReturnRows(sqlmock.NewRows(urlArchiveColumns).AddRow(7, "abc", "https://example.com", nil, now, "alice", nil, nil, now, "bob"))
+	mock.ExpectExec("INSERT INTO urls").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err = repo.RestoreShortURL(context.Background(), "abc", now, "carol", nil)
+
+	assert.ErrorIs(t, err, ErrShortURLAlreadyExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestURLArchiveRepositoryPostgresqlImpl_RestoreShortURL_Expired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLArchiveRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM urls_archive WHERE short_path = \\$1").WithArgs("abc").
+		WillReturnRows(sqlmock.NewRows(urlArchiveColumns).AddRow(7, "abc", "https://example.com", now.Add(-time.Minute), now, "alice", nil, nil, now, "system"))
+	mock.ExpectRollback()
+
+	_, err = repo.RestoreShortURL(context.Background(), "abc", now, "carol", nil)
+
+	assert.ErrorIs(t, err, ErrURLExpired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestURLArchiveRepositoryPostgresqlImpl_RestoreShortURL_NotArchived(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLArchiveRepositoryPostgresql(newTestCluster(db))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM urls_archive WHERE short_path = \\$1").WithArgs("abc").WillReturnRows(sqlmock.NewRows(urlArchiveColumns))
+	mock.ExpectRollback()
+
+	_, err = repo.RestoreShortURL(context.Background(), "abc", time.Now(), "carol", nil)
+
+	assert.ErrorIs(t, err, ErrURLNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestURLArchiveRepositoryPostgresqlImpl_ListArchivedURLs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLArchiveRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("FROM urls_archive WHERE \\(\\$1 = '' OR short_path = \\$1\\) AND \\(\\$2 = 0 OR id < \\$2\\) ORDER BY id DESC LIMIT \\$3").
+		WithArgs("abc", int64(0), 50).
+		WillReturnRows(sqlmock.NewRows(urlArchiveColumns).
+			AddRow(9, "abc", "https://b.example.com", nil, now, "alice", nil, nil, now, "bob").
+			AddRow(4, "abc", "https://a.example.com", nil, now, "alice", nil, nil, now, "system"))
+
+	archived, err := repo.ListArchivedURLs(context.Background(), "abc", 0, 50)
+
+	assert.NoError(t, err)
+	assert.Len(t, archived, 2)
+	assert.Equal(t, int64(9), archived[0].ID)
+	assert.Equal(t, "system", *archived[1].DeletedBy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
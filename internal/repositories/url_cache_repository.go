@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=URLCacheRepository --output=./mocks
+type URLCacheRepository interface {
+	// InsertShortURLs bulk loads urls into the cache and returns how many were written.
+	InsertShortURLs(ctx context.Context, urls []*models.URL) (int, error)
+}
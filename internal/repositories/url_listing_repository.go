@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=URLListingRepository --output=./mocks
+type URLListingRepository interface {
+	ListURLs(ctx context.Context, afterShortPath string, limit int) ([]*models.URL, error)
+	ListTopURLs(ctx context.Context, since time.Time, limit int) ([]*models.URL, error)
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+)
+
+type urlListingRepositoryPostgresqlImpl struct {
+	cluster *db.PostgresCluster
+}
+
+func NewURLListingRepositoryPostgresql(cluster *db.PostgresCluster) URLListingRepository {
+	return &urlListingRepositoryPostgresqlImpl{cluster: cluster}
+}
+
+// ListURLs returns up to limit unexpired urls ordered by short path, starting after afterShortPath.
+// Paging by key keeps each query short instead of holding a cursor open over the whole table.
+func (r *urlListingRepositoryPostgresqlImpl) ListURLs(ctx context.Context, afterShortPath string, limit int) ([]*models.URL, error) {
+	rows, err := r.cluster.Reader(ctx).QueryContext(ctx, PG_LIST_URLS, afterShortPath, time.Now(), limit)
+	if err != nil {
+		log.Printf("Error listing urls from database: %v, after: %s", err, afterShortPath)
+		return nil, ErrDBError
+	}
+	return scanURLs(rows)
+}
+
+// ListTopURLs returns up to limit unexpired urls with the most clicks since the given time, most clicked first.
+func (r *urlListingRepositoryPostgresqlImpl) ListTopURLs(ctx context.Context, since time.Time, limit int) ([]*models.URL, error) {
+	rows, err := r.cluster.Reader(ctx).QueryContext(ctx, PG_LIST_TOP_URLS, since, limit, time.Now())
+	if err != nil {
+		log.Printf("Error listing top urls from database: %v, since: %s", err, since)
+		return nil, ErrDBError
+	}
+	return scanURLs(rows)
+}
+
+func scanURLs(rows *sql.Rows) ([]*models.URL, error) {
+	defer rows.Close()
+	urls := []*models.URL{}
+	for rows.Next() {
+		url := &models.URL{}
+		err := rows.Scan(&url.ShortPath, &url.OriginalURL, &url.Expiry, &url.CreatedAt, &url.CreatedBy, &url.ModifiedAt, &url.ModifiedBy)
+		if err != nil {
+			log.Printf("Error scanning url row: %v", err)
+			return nil, ErrDBError
+		}
+		urls = append(urls, url)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating url rows: %v", err)
+		return nil, ErrDBError
+	}
+	return urls, nil
+}
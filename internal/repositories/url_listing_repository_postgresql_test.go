@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var urlColumns = []string{"short_path", "original_url", "expiry", "created_at", "created_by", "modified_at", "modified_by"}
+
+func TestURLListingRepositoryPostgresqlImpl_ListURLs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLListingRepositoryPostgresql(newTestCluster(db))
+	rows := sqlmock.NewRows(urlColumns).
+		AddRow("path1", "https://www.example.com/1", nil, time.Now(), "system", nil, nil).
+		AddRow("path2", "https://www.example.com/2", time.Now().Add(time.Hour), time.Now(), "system", nil, nil)
+	mock.ExpectQuery("SELECT (.+) FROM urls WHERE short_path > \\$1").WithArgs("path0", sqlmock.AnyArg(), 2).WillReturnRows(rows)
+
+	urls, err := repo.ListURLs(context.Background(), "path0", 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, urls, 2)
+	assert.Equal(t, "path1", urls[0].ShortPath)
+	assert.Equal(t, "https://www.example.com/2", urls[1].OriginalURL)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestURLListingRepositoryPostgresqlImpl_ListURLs_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLListingRepositoryPostgresql(newTestCluster(db))
+	mock.ExpectQuery("SELECT (.+) FROM urls WHERE short_path > \\$1").WillReturnError(fmt.Errorf("some error"))
+
+	urls, err := repo.ListURLs(context.Background(), "", 10)
+
+	assert.ErrorIs(t, err, ErrDBError)
+	assert.Nil(t, urls)
+}
+
+func TestURLListingRepositoryPostgresqlImpl_ListTopURLs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLListingRepositoryPostgresql(newTestCluster(db))
+	since := time.Now().Add(-24 * time.Hour)
+	rows := sqlmock.NewRows(urlColumns).
+		AddRow("hot", "https://www.example.com/hot", nil, time.Now(), "system", nil, nil)
+	mock.ExpectQuery("SELECT (.+) FROM urls u JOIN \\(SELECT short_path, COUNT\\(\\*\\) AS clicks FROM url_access_logs").WithArgs(since, 1, sqlmock.AnyArg()).WillReturnRows(rows)
+
+	urls, err := repo.ListTopURLs(context.Background(), since, 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, urls, 1)
+	assert.Equal(t, "hot", urls[0].ShortPath)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -10,7 +10,9 @@ import (
 type URLRepository interface {
 	GetShortURL(ctx context.Context, originalURL string) (*models.URL, error)
 	GetOriginalURL(ctx context.Context, shortPath string) (*models.URL, error)
-	UpdateShortURL(ctx context.Context, url *models.URL) error
-	DeleteShortURL(ctx context.Context, shortPath string, currentTime time.Time, deletedBy string) error
-	InsertShortURL(ctx context.Context, url *models.URL) error
+	// UpdateShortURL, DeleteShortURL and InsertShortURL record the change in the audit log as made by actor, nil for
+	// the system. Caches do not keep an audit log and ignore actor.
+	UpdateShortURL(ctx context.Context, url *models.URL, actor *models.AuditActor) error
+	DeleteShortURL(ctx context.Context, shortPath string, currentTime time.Time, deletedBy string, actor *models.AuditActor) error
+	InsertShortURL(ctx context.Context, url *models.URL, actor *models.AuditActor) error
 }
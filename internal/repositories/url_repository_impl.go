@@ -28,7 +28,7 @@ func (r *urlRepositoryImpl) GetShortURL(ctx context.Context, originalURL string)
 	}
 	if url != nil {
 		go func() {
-			err = r.redisRepo.InsertShortURL(context.Background(), url)
+			err = r.redisRepo.InsertShortURL(context.Background(), url, nil)
 			if err != nil {
 				log.Print("Error inserting to redis" + err.Error())
 			}
@@ -51,7 +51,7 @@ func (r *urlRepositoryImpl) GetOriginalURL(ctx context.Context, shortPath string
 		return nil, err
 	}
 	if url != nil {
-		err = r.redisRepo.InsertShortURL(ctx, url)
+		err = r.redisRepo.InsertShortURL(ctx, url, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -59,31 +59,35 @@ func (r *urlRepositoryImpl) GetOriginalURL(ctx context.Context, shortPath string
 	return url, nil
 }
 
-func (r *urlRepositoryImpl) UpdateShortURL(ctx context.Context, url *models.URL) error {
-	err := r.redisRepo.DeleteShortURL(ctx, url.ShortPath, r.timeProvider.Now(), "system")
+// UpdateShortURL drops the cached link once the change is committed. The outbox relay drops it again from the
+// link.updated event, so a failed delete or a reader caching the old link in between is only stale until then.
+func (r *urlRepositoryImpl) UpdateShortURL(ctx context.Context, url *models.URL, actor *models.AuditActor) error {
+	err := r.postgresRepo.UpdateShortURL(ctx, url, actor)
 	if err != nil {
 		log.Printf(err.Error())
 		return err
 	}
-	err = r.postgresRepo.UpdateShortURL(ctx, url)
-	if err != nil {
-		log.Printf(err.Error())
-		return err
+	if err := r.redisRepo.DeleteShortURL(ctx, url.ShortPath, r.timeProvider.Now(), "system", nil); err != nil {
+		log.Printf("Error invalidating cache of %s, left to the outbox relay: %v", url.ShortPath, err)
 	}
 	return nil
 }
 
-func (r *urlRepositoryImpl) DeleteShortURL(ctx context.Context, shortPath string, currentTime time.Time, deletedBy string) error {
-	err := r.postgresRepo.DeleteShortURL(ctx, shortPath, currentTime, deletedBy)
+// DeleteShortURL drops the cached link like UpdateShortURL, the relay drops it again from the link.deleted event.
+func (r *urlRepositoryImpl) DeleteShortURL(ctx context.Context, shortPath string, currentTime time.Time, deletedBy string, actor *models.AuditActor) error {
+	err := r.postgresRepo.DeleteShortURL(ctx, shortPath, currentTime, deletedBy, actor)
 	if err != nil {
 		log.Printf(err.Error())
 		return err
 	}
-	return r.redisRepo.DeleteShortURL(ctx, shortPath, currentTime, deletedBy)
+	if err := r.redisRepo.DeleteShortURL(ctx, shortPath, currentTime, deletedBy, nil); err != nil {
+		log.Printf("Error invalidating cache of %s, left to the outbox relay: %v", shortPath, err)
+	}
+	return nil
 }
 
-func (r *urlRepositoryImpl) InsertShortURL(ctx context.Context, url *models.URL) error {
-	err := r.postgresRepo.InsertShortURL(ctx, url)
+func (r *urlRepositoryImpl) InsertShortURL(ctx context.Context, url *models.URL, actor *models.AuditActor) error {
+	err := r.postgresRepo.InsertShortURL(ctx, url, actor)
 	if err != nil {
 		log.Printf(err.Error())
 		return err
@@ -5,22 +5,23 @@ import (
 	"database/sql"
 	"errors"
 	"time"
+	"url-shortener/internal/db"
 	"url-shortener/internal/models"
 
 	"log"
 )
 
 type urlRepositoryPostgresqlImpl struct {
-	db *sql.DB
+	cluster *db.PostgresCluster
 }
 
-func NewURLRepositoryPostgresql(db *sql.DB) URLRepository {
-	return &urlRepositoryPostgresqlImpl{db: db}
+func NewURLRepositoryPostgresql(cluster *db.PostgresCluster) URLRepository {
+	return &urlRepositoryPostgresqlImpl{cluster: cluster}
 }
 
 // GetShortURL implements URLRepository.
 func (r *urlRepositoryPostgresqlImpl) GetShortURL(ctx context.Context, originalURL string) (*models.URL, error) {
-	row := r.db.QueryRowContext(ctx, PG_GET_BY_ORIGINAL_URL, originalURL)
+	row := r.cluster.Reader(ctx, originalURL).QueryRowContext(ctx, PG_GET_BY_ORIGINAL_URL, originalURL)
 	url := &models.URL{}
 	err := row.Scan(&url.ShortPath, &url.OriginalURL, &url.Expiry, &url.CreatedAt, &url.CreatedBy, &url.ModifiedAt, &url.ModifiedBy)
 	if err != nil {
@@ -38,7 +39,7 @@ func (r *urlRepositoryPostgresqlImpl) GetShortURL(ctx context.Context, originalU
 
 // GetOriginalURL implements URLRepository.
 func (r *urlRepositoryPostgresqlImpl) GetOriginalURL(ctx context.Context, shortPath string) (*models.URL, error) {
-	row := r.db.QueryRowContext(ctx, PG_GET_BY_SHORT_URL, shortPath)
+	row := r.cluster.Reader(ctx, shortPath).QueryRowContext(ctx, PG_GET_BY_SHORT_URL, shortPath)
 	url := &models.URL{}
 	err := row.Scan(&url.ShortPath, &url.OriginalURL, &url.Expiry, &url.CreatedAt, &url.CreatedBy, &url.ModifiedAt, &url.ModifiedBy)
 	if err != nil {
@@ -54,27 +55,56 @@ func (r *urlRepositoryPostgresqlImpl) GetOriginalURL(ctx context.Context, shortP
 	return url, nil
 }
 
-// UpdateShortURL implements URLRepository.
-func (r *urlRepositoryPostgresqlImpl) UpdateShortURL(ctx context.Context, url *models.URL) error {
-	_, err := r.db.ExecContext(ctx, PG_UPDATE_SHORT_URL, url.OriginalURL, url.Expiry, url.ModifiedAt, url.ModifiedBy, url.ShortPath)
+// UpdateShortURL implements URLRepository. The link.updated event and the audit entry are recorded in the same
+// transaction, updating a link that does not exist changes nothing.
+func (r *urlRepositoryPostgresqlImpl) UpdateShortURL(ctx context.Context, url *models.URL, actor *models.AuditActor) error {
+	tx, err := r.cluster.Writer(url.ShortPath, url.OriginalURL).BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction: %v, shortPath: %s", err, url.ShortPath)
+		return ErrDBError
+	}
+	defer tx.Rollback()
+
+	before := &models.URL{}
+	err = tx.QueryRowContext(ctx, PG_LOCK_SHORT_URL, url.ShortPath).Scan(&before.ShortPath, &before.OriginalURL, &before.Expiry,
+		&before.CreatedAt, &before.CreatedBy, &before.ModifiedAt, &before.ModifiedBy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		log.Printf("Error locking short URL before update: %v, shortPath: %s", err, url.ShortPath)
+		return ErrDBError
+	}
+
+	_, err = tx.ExecContext(ctx, PG_UPDATE_SHORT_URL, url.OriginalURL, url.Expiry, url.ModifiedAt, url.ModifiedBy, url.ShortPath)
 	if err != nil {
 		log.Printf("Error updating short URL in database: %v, url: %+v", err, url)
 		return ErrDBError
 	}
+	if err := insertLinkEvent(ctx, tx, models.LinkEventUpdated, linkEventData(url)); err != nil {
+		return err
+	}
+	if err := insertAuditEntry(ctx, tx, models.AuditActionUpdate, url.ShortPath, actor, before, url, auditTime(url.ModifiedAt)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing update of short URL: %v, shortPath: %s", err, url.ShortPath)
+		return ErrDBError
+	}
 	return nil
 }
 
 // DeleteShortURL implements URLRepository.
-func (r *urlRepositoryPostgresqlImpl) DeleteShortURL(ctx context.Context, shortPath string, currentTime time.Time, deletedBy string) error {
-	tx, err := r.db.BeginTx(ctx, nil)
+func (r *urlRepositoryPostgresqlImpl) DeleteShortURL(ctx context.Context, shortPath string, currentTime time.Time, deletedBy string, actor *models.AuditActor) error {
+	tx, err := r.cluster.Writer(shortPath).BeginTx(ctx, nil)
 	if err != nil {
 		log.Printf("Error starting transaction: %v, shortPath: %s", err, shortPath)
 		return ErrDBError
 	}
 	defer tx.Rollback() // Rollback on error
 
-	// Delete from urls and retrieve the deleted row using RETURNING
-	row := tx.QueryRowContext(ctx, PG_GET_BY_SHORT_URL, shortPath)
+	// Lock the row, so it is archived and audited as it is deleted
+	row := tx.QueryRowContext(ctx, PG_LOCK_SHORT_URL, shortPath)
 
 	urlArchive := &models.URLArchive{}
 	err = row.Scan(&urlArchive.ShortPath, &urlArchive.OriginalURL, &urlArchive.Expiry, &urlArchive.CreatedAt, &urlArchive.CreatedBy, &urlArchive.ModifiedAt, &urlArchive.ModifiedBy)
@@ -93,6 +123,7 @@ func (r *urlRepositoryPostgresqlImpl) DeleteShortURL(ctx context.Context, shortP
 	_, err = tx.ExecContext(ctx, PG_INSERT_URL_ARCHIVE, urlArchive.ShortPath, urlArchive.OriginalURL, urlArchive.Expiry, urlArchive.CreatedAt, urlArchive.CreatedBy, urlArchive.ModifiedAt, urlArchive.ModifiedBy, urlArchive.DeletedAt, urlArchive.DeletedBy)
 	if err != nil {
 		log.Printf("Error inserting into url_archive: %v, urlArchive: %+v", err, urlArchive)
+		return ErrDBError
 	}
 
 	// Delete from urls
@@ -102,15 +133,49 @@ func (r *urlRepositoryPostgresqlImpl) DeleteShortURL(ctx context.Context, shortP
 		return ErrDBError
 	}
 
+	if err := insertLinkEvent(ctx, tx, models.LinkEventDeleted, archivedLinkEventData(urlArchive)); err != nil {
+		return err
+	}
+	before := &models.URL{ShortPath: urlArchive.ShortPath, OriginalURL: urlArchive.OriginalURL, Expiry: urlArchive.Expiry}
+	if err := insertAuditEntry(ctx, tx, models.AuditActionDelete, shortPath, actor, before, nil, currentTime); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
-// InsertShortURL implements URLRepository.
-func (r *urlRepositoryPostgresqlImpl) InsertShortURL(ctx context.Context, url *models.URL) error {
-	_, err := r.db.ExecContext(ctx, PG_INSERT_SHORT_URL, url.ShortPath, url.OriginalURL, url.Expiry, url.CreatedAt, url.CreatedBy)
+// InsertShortURL implements URLRepository. The link.created event and the audit entry are recorded in the same
+// transaction.
+func (r *urlRepositoryPostgresqlImpl) InsertShortURL(ctx context.Context, url *models.URL, actor *models.AuditActor) error {
+	tx, err := r.cluster.Writer(url.ShortPath, url.OriginalURL).BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction: %v, shortPath: %s", err, url.ShortPath)
+		return ErrDBError
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, PG_INSERT_SHORT_URL, url.ShortPath, url.OriginalURL, url.Expiry, url.CreatedAt, url.CreatedBy)
 	if err != nil {
 		log.Printf("Error inserting short URL into database: %v, url: %+v", err, url)
 		return ErrDBError
 	}
+	if err := insertLinkEvent(ctx, tx, models.LinkEventCreated, linkEventData(url)); err != nil {
+		return err
+	}
+	if err := insertAuditEntry(ctx, tx, models.AuditActionCreate, url.ShortPath, actor, nil, url, auditTime(url.CreatedAt)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing short URL: %v, shortPath: %s", err, url.ShortPath)
+		return ErrDBError
+	}
 	return nil
 }
+
+// auditTime is when a change stamped with at was made, now for changes without a stamp.
+func auditTime(at *time.Time) time.Time {
+	if at == nil {
+		return time.Now()
+	}
+	return *at
+}
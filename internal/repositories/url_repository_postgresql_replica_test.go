@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+	"url-shortener/internal/config"
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupReplicaCluster(t *testing.T, lagSeconds float64) (sqlmock.Sqlmock, sqlmock.Sqlmock, *db.PostgresCluster) {
+	primary, primaryMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { primary.Close() })
+	replica, replicaMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { replica.Close() })
+
+	cluster := db.NewPostgresCluster(primary, []*sql.DB{replica}, &config.DatabaseConfig{MaxReplicaLag: 2 * time.Second, ReadYourWritesWindow: time.Minute})
+	replicaMock.ExpectQuery("SELECT CASE WHEN pg_last_wal_receive_lsn\\(\\)").WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(lagSeconds))
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	cluster.StartHealthChecks(ctx, time.Hour)
+	return primaryMock, replicaMock, cluster
+}
+
+func urlRows(shortPath string) *sqlmock.Rows {
+	return sqlmock.NewRows(urlColumns).AddRow(shortPath, "https://www.example.com", nil, time.Now(), "system", nil, nil)
+}
+
+func TestURLRepositoryPostgresqlImpl_ReadsFromReplica(t *testing.T) {
+	primaryMock, replicaMock, cluster := setupReplicaCluster(t, 0)
+	repo := NewURLRepositoryPostgresql(cluster)
+	replicaMock.ExpectQuery("SELECT (.+) FROM urls WHERE short_path = ?").WithArgs("shortPath").WillReturnRows(urlRows("shortPath"))
+
+	url, err := repo.GetOriginalURL(context.Background(), "shortPath")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "shortPath", url.ShortPath)
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
+
+func TestURLRepositoryPostgresqlImpl_LaggingReplicaFallsBackToPrimary(t *testing.T) {
+	primaryMock, replicaMock, cluster := setupReplicaCluster(t, 30)
+	repo := NewURLRepositoryPostgresql(cluster)
+	primaryMock.ExpectQuery("SELECT (.+) FROM urls WHERE short_path = ?").WithArgs("shortPath").WillReturnRows(urlRows("shortPath"))
+
+	_, err := repo.GetOriginalURL(context.Background(), "shortPath")
+
+	assert.NoError(t, err)
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
+
+func TestURLRepositoryPostgresqlImpl_ReadYourWrites(t *testing.T) {
+	primaryMock, replicaMock, cluster := setupReplicaCluster(t, 0)
+	repo := NewURLRepositoryPostgresql(cluster)
+	now := time.Now()
+	url := &models.URL{ShortPath: "shortPath", OriginalURL: "https://www.example.com", CreatedAt: &now, CreatedBy: "system"}
+	primaryMock.ExpectBegin()
+	primaryMock.ExpectExec("INSERT INTO urls").WillReturnResult(sqlmock.NewResult(1, 1))
+	primaryMock.ExpectExec("INSERT INTO outbox_events").WillReturnResult(sqlmock.NewResult(1, 1))
+	primaryMock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(1, 1))
+	primaryMock.ExpectCommit()
+	primaryMock.ExpectQuery("SELECT (.+) FROM urls WHERE short_path = ?").WithArgs("shortPath").WillReturnRows(urlRows("shortPath"))
+	replicaMock.ExpectQuery("SELECT (.+) FROM urls WHERE short_path = ?").WithArgs("otherPath").WillReturnRows(urlRows("otherPath"))
+
+	assert.NoError(t, repo.InsertShortURL(context.Background(), url, nil))
+	_, err := repo.GetOriginalURL(context.Background(), "shortPath")
+	assert.NoError(t, err)
+	_, err = repo.GetOriginalURL(context.Background(), "otherPath")
+	assert.NoError(t, err)
+
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
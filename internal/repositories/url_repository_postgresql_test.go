@@ -18,7 +18,7 @@ func TestURLRepositoryPostgresqlImpl_GetShortURL(t *testing.T) {
 	assert.Nil(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	originalURL := "https://www.example.com"
 
@@ -38,7 +38,7 @@ func TestURLRepositoryPostgresqlImpl_GetOriginalURL(t *testing.T) {
 	assert.Nil(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	shortPath := "shortPath"
 
@@ -58,7 +58,7 @@ func TestURLRepositoryPostgresqlImpl_InsertShortURL(t *testing.T) {
 	assert.Nil(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	currentTime := time.Now()
 
@@ -70,9 +70,17 @@ func TestURLRepositoryPostgresqlImpl_InsertShortURL(t *testing.T) {
 		CreatedBy:   "system",
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectExec("INSERT INTO urls \\(short_path, original_url, expiry, created_at, created_by\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5\\)").WithArgs(url.ShortPath, url.OriginalURL, url.Expiry, url.CreatedAt, url.CreatedBy).WillReturnResult(sqlmock.NewResult(1, 1))
-	err = repo.InsertShortURL(ctx, url)
+	mock.ExpectExec("INSERT INTO outbox_events \\(type, short_path, payload\\) VALUES \\(\\$1, \\$2, \\$3\\)").
+		WithArgs(models.LinkEventCreated, "shortPath", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO audit_log").
+		WithArgs(models.AuditActionCreate, "shortPath", "alice", "203.0.113.7", "", "", nil, sqlmock.AnyArg(), sqlmock.AnyArg(), currentTime.UTC()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	err = repo.InsertShortURL(ctx, url, &models.AuditActor{Actor: "alice", SourceIP: "203.0.113.7"})
 	assert.Nil(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestURLRepositoryPostgresqlImpl_UpdateShortURL(t *testing.T) {
@@ -80,7 +88,7 @@ func TestURLRepositoryPostgresqlImpl_UpdateShortURL(t *testing.T) {
 	assert.Nil(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	currentTime := time.Now()
 	modifiedBy := "system"
@@ -93,17 +101,51 @@ func TestURLRepositoryPostgresqlImpl_UpdateShortURL(t *testing.T) {
 		ModifiedBy:  &modifiedBy,
 	}
 
+	// The audit entry diffs the change against the row locked by the transaction.
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM urls WHERE short_path = \\$1 FOR UPDATE").WithArgs("shortPath").
+		WillReturnRows(sqlmock.NewRows([]string{"short_path", "original_url", "expiry", "created_at", "created_by", "modified_at", "modified_by"}).
+			AddRow("shortPath", "https://old.example.com", nil, currentTime, "system", nil, nil))
 	mock.ExpectExec("UPDATE urls SET original_url = \\$1, expiry = \\$2, modified_at = \\$3, modified_by = \\$4 WHERE short_path = \\$5").WithArgs(url.OriginalURL, url.Expiry, url.ModifiedAt, url.ModifiedBy, url.ShortPath).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO outbox_events").WithArgs(models.LinkEventUpdated, "shortPath", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO audit_log").
+		WithArgs(models.AuditActionUpdate, "shortPath", "system", "", "", "", []byte(`{"originalUrl":"https://old.example.com","expiry":null}`),
+			[]byte(`{"originalUrl":"https://www.example.com","expiry":"0001-01-01T00:00:00Z"}`),
+			[]byte(`{"expiry":{"from":null,"to":"0001-01-01T00:00:00Z"},"originalUrl":{"from":"https://old.example.com","to":"https://www.example.com"}}`),
+			currentTime.UTC()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = repo.UpdateShortURL(ctx, url, nil)
+	assert.Nil(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestURLRepositoryPostgresqlImpl_UpdateShortURL_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
+	currentTime := time.Now()
+	modifiedBy := "system"
+	url := &models.URL{ShortPath: "missing", OriginalURL: "https://www.example.com", ModifiedAt: &currentTime, ModifiedBy: &modifiedBy}
+
+	// Without a change there is no event and no audit entry.
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM urls WHERE short_path = \\$1 FOR UPDATE").WithArgs("missing").WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
 
-	err = repo.UpdateShortURL(ctx, url)
+	err = repo.UpdateShortURL(context.Background(), url, nil)
 	assert.Nil(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 func TestURLRepositoryPostgresqlImpl_DeleteShortURL_Success(t *testing.T) {
 	db, mockDB, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	shortPath := "shortPath"
 	currentTime := time.Now()
@@ -113,7 +155,7 @@ func TestURLRepositoryPostgresqlImpl_DeleteShortURL_Success(t *testing.T) {
 
 	returnedRows := sqlmock.NewRows([]string{"short_path", "original_url", "expiry", "created_at", "created_by", "modified_at", "modified_by"}).
 		AddRow(shortPath, "https://www.example.com", currentTime.Add(time.Minute*60), currentTime, "user", currentTime, "user")
-	mockDB.ExpectQuery("SELECT short_path, original_url, expiry, created_at, created_by, modified_at, modified_by FROM urls WHERE short_path = \\$1").WithArgs(shortPath).WillReturnRows(returnedRows)
+	mockDB.ExpectQuery("SELECT short_path, original_url, expiry, created_at, created_by, modified_at, modified_by FROM urls WHERE short_path = \\$1 FOR UPDATE").WithArgs(shortPath).WillReturnRows(returnedRows)
 
 	mockDB.ExpectExec("INSERT INTO urls_archive \\(short_path, original_url, expiry, created_at, created_by, modified_at, modified_by, deleted_at, deleted_by\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7, \\$8, \\$9\\)").
 		WithArgs(shortPath, "https://www.example.com", currentTime.Add(time.Minute*60), currentTime, "user", currentTime, "user", &currentTime, &deletedBy).
@@ -121,10 +163,37 @@ func TestURLRepositoryPostgresqlImpl_DeleteShortURL_Success(t *testing.T) {
 
 	mockDB.ExpectExec("DELETE FROM urls WHERE short_path = \\$1").WithArgs(shortPath).WillReturnResult(sqlmock.NewResult(1, 1))
 
+	mockDB.ExpectExec("INSERT INTO outbox_events").WithArgs(models.LinkEventDeleted, shortPath, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mockDB.ExpectExec("INSERT INTO audit_log").
+		WithArgs(models.AuditActionDelete, shortPath, deletedBy, "", "", "req-1", sqlmock.AnyArg(), nil, sqlmock.AnyArg(), currentTime.UTC()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
 	mockDB.ExpectCommit()
 
-	err = repo.DeleteShortURL(ctx, shortPath, currentTime, deletedBy)
+	err = repo.DeleteShortURL(ctx, shortPath, currentTime, deletedBy, &models.AuditActor{Actor: deletedBy, RequestID: "req-1"})
 	assert.NoError(t, err)
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestURLRepositoryPostgresqlImpl_InsertShortURL_OutboxError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
+	currentTime := time.Now()
+	url := &models.URL{ShortPath: "shortPath", OriginalURL: "https://www.example.com", CreatedAt: &currentTime, CreatedBy: "system"}
+
+	// The link is not created without its event.
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO urls").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO outbox_events").WillReturnError(fmt.Errorf("some error"))
+	mock.ExpectRollback()
+
+	err = repo.InsertShortURL(context.Background(), url, nil)
+	assert.ErrorIs(t, err, ErrDBError)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestURLRepositoryPostgresqlImpl_DeleteShortURL_BeginTxError(t *testing.T) {
@@ -132,13 +201,13 @@ func TestURLRepositoryPostgresqlImpl_DeleteShortURL_BeginTxError(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	shortPath := "shortPath"
 	currentTime := time.Now()
 	deletedBy := "testuser"
 	mockDB.ExpectBegin().WillReturnError(fmt.Errorf("begin error"))
-	err = repo.DeleteShortURL(ctx, shortPath, currentTime, deletedBy)
+	err = repo.DeleteShortURL(ctx, shortPath, currentTime, deletedBy, nil)
 	assert.NotNil(t, err)
 }
 
@@ -147,7 +216,7 @@ func TestURLRepositoryPostgresqlImpl_DeleteShortURL_SelectError(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	shortPath := "shortPath"
 	currentTime := time.Now()
@@ -155,7 +224,7 @@ func TestURLRepositoryPostgresqlImpl_DeleteShortURL_SelectError(t *testing.T) {
 	mockDB.ExpectBegin()
 	mockDB.ExpectQuery("SELECT short_path, original_url, expiry, created_at, created_by, modified_at, modified_by FROM urls WHERE short_path = \\$1").WithArgs(shortPath).WillReturnError(fmt.Errorf("select error"))
 	mockDB.ExpectRollback()
-	err = repo.DeleteShortURL(ctx, shortPath, currentTime, deletedBy)
+	err = repo.DeleteShortURL(ctx, shortPath, currentTime, deletedBy, nil)
 	assert.NotNil(t, err)
 }
 
@@ -164,7 +233,7 @@ func TestURLRepositoryPostgresqlImpl_DeleteShortURL_InsertArchiveError(t *testin
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	shortPath := "shortPath"
 	currentTime := time.Now()
@@ -175,7 +244,7 @@ func TestURLRepositoryPostgresqlImpl_DeleteShortURL_InsertArchiveError(t *testin
 		WillReturnError(fmt.Errorf("insert archive error"))
 	mockDB.ExpectRollback()
 
-	err = repo.DeleteShortURL(ctx, shortPath, currentTime, deletedBy)
+	err = repo.DeleteShortURL(ctx, shortPath, currentTime, deletedBy, nil)
 	assert.NotNil(t, err)
 }
 
@@ -184,7 +253,7 @@ func TestURLRepositoryPostgresqlImpl_DeleteShortURL_DeleteError(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	shortPath := "shortPath"
 	currentTime := time.Now()
@@ -193,7 +262,7 @@ func TestURLRepositoryPostgresqlImpl_DeleteShortURL_DeleteError(t *testing.T) {
 	mockDB.ExpectExec("DELETE FROM urls WHERE short_path = \\$1").WithArgs(shortPath).WillReturnError(fmt.Errorf("delete error"))
 
 	mockDB.ExpectRollback()
-	err = repo.DeleteShortURL(ctx, shortPath, currentTime, deletedBy)
+	err = repo.DeleteShortURL(ctx, shortPath, currentTime, deletedBy, nil)
 	assert.NotNil(t, err)
 }
 
@@ -202,7 +271,7 @@ func TestURLRepositoryPostgresqlImpl_GetShortURL_Error(t *testing.T) {
 	assert.Nil(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	originalURL := "https://www.example.com"
 
@@ -217,7 +286,7 @@ func TestURLRepositoryPostgresqlImpl_GetOriginalURL_Error(t *testing.T) {
 	assert.Nil(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	shortPath := "shortPath"
 
@@ -232,7 +301,7 @@ func TestURLRepositoryPostgresqlImpl_InsertShortURL_Error(t *testing.T) {
 	assert.Nil(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	currentTime := time.Now()
 	createdBy := "system"
@@ -244,9 +313,11 @@ func TestURLRepositoryPostgresqlImpl_InsertShortURL_Error(t *testing.T) {
 		CreatedBy:   createdBy,
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectExec("INSERT INTO urls \\(short_path, original_url, expiry, created_at, created_by\\) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5\\)").WithArgs(url.ShortPath, url.OriginalURL, url.Expiry, url.CreatedAt, url.CreatedBy).WillReturnError(fmt.Errorf("some error"))
+	mock.ExpectRollback()
 
-	err = repo.InsertShortURL(ctx, url)
+	err = repo.InsertShortURL(ctx, url, nil)
 	assert.NotNil(t, err)
 }
 
@@ -255,7 +326,7 @@ func TestURLRepositoryPostgresqlImpl_UpdateShortURL_Error(t *testing.T) {
 	assert.Nil(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	currentTime := time.Now()
 	modifiedBy := "system"
@@ -267,9 +338,14 @@ func TestURLRepositoryPostgresqlImpl_UpdateShortURL_Error(t *testing.T) {
 		ModifiedBy:  &modifiedBy,
 	}
 
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM urls WHERE short_path = \\$1 FOR UPDATE").WithArgs("shortPath").
+		WillReturnRows(sqlmock.NewRows([]string{"short_path", "original_url", "expiry", "created_at", "created_by", "modified_at", "modified_by"}).
+			AddRow("shortPath", "https://www.example.com", nil, currentTime, "system", nil, nil))
 	mock.ExpectExec("UPDATE urls SET original_url = \\$1, expiry = \\$2, modified_at = \\$3, modified_by = \\$4 WHERE short_path = \\$5").WithArgs(url.OriginalURL, url.Expiry, url.ModifiedAt, url.ModifiedBy, url.ShortPath).WillReturnError(fmt.Errorf("some error"))
+	mock.ExpectRollback()
 
-	err = repo.UpdateShortURL(ctx, url)
+	err = repo.UpdateShortURL(ctx, url, nil)
 	assert.NotNil(t, err)
 }
 
@@ -278,7 +354,7 @@ func TestURLRepositoryPostgresqlImpl_GetShortURL_NoRowsError(t *testing.T) {
 	assert.Nil(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	originalURL := "https://www.example.com"
 
@@ -294,7 +370,7 @@ func TestURLRepositoryPostgresqlImpl_GetOriginalURL_NoRowsError(t *testing.T) {
 	assert.Nil(t, err)
 	defer db.Close()
 
-	repo := NewURLRepositoryPostgresql(db)
+	repo := NewURLRepositoryPostgresql(newTestCluster(db))
 	ctx := context.Background()
 	shortPath := "shortPath"
 
@@ -13,12 +13,16 @@ import (
 )
 
 type urlRepositoryRedisImpl struct {
-	client      db.RedisClient
-	cacheExpiry time.Duration
+	client db.RedisClient
+	policy CachePolicy
 }
 
-func NewURLRepositoryRedis(client db.RedisClient, cacheExpiry time.Duration) URLRepository {
-	return &urlRepositoryRedisImpl{client: client, cacheExpiry: cacheExpiry}
+func NewURLRepositoryRedis(client db.RedisClient, policy CachePolicy) URLRepository {
+	return &urlRepositoryRedisImpl{client: client, policy: policy}
+}
+
+func NewURLCacheRepositoryRedis(client db.RedisClient, policy CachePolicy) URLCacheRepository {
+	return &urlRepositoryRedisImpl{client: client, policy: policy}
 }
 
 func (r *urlRepositoryRedisImpl) GetShortURL(ctx context.Context, originalURL string) (*models.URL, error) {
@@ -38,14 +42,19 @@ func (r *urlRepositoryRedisImpl) GetOriginalURL(ctx context.Context, shortPath s
 	if err != nil {
 		return nil, err
 	}
+	if expiry := r.policy.OnHit(&url); expiry > 0 {
+		if err := r.client.Expire(ctx, shortPath, expiry).Err(); err != nil {
+			log.Printf("Error extending cache expiry: %v, shortPath: %s", err, shortPath)
+		}
+	}
 	return &url, nil
 }
 
-func (r *urlRepositoryRedisImpl) UpdateShortURL(ctx context.Context, url *models.URL) error {
+func (r *urlRepositoryRedisImpl) UpdateShortURL(ctx context.Context, url *models.URL, actor *models.AuditActor) error {
 	return errors.New("not implemented")
 }
 
-func (r *urlRepositoryRedisImpl) DeleteShortURL(ctx context.Context, shortPath string, currentTime time.Time, deletedBy string) error {
+func (r *urlRepositoryRedisImpl) DeleteShortURL(ctx context.Context, shortPath string, currentTime time.Time, deletedBy string, actor *models.AuditActor) error {
 	err := r.client.Del(ctx, shortPath).Err()
 	if err != nil {
 		log.Printf(err.Error())
@@ -54,15 +63,15 @@ func (r *urlRepositoryRedisImpl) DeleteShortURL(ctx context.Context, shortPath s
 	return nil
 }
 
-func (r *urlRepositoryRedisImpl) InsertShortURL(ctx context.Context, url *models.URL) error {
+func (r *urlRepositoryRedisImpl) InsertShortURL(ctx context.Context, url *models.URL, actor *models.AuditActor) error {
 	data, err := json.Marshal(url)
 	if err != nil {
 		log.Printf(err.Error())
 		return err
 	}
-	expiry := r.cacheExpiry
-	if url.Expiry != nil && time.Until(*url.Expiry) < (expiry) {
-		expiry = time.Until(*url.Expiry)
+	expiry := r.policy.TTL(url)
+	if expiry <= 0 {
+		return nil
 	}
 	err = r.client.Set(ctx, url.ShortPath, string(data), expiry).Err()
 	if err != nil {
@@ -71,3 +80,29 @@ func (r *urlRepositoryRedisImpl) InsertShortURL(ctx context.Context, url *models
 	}
 	return nil
 }
+
+// InsertShortURLs implements URLCacheRepository. All urls are written in a single pipeline,
+// skipping urls that have already expired.
+func (r *urlRepositoryRedisImpl) InsertShortURLs(ctx context.Context, urls []*models.URL) (int, error) {
+	inserted := 0
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, url := range urls {
+			expiry := r.policy.TTL(url)
+			if expiry <= 0 {
+				continue
+			}
+			data, err := json.Marshal(url)
+			if err != nil {
+				return err
+			}
+			pipe.Set(ctx, url.ShortPath, string(data), expiry)
+			inserted++
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf(err.Error())
+		return 0, err
+	}
+	return inserted, nil
+}
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"testing"
 	"time"
+	"url-shortener/internal/config"
 	"url-shortener/internal/models"
+	"url-shortener/internal/utils"
 
 	dbMocks "url-shortener/internal/db/mocks"
 
@@ -15,9 +17,15 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+const testCacheTTL = 10 * time.Minute
+
 func setupRedisRepository() (*dbMocks.RedisClient, *urlRepositoryRedisImpl) {
+	return setupRedisRepositoryWithConfig(config.CacheConfig{BaseTTL: testCacheTTL})
+}
+
+func setupRedisRepositoryWithConfig(cacheConfig config.CacheConfig) (*dbMocks.RedisClient, *urlRepositoryRedisImpl) {
 	mockClient := &dbMocks.RedisClient{}
-	repo := NewURLRepositoryRedis(mockClient, 10*time.Minute)
+	repo := NewURLRepositoryRedis(mockClient, NewCachePolicy(cacheConfig, utils.NewTimeProvider()))
 	return mockClient, repo.(*urlRepositoryRedisImpl)
 }
 
@@ -38,6 +46,22 @@ func TestRedisGetOriginalURL_Success(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+func TestRedisGetOriginalURL_ExtendsHotKey(t *testing.T) {
+	mockClient, repo := setupRedisRepositoryWithConfig(config.CacheConfig{BaseTTL: testCacheTTL, HotThreshold: 2, HotWindow: time.Minute, HotTTL: time.Hour})
+	data, _ := json.Marshal(models.URL{OriginalURL: "https://example.com", ShortPath: "shortpath"})
+	expectedOut := &redis.StringCmd{}
+	expectedOut.SetVal(string(data))
+	mockClient.On("Get", mock.Anything, "shortpath").Return(expectedOut).Twice()
+	mockClient.On("Expire", mock.Anything, "shortpath", time.Hour).Return(&redis.BoolCmd{}).Once()
+
+	for i := 0; i < 2; i++ {
+		_, err := repo.GetOriginalURL(context.Background(), "shortpath")
+		assert.NoError(t, err)
+	}
+
+	mockClient.AssertExpectations(t)
+}
+
 func TestRedisGetOriginalURL_NotFound(t *testing.T) {
 	mockClient, repo := setupRedisRepository()
 	expectedOut := &redis.StringCmd{}
@@ -71,20 +95,29 @@ func TestRedisInsertShortURL_Success(t *testing.T) {
 	expectedOut.SetVal("")
 	expectedOut.SetErr(nil)
 	mockURL := models.URL{OriginalURL: "https://example.com", ShortPath: "shortpath"}
-	mockClient.On("Set", mock.Anything, "shortpath", mock.Anything, repo.cacheExpiry).Return(expectedOut).Once()
-	err := repo.InsertShortURL(context.Background(), &mockURL)
+	mockClient.On("Set", mock.Anything, "shortpath", mock.Anything, testCacheTTL).Return(expectedOut).Once()
+	err := repo.InsertShortURL(context.Background(), &mockURL, nil)
 	assert.NoError(t, err)
 	mockClient.AssertExpectations(t)
 }
 
+func TestRedisInsertShortURL_Expired(t *testing.T) {
+	mockClient, repo := setupRedisRepository()
+	expired := time.Now().Add(-time.Minute)
+	mockURL := models.URL{OriginalURL: "https://example.com", ShortPath: "shortpath", Expiry: &expired}
+	err := repo.InsertShortURL(context.Background(), &mockURL, nil)
+	assert.NoError(t, err)
+	mockClient.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestRedisInsertShortURL_Error(t *testing.T) {
 	mockClient, repo := setupRedisRepository()
 	mockURL := models.URL{OriginalURL: "https://example.com", ShortPath: "shortpath"}
 	expectedOut := &redis.StatusCmd{}
 	expectedOut.SetVal("")
 	expectedOut.SetErr(errors.New("redis error"))
-	mockClient.On("Set", mock.Anything, "shortpath", mock.Anything, repo.cacheExpiry).Return(expectedOut).Once()
-	err := repo.InsertShortURL(context.Background(), &mockURL)
+	mockClient.On("Set", mock.Anything, "shortpath", mock.Anything, testCacheTTL).Return(expectedOut).Once()
+	err := repo.InsertShortURL(context.Background(), &mockURL, nil)
 	assert.Error(t, err)
 	mockClient.AssertExpectations(t)
 }
@@ -95,7 +128,7 @@ func TestRedisDeleteShortURL_Success(t *testing.T) {
 	expectedOut.SetVal(0)
 	expectedOut.SetErr(nil)
 	mockClient.On("Del", mock.Anything, "shortpath").Return(expectedOut).Once()
-	err := repo.DeleteShortURL(context.Background(), "shortpath", time.Now(), "testuser")
+	err := repo.DeleteShortURL(context.Background(), "shortpath", time.Now(), "testuser", nil)
 	assert.NoError(t, err)
 	mockClient.AssertExpectations(t)
 }
@@ -106,7 +139,41 @@ func TestRedisDeleteShortURL_Error(t *testing.T) {
 	expectedOut.SetVal(0)
 	expectedOut.SetErr(errors.New("redis error"))
 	mockClient.On("Del", mock.Anything, "shortpath").Return(expectedOut).Once()
-	err := repo.DeleteShortURL(context.Background(), "shortpath", time.Now(), "testuser")
+	err := repo.DeleteShortURL(context.Background(), "shortpath", time.Now(), "testuser", nil)
+	assert.Error(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisInsertShortURLs_SkipsExpired(t *testing.T) {
+	mockClient, repo := setupRedisRepository()
+	expired := time.Now().Add(-time.Minute)
+	soon := time.Now().Add(time.Minute)
+	urls := []*models.URL{
+		{OriginalURL: "https://example.com/1", ShortPath: "path1"},
+		{OriginalURL: "https://example.com/2", ShortPath: "path2", Expiry: &expired},
+		{OriginalURL: "https://example.com/3", ShortPath: "path3", Expiry: &soon},
+	}
+	pipe := redis.NewClient(&redis.Options{}).Pipeline()
+	mockClient.On("Pipelined", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(redis.Pipeliner) error)
+		assert.NoError(t, fn(pipe))
+	}).Return(nil, nil).Once()
+
+	inserted, err := repo.InsertShortURLs(context.Background(), urls)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, inserted)
+	assert.Equal(t, 2, pipe.Len())
+	mockClient.AssertExpectations(t)
+}
+
+func TestRedisInsertShortURLs_Error(t *testing.T) {
+	mockClient, repo := setupRedisRepository()
+	mockClient.On("Pipelined", mock.Anything, mock.Anything).Return(nil, errors.New("redis error")).Once()
+
+	inserted, err := repo.InsertShortURLs(context.Background(), []*models.URL{{ShortPath: "path1"}})
+
 	assert.Error(t, err)
+	assert.Equal(t, 0, inserted)
 	mockClient.AssertExpectations(t)
 }
@@ -13,6 +13,9 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+// testActor is passed on to Postgres, which audits the change, and never to the cache.
+var testActor = &models.AuditActor{Actor: "testuser", RequestID: "req-1"}
+
 func setupRepository() (*mocks.URLRepository, *mocks.URLRepository, *utilMocks.TimeProvider, URLRepository) {
 	redisRepo := mocks.URLRepository{}
 	postgresRepo := mocks.URLRepository{}
@@ -25,7 +28,7 @@ func TestGetShortURL_Success(t *testing.T) {
 	redisRepo, postgresRepo, _, repo := setupRepository()
 	mockURL := &models.URL{OriginalURL: "https://example.com", ShortPath: "shortpath"}
 	postgresRepo.On("GetShortURL", mock.Anything, "https://example.com").Return(mockURL, nil).Once()
-	redisRepo.On("InsertShortURL", mock.Anything, mockURL).Return(nil).Once()
+	redisRepo.On("InsertShortURL", mock.Anything, mockURL, (*models.AuditActor)(nil)).Return(nil).Once()
 	url, err := repo.GetShortURL(context.Background(), "https://example.com")
 
 	assert.NoError(t, err)
@@ -61,7 +64,7 @@ func TestGetOriginalURL_SuccessPostgres(t *testing.T) {
 	mockURL := &models.URL{OriginalURL: "https://example.com", ShortPath: "shortpath"}
 	redisRepo.On("GetOriginalURL", mock.Anything, "shortpath").Return(nil, ErrCacheMiss).Once()
 	postgresRepo.On("GetOriginalURL", mock.Anything, "shortpath").Return(mockURL, nil).Once()
-	redisRepo.On("InsertShortURL", mock.Anything, mockURL).Return(nil).Once()
+	redisRepo.On("InsertShortURL", mock.Anything, mockURL, (*models.AuditActor)(nil)).Return(nil).Once()
 	url, err := repo.GetOriginalURL(context.Background(), "shortpath")
 
 	assert.NoError(t, err)
@@ -97,10 +100,10 @@ func TestUpdateShortURL_Success(t *testing.T) {
 	redisRepo, postgresRepo, timeProvider, repo := setupRepository()
 	currTime := time.Now()
 	mockURL := &models.URL{OriginalURL: "https://example.com", ShortPath: "shortpath", Expiry: &currTime}
-	postgresRepo.On("UpdateShortURL", mock.Anything, mockURL).Return(nil).Once()
-	redisRepo.On("DeleteShortURL", mock.Anything, "shortpath", mock.Anything, "system").Return(nil).Once()
+	postgresRepo.On("UpdateShortURL", mock.Anything, mockURL, testActor).Return(nil).Once()
+	redisRepo.On("DeleteShortURL", mock.Anything, "shortpath", mock.Anything, "system", (*models.AuditActor)(nil)).Return(nil).Once()
 	timeProvider.On("Now").Return(currTime).Once()
-	err := repo.UpdateShortURL(context.Background(), mockURL)
+	err := repo.UpdateShortURL(context.Background(), mockURL, testActor)
 
 	assert.NoError(t, err)
 	postgresRepo.AssertExpectations(t)
@@ -108,33 +111,56 @@ func TestUpdateShortURL_Success(t *testing.T) {
 }
 
 func TestUpdateShortURL_Error(t *testing.T) {
-	redisRepo, postgresRepo, timeProvider, repo := setupRepository()
+	redisRepo, postgresRepo, _, repo := setupRepository()
 	mockURL := &models.URL{OriginalURL: "https://example.com", ShortPath: "shortpath"}
-	postgresRepo.On("UpdateShortURL", mock.Anything, mockURL).Return(assert.AnError).Once()
+	postgresRepo.On("UpdateShortURL", mock.Anything, mockURL, testActor).Return(assert.AnError).Once()
 
-	timeProvider.On("Now").Return(time.Now()).Once()
-	redisRepo.On("DeleteShortURL", mock.Anything, "shortpath", mock.Anything, "system").Return(nil).Once()
-	err := repo.UpdateShortURL(context.Background(), mockURL)
+	err := repo.UpdateShortURL(context.Background(), mockURL, testActor)
 
 	assert.Error(t, err)
 	postgresRepo.AssertExpectations(t)
+	redisRepo.AssertNotCalled(t, "DeleteShortURL")
+}
+
+func TestUpdateShortURL_CacheErrorIgnored(t *testing.T) {
+	redisRepo, postgresRepo, timeProvider, repo := setupRepository()
+	mockURL := &models.URL{OriginalURL: "https://example.com", ShortPath: "shortpath"}
+	postgresRepo.On("UpdateShortURL", mock.Anything, mockURL, testActor).Return(nil).Once()
+	timeProvider.On("Now").Return(time.Now()).Once()
+	redisRepo.On("DeleteShortURL", mock.Anything, "shortpath", mock.Anything, "system", (*models.AuditActor)(nil)).Return(assert.AnError).Once()
+
+	err := repo.UpdateShortURL(context.Background(), mockURL, testActor)
+
+	assert.NoError(t, err)
+	redisRepo.AssertExpectations(t)
 }
 
 func TestDeleteShortURL_Success(t *testing.T) {
 	redisRepo, postgresRepo, _, repo := setupRepository()
-	postgresRepo.On("DeleteShortURL", mock.Anything, "shortpath", mock.Anything, "testuser").Return(nil).Once()
-	redisRepo.On("DeleteShortURL", mock.Anything, "shortpath", mock.Anything, "testuser").Return(nil).Once()
-	err := repo.DeleteShortURL(context.Background(), "shortpath", time.Now(), "testuser")
+	postgresRepo.On("DeleteShortURL", mock.Anything, "shortpath", mock.Anything, "testuser", testActor).Return(nil).Once()
+	redisRepo.On("DeleteShortURL", mock.Anything, "shortpath", mock.Anything, "testuser", (*models.AuditActor)(nil)).Return(nil).Once()
+	err := repo.DeleteShortURL(context.Background(), "shortpath", time.Now(), "testuser", testActor)
 
 	assert.NoError(t, err)
 	postgresRepo.AssertExpectations(t)
 	redisRepo.AssertExpectations(t)
 }
 
+func TestDeleteShortURL_CacheErrorIgnored(t *testing.T) {
+	redisRepo, postgresRepo, _, repo := setupRepository()
+	postgresRepo.On("DeleteShortURL", mock.Anything, "shortpath", mock.Anything, "testuser", testActor).Return(nil).Once()
+	redisRepo.On("DeleteShortURL", mock.Anything, "shortpath", mock.Anything, "testuser", (*models.AuditActor)(nil)).Return(assert.AnError).Once()
+
+	err := repo.DeleteShortURL(context.Background(), "shortpath", time.Now(), "testuser", testActor)
+
+	assert.NoError(t, err)
+	redisRepo.AssertExpectations(t)
+}
+
 func TestDeleteShortURL_Error(t *testing.T) {
 	_, postgresRepo, _, repo := setupRepository()
-	postgresRepo.On("DeleteShortURL", mock.Anything, "shortpath", mock.Anything, "testuser").Return(assert.AnError).Once()
-	err := repo.DeleteShortURL(context.Background(), "shortpath", time.Now(), "testuser")
+	postgresRepo.On("DeleteShortURL", mock.Anything, "shortpath", mock.Anything, "testuser", testActor).Return(assert.AnError).Once()
+	err := repo.DeleteShortURL(context.Background(), "shortpath", time.Now(), "testuser", testActor)
 
 	assert.Error(t, err)
 	postgresRepo.AssertExpectations(t)
@@ -143,8 +169,8 @@ func TestDeleteShortURL_Error(t *testing.T) {
 func TestInsertShortURL_Success(t *testing.T) {
 	_, postgresRepo, _, repo := setupRepository()
 	mockURL := &models.URL{OriginalURL: "https://example.com", ShortPath: "shortpath"}
-	postgresRepo.On("InsertShortURL", mock.Anything, mockURL).Return(nil).Once()
-	err := repo.InsertShortURL(context.Background(), mockURL)
+	postgresRepo.On("InsertShortURL", mock.Anything, mockURL, testActor).Return(nil).Once()
+	err := repo.InsertShortURL(context.Background(), mockURL, testActor)
 
 	assert.NoError(t, err)
 	postgresRepo.AssertExpectations(t)
@@ -153,8 +179,8 @@ func TestInsertShortURL_Success(t *testing.T) {
 func TestInsertShortURL_Error(t *testing.T) {
 	_, postgresRepo, _, repo := setupRepository()
 	mockURL := &models.URL{OriginalURL: "https://example.com", ShortPath: "shortpath"}
-	postgresRepo.On("InsertShortURL", mock.Anything, mockURL).Return(assert.AnError).Once()
-	err := repo.InsertShortURL(context.Background(), mockURL)
+	postgresRepo.On("InsertShortURL", mock.Anything, mockURL, testActor).Return(assert.AnError).Once()
+	err := repo.InsertShortURL(context.Background(), mockURL, testActor)
 
 	assert.Error(t, err)
 	postgresRepo.AssertExpectations(t)
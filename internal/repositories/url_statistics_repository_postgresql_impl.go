@@ -3,21 +3,38 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
+	"url-shortener/internal/db"
 	"url-shortener/internal/models"
 )
 
+// statisticsBreakdownLimit is how many values of each dimension GetURLStatistics returns.
+const statisticsBreakdownLimit = 10
+
+// accessLogColumns is how many bind parameters an access log takes in PG_INSERT_ACCESS_LOGS. A statement takes at
+// most 65535, which caps the rows per insert at maxAccessLogsPerInsert.
+const (
+	accessLogColumns       = 15
+	maxAccessLogsPerInsert = 65535 / accessLogColumns
+)
+
 type urlStatisticsRepositoryPostgresqlImpl struct {
-	db *sql.DB
+	cluster         *db.PostgresCluster
+	breakdownWindow time.Duration
 }
 
-func NewURLStatisticsRepositoryPostgresql(db *sql.DB) URLStatisticsRepository {
-	return &urlStatisticsRepositoryPostgresqlImpl{db: db}
+// NewURLStatisticsRepositoryPostgresql computes the breakdowns from the raw clicks of the last breakdownWindow, zero
+// leaves them empty.
+func NewURLStatisticsRepositoryPostgresql(cluster *db.PostgresCluster, breakdownWindow time.Duration) URLStatisticsRepository {
+	return &urlStatisticsRepositoryPostgresqlImpl{cluster: cluster, breakdownWindow: breakdownWindow}
 }
 
-func (r *urlStatisticsRepositoryPostgresqlImpl) GetURLStatistics(ctx context.Context, shortPath string) (*models.URLStatistics, error) {
-	row := r.db.QueryRowContext(ctx, PG_GET_URL_STATISTICS, shortPath)
+func (r *urlStatisticsRepositoryPostgresqlImpl) GetURLStatistics(ctx context.Context, shortPath string, includeBots bool) (*models.URLStatistics, error) {
+	row := r.cluster.Reader(ctx).QueryRowContext(ctx, PG_GET_URL_STATISTICS, shortPath, includeBots)
 	var statistics models.URLStatistics
 	err := row.Scan(&statistics.Last24Hours, &statistics.PastWeek, &statistics.AllTime)
 	if err != nil {
@@ -27,10 +44,184 @@ func (r *urlStatisticsRepositoryPostgresqlImpl) GetURLStatistics(ctx context.Con
 		return nil, ErrInternalServerError
 	}
 	statistics.ShortPath = shortPath
+	if err := r.addBreakdowns(ctx, &statistics, includeBots); err != nil {
+		return nil, err
+	}
 	return &statistics, nil
 }
 
-func (r *urlStatisticsRepositoryPostgresqlImpl) InsertAccessLog(ctx context.Context, shortPath string, accessedAt time.Time) error {
-	_, err := r.db.ExecContext(ctx, PG_INSERT_ACCESS_LOG, shortPath, accessedAt)
-	return err
+func (r *urlStatisticsRepositoryPostgresqlImpl) addBreakdowns(ctx context.Context, statistics *models.URLStatistics, includeBots bool) error {
+	breakdowns := map[string]*[]models.BreakdownEntry{
+		"referrer": &statistics.Referrers,
+		"browser":  &statistics.Browsers,
+		"os":       &statistics.OperatingSystems,
+		"device":   &statistics.Devices,
+		"language": &statistics.Languages,
+		"country":  &statistics.Countries,
+		"region":   &statistics.Regions,
+		"city":     &statistics.Cities,
+	}
+	for _, breakdown := range breakdowns {
+		*breakdown = []models.BreakdownEntry{}
+	}
+	if r.breakdownWindow <= 0 {
+		return nil
+	}
+
+	rows, err := r.cluster.Reader(ctx).QueryContext(ctx, PG_GET_URL_STATISTICS_BREAKDOWN, statistics.ShortPath, statisticsBreakdownLimit,
+		includeBots, int64(r.breakdownWindow/time.Second))
+	if err != nil {
+		log.Printf("Error getting statistics breakdown of %s: %v", statistics.ShortPath, err)
+		return ErrInternalServerError
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dimension string
+		var entry models.BreakdownEntry
+		if err := rows.Scan(&dimension, &entry.Value, &entry.Count); err != nil {
+			log.Printf("Error scanning statistics breakdown of %s: %v", statistics.ShortPath, err)
+			return ErrInternalServerError
+		}
+		if breakdown, ok := breakdowns[dimension]; ok {
+			*breakdown = append(*breakdown, entry)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error reading statistics breakdown of %s: %v", statistics.ShortPath, err)
+		return ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *urlStatisticsRepositoryPostgresqlImpl) GetAccessCounts(ctx context.Context, shortPath string, from time.Time, to time.Time, interval string, timeZone string, includeBots bool) ([]models.TimeseriesBucket, error) {
+	rows, err := r.cluster.Reader(ctx).QueryContext(ctx, PG_GET_URL_TIMESERIES, shortPath, from.UTC(), to.UTC(), interval, timeZone, includeBots)
+	if err != nil {
+		log.Printf("Error getting access counts of %s: %v", shortPath, err)
+		return nil, ErrInternalServerError
+	}
+	defer rows.Close()
+
+	buckets := []models.TimeseriesBucket{}
+	for rows.Next() {
+		var bucket models.TimeseriesBucket
+		if err := rows.Scan(&bucket.Start, &bucket.Count); err != nil {
+			log.Printf("Error scanning access counts of %s: %v", shortPath, err)
+			return nil, ErrInternalServerError
+		}
+		buckets = append(buckets, bucket)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error reading access counts of %s: %v", shortPath, err)
+		return nil, ErrInternalServerError
+	}
+	return buckets, nil
+}
+
+func (r *urlStatisticsRepositoryPostgresqlImpl) GetTopLinks(ctx context.Context, since time.Time, limit int) ([]models.LinkClicks, error) {
+	rows, err := r.cluster.Reader(ctx).QueryContext(ctx, PG_GET_TOP_LINKS, since.UTC(), limit)
+	if err != nil {
+		log.Printf("Error getting top links since %s: %v", since, err)
+		return nil, ErrInternalServerError
+	}
+	defer rows.Close()
+
+	links := []models.LinkClicks{}
+	for rows.Next() {
+		var link models.LinkClicks
+		if err := rows.Scan(&link.ShortPath, &link.OriginalURL, &link.Clicks); err != nil {
+			log.Printf("Error scanning top links: %v", err)
+			return nil, ErrInternalServerError
+		}
+		links = append(links, link)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error reading top links: %v", err)
+		return nil, ErrInternalServerError
+	}
+	return links, nil
+}
+
+func (r *urlStatisticsRepositoryPostgresqlImpl) GetDomainClicks(ctx context.Context, since time.Time, limit int) ([]models.DomainClicks, error) {
+	rows, err := r.cluster.Reader(ctx).QueryContext(ctx, PG_GET_DOMAIN_CLICKS, since.UTC(), limit)
+	if err != nil {
+		log.Printf("Error getting domain clicks since %s: %v", since, err)
+		return nil, ErrInternalServerError
+	}
+	defer rows.Close()
+
+	domains := []models.DomainClicks{}
+	for rows.Next() {
+		var domain models.DomainClicks
+		if err := rows.Scan(&domain.Domain, &domain.Links, &domain.Clicks); err != nil {
+			log.Printf("Error scanning domain clicks: %v", err)
+			return nil, ErrInternalServerError
+		}
+		domains = append(domains, domain)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error reading domain clicks: %v", err)
+		return nil, ErrInternalServerError
+	}
+	return domains, nil
+}
+
+// InsertAccessLogs writes all logs with a single multi-row insert, or with one per maxAccessLogsPerInsert logs.
+func (r *urlStatisticsRepositoryPostgresqlImpl) InsertAccessLogs(ctx context.Context, logs []*models.AccessLog) error {
+	if len(logs) <= maxAccessLogsPerInsert {
+		if len(logs) == 0 {
+			return nil
+		}
+		query, args := accessLogsInsert(logs)
+		if _, err := r.cluster.Primary().ExecContext(ctx, query, args...); err != nil {
+			log.Printf("Error inserting %d access logs: %v", len(logs), err)
+			return ErrDBError
+		}
+		return nil
+	}
+
+	// Larger batches are split, in one transaction so a retried batch is not inserted twice.
+	tx, err := r.cluster.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return ErrDBError
+	}
+	defer tx.Rollback()
+	for start := 0; start < len(logs); start += maxAccessLogsPerInsert {
+		query, args := accessLogsInsert(logs[start:min(start+maxAccessLogsPerInsert, len(logs))])
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			log.Printf("Error inserting %d access logs: %v", len(logs), err)
+			return ErrDBError
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing %d access logs: %v", len(logs), err)
+		return ErrDBError
+	}
+	return nil
+}
+
+// accessLogsInsert returns the multi-row insert of logs and its arguments.
+func accessLogsInsert(logs []*models.AccessLog) (string, []interface{}) {
+	query := strings.Builder{}
+	query.WriteString(PG_INSERT_ACCESS_LOGS)
+	args := make([]interface{}, 0, len(logs)*accessLogColumns)
+	for i, accessLog := range logs {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString("(")
+		for column := 1; column <= accessLogColumns; column++ {
+			if column > 1 {
+				query.WriteString(", ")
+			}
+			fmt.Fprintf(&query, "$%d", i*accessLogColumns+column)
+		}
+		query.WriteString(")")
+		args = append(args, accessLog.ShortPath, accessLog.AccessedAt.UTC(), accessLog.ReferrerHost, accessLog.Browser, accessLog.OS,
+			accessLog.DeviceClass, accessLog.ClientIP, accessLog.Language, accessLog.QueryString, accessLog.Country, accessLog.Region, accessLog.City, accessLog.IsBot,
+			// Clicks without an ID are left out of the partial click_id index.
+			sql.NullString{String: accessLog.ClickID, Valid: accessLog.ClickID != ""}, accessLog.IsDuplicate)
+	}
+	return query.String(), args
 }
@@ -2,9 +2,11 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"testing"
 	"time"
+	"url-shortener/internal/models"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -15,16 +17,26 @@ func TestURLStatisticsRepositoryPostgresqlImpl_GetURLStatistics(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := NewURLStatisticsRepositoryPostgresql(db)
+	repo := NewURLStatisticsRepositoryPostgresql(newTestCluster(db), 30*24*time.Hour)
 	ctx := context.Background()
 	shortPath := "shortPath"
 
 	rows := sqlmock.NewRows([]string{"last_24_hours", "past_week", "all_time"}).
 		AddRow(10, 100, 1000)
 
-	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FILTER \\(WHERE accessed_at >= NOW\\(\\) - INTERVAL '24 hours'\\) AS last_24_hours, COUNT\\(\\*\\) FILTER \\(WHERE accessed_at >= NOW\\(\\) - INTERVAL '7 days'\\) AS past_week, COUNT\\(\\*\\) AS all_time FROM url_access_logs WHERE short_path = \\$1;").WithArgs(shortPath).WillReturnRows(rows)
+	mock.ExpectQuery("WITH bounds AS .* FROM raw, hourly, daily").WithArgs(shortPath, false).WillReturnRows(rows)
+	breakdownRows := sqlmock.NewRows([]string{"dimension", "value", "clicks"}).
+		AddRow("browser", "Chrome", 700).
+		AddRow("browser", "Firefox", 300).
+		AddRow("country", "DE", 1000).
+		AddRow("device", "mobile", 1000).
+		AddRow("language", "en-us", 1000).
+		AddRow("os", "Android", 1000).
+		AddRow("referrer", "direct", 600).
+		AddRow("referrer", "news.example.org", 400)
+	mock.ExpectQuery("SELECT dimension, value, clicks FROM").WithArgs(shortPath, statisticsBreakdownLimit, false, int64(30*24*60*60)).WillReturnRows(breakdownRows)
 
-	stats, err := repo.GetURLStatistics(ctx, shortPath)
+	stats, err := repo.GetURLStatistics(ctx, shortPath, false)
 
 	assert.Nil(t, err)
 	assert.NotNil(t, stats)
@@ -32,55 +44,213 @@ func TestURLStatisticsRepositoryPostgresqlImpl_GetURLStatistics(t *testing.T) {
 	assert.Equal(t, int64(10), stats.Last24Hours)
 	assert.Equal(t, int64(100), stats.PastWeek)
 	assert.Equal(t, int64(1000), stats.AllTime)
+	assert.Equal(t, []models.BreakdownEntry{{Value: "Chrome", Count: 700}, {Value: "Firefox", Count: 300}}, stats.Browsers)
+	assert.Equal(t, []models.BreakdownEntry{{Value: "mobile", Count: 1000}}, stats.Devices)
+	assert.Equal(t, []models.BreakdownEntry{{Value: "DE", Count: 1000}}, stats.Countries)
+	assert.Empty(t, stats.Cities)
+	assert.Equal(t, []models.BreakdownEntry{{Value: "en-us", Count: 1000}}, stats.Languages)
+	assert.Equal(t, []models.BreakdownEntry{{Value: "Android", Count: 1000}}, stats.OperatingSystems)
+	assert.Equal(t, []models.BreakdownEntry{{Value: "direct", Count: 600}, {Value: "news.example.org", Count: 400}}, stats.Referrers)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestURLStatisticsRepositoryPostgresqlImpl_GetURLStatistics_Error(t *testing.T) {
+func TestURLStatisticsRepositoryPostgresqlImpl_GetURLStatistics_NoBreakdownWindow(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// Without a window the raw clicks are not scanned for breakdowns.
+	repo := NewURLStatisticsRepositoryPostgresql(newTestCluster(db), 0)
+	mock.ExpectQuery("WITH bounds AS").WithArgs("shortPath", false).WillReturnRows(sqlmock.NewRows([]string{"last_24_hours", "past_week", "all_time"}).AddRow(1, 1, 1))
+
+	stats, err := repo.GetURLStatistics(context.Background(), "shortPath", false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), stats.AllTime)
+	assert.Equal(t, []models.BreakdownEntry{}, stats.Browsers)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestURLStatisticsRepositoryPostgresqlImpl_GetURLStatistics_BreakdownError(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := NewURLStatisticsRepositoryPostgresql(db)
+	repo := NewURLStatisticsRepositoryPostgresql(newTestCluster(db), 30*24*time.Hour)
 	ctx := context.Background()
 	shortPath := "shortPath"
 
-	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FILTER \\(WHERE accessed_at >= NOW\\(\\) - INTERVAL '24 hours'\\) AS last_24_hours, COUNT\\(\\*\\) FILTER \\(WHERE accessed_at >= NOW\\(\\) - INTERVAL '7 days'\\) AS past_week, COUNT\\(\\*\\) AS all_time FROM url_access_logs WHERE short_path = \\$1;").WithArgs(shortPath).WillReturnError(fmt.Errorf("some error"))
+	mock.ExpectQuery("WITH bounds AS").WithArgs(shortPath, false).WillReturnRows(sqlmock.NewRows([]string{"last_24_hours", "past_week", "all_time"}).AddRow(1, 1, 1))
+	mock.ExpectQuery("SELECT dimension, value, clicks FROM").WithArgs(shortPath, statisticsBreakdownLimit, false, int64(30*24*60*60)).WillReturnError(fmt.Errorf("some error"))
 
-	stats, err := repo.GetURLStatistics(ctx, shortPath)
+	stats, err := repo.GetURLStatistics(ctx, shortPath, false)
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrInternalServerError)
 	assert.Nil(t, stats)
 }
 
-func TestURLStatisticsRepositoryPostgresqlImpl_InsertAccessLog(t *testing.T) {
+func TestURLStatisticsRepositoryPostgresqlImpl_GetURLStatistics_Error(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := NewURLStatisticsRepositoryPostgresql(db)
+	repo := NewURLStatisticsRepositoryPostgresql(newTestCluster(db), 30*24*time.Hour)
 	ctx := context.Background()
 	shortPath := "shortPath"
-	accessedAt := time.Now()
 
-	mock.ExpectExec("INSERT INTO url_access_logs \\(short_path,accessed_at\\) VALUES \\(\\$1,\\$2\\);").WithArgs(shortPath, accessedAt).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("WITH bounds AS .* FROM raw, hourly, daily").WithArgs(shortPath, false).WillReturnError(fmt.Errorf("some error"))
+
+	stats, err := repo.GetURLStatistics(ctx, shortPath, false)
+
+	assert.Error(t, err)
+	assert.Nil(t, stats)
+}
+
+func TestURLStatisticsRepositoryPostgresqlImpl_InsertAccessLogs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLStatisticsRepositoryPostgresql(newTestCluster(db), 30*24*time.Hour)
+	first := time.Now()
+	second := first.Add(time.Second)
+	logs := []*models.AccessLog{
+		{ShortPath: "path1", AccessedAt: first, ReferrerHost: "example.org", Browser: "Firefox", OS: "Linux", DeviceClass: "desktop", ClientIP: "203.0.113.7", Language: "en-us", QueryString: "a=1",
+			Country: "DE", Region: "DE-BY", City: "Munich"},
+		{ShortPath: "path2", AccessedAt: second, IsBot: true, ClickID: "clk2", IsDuplicate: true},
+	}
+
+	mock.ExpectExec("INSERT INTO url_access_logs \\(short_path, accessed_at, referrer_host, browser, os, device_class, client_ip, language, query_string, country, region, city, is_bot, click_id, is_duplicate\\) "+
+		"VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, \\$6, \\$7, \\$8, \\$9, \\$10, \\$11, \\$12, \\$13, \\$14, \\$15\\), \\(\\$16, \\$17, .*\\$30\\)").
+		WithArgs("path1", first.UTC(), "example.org", "Firefox", "Linux", "desktop", "203.0.113.7", "en-us", "a=1", "DE", "DE-BY", "Munich", false, sql.NullString{}, false,
+			"path2", second.UTC(), "", "", "", "", "", "", "", "", "", "", true, sql.NullString{String: "clk2", Valid: true}, true).
+		WillReturnResult(sqlmock.NewResult(0, 2))
 
-	err = repo.InsertAccessLog(ctx, shortPath, accessedAt)
+	err = repo.InsertAccessLogs(context.Background(), logs)
 
 	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestURLStatisticsRepositoryPostgresqlImpl_InsertAccessLog_Error(t *testing.T) {
+func TestURLStatisticsRepositoryPostgresqlImpl_InsertAccessLogs_Error(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
-	repo := NewURLStatisticsRepositoryPostgresql(db)
-	ctx := context.Background()
-	shortPath := "shortPath"
-	accessedAt := time.Now()
+	repo := NewURLStatisticsRepositoryPostgresql(newTestCluster(db), 30*24*time.Hour)
+	mock.ExpectExec("INSERT INTO url_access_logs").WillReturnError(fmt.Errorf("some error"))
 
-	mock.ExpectExec("INSERT INTO url_access_logs \\(short_path,accessed_at\\) VALUES \\(\\$1,\\$2\\);").WithArgs(shortPath, accessedAt).WillReturnError(fmt.Errorf("some error"))
+	err = repo.InsertAccessLogs(context.Background(), []*models.AccessLog{{ShortPath: "path1", AccessedAt: time.Now()}})
 
-	err = repo.InsertAccessLog(ctx, shortPath, accessedAt)
+	assert.ErrorIs(t, err, ErrDBError)
+}
 
-	assert.Error(t, err)
+func TestURLStatisticsRepositoryPostgresqlImpl_InsertAccessLogs_SplitsLargeBatches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLStatisticsRepositoryPostgresql(newTestCluster(db), 30*24*time.Hour)
+	logs := make([]*models.AccessLog, maxAccessLogsPerInsert+1)
+	for i := range logs {
+		logs[i] = &models.AccessLog{ShortPath: "path1", AccessedAt: time.Now()}
+	}
+
+	// A batch over the bind parameter limit is split, the last row goes in its own insert.
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO url_access_logs .*\\$65535\\)$").WillReturnResult(sqlmock.NewResult(0, maxAccessLogsPerInsert))
+	mock.ExpectExec("INSERT INTO url_access_logs .* VALUES \\(\\$1, .*\\$15\\)$").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.InsertAccessLogs(context.Background(), logs)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestURLStatisticsRepositoryPostgresqlImpl_GetAccessCounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLStatisticsRepositoryPostgresql(newTestCluster(db), 30*24*time.Hour)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	from := time.Date(2025, 6, 9, 0, 0, 0, 0, berlin)
+	to := time.Date(2025, 6, 11, 0, 0, 0, 0, berlin)
+	bucket := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT date_trunc\\(\\$4, accessed_at AT TIME ZONE 'UTC' AT TIME ZONE \\$5\\) AS bucket").
+		WithArgs("shortPath", from.UTC(), to.UTC(), "day", "Europe/Berlin", true).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "clicks"}).AddRow(bucket, 4))
+
+	buckets, err := repo.GetAccessCounts(context.Background(), "shortPath", from, to, "day", "Europe/Berlin", true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TimeseriesBucket{{Start: bucket, Count: 4}}, buckets)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestURLStatisticsRepositoryPostgresqlImpl_GetAccessCounts_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLStatisticsRepositoryPostgresql(newTestCluster(db), 30*24*time.Hour)
+	mock.ExpectQuery("SELECT date_trunc").WillReturnError(fmt.Errorf("some error"))
+
+	_, err = repo.GetAccessCounts(context.Background(), "shortPath", time.Now().Add(-time.Hour), time.Now(), "hour", "UTC", false)
+
+	assert.ErrorIs(t, err, ErrInternalServerError)
+}
+
+func TestURLStatisticsRepositoryPostgresqlImpl_GetTopLinks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLStatisticsRepositoryPostgresql(newTestCluster(db), 30*24*time.Hour)
+	since := time.Date(2025, 6, 11, 10, 30, 0, 0, time.UTC)
+	mock.ExpectQuery("WITH bounds AS .* SELECT u.short_path, u.original_url, c.clicks FROM clicks c").WithArgs(since, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"short_path", "original_url", "clicks"}).
+			AddRow("path1", "https://www.example.com", 42).
+			AddRow("path2", "https://www.example.org", 7))
+
+	links, err := repo.GetTopLinks(context.Background(), since, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.LinkClicks{
+		{ShortPath: "path1", OriginalURL: "https://www.example.com", Clicks: 42},
+		{ShortPath: "path2", OriginalURL: "https://www.example.org", Clicks: 7},
+	}, links)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestURLStatisticsRepositoryPostgresqlImpl_GetTopLinks_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLStatisticsRepositoryPostgresql(newTestCluster(db), 30*24*time.Hour)
+	mock.ExpectQuery("WITH bounds AS").WillReturnError(fmt.Errorf("some error"))
+
+	_, err = repo.GetTopLinks(context.Background(), time.Now(), 10)
+
+	assert.ErrorIs(t, err, ErrInternalServerError)
+}
+
+func TestURLStatisticsRepositoryPostgresqlImpl_GetDomainClicks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewURLStatisticsRepositoryPostgresql(newTestCluster(db), 30*24*time.Hour)
+	mock.ExpectQuery("WITH bounds AS .* SELECT u.domain, COUNT\\(\\*\\) AS links").WithArgs(time.Time{}, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"domain", "links", "clicks"}).AddRow("example.com", 3, 10).AddRow("example.org", 1, 0))
+
+	domains, err := repo.GetDomainClicks(context.Background(), time.Time{}, 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.DomainClicks{{Domain: "example.com", Links: 3, Clicks: 10}, {Domain: "example.org", Links: 1, Clicks: 0}}, domains)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -8,6 +8,15 @@ import (
 
 //go:generate mockery --name=URLStatisticsRepository --output=./mocks
 type URLStatisticsRepository interface {
-	GetURLStatistics(ctx context.Context, shortPath string) (*models.URLStatistics, error)
-	InsertAccessLog(ctx context.Context, shortPath string, accessedAt time.Time) error
+	// GetURLStatistics counts bot clicks only when includeBots is set, as does GetAccessCounts.
+	GetURLStatistics(ctx context.Context, shortPath string, includeBots bool) (*models.URLStatistics, error)
+	// GetAccessCounts returns the non-empty buckets between from and to. Bucket starts are wall clock times in timeZone
+	// carried in a UTC time.Time.
+	GetAccessCounts(ctx context.Context, shortPath string, from time.Time, to time.Time, interval string, timeZone string, includeBots bool) ([]models.TimeseriesBucket, error)
+	// GetTopLinks returns the limit links with the most clicks since since, bots excluded.
+	GetTopLinks(ctx context.Context, since time.Time, limit int) ([]models.LinkClicks, error)
+	// GetDomainClicks returns the limit destination hosts with the most clicks since since, bots excluded, with the
+	// number of links pointing at each.
+	GetDomainClicks(ctx context.Context, since time.Time, limit int) ([]models.DomainClicks, error)
+	InsertAccessLogs(ctx context.Context, logs []*models.AccessLog) error
 }
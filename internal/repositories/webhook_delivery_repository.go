@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=WebhookDeliveryRepository --output=./mocks
+type WebhookDeliveryRepository interface {
+	// EnqueueDeliveries creates a pending delivery of up to limit new outbox events for every active subscription of
+	// their type, due at now, and moves the webhooks offset past them. It returns how many events were read.
+	EnqueueDeliveries(ctx context.Context, now time.Time, limit int) (int, error)
+	// ClaimDeliveries counts an attempt for up to limit deliveries due at now and holds them until leaseUntil, so no
+	// other instance sends them in the meantime.
+	ClaimDeliveries(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]models.WebhookDispatch, error)
+	// CompleteDelivery stores the status, next attempt, last status code, last error and delivery time of an attempt.
+	CompleteDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
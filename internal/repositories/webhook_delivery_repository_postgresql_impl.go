@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// webhooksOutboxConsumer is the outbox offset the webhook fan-out reads from.
+const webhooksOutboxConsumer = "webhooks"
+
+type webhookDeliveryRepositoryPostgresqlImpl struct {
+	cluster *db.PostgresCluster
+}
+
+func NewWebhookDeliveryRepositoryPostgresql(cluster *db.PostgresCluster) WebhookDeliveryRepository {
+	return &webhookDeliveryRepositoryPostgresqlImpl{cluster: cluster}
+}
+
+// EnqueueDeliveries implements WebhookDeliveryRepository. The offset row is locked for the whole transaction, so
+// instances enqueueing at the same time wait for each other and the deliveries and the offset move together.
+func (r *webhookDeliveryRepositoryPostgresqlImpl) EnqueueDeliveries(ctx context.Context, now time.Time, limit int) (int, error) {
+	tx, err := r.cluster.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Error starting transaction to enqueue webhook deliveries: %v", err)
+		return 0, ErrDBError
+	}
+	defer tx.Rollback()
+
+	events, position, err := readOutbox(ctx, tx, webhooksOutboxConsumer, limit)
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+	ids := make([]int64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+
+	if _, err := tx.ExecContext(ctx, PG_INSERT_WEBHOOK_DELIVERIES, pq.Array(ids), now.UTC()); err != nil {
+		log.Printf("Error enqueueing webhook deliveries of %d events: %v", len(ids), err)
+		return 0, ErrDBError
+	}
+	if err := advanceOutbox(ctx, tx, webhooksOutboxConsumer, position); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing webhook deliveries: %v", err)
+		return 0, ErrDBError
+	}
+	return len(ids), nil
+}
+
+// ClaimDeliveries implements WebhookDeliveryRepository.
+func (r *webhookDeliveryRepositoryPostgresqlImpl) ClaimDeliveries(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]models.WebhookDispatch, error) {
+	rows, err := r.cluster.Primary().QueryContext(ctx, PG_CLAIM_WEBHOOK_DELIVERIES, now.UTC(), leaseUntil.UTC(), limit)
+	if err != nil {
+		log.Printf("Error claiming webhook deliveries: %v", err)
+		return nil, ErrDBError
+	}
+	defer rows.Close()
+
+	dispatches := []models.WebhookDispatch{}
+	for rows.Next() {
+		var dispatch models.WebhookDispatch
+		delivery := &dispatch.Delivery
+		if err := rows.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.Attempts, &delivery.CreatedAt,
+			&dispatch.URL, &dispatch.Secret, &dispatch.Event.Type, &dispatch.Event.ShortPath, &dispatch.Event.Payload,
+			&dispatch.Event.CreatedAt); err != nil {
+			log.Printf("Error scanning webhook delivery: %v", err)
+			return nil, ErrDBError
+		}
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.EventType = dispatch.Event.Type
+		dispatch.Event.ID = delivery.EventID
+		dispatches = append(dispatches, dispatch)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error claiming webhook deliveries: %v", err)
+		return nil, ErrDBError
+	}
+	return dispatches, nil
+}
+
+// CompleteDelivery implements WebhookDeliveryRepository.
+func (r *webhookDeliveryRepositoryPostgresqlImpl) CompleteDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	var nextAttemptAt, deliveredAt sql.NullTime
+	if delivery.NextAttemptAt != nil {
+		nextAttemptAt = sql.NullTime{Time: delivery.NextAttemptAt.UTC(), Valid: true}
+	}
+	if delivery.DeliveredAt != nil {
+		deliveredAt = sql.NullTime{Time: delivery.DeliveredAt.UTC(), Valid: true}
+	}
+	var lastStatusCode sql.NullInt64
+	if delivery.LastStatusCode != nil {
+		lastStatusCode = sql.NullInt64{Int64: int64(*delivery.LastStatusCode), Valid: true}
+	}
+	var lastError sql.NullString
+	if delivery.LastError != "" {
+		lastError = sql.NullString{String: delivery.LastError, Valid: true}
+	}
+	if _, err := r.cluster.Primary().ExecContext(ctx, PG_COMPLETE_WEBHOOK_DELIVERY, delivery.ID, delivery.Status,
+		nextAttemptAt, lastStatusCode, lastError, deliveredAt); err != nil {
+		log.Printf("Error completing webhook delivery %d: %v", delivery.ID, err)
+		return ErrDBError
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookDeliveryRepositoryPostgresqlImpl_EnqueueDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookDeliveryRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO outbox_offsets \\(consumer, txid, event_id\\) VALUES \\(\\$1, '0', 0\\) ON CONFLICT").
+		WithArgs("webhooks").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT txid::text, event_id FROM outbox_offsets WHERE consumer = \\$1 FOR UPDATE").WithArgs("webhooks").
+		WillReturnRows(sqlmock.NewRows([]string{"txid", "event_id"}).AddRow("750", 20))
+	mock.ExpectQuery("SELECT id, txid::text, type, short_path, payload, created_at FROM outbox_events WHERE \\(txid, id\\) > \\(\\$1::xid8, \\$2\\) AND txid < pg_snapshot_xmin").
+		WithArgs("750", int64(20), 100).
+		WillReturnRows(outboxEventRows().
+			AddRow(22, "751", models.LinkEventCreated, "abc", []byte(`{"shortPath":"abc"}`), now).
+			AddRow(21, "752", models.LinkEventDeleted, "def", []byte(`{"shortPath":"def"}`), now))
+	mock.ExpectExec("INSERT INTO webhook_deliveries \\(subscription_id, event_id, next_attempt_at, created_at\\)").
+		WithArgs(pq.Array([]int64{22, 21}), now).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("UPDATE outbox_offsets SET txid = \\$2::xid8, event_id = \\$3 WHERE consumer = \\$1").
+		WithArgs("webhooks", "752", int64(21)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	events, err := repo.EnqueueDeliveries(context.Background(), now, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookDeliveryRepositoryPostgresqlImpl_EnqueueDeliveries_NoEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookDeliveryRepositoryPostgresql(newTestCluster(db))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO outbox_offsets").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("FROM outbox_offsets").WillReturnRows(sqlmock.NewRows([]string{"txid", "event_id"}).AddRow("0", 0))
+	mock.ExpectQuery("FROM outbox_events").WillReturnRows(outboxEventRows())
+	mock.ExpectRollback()
+
+	events, err := repo.EnqueueDeliveries(context.Background(), time.Now(), 100)
+
+	assert.NoError(t, err)
+	assert.Zero(t, events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookDeliveryRepositoryPostgresqlImpl_ClaimDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookDeliveryRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	payload := `{"shortPath":"abc","originalUrl":"https://example.com"}`
+
+	mock.ExpectQuery("WITH due AS \\( SELECT d.id FROM webhook_deliveries d .* FOR UPDATE OF d SKIP LOCKED \\)").
+		WithArgs(now, now.Add(time.Minute), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "event_id", "attempts", "created_at", "url", "secret",
+			"type", "short_path", "payload", "event_created_at"}).
+			AddRow(5, 7, 40, 1, now, "https://example.com/hook", "s3cret", models.LinkEventCreated, "abc", []byte(payload), now))
+
+	dispatches, err := repo.ClaimDeliveries(context.Background(), now, now.Add(time.Minute), 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, dispatches, 1)
+	assert.Equal(t, int64(5), dispatches[0].Delivery.ID)
+	assert.Equal(t, 1, dispatches[0].Delivery.Attempts)
+	assert.Equal(t, models.LinkEventCreated, dispatches[0].Delivery.EventType)
+	assert.Equal(t, int64(40), dispatches[0].Event.ID)
+	assert.Equal(t, "https://example.com/hook", dispatches[0].URL)
+	assert.JSONEq(t, payload, string(dispatches[0].Event.Payload))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookDeliveryRepositoryPostgresqlImpl_CompleteDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookDeliveryRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	statusCode := 200
+
+	mock.ExpectExec("UPDATE webhook_deliveries SET status = \\$2, next_attempt_at = COALESCE\\(\\$3, next_attempt_at\\)").
+		WithArgs(int64(5), models.WebhookDeliverySucceeded, nil, int64(200), nil, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.CompleteDelivery(context.Background(), &models.WebhookDelivery{ID: 5, Status: models.WebhookDeliverySucceeded,
+		LastStatusCode: &statusCode, DeliveredAt: &now})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"url-shortener/internal/models"
+)
+
+//go:generate mockery --name=WebhookSubscriptionRepository --output=./mocks
+type WebhookSubscriptionRepository interface {
+	// CreateSubscription stores subscription and fills in its ID.
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	// GetSubscription returns ErrWebhookNotFound when there is no subscription with the ID, as do UpdateSubscription
+	// and DeleteSubscription.
+	GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id int64) error
+	// ListDeliveries returns up to limit deliveries of the subscription, latest first, older than the delivery before
+	// unless it is 0.
+	ListDeliveries(ctx context.Context, subscriptionID int64, before int64, limit int) ([]models.WebhookDelivery, error)
+}
@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"url-shortener/internal/db"
+	"url-shortener/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type webhookSubscriptionRepositoryPostgresqlImpl struct {
+	cluster *db.PostgresCluster
+}
+
+func NewWebhookSubscriptionRepositoryPostgresql(cluster *db.PostgresCluster) WebhookSubscriptionRepository {
+	return &webhookSubscriptionRepositoryPostgresqlImpl{cluster: cluster}
+}
+
+// CreateSubscription implements WebhookSubscriptionRepository.
+func (r *webhookSubscriptionRepositoryPostgresqlImpl) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	subscription.CreatedAt = subscription.CreatedAt.UTC()
+	subscription.UpdatedAt = subscription.CreatedAt
+	row := r.cluster.Primary().QueryRowContext(ctx, PG_INSERT_WEBHOOK_SUBSCRIPTION, subscription.URL, subscription.Secret,
+		pq.Array(eventTypes(subscription.EventTypes)), subscription.Active, subscription.CreatedAt)
+	if err := row.Scan(&subscription.ID); err != nil {
+		log.Printf("Error creating webhook subscription for %s: %v", subscription.URL, err)
+		return ErrDBError
+	}
+	return nil
+}
+
+// GetSubscription implements WebhookSubscriptionRepository. Subscriptions are read from the primary, a webhook is
+// usually looked at right after it was changed.
+func (r *webhookSubscriptionRepositoryPostgresqlImpl) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	subscription, err := scanWebhookSubscription(r.cluster.Primary().QueryRowContext(ctx, PG_GET_WEBHOOK_SUBSCRIPTION, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		log.Printf("Error getting webhook subscription %d: %v", id, err)
+		return nil, ErrDBError
+	}
+	return subscription, nil
+}
+
+// ListSubscriptions implements WebhookSubscriptionRepository.
+func (r *webhookSubscriptionRepositoryPostgresqlImpl) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	rows, err := r.cluster.Primary().QueryContext(ctx, PG_LIST_WEBHOOK_SUBSCRIPTIONS)
+	if err != nil {
+		log.Printf("Error listing webhook subscriptions: %v", err)
+		return nil, ErrDBError
+	}
+	defer rows.Close()
+
+	subscriptions := []models.WebhookSubscription{}
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			log.Printf("Error scanning webhook subscription: %v", err)
+			return nil, ErrDBError
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error listing webhook subscriptions: %v", err)
+		return nil, ErrDBError
+	}
+	return subscriptions, nil
+}
+
+// UpdateSubscription implements WebhookSubscriptionRepository.
+func (r *webhookSubscriptionRepositoryPostgresqlImpl) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	subscription.UpdatedAt = subscription.UpdatedAt.UTC()
+	result, err := r.cluster.Primary().ExecContext(ctx, PG_UPDATE_WEBHOOK_SUBSCRIPTION, subscription.ID, subscription.URL,
+		subscription.Secret, pq.Array(eventTypes(subscription.EventTypes)), subscription.Active, subscription.UpdatedAt)
+	return webhookSubscriptionChanged(result, err, "updating", subscription.ID)
+}
+
+// DeleteSubscription implements WebhookSubscriptionRepository. The deliveries of the subscription go with it.
+func (r *webhookSubscriptionRepositoryPostgresqlImpl) DeleteSubscription(ctx context.Context, id int64) error {
+	result, err := r.cluster.Primary().ExecContext(ctx, PG_DELETE_WEBHOOK_SUBSCRIPTION, id)
+	return webhookSubscriptionChanged(result, err, "deleting", id)
+}
+
+// ListDeliveries implements WebhookSubscriptionRepository.
+func (r *webhookSubscriptionRepositoryPostgresqlImpl) ListDeliveries(ctx context.Context, subscriptionID int64, before int64, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := r.cluster.Reader(ctx).QueryContext(ctx, PG_LIST_WEBHOOK_DELIVERIES, subscriptionID, before, limit)
+	if err != nil {
+		log.Printf("Error listing deliveries of webhook subscription %d: %v", subscriptionID, err)
+		return nil, ErrDBError
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		var nextAttemptAt, deliveredAt sql.NullTime
+		var lastStatusCode sql.NullInt64
+		if err := rows.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &delivery.Status,
+			&delivery.Attempts, &nextAttemptAt, &lastStatusCode, &delivery.LastError, &delivery.CreatedAt, &deliveredAt); err != nil {
+			log.Printf("Error scanning delivery of webhook subscription %d: %v", subscriptionID, err)
+			return nil, ErrDBError
+		}
+		if nextAttemptAt.Valid && delivery.Status == models.WebhookDeliveryPending {
+			delivery.NextAttemptAt = &nextAttemptAt.Time
+		}
+		if lastStatusCode.Valid {
+			code := int(lastStatusCode.Int64)
+			delivery.LastStatusCode = &code
+		}
+		if deliveredAt.Valid {
+			delivery.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error listing deliveries of webhook subscription %d: %v", subscriptionID, err)
+		return nil, ErrDBError
+	}
+	return deliveries, nil
+}
+
+func scanWebhookSubscription(row scanner) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	var types pq.StringArray
+	if err := row.Scan(&subscription.ID, &subscription.URL, &subscription.Secret, &types, &subscription.Active,
+		&subscription.CreatedAt, &subscription.UpdatedAt); err != nil {
+		return nil, err
+	}
+	subscription.EventTypes = eventTypes(types)
+	return &subscription, nil
+}
+
+func webhookSubscriptionChanged(result sql.Result, err error, action string, id int64) error {
+	if err != nil {
+		log.Printf("Error %s webhook subscription %d: %v", action, id, err)
+		return ErrDBError
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("Error %s webhook subscription %d: %v", action, id, err)
+		return ErrDBError
+	}
+	if rows == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// eventTypes never returns nil, a NULL array would not match the empty array that subscribes to every event.
+func eventTypes(types []string) []string {
+	if types == nil {
+		return []string{}
+	}
+	return types
+}
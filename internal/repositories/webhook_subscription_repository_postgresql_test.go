@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var webhookSubscriptionColumns = []string{"id", "url", "secret", "event_types", "active", "created_at", "updated_at"}
+
+func TestWebhookSubscriptionRepositoryPostgresqlImpl_CreateSubscription(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookSubscriptionRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	subscription := &models.WebhookSubscription{URL: "https://example.com/hook", Secret: "s3cret", Active: true, CreatedAt: now}
+
+	mock.ExpectQuery("INSERT INTO webhook_subscriptions \\(url, secret, event_types, active, created_at, updated_at\\)").
+		WithArgs("https://example.com/hook", "s3cret", pq.Array([]string{}), true, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	err = repo.CreateSubscription(context.Background(), subscription)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), subscription.ID)
+	assert.Equal(t, now, subscription.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookSubscriptionRepositoryPostgresqlImpl_GetSubscription(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookSubscriptionRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT id, url, secret, event_types, active, created_at, updated_at FROM webhook_subscriptions WHERE id = \\$1").
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows(webhookSubscriptionColumns).
+			AddRow(7, "https://example.com/hook", "s3cret", "{link.created,link.deleted}", true, now, now))
+
+	subscription, err := repo.GetSubscription(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{models.LinkEventCreated, models.LinkEventDeleted}, subscription.EventTypes)
+	assert.Equal(t, "s3cret", subscription.Secret)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookSubscriptionRepositoryPostgresqlImpl_GetSubscription_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookSubscriptionRepositoryPostgresql(newTestCluster(db))
+	mock.ExpectQuery("FROM webhook_subscriptions WHERE id = \\$1").WithArgs(int64(9)).
+		WillReturnRows(sqlmock.NewRows(webhookSubscriptionColumns))
+
+	_, err = repo.GetSubscription(context.Background(), 9)
+
+	assert.ErrorIs(t, err, ErrWebhookNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookSubscriptionRepositoryPostgresqlImpl_ListSubscriptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookSubscriptionRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("FROM webhook_subscriptions ORDER BY id").
+		WillReturnRows(sqlmock.NewRows(webhookSubscriptionColumns).
+			AddRow(1, "https://a.example.com", "a", "{}", true, now, now).
+			AddRow(2, "https://b.example.com", "b", "{link.expired}", false, now, now))
+
+	subscriptions, err := repo.ListSubscriptions(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, subscriptions, 2)
+	assert.Equal(t, []string{}, subscriptions[0].EventTypes)
+	assert.False(t, subscriptions[1].Active)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookSubscriptionRepositoryPostgresqlImpl_UpdateSubscription_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookSubscriptionRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	mock.ExpectExec("UPDATE webhook_subscriptions SET url = \\$2, secret = \\$3, event_types = \\$4, active = \\$5, updated_at = \\$6 WHERE id = \\$1").
+		WithArgs(int64(9), "https://example.com/hook", "s3cret", pq.Array([]string{models.LinkEventUpdated}), true, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateSubscription(context.Background(), &models.WebhookSubscription{ID: 9, URL: "https://example.com/hook",
+		Secret: "s3cret", EventTypes: []string{models.LinkEventUpdated}, Active: true, UpdatedAt: now})
+
+	assert.ErrorIs(t, err, ErrWebhookNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookSubscriptionRepositoryPostgresqlImpl_DeleteSubscription(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookSubscriptionRepositoryPostgresql(newTestCluster(db))
+	mock.ExpectExec("DELETE FROM webhook_subscriptions WHERE id = \\$1").WithArgs(int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.DeleteSubscription(context.Background(), 7))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookSubscriptionRepositoryPostgresqlImpl_ListDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookSubscriptionRepositoryPostgresql(newTestCluster(db))
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("FROM webhook_deliveries d JOIN outbox_events e ON e.id = d.event_id").
+		WithArgs(int64(7), int64(0), 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "event_id", "type", "status", "attempts",
+			"next_attempt_at", "last_status_code", "last_error", "created_at", "delivered_at"}).
+			AddRow(12, 7, 40, models.LinkEventDeleted, models.WebhookDeliveryPending, 2, now.Add(time.Minute), 503, "503 Service Unavailable", now, nil).
+			AddRow(11, 7, 39, models.LinkEventCreated, models.WebhookDeliverySucceeded, 1, now, 200, "", now, now))
+
+	deliveries, err := repo.ListDeliveries(context.Background(), 7, 0, 50)
+
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.Equal(t, now.Add(time.Minute), *deliveries[0].NextAttemptAt)
+	assert.Equal(t, 503, *deliveries[0].LastStatusCode)
+	assert.Nil(t, deliveries[0].DeliveredAt)
+	assert.Nil(t, deliveries[1].NextAttemptAt)
+	assert.Equal(t, now, *deliveries[1].DeliveredAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
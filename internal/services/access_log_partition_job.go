@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/utils"
+)
+
+// AccessLogPartitionJob creates the monthly partitions of url_access_logs ahead of time and drops or archives the
+// ones older than the raw retention. A partition is only removed once the click rollups cover it, so the all time
+// and weekly counts survive it.
+type AccessLogPartitionJob struct {
+	repo         repositories.AccessLogPartitionRepository
+	rollupRepo   repositories.ClickRollupRepository
+	config       config.PartitionsConfig
+	timeProvider utils.TimeProvider
+}
+
+func NewAccessLogPartitionJob(repo repositories.AccessLogPartitionRepository, rollupRepo repositories.ClickRollupRepository, partitionsConfig config.PartitionsConfig, timeProvider utils.TimeProvider) *AccessLogPartitionJob {
+	return &AccessLogPartitionJob{repo: repo, rollupRepo: rollupRepo, config: partitionsConfig, timeProvider: timeProvider}
+}
+
+// Name implements Job.
+func (j *AccessLogPartitionJob) Name() string {
+	return "access-log-partitions"
+}
+
+// CreatePartitions creates the partitions of the current month and the premade months after it. It is run once at
+// startup before any click is logged, so clicks do not land in the default partition while the job waits for its
+// first tick.
+func (j *AccessLogPartitionJob) CreatePartitions(ctx context.Context) error {
+	now := j.timeProvider.Now().UTC()
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i <= j.config.PremakeMonths; i++ {
+		if err := j.repo.CreateMonthlyPartition(ctx, currentMonth.AddDate(0, i, 0)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run implements Job. Expired partitions are removed even when creating the next ones fails.
+func (j *AccessLogPartitionJob) Run(ctx context.Context) error {
+	createErr := j.CreatePartitions(ctx)
+	if j.config.Retention <= 0 {
+		return createErr
+	}
+	return errors.Join(createErr, j.removeExpiredPartitions(ctx))
+}
+
+func (j *AccessLogPartitionJob) removeExpiredPartitions(ctx context.Context) error {
+	now := j.timeProvider.Now().UTC()
+	partitions, err := j.repo.ListMonthlyPartitions(ctx)
+	if err != nil {
+		return err
+	}
+	watermark, err := j.rollupRepo.Watermark(ctx)
+	if err != nil {
+		return err
+	}
+	cutoff := now.Add(-j.config.Retention)
+	for _, partition := range partitions {
+		if partition.To.After(cutoff) {
+			break
+		}
+		if partition.To.After(watermark) {
+			log.Printf("Keeping access log partition %s past retention until its clicks are rolled up", partition.Name)
+			break
+		}
+		if j.config.Archive {
+			err = j.repo.ArchivePartition(ctx, partition)
+		} else {
+			err = j.repo.DropPartition(ctx, partition)
+		}
+		if err != nil {
+			return err
+		}
+		log.Printf("Removed access log partition %s (archived: %t)", partition.Name, j.config.Archive)
+	}
+	return nil
+}
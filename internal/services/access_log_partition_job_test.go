@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	repoMocks "url-shortener/internal/repositories/mocks"
+	utilsMocks "url-shortener/internal/utils/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func monthPartition(year int, month time.Month) models.AccessLogPartition {
+	from := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return models.AccessLogPartition{Name: "url_access_logs_p" + from.Format("200601"), From: from, To: from.AddDate(0, 1, 0)}
+}
+
+func setupPartitionJob(partitionsConfig config.PartitionsConfig) (*repoMocks.AccessLogPartitionRepository, *repoMocks.ClickRollupRepository, *AccessLogPartitionJob) {
+	repo := &repoMocks.AccessLogPartitionRepository{}
+	rollupRepo := &repoMocks.ClickRollupRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	timeProvider.On("Now").Return(time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC))
+	return repo, rollupRepo, NewAccessLogPartitionJob(repo, rollupRepo, partitionsConfig, timeProvider)
+}
+
+func TestAccessLogPartitionJob_CreatesFuturePartitions(t *testing.T) {
+	repo, rollupRepo, job := setupPartitionJob(config.PartitionsConfig{PremakeMonths: 2})
+	for _, month := range []time.Month{time.May, time.June, time.July} {
+		repo.On("CreateMonthlyPartition", mock.Anything, time.Date(2026, month, 1, 0, 0, 0, 0, time.UTC)).Return(nil).Once()
+	}
+
+	err := job.Run(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	rollupRepo.AssertExpectations(t)
+}
+
+func TestAccessLogPartitionJob_DropsRolledUpPartitionsPastRetention(t *testing.T) {
+	repo, rollupRepo, job := setupPartitionJob(config.PartitionsConfig{Retention: 60 * 24 * time.Hour})
+	repo.On("CreateMonthlyPartition", mock.Anything, mock.Anything).Return(nil)
+	repo.On("ListMonthlyPartitions", mock.Anything).Return([]models.AccessLogPartition{
+		monthPartition(2026, time.January), monthPartition(2026, time.February), monthPartition(2026, time.March), monthPartition(2026, time.April),
+	}, nil).Once()
+	// February is past retention but not rolled up completely yet.
+	rollupRepo.On("Watermark", mock.Anything).Return(time.Date(2026, 2, 27, 0, 0, 0, 0, time.UTC), nil).Once()
+	repo.On("DropPartition", mock.Anything, monthPartition(2026, time.January)).Return(nil).Once()
+
+	err := job.Run(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	rollupRepo.AssertExpectations(t)
+}
+
+func TestAccessLogPartitionJob_ArchivesInsteadOfDropping(t *testing.T) {
+	repo, rollupRepo, job := setupPartitionJob(config.PartitionsConfig{Retention: 60 * 24 * time.Hour, Archive: true})
+	repo.On("CreateMonthlyPartition", mock.Anything, mock.Anything).Return(nil)
+	repo.On("ListMonthlyPartitions", mock.Anything).Return([]models.AccessLogPartition{
+		monthPartition(2026, time.January), monthPartition(2026, time.February), monthPartition(2026, time.May),
+	}, nil).Once()
+	rollupRepo.On("Watermark", mock.Anything).Return(time.Date(2026, 5, 20, 11, 0, 0, 0, time.UTC), nil).Once()
+	repo.On("ArchivePartition", mock.Anything, monthPartition(2026, time.January)).Return(nil).Once()
+	repo.On("ArchivePartition", mock.Anything, monthPartition(2026, time.February)).Return(nil).Once()
+
+	err := job.Run(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "DropPartition", mock.Anything, mock.Anything)
+}
+
+func TestAccessLogPartitionJob_RemovesExpiredPartitionsWhenCreateFails(t *testing.T) {
+	repo, rollupRepo, job := setupPartitionJob(config.PartitionsConfig{Retention: 60 * 24 * time.Hour})
+	repo.On("CreateMonthlyPartition", mock.Anything, mock.Anything).Return(repositories.ErrDBError).Once()
+	repo.On("ListMonthlyPartitions", mock.Anything).Return([]models.AccessLogPartition{monthPartition(2026, time.January)}, nil).Once()
+	rollupRepo.On("Watermark", mock.Anything).Return(time.Date(2026, 5, 20, 11, 0, 0, 0, time.UTC), nil).Once()
+	repo.On("DropPartition", mock.Anything, monthPartition(2026, time.January)).Return(nil).Once()
+
+	err := job.Run(context.Background())
+
+	assert.ErrorIs(t, err, repositories.ErrDBError)
+	repo.AssertExpectations(t)
+	rollupRepo.AssertExpectations(t)
+}
+
+func TestAccessLogPartitionJob_CreatePartitions(t *testing.T) {
+	repo, _, job := setupPartitionJob(config.PartitionsConfig{PremakeMonths: 1, Retention: 60 * 24 * time.Hour})
+	repo.On("CreateMonthlyPartition", mock.Anything, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)).Return(nil).Once()
+	repo.On("CreateMonthlyPartition", mock.Anything, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)).Return(nil).Once()
+
+	// Startup only creates the partitions, retention is left to the scheduled runs.
+	err := job.CreatePartitions(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "ListMonthlyPartitions", mock.Anything)
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/utils"
+)
+
+//go:generate mockery --name=AccessLogger --output=./mocks
+type AccessLogger interface {
+	// Log queues an access for asynchronous storage. It never blocks longer than the enqueue timeout.
+	Log(accessLog *models.AccessLog)
+	// Stats returns counters of queued, dropped, flushed and failed accesses.
+	Stats() models.AccessLogPipelineStats
+}
+
+// AccessLogPipeline batches access logs in a bounded queue and flushes them from a fixed set of workers,
+// either when a batch is full or every flush interval. Workers add the geo-IP location of each access so lookups
+// stay off the redirect path, and anonymize the client IP once it has been looked up.
+type AccessLogPipeline struct {
+	repo       repositories.URLStatisticsRepository
+	visitors   repositories.UniqueVisitorRepository
+	geoIP      utils.GeoIPResolver
+	anonymizer *IPAnonymizer
+	config     config.ClicksConfig
+	queue      chan *models.AccessLog
+	wg         sync.WaitGroup
+
+	// mu guards closed so Log never sends on the queue after Shutdown has closed it.
+	mu     sync.RWMutex
+	closed bool
+
+	enqueued atomic.Int64
+	dropped  atomic.Int64
+	flushed  atomic.Int64
+	failed   atomic.Int64
+}
+
+func NewAccessLogPipeline(repo repositories.URLStatisticsRepository, visitors repositories.UniqueVisitorRepository, geoIP utils.GeoIPResolver, anonymizer *IPAnonymizer, clicksConfig config.ClicksConfig) *AccessLogPipeline {
+	if clicksConfig.Workers <= 0 {
+		clicksConfig.Workers = 1
+	}
+	if clicksConfig.BatchSize <= 0 {
+		clicksConfig.BatchSize = 1
+	}
+	if clicksConfig.FlushInterval <= 0 {
+		clicksConfig.FlushInterval = time.Second
+	}
+	return &AccessLogPipeline{
+		repo:       repo,
+		visitors:   visitors,
+		geoIP:      geoIP,
+		anonymizer: anonymizer,
+		config:     clicksConfig,
+		queue:      make(chan *models.AccessLog, clicksConfig.QueueSize),
+	}
+}
+
+// Start launches the workers.
+func (p *AccessLogPipeline) Start() {
+	for i := 0; i < p.config.Workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+}
+
+// Log implements AccessLogger. When the queue is full it waits up to the enqueue timeout and then drops the access.
+func (p *AccessLogPipeline) Log(accessLog *models.AccessLog) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		p.drop()
+		return
+	}
+
+	select {
+	case p.queue <- accessLog:
+		p.enqueued.Add(1)
+		return
+	default:
+	}
+	if p.config.EnqueueTimeout > 0 {
+		timer := time.NewTimer(p.config.EnqueueTimeout)
+		defer timer.Stop()
+		select {
+		case p.queue <- accessLog:
+			p.enqueued.Add(1)
+			return
+		case <-timer.C:
+		}
+	}
+	p.drop()
+}
+
+func (p *AccessLogPipeline) drop() {
+	// Log the first drop and then every thousandth so a saturated queue does not flood the logs.
+	if dropped := p.dropped.Add(1); dropped%1000 == 1 {
+		log.Printf("Access log queue full, %d accesses dropped so far", dropped)
+	}
+}
+
+// Shutdown stops accepting accesses and waits for the workers to flush everything already queued.
+func (p *AccessLogPipeline) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats implements AccessLogger.
+func (p *AccessLogPipeline) Stats() models.AccessLogPipelineStats {
+	return models.AccessLogPipelineStats{
+		Queued:   int64(len(p.queue)),
+		Enqueued: p.enqueued.Load(),
+		Dropped:  p.dropped.Load(),
+		Flushed:  p.flushed.Load(),
+		Failed:   p.failed.Load(),
+	}
+}
+
+func (p *AccessLogPipeline) work() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*models.AccessLog, 0, p.config.BatchSize)
+	for {
+		select {
+		case accessLog, ok := <-p.queue:
+			if !ok {
+				p.flush(batch)
+				return
+			}
+			p.enrich(accessLog)
+			batch = append(batch, accessLog)
+			if len(batch) >= p.config.BatchSize {
+				p.flush(batch)
+				batch = make([]*models.AccessLog, 0, p.config.BatchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				p.flush(batch)
+				batch = make([]*models.AccessLog, 0, p.config.BatchSize)
+			}
+		}
+	}
+}
+
+func (p *AccessLogPipeline) enrich(accessLog *models.AccessLog) {
+	if accessLog.ClientIP == "" {
+		return
+	}
+	location := p.geoIP.Lookup(accessLog.ClientIP)
+	accessLog.Country = location.Country
+	accessLog.Region = location.Region
+	accessLog.City = location.City
+	if accessLog.DoNotTrack {
+		// Only the country is kept of clicks that asked not to be tracked.
+		accessLog.ClientIP, accessLog.Region, accessLog.City = "", "", ""
+		return
+	}
+	accessLog.ClientIP = p.anonymizer.Anonymize(context.Background(), accessLog.ClientIP, accessLog.AccessedAt)
+}
+
+// flush writes the batch, retrying with a growing delay before giving up on it. Unique visitors are counted
+// separately, losing them only affects the estimates.
+func (p *AccessLogPipeline) flush(batch []*models.AccessLog) {
+	if len(batch) == 0 {
+		return
+	}
+	p.addVisitors(batch)
+	var err error
+	for attempt := 0; attempt <= p.config.FlushRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+		}
+		err = p.insert(batch)
+		if err == nil {
+			p.flushed.Add(int64(len(batch)))
+			return
+		}
+	}
+	p.failed.Add(int64(len(batch)))
+	log.Printf("Error flushing %d access logs: %v", len(batch), err)
+}
+
+func (p *AccessLogPipeline) addVisitors(batch []*models.AccessLog) {
+	ctx := context.Background()
+	if p.config.FlushTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.config.FlushTimeout)
+		defer cancel()
+	}
+	// The repository logs the error.
+	_ = p.visitors.AddVisitors(ctx, batch)
+}
+
+func (p *AccessLogPipeline) insert(batch []*models.AccessLog) error {
+	ctx := context.Background()
+	if p.config.FlushTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.config.FlushTimeout)
+		defer cancel()
+	}
+	return p.repo.InsertAccessLogs(ctx, batch)
+}
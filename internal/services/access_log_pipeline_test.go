@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	repoMocks "url-shortener/internal/repositories/mocks"
+	"url-shortener/internal/utils"
+	utilsMocks "url-shortener/internal/utils/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func accessLogs(n int) []*models.AccessLog {
+	logs := make([]*models.AccessLog, n)
+	for i := range logs {
+		logs[i] = &models.AccessLog{ShortPath: "shortPath", AccessedAt: time.Now()}
+	}
+	return logs
+}
+
+func noVisitors() *repoMocks.UniqueVisitorRepository {
+	visitors := &repoMocks.UniqueVisitorRepository{}
+	visitors.On("AddVisitors", mock.Anything, mock.Anything).Return(nil)
+	return visitors
+}
+
+func ipAnonymizer(mode string) *IPAnonymizer {
+	anonymizer, _ := NewIPAnonymizer(config.PrivacyConfig{IPMode: mode}, nil)
+	return anonymizer
+}
+
+func TestAccessLogPipeline_FlushesFullBatches(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	logs := accessLogs(4)
+	repo.On("InsertAccessLogs", mock.Anything, logs[:2]).Return(nil).Once()
+	repo.On("InsertAccessLogs", mock.Anything, logs[2:]).Return(nil).Once()
+	pipeline := NewAccessLogPipeline(repo, noVisitors(), &utilsMocks.GeoIPResolver{}, ipAnonymizer(IPModeFull), config.ClicksConfig{QueueSize: 10, Workers: 1, BatchSize: 2, FlushInterval: time.Hour})
+	pipeline.Start()
+
+	for _, accessLog := range logs {
+		pipeline.Log(accessLog)
+	}
+
+	assert.Eventually(t, func() bool { return pipeline.Stats().Flushed == 4 }, time.Second, 10*time.Millisecond)
+	assert.NoError(t, pipeline.Shutdown(context.Background()))
+	repo.AssertExpectations(t)
+}
+
+func TestAccessLogPipeline_FlushesOnInterval(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	logs := accessLogs(1)
+	repo.On("InsertAccessLogs", mock.Anything, logs).Return(nil).Once()
+	pipeline := NewAccessLogPipeline(repo, noVisitors(), &utilsMocks.GeoIPResolver{}, ipAnonymizer(IPModeFull), config.ClicksConfig{QueueSize: 10, Workers: 1, BatchSize: 100, FlushInterval: 10 * time.Millisecond})
+	pipeline.Start()
+
+	pipeline.Log(logs[0])
+
+	assert.Eventually(t, func() bool { return pipeline.Stats().Flushed == 1 }, time.Second, 10*time.Millisecond)
+	assert.NoError(t, pipeline.Shutdown(context.Background()))
+	repo.AssertExpectations(t)
+}
+
+func TestAccessLogPipeline_DropsWhenFull(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	pipeline := NewAccessLogPipeline(repo, noVisitors(), &utilsMocks.GeoIPResolver{}, ipAnonymizer(IPModeFull), config.ClicksConfig{QueueSize: 1, Workers: 1, BatchSize: 10, FlushInterval: time.Hour})
+
+	// Workers are not started, so the queue fills up after the first access.
+	for _, accessLog := range accessLogs(3) {
+		pipeline.Log(accessLog)
+	}
+
+	stats := pipeline.Stats()
+	assert.Equal(t, int64(1), stats.Enqueued)
+	assert.Equal(t, int64(2), stats.Dropped)
+	assert.Equal(t, int64(1), stats.Queued)
+}
+
+func TestAccessLogPipeline_ShutdownDrainsQueue(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	logs := accessLogs(3)
+	repo.On("InsertAccessLogs", mock.Anything, logs).Return(nil).Once()
+	pipeline := NewAccessLogPipeline(repo, noVisitors(), &utilsMocks.GeoIPResolver{}, ipAnonymizer(IPModeFull), config.ClicksConfig{QueueSize: 10, Workers: 1, BatchSize: 100, FlushInterval: time.Hour})
+	for _, accessLog := range logs {
+		pipeline.Log(accessLog)
+	}
+	pipeline.Start()
+
+	assert.NoError(t, pipeline.Shutdown(context.Background()))
+	assert.Equal(t, int64(3), pipeline.Stats().Flushed)
+
+	pipeline.Log(accessLogs(1)[0])
+	assert.Equal(t, int64(1), pipeline.Stats().Dropped)
+	repo.AssertExpectations(t)
+}
+
+func TestAccessLogPipeline_RetriesFailedFlush(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	logs := accessLogs(1)
+	repo.On("InsertAccessLogs", mock.Anything, logs).Return(assert.AnError).Once()
+	repo.On("InsertAccessLogs", mock.Anything, logs).Return(nil).Once()
+	pipeline := NewAccessLogPipeline(repo, noVisitors(), &utilsMocks.GeoIPResolver{}, ipAnonymizer(IPModeFull), config.ClicksConfig{QueueSize: 10, Workers: 1, BatchSize: 1, FlushInterval: time.Hour, FlushRetries: 1})
+	pipeline.Start()
+
+	pipeline.Log(logs[0])
+
+	assert.NoError(t, pipeline.Shutdown(context.Background()))
+	stats := pipeline.Stats()
+	assert.Equal(t, int64(1), stats.Flushed)
+	assert.Equal(t, int64(0), stats.Failed)
+	repo.AssertExpectations(t)
+}
+
+func TestAccessLogPipeline_AddsGeoLocation(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	geoIP := &utilsMocks.GeoIPResolver{}
+	geoIP.On("Lookup", "203.0.113.7").Return(utils.GeoLocation{Country: "DE", Region: "DE-BY", City: "Munich"}).Once()
+	accessedAt := time.Now()
+	repo.On("InsertAccessLogs", mock.Anything, []*models.AccessLog{
+		{ShortPath: "shortPath", AccessedAt: accessedAt, ClientIP: "203.0.113.0", Country: "DE", Region: "DE-BY", City: "Munich"},
+	}).Return(nil).Once()
+	// The location is looked up from the full IP, only the truncated one is stored.
+	pipeline := NewAccessLogPipeline(repo, noVisitors(), geoIP, ipAnonymizer(IPModeTruncate), config.ClicksConfig{QueueSize: 10, Workers: 1, BatchSize: 1, FlushInterval: time.Hour})
+	pipeline.Start()
+
+	pipeline.Log(&models.AccessLog{ShortPath: "shortPath", AccessedAt: accessedAt, ClientIP: "203.0.113.7"})
+
+	assert.NoError(t, pipeline.Shutdown(context.Background()))
+	repo.AssertExpectations(t)
+	geoIP.AssertExpectations(t)
+}
+
+func TestAccessLogPipeline_DoNotTrackKeepsOnlyCountry(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	geoIP := &utilsMocks.GeoIPResolver{}
+	geoIP.On("Lookup", "203.0.113.7").Return(utils.GeoLocation{Country: "DE", Region: "DE-BY", City: "Munich"}).Once()
+	accessedAt := time.Now()
+	repo.On("InsertAccessLogs", mock.Anything, []*models.AccessLog{
+		{ShortPath: "shortPath", AccessedAt: accessedAt, Country: "DE", DoNotTrack: true},
+	}).Return(nil).Once()
+	pipeline := NewAccessLogPipeline(repo, noVisitors(), geoIP, ipAnonymizer(IPModeFull), config.ClicksConfig{QueueSize: 10, Workers: 1, BatchSize: 1, FlushInterval: time.Hour})
+	pipeline.Start()
+
+	pipeline.Log(&models.AccessLog{ShortPath: "shortPath", AccessedAt: accessedAt, ClientIP: "203.0.113.7", DoNotTrack: true})
+
+	assert.NoError(t, pipeline.Shutdown(context.Background()))
+	repo.AssertExpectations(t)
+}
+
+func TestAccessLogPipeline_VisitorErrorDoesNotFailBatch(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	visitors := &repoMocks.UniqueVisitorRepository{}
+	logs := accessLogs(2)
+	visitors.On("AddVisitors", mock.Anything, logs).Return(assert.AnError).Once()
+	repo.On("InsertAccessLogs", mock.Anything, logs).Return(nil).Once()
+	pipeline := NewAccessLogPipeline(repo, visitors, &utilsMocks.GeoIPResolver{}, ipAnonymizer(IPModeFull), config.ClicksConfig{QueueSize: 10, Workers: 1, BatchSize: 100, FlushInterval: time.Hour})
+	for _, accessLog := range logs {
+		pipeline.Log(accessLog)
+	}
+	pipeline.Start()
+
+	assert.NoError(t, pipeline.Shutdown(context.Background()))
+	assert.Equal(t, int64(2), pipeline.Stats().Flushed)
+	repo.AssertExpectations(t)
+	visitors.AssertExpectations(t)
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/utils"
+)
+
+// AccessLogPurgeJob clears the columns of url_access_logs that have a retention once clicks are older than it. The
+// click itself is kept, so counts and rollups are not affected.
+type AccessLogPurgeJob struct {
+	repo         repositories.AccessLogPurgeRepository
+	config       config.PrivacyConfig
+	timeProvider utils.TimeProvider
+}
+
+func NewAccessLogPurgeJob(repo repositories.AccessLogPurgeRepository, privacyConfig config.PrivacyConfig, timeProvider utils.TimeProvider) *AccessLogPurgeJob {
+	if privacyConfig.PurgeBatchSize <= 0 {
+		privacyConfig.PurgeBatchSize = 10000
+	}
+	return &AccessLogPurgeJob{repo: repo, config: privacyConfig, timeProvider: timeProvider}
+}
+
+// Name implements Job.
+func (j *AccessLogPurgeJob) Name() string {
+	return "access-log-purge"
+}
+
+// Run implements Job. A column that fails, or is not known, does not keep the other columns from being purged.
+func (j *AccessLogPurgeJob) Run(ctx context.Context) error {
+	columns := make([]string, 0, len(j.config.Retention))
+	for column, retention := range j.config.Retention {
+		if retention > 0 {
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+
+	now := j.timeProvider.Now().UTC()
+	var errs []error
+	for _, column := range columns {
+		purged, err := j.repo.PurgeColumn(ctx, column, now.Add(-j.config.Retention[column]), j.config.PurgeBatchSize)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if purged > 0 {
+			log.Printf("Purged %s of %d access logs", column, purged)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/repositories"
+	repoMocks "url-shortener/internal/repositories/mocks"
+	utilsMocks "url-shortener/internal/utils/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var purgeNow = time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+
+func setupPurgeJob(privacyConfig config.PrivacyConfig) (*repoMocks.AccessLogPurgeRepository, *AccessLogPurgeJob) {
+	repo := &repoMocks.AccessLogPurgeRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	timeProvider.On("Now").Return(purgeNow)
+	return repo, NewAccessLogPurgeJob(repo, privacyConfig, timeProvider)
+}
+
+func TestAccessLogPurgeJob_PurgesColumnsPastRetention(t *testing.T) {
+	repo, job := setupPurgeJob(config.PrivacyConfig{
+		Retention:      map[string]time.Duration{"client_ip": 720 * time.Hour, "query_string": 24 * time.Hour, "city": 0},
+		PurgeBatchSize: 500,
+	})
+	repo.On("PurgeColumn", context.Background(), "client_ip", purgeNow.Add(-720*time.Hour), 500).Return(int64(12), nil).Once()
+	repo.On("PurgeColumn", context.Background(), "query_string", purgeNow.Add(-24*time.Hour), 500).Return(int64(0), nil).Once()
+
+	err := job.Run(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestAccessLogPurgeJob_ErrorDoesNotStopOtherColumns(t *testing.T) {
+	repo, job := setupPurgeJob(config.PrivacyConfig{Retention: map[string]time.Duration{"clientip": time.Hour, "region": time.Hour}})
+	repo.On("PurgeColumn", context.Background(), "clientip", purgeNow.Add(-time.Hour), 10000).Return(int64(0), repositories.ErrUnknownAccessLogColumn).Once()
+	repo.On("PurgeColumn", context.Background(), "region", purgeNow.Add(-time.Hour), 10000).Return(int64(3), nil).Once()
+
+	err := job.Run(context.Background())
+
+	assert.ErrorIs(t, err, repositories.ErrUnknownAccessLogColumn)
+	repo.AssertExpectations(t)
+}
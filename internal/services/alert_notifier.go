@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+)
+
+const (
+	alertWebhookAttempts = 3
+	alertWebhookBackoff  = time.Second
+)
+
+//go:generate mockery --name=AlertNotifier --output=./mocks
+type AlertNotifier interface {
+	Notify(ctx context.Context, alert models.TrafficAlert) error
+}
+
+// alertPayload is the body of an alert webhook. Receivers can use dedupKey to drop an alert delivered twice.
+type alertPayload struct {
+	Type        string    `json:"type"`
+	DedupKey    string    `json:"dedupKey"`
+	ShortPath   string    `json:"shortPath"`
+	BucketStart time.Time `json:"bucketStart"`
+	BucketEnd   time.Time `json:"bucketEnd"`
+	Clicks      int64     `json:"clicks"`
+	Baseline    float64   `json:"baseline"`
+	ZScore      float64   `json:"zScore"`
+}
+
+// WebhookAlertNotifier posts alerts to every configured webhook, retrying a webhook that fails a few times. With a
+// secret set the body is signed, see signWebhook. Like link event webhooks, alerts are only sent to public addresses.
+type WebhookAlertNotifier struct {
+	client   *http.Client
+	webhooks []string
+	secret   []byte
+	backoff  time.Duration
+}
+
+func NewWebhookAlertNotifier(alertsConfig config.AlertsConfig) *WebhookAlertNotifier {
+	return &WebhookAlertNotifier{
+		client:   newWebhookClient(alertsConfig.WebhookTimeout),
+		webhooks: alertsConfig.Webhooks,
+		secret:   []byte(alertsConfig.WebhookSecret),
+		backoff:  alertWebhookBackoff,
+	}
+}
+
+// Notify implements AlertNotifier. It returns the errors of all webhooks that could not be reached.
+func (n *WebhookAlertNotifier) Notify(ctx context.Context, alert models.TrafficAlert) error {
+	body, err := json.Marshal(alertPayload{
+		Type:        "link.traffic_" + alert.Kind,
+		DedupKey:    alert.DedupKey,
+		ShortPath:   alert.ShortPath,
+		BucketStart: alert.BucketStart.UTC(),
+		BucketEnd:   alert.BucketEnd.UTC(),
+		Clicks:      alert.Clicks,
+		Baseline:    alert.Baseline,
+		ZScore:      alert.ZScore,
+	})
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{"X-Alert-Dedup-Key": alert.DedupKey}
+	if len(n.secret) > 0 {
+		headers["X-Signature-256"] = signWebhook(n.secret, body)
+	}
+
+	var errs []error
+	for _, webhook := range n.webhooks {
+		if err := n.deliver(ctx, webhook, headers, body); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", webhook, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// alertNotifyTimeout is the longest a webhook can take to be notified, all attempts and the backoff between them.
+func alertNotifyTimeout(timeout time.Duration) time.Duration {
+	return alertWebhookAttempts*timeout + (1<<(alertWebhookAttempts-1)-1)*alertWebhookBackoff
+}
+
+func (n *WebhookAlertNotifier) deliver(ctx context.Context, webhook string, headers map[string]string, body []byte) error {
+	var err error
+	for attempt := 0; attempt < alertWebhookAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(webhookBackoff(n.backoff, 0, attempt)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if _, err = postWebhook(ctx, n.client, webhook, body, headers); err == nil {
+			return nil
+		}
+	}
+	return err
+}
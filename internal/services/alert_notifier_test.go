@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testTrafficAlert = models.TrafficAlert{
+	Kind:        models.TrafficStateSpike,
+	ShortPath:   "abc",
+	BucketStart: time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC),
+	BucketEnd:   time.Date(2025, 6, 12, 10, 5, 0, 0, time.UTC),
+	Clicks:      90,
+	Baseline:    30,
+	ZScore:      10,
+	DedupKey:    "abc:spike:2025-06-12T10:00:00Z",
+}
+
+// newTestAlertNotifier returns a notifier that can reach httptest servers, which listen on loopback.
+func newTestAlertNotifier(alertsConfig config.AlertsConfig) *WebhookAlertNotifier {
+	notifier := NewWebhookAlertNotifier(alertsConfig)
+	notifier.client = &http.Client{Timeout: alertsConfig.WebhookTimeout}
+	return notifier
+}
+
+func TestWebhookAlertNotifier_PostsSignedAlert(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(body)
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get("X-Signature-256"))
+		assert.Equal(t, "abc:spike:2025-06-12T10:00:00Z", r.Header.Get("X-Alert-Dedup-Key"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	notifier := newTestAlertNotifier(config.AlertsConfig{Webhooks: []string{server.URL}, WebhookSecret: "secret", WebhookTimeout: time.Second})
+
+	err := notifier.Notify(context.Background(), testTrafficAlert)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "link.traffic_spike", payload["type"])
+	assert.Equal(t, "abc", payload["shortPath"])
+	assert.Equal(t, "2025-06-12T10:00:00Z", payload["bucketStart"])
+	assert.Equal(t, 90.0, payload["clicks"])
+	assert.Equal(t, 30.0, payload["baseline"])
+}
+
+func TestWebhookAlertNotifier_RetriesFailedWebhook(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("X-Signature-256"))
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+	notifier := newTestAlertNotifier(config.AlertsConfig{Webhooks: []string{server.URL}, WebhookTimeout: time.Second})
+	notifier.backoff = time.Millisecond
+
+	err := notifier.Notify(context.Background(), testTrafficAlert)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestWebhookAlertNotifier_Error(t *testing.T) {
+	var requests atomic.Int32
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	working := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer working.Close()
+	notifier := newTestAlertNotifier(config.AlertsConfig{Webhooks: []string{failing.URL, working.URL}, WebhookTimeout: time.Second})
+	notifier.backoff = time.Millisecond
+
+	err := notifier.Notify(context.Background(), testTrafficAlert)
+
+	assert.ErrorContains(t, err, failing.URL)
+	assert.NotContains(t, err.Error(), working.URL)
+	assert.Equal(t, int32(alertWebhookAttempts), requests.Load())
+}
+
+func TestWebhookAlertNotifier_RefusesNonPublicTargets(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+	notifier := NewWebhookAlertNotifier(config.AlertsConfig{Webhooks: []string{server.URL}, WebhookTimeout: time.Second})
+	notifier.backoff = time.Millisecond
+
+	err := notifier.Notify(context.Background(), testTrafficAlert)
+
+	assert.ErrorIs(t, err, errWebhookTargetNotPublic)
+	assert.Zero(t, requests.Load())
+}
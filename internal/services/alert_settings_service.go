@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+)
+
+var ErrInvalidAlertSettings = errors.New("spikeZ and dropZ must be greater than 0 and minClicks at least 0")
+
+//go:generate mockery --name=AlertSettingsService --output=./mocks
+type AlertSettingsService interface {
+	// GetAlertSettings returns the alert thresholds of a link, with the defaults filled in for those it does not set.
+	GetAlertSettings(ctx context.Context, shortPath string) (*models.AlertSettings, error)
+	// SetAlertSettings replaces the thresholds of a link, nil thresholds fall back to the defaults.
+	SetAlertSettings(ctx context.Context, settings *models.AlertSettings) error
+}
+
+type alertSettingsServiceImpl struct {
+	repo    repositories.TrafficAnomalyRepository
+	urlRepo repositories.URLRepository
+	config  config.AlertsConfig
+}
+
+func NewAlertSettingsService(repo repositories.TrafficAnomalyRepository, urlRepo repositories.URLRepository, alertsConfig config.AlertsConfig) AlertSettingsService {
+	return &alertSettingsServiceImpl{repo: repo, urlRepo: urlRepo, config: alertsConfig}
+}
+
+// GetAlertSettings implements AlertSettingsService.
+func (s *alertSettingsServiceImpl) GetAlertSettings(ctx context.Context, shortPath string) (*models.AlertSettings, error) {
+	if err := s.checkLink(ctx, shortPath); err != nil {
+		return nil, err
+	}
+	settings, err := s.repo.GetAlertSettings(ctx, shortPath)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = &models.AlertSettings{ShortPath: shortPath, Enabled: true}
+	}
+	if settings.SpikeZ == nil {
+		settings.SpikeZ = &s.config.SpikeZ
+	}
+	if settings.DropZ == nil {
+		settings.DropZ = &s.config.DropZ
+	}
+	if settings.MinClicks == nil {
+		settings.MinClicks = &s.config.MinClicks
+	}
+	return settings, nil
+}
+
+// SetAlertSettings implements AlertSettingsService.
+func (s *alertSettingsServiceImpl) SetAlertSettings(ctx context.Context, settings *models.AlertSettings) error {
+	if (settings.SpikeZ != nil && *settings.SpikeZ <= 0) || (settings.DropZ != nil && *settings.DropZ <= 0) ||
+		(settings.MinClicks != nil && *settings.MinClicks < 0) {
+		return ErrInvalidAlertSettings
+	}
+	if err := s.checkLink(ctx, settings.ShortPath); err != nil {
+		return err
+	}
+	return s.repo.SaveAlertSettings(ctx, settings)
+}
+
+func (s *alertSettingsServiceImpl) checkLink(ctx context.Context, shortPath string) error {
+	url, err := s.urlRepo.GetOriginalURL(ctx, shortPath)
+	if errors.Is(err, repositories.ErrURLNotFound) || (err == nil && url == nil) {
+		return repositories.ErrShortURLNotFound
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	repoMocks "url-shortener/internal/repositories/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertSettingsService_GetFillsInDefaults(t *testing.T) {
+	repo := &repoMocks.TrafficAnomalyRepository{}
+	urlRepo := &repoMocks.URLRepository{}
+	spikeZ := 8.0
+	urlRepo.On("GetOriginalURL", context.Background(), "abc").Return(&models.URL{ShortPath: "abc"}, nil).Once()
+	repo.On("GetAlertSettings", context.Background(), "abc").Return(&models.AlertSettings{ShortPath: "abc", SpikeZ: &spikeZ, Enabled: false}, nil).Once()
+	service := NewAlertSettingsService(repo, urlRepo, testAlertsConfig)
+
+	settings, err := service.GetAlertSettings(context.Background(), "abc")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 8.0, *settings.SpikeZ)
+	assert.Equal(t, 3.0, *settings.DropZ)
+	assert.Equal(t, 20, *settings.MinClicks)
+	assert.False(t, settings.Enabled)
+	repo.AssertExpectations(t)
+}
+
+func TestAlertSettingsService_GetUnknownLink(t *testing.T) {
+	repo := &repoMocks.TrafficAnomalyRepository{}
+	urlRepo := &repoMocks.URLRepository{}
+	urlRepo.On("GetOriginalURL", context.Background(), "missing").Return(nil, repositories.ErrURLNotFound).Once()
+	service := NewAlertSettingsService(repo, urlRepo, testAlertsConfig)
+
+	_, err := service.GetAlertSettings(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, repositories.ErrShortURLNotFound)
+	repo.AssertNotCalled(t, "GetAlertSettings")
+}
+
+func TestAlertSettingsService_SetRejectsInvalidThresholds(t *testing.T) {
+	repo := &repoMocks.TrafficAnomalyRepository{}
+	urlRepo := &repoMocks.URLRepository{}
+	dropZ := 0.0
+	service := NewAlertSettingsService(repo, urlRepo, testAlertsConfig)
+
+	err := service.SetAlertSettings(context.Background(), &models.AlertSettings{ShortPath: "abc", DropZ: &dropZ})
+
+	assert.ErrorIs(t, err, ErrInvalidAlertSettings)
+	repo.AssertNotCalled(t, "SaveAlertSettings")
+}
+
+func TestAlertSettingsService_Set(t *testing.T) {
+	repo := &repoMocks.TrafficAnomalyRepository{}
+	urlRepo := &repoMocks.URLRepository{}
+	minClicks := 0
+	settings := &models.AlertSettings{ShortPath: "abc", MinClicks: &minClicks, Enabled: true}
+	urlRepo.On("GetOriginalURL", context.Background(), "abc").Return(&models.URL{ShortPath: "abc"}, nil).Once()
+	repo.On("SaveAlertSettings", context.Background(), settings).Return(nil).Once()
+	service := NewAlertSettingsService(repo, urlRepo, testAlertsConfig)
+
+	err := service.SetAlertSettings(context.Background(), settings)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+)
+
+const (
+	defaultAuditLogLimit = 50
+	maxAuditLogLimit     = 500
+)
+
+var ErrInvalidAuditRange = errors.New("from must be before to")
+
+//go:generate mockery --name=AuditLogService --output=./mocks
+type AuditLogService interface {
+	// ListEntries returns the latest audit entries matching filter, its limit defaults to 50 and is at most 500.
+	ListEntries(ctx context.Context, filter models.AuditLogFilter) ([]models.AuditEntry, error)
+}
+
+type auditLogServiceImpl struct {
+	repo repositories.AuditLogRepository
+}
+
+func NewAuditLogService(repo repositories.AuditLogRepository) AuditLogService {
+	return &auditLogServiceImpl{repo: repo}
+}
+
+// ListEntries implements AuditLogService.
+func (s *auditLogServiceImpl) ListEntries(ctx context.Context, filter models.AuditLogFilter) ([]models.AuditEntry, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, ErrInvalidAuditRange
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLogLimit
+	}
+	filter.Limit = min(filter.Limit, maxAuditLogLimit)
+	filter.Before = max(filter.Before, 0)
+	return s.repo.ListEntries(ctx, filter)
+}
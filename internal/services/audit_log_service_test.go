@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+	repoMocks "url-shortener/internal/repositories/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLogService_ListEntriesClampsLimit(t *testing.T) {
+	repo := &repoMocks.AuditLogRepository{}
+	service := NewAuditLogService(repo)
+	repo.On("ListEntries", context.Background(), models.AuditLogFilter{Actor: "alice", Limit: defaultAuditLogLimit}).Return([]models.AuditEntry{}, nil).Once()
+	repo.On("ListEntries", context.Background(), models.AuditLogFilter{ShortPath: "abc", Limit: maxAuditLogLimit}).Return([]models.AuditEntry{}, nil).Once()
+
+	_, err := service.ListEntries(context.Background(), models.AuditLogFilter{Actor: "alice", Before: -1})
+	assert.NoError(t, err)
+	_, err = service.ListEntries(context.Background(), models.AuditLogFilter{ShortPath: "abc", Limit: 10000})
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestAuditLogService_ListEntriesRejectsInvalidRange(t *testing.T) {
+	repo := &repoMocks.AuditLogRepository{}
+	service := NewAuditLogService(repo)
+	from := time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+
+	_, err := service.ListEntries(context.Background(), models.AuditLogFilter{From: &from, To: &to})
+
+	assert.ErrorIs(t, err, ErrInvalidAuditRange)
+	repo.AssertNotCalled(t, "ListEntries")
+}
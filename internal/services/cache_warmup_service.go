@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/utils"
+)
+
+const (
+	defaultWarmupBatchSize = 500
+	defaultWarmupWindow    = 24 * time.Hour
+)
+
+var ErrCacheWarmupInProgress = errors.New("cache warm-up already in progress")
+
+//go:generate mockery --name=CacheWarmupService --output=./mocks
+type CacheWarmupService interface {
+	// WarmUp loads urls into the cache and blocks until done, calling onProgress after every batch.
+	WarmUp(ctx context.Context, opts models.CacheWarmupOptions, onProgress func(models.CacheWarmupProgress)) (*models.CacheWarmupProgress, error)
+	// Start runs a warm-up in the background.
+	Start(opts models.CacheWarmupOptions) error
+	// Status returns the progress of the current or last warm-up.
+	Status() models.CacheWarmupProgress
+}
+
+type cacheWarmupServiceImpl struct {
+	listingRepo  repositories.URLListingRepository
+	cacheRepo    repositories.URLCacheRepository
+	timeProvider utils.TimeProvider
+
+	mu     sync.Mutex
+	status models.CacheWarmupProgress
+}
+
+func NewCacheWarmupService(listingRepo repositories.URLListingRepository, cacheRepo repositories.URLCacheRepository, timeProvider utils.TimeProvider) CacheWarmupService {
+	return &cacheWarmupServiceImpl{listingRepo: listingRepo, cacheRepo: cacheRepo, timeProvider: timeProvider}
+}
+
+// WarmUp implements CacheWarmupService.
+func (s *cacheWarmupServiceImpl) WarmUp(ctx context.Context, opts models.CacheWarmupOptions, onProgress func(models.CacheWarmupProgress)) (*models.CacheWarmupProgress, error) {
+	if err := s.begin(); err != nil {
+		return nil, err
+	}
+	progress, err := s.run(ctx, opts, onProgress)
+	return &progress, err
+}
+
+// Start implements CacheWarmupService.
+func (s *cacheWarmupServiceImpl) Start(opts models.CacheWarmupOptions) error {
+	if err := s.begin(); err != nil {
+		return err
+	}
+	go func() {
+		progress, err := s.run(context.Background(), opts, nil)
+		if err != nil {
+			log.Printf("Cache warm-up failed: %v", err)
+			return
+		}
+		log.Printf("Cache warm-up finished: scanned=%d loaded=%d skipped=%d", progress.Scanned, progress.Loaded, progress.Skipped)
+	}()
+	return nil
+}
+
+// Status implements CacheWarmupService.
+func (s *cacheWarmupServiceImpl) Status() models.CacheWarmupProgress {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+func (s *cacheWarmupServiceImpl) begin() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status.Running {
+		return ErrCacheWarmupInProgress
+	}
+	startedAt := s.timeProvider.Now()
+	s.status = models.CacheWarmupProgress{Running: true, StartedAt: &startedAt}
+	return nil
+}
+
+func (s *cacheWarmupServiceImpl) run(ctx context.Context, opts models.CacheWarmupOptions, onProgress func(models.CacheWarmupProgress)) (models.CacheWarmupProgress, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultWarmupBatchSize
+	}
+	if opts.Window <= 0 {
+		opts.Window = defaultWarmupWindow
+	}
+	progress := s.Status()
+
+	load := func(batch []*models.URL) error {
+		loaded, err := s.cacheRepo.InsertShortURLs(ctx, batch)
+		if err != nil {
+			return err
+		}
+		progress.Scanned += int64(len(batch))
+		progress.Loaded += int64(loaded)
+		progress.Skipped += int64(len(batch) - loaded)
+		s.setStatus(progress)
+		if onProgress != nil {
+			onProgress(progress)
+		}
+		return s.throttle(ctx, *progress.StartedAt, progress.Scanned, opts.RatePerSecond)
+	}
+
+	var err error
+	if opts.TopN > 0 {
+		err = s.loadTopURLs(ctx, opts, load)
+	} else {
+		err = s.loadAllURLs(ctx, opts, load)
+	}
+
+	finishedAt := s.timeProvider.Now()
+	progress.Running = false
+	progress.FinishedAt = &finishedAt
+	if err != nil {
+		progress.Error = err.Error()
+	}
+	s.setStatus(progress)
+	return progress, err
+}
+
+func (s *cacheWarmupServiceImpl) loadTopURLs(ctx context.Context, opts models.CacheWarmupOptions, load func([]*models.URL) error) error {
+	urls, err := s.listingRepo.ListTopURLs(ctx, s.timeProvider.Now().Add(-opts.Window), opts.TopN)
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(urls); start += opts.BatchSize {
+		if err := load(urls[start:min(start+opts.BatchSize, len(urls))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *cacheWarmupServiceImpl) loadAllURLs(ctx context.Context, opts models.CacheWarmupOptions, load func([]*models.URL) error) error {
+	after := ""
+	for {
+		urls, err := s.listingRepo.ListURLs(ctx, after, opts.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(urls) == 0 {
+			return nil
+		}
+		if err := load(urls); err != nil {
+			return err
+		}
+		if len(urls) < opts.BatchSize {
+			return nil
+		}
+		after = urls[len(urls)-1].ShortPath
+	}
+}
+
+// throttle sleeps until the average rate since startedAt drops to ratePerSecond.
+func (s *cacheWarmupServiceImpl) throttle(ctx context.Context, startedAt time.Time, scanned int64, ratePerSecond int) error {
+	if ratePerSecond <= 0 {
+		return nil
+	}
+	due := startedAt.Add(time.Duration(scanned) * time.Second / time.Duration(ratePerSecond))
+	wait := due.Sub(s.timeProvider.Now())
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
+
+func (s *cacheWarmupServiceImpl) setStatus(progress models.CacheWarmupProgress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = progress
+}
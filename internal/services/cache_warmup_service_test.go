@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+	repoMocks "url-shortener/internal/repositories/mocks"
+	utilsMocks "url-shortener/internal/utils/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupCacheWarmupService() (*repoMocks.URLListingRepository, *repoMocks.URLCacheRepository, *utilsMocks.TimeProvider, CacheWarmupService) {
+	listingRepo := &repoMocks.URLListingRepository{}
+	cacheRepo := &repoMocks.URLCacheRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	timeProvider.On("Now").Return(time.Now())
+	return listingRepo, cacheRepo, timeProvider, NewCacheWarmupService(listingRepo, cacheRepo, timeProvider)
+}
+
+func TestCacheWarmupServiceImpl_WarmUp_AllURLs(t *testing.T) {
+	listingRepo, cacheRepo, _, service := setupCacheWarmupService()
+	ctx := context.Background()
+	firstPage := []*models.URL{{ShortPath: "a"}, {ShortPath: "b"}}
+	secondPage := []*models.URL{{ShortPath: "c"}}
+	listingRepo.On("ListURLs", ctx, "", 2).Return(firstPage, nil).Once()
+	listingRepo.On("ListURLs", ctx, "b", 2).Return(secondPage, nil).Once()
+	cacheRepo.On("InsertShortURLs", ctx, firstPage).Return(2, nil).Once()
+	cacheRepo.On("InsertShortURLs", ctx, secondPage).Return(0, nil).Once()
+
+	reports := 0
+	progress, err := service.WarmUp(ctx, models.CacheWarmupOptions{BatchSize: 2}, func(models.CacheWarmupProgress) { reports++ })
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, reports)
+	assert.False(t, progress.Running)
+	assert.Equal(t, int64(3), progress.Scanned)
+	assert.Equal(t, int64(2), progress.Loaded)
+	assert.Equal(t, int64(1), progress.Skipped)
+	assert.NotNil(t, progress.FinishedAt)
+	assert.Equal(t, *progress, service.Status())
+	listingRepo.AssertExpectations(t)
+	cacheRepo.AssertExpectations(t)
+}
+
+func TestCacheWarmupServiceImpl_WarmUp_TopURLs(t *testing.T) {
+	listingRepo, cacheRepo, _, service := setupCacheWarmupService()
+	ctx := context.Background()
+	urls := []*models.URL{{ShortPath: "a"}, {ShortPath: "b"}, {ShortPath: "c"}}
+	listingRepo.On("ListTopURLs", ctx, mock.Anything, 3).Return(urls, nil).Once()
+	cacheRepo.On("InsertShortURLs", ctx, urls[:2]).Return(2, nil).Once()
+	cacheRepo.On("InsertShortURLs", ctx, urls[2:]).Return(1, nil).Once()
+
+	progress, err := service.WarmUp(ctx, models.CacheWarmupOptions{TopN: 3, BatchSize: 2}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), progress.Loaded)
+	listingRepo.AssertExpectations(t)
+	cacheRepo.AssertExpectations(t)
+}
+
+func TestCacheWarmupServiceImpl_WarmUp_CacheError(t *testing.T) {
+	listingRepo, cacheRepo, _, service := setupCacheWarmupService()
+	ctx := context.Background()
+	urls := []*models.URL{{ShortPath: "a"}}
+	listingRepo.On("ListURLs", ctx, "", defaultWarmupBatchSize).Return(urls, nil).Once()
+	cacheRepo.On("InsertShortURLs", ctx, urls).Return(0, assert.AnError).Once()
+
+	progress, err := service.WarmUp(ctx, models.CacheWarmupOptions{}, nil)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.False(t, progress.Running)
+	assert.Equal(t, assert.AnError.Error(), progress.Error)
+}
+
+func TestCacheWarmupServiceImpl_Start_AlreadyRunning(t *testing.T) {
+	listingRepo, cacheRepo, _, service := setupCacheWarmupService()
+	release := make(chan struct{})
+	listingRepo.On("ListURLs", mock.Anything, "", defaultWarmupBatchSize).Run(func(mock.Arguments) { <-release }).Return([]*models.URL{}, nil).Once()
+
+	assert.NoError(t, service.Start(models.CacheWarmupOptions{}))
+	assert.ErrorIs(t, service.Start(models.CacheWarmupOptions{}), ErrCacheWarmupInProgress)
+	close(release)
+
+	assert.Eventually(t, func() bool { return !service.Status().Running }, time.Second, 10*time.Millisecond)
+	listingRepo.AssertExpectations(t)
+	cacheRepo.AssertExpectations(t)
+}
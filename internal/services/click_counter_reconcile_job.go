@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/utils"
+)
+
+// clickCounterWindow is how far back the Redis click counters are read, and so how far back they are reconciled.
+// The minute counters are only read for the hour the last 24 hours start in, so only the last minuteCounterWindow
+// of them is reconciled.
+const (
+	clickCounterWindow  = 7 * 24 * time.Hour
+	minuteCounterWindow = 24 * time.Hour
+)
+
+// ClickCounterReconcileJob overwrites the hourly Redis click counters with the rolled up counts from Postgres once
+// an hour is rolled up, and the minute counters of the hour with its raw clicks. That corrects clicks counted in
+// Redis but dropped from the ingestion queue, and restores counters lost while Redis was unavailable. Links with a
+// counter but no rolled up clicks are left alone. Runs continue from the click_counters watermark in Postgres, unless
+// Redis lost its data since the last run, then the whole window is reconciled again.
+type ClickCounterReconcileJob struct {
+	rollups      repositories.ClickRollupRepository
+	counters     repositories.ClickCounterRepository
+	config       config.CountersConfig
+	timeProvider utils.TimeProvider
+}
+
+func NewClickCounterReconcileJob(rollups repositories.ClickRollupRepository, counters repositories.ClickCounterRepository, countersConfig config.CountersConfig, timeProvider utils.TimeProvider) *ClickCounterReconcileJob {
+	return &ClickCounterReconcileJob{rollups: rollups, counters: counters, config: countersConfig, timeProvider: timeProvider}
+}
+
+// Name implements Job.
+func (j *ClickCounterReconcileJob) Name() string {
+	return "click-counter-reconcile"
+}
+
+// Run implements Job. It reads at most MaxSpan of rollups at a time up to the rollup watermark.
+func (j *ClickCounterReconcileJob) Run(ctx context.Context) error {
+	watermark, err := j.rollups.Watermark(ctx)
+	if err != nil || watermark.IsZero() {
+		return err
+	}
+	reconciled, err := j.counters.Reconciled(ctx)
+	if err != nil {
+		return err
+	}
+	now := j.timeProvider.Now().UTC()
+	from := now.Add(-clickCounterWindow).Truncate(time.Hour)
+	if reconciled {
+		reconciledTo, err := j.rollups.CounterWatermark(ctx)
+		if err != nil {
+			return err
+		}
+		if reconciledTo.After(from) {
+			from = reconciledTo
+		}
+	}
+	for from.Before(watermark) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		to := watermark
+		if j.config.MaxSpan > 0 && from.Add(j.config.MaxSpan).Before(to) {
+			to = from.Add(j.config.MaxSpan)
+		}
+		clicks, err := j.rollups.HourlyClicks(ctx, from, to)
+		if err != nil {
+			return err
+		}
+		if err := j.counters.SetHourlyClicks(ctx, clicks, now); err != nil {
+			return err
+		}
+		if err := j.reconcileMinutes(ctx, from, to, now); err != nil {
+			return err
+		}
+		if err := j.rollups.SetCounterWatermark(ctx, to); err != nil {
+			return err
+		}
+		from = to
+	}
+	if reconciled {
+		return nil
+	}
+	return j.counters.MarkReconciled(ctx)
+}
+
+// reconcileMinutes overwrites the minute counters in [from, to) that are still read.
+func (j *ClickCounterReconcileJob) reconcileMinutes(ctx context.Context, from time.Time, to time.Time, now time.Time) error {
+	if minutesFrom := now.Add(-minuteCounterWindow).Truncate(time.Minute); from.Before(minutesFrom) {
+		from = minutesFrom
+	}
+	if !from.Before(to) {
+		return nil
+	}
+	minutes, err := j.rollups.MinuteClicks(ctx, from, to)
+	if err != nil {
+		return err
+	}
+	return j.counters.SetMinuteClicks(ctx, minutes, now)
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	repoMocks "url-shortener/internal/repositories/mocks"
+	utilsMocks "url-shortener/internal/utils/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClickCounterReconcileJob_CatchesUpThenContinues(t *testing.T) {
+	rollups := &repoMocks.ClickRollupRepository{}
+	counters := &repoMocks.ClickCounterRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	now := time.Date(2025, 6, 12, 10, 30, 0, 0, time.UTC)
+	watermark := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	weekStart := time.Date(2025, 6, 5, 10, 0, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now).Twice()
+	rollups.On("Watermark", ctx).Return(watermark, nil).Once()
+	// Redis has never been reconciled, so the whole week is, three days at a time.
+	counters.On("Reconciled", ctx).Return(false, nil).Once()
+	clicks := []models.HourlyClicks{{ShortPath: "path1", Bucket: weekStart, Clicks: 3}}
+	rollups.On("HourlyClicks", ctx, weekStart, weekStart.Add(72*time.Hour)).Return(clicks, nil).Once()
+	rollups.On("HourlyClicks", ctx, weekStart.Add(72*time.Hour), weekStart.Add(144*time.Hour)).Return([]models.HourlyClicks{}, nil).Once()
+	rollups.On("HourlyClicks", ctx, weekStart.Add(144*time.Hour), watermark).Return([]models.HourlyClicks{}, nil).Once()
+	counters.On("SetHourlyClicks", ctx, clicks, now).Return(nil).Once()
+	counters.On("SetHourlyClicks", ctx, []models.HourlyClicks{}, now).Return(nil).Twice()
+	// Only the minutes of the last 24 hours are still read.
+	minutes := []models.MinuteClicks{{ShortPath: "path1", Bucket: now.Add(-23 * time.Hour), Clicks: 2}}
+	rollups.On("MinuteClicks", ctx, now.Add(-24*time.Hour), watermark).Return(minutes, nil).Once()
+	counters.On("SetMinuteClicks", ctx, minutes, now).Return(nil).Once()
+	rollups.On("SetCounterWatermark", ctx, weekStart.Add(72*time.Hour)).Return(nil).Once()
+	rollups.On("SetCounterWatermark", ctx, weekStart.Add(144*time.Hour)).Return(nil).Once()
+	rollups.On("SetCounterWatermark", ctx, watermark).Return(nil).Once()
+	counters.On("MarkReconciled", ctx).Return(nil).Once()
+	job := NewClickCounterReconcileJob(rollups, counters, config.CountersConfig{MaxSpan: 72 * time.Hour}, timeProvider)
+
+	assert.NoError(t, job.Run(ctx))
+
+	// The next run, of this or any other instance, only reads the hour rolled up since.
+	rollups.On("Watermark", ctx).Return(watermark.Add(time.Hour), nil).Once()
+	counters.On("Reconciled", ctx).Return(true, nil).Once()
+	rollups.On("CounterWatermark", ctx).Return(watermark, nil).Once()
+	rollups.On("SetCounterWatermark", ctx, watermark.Add(time.Hour)).Return(nil).Once()
+	rollups.On("HourlyClicks", ctx, watermark, watermark.Add(time.Hour)).Return([]models.HourlyClicks{}, nil).Once()
+	counters.On("SetHourlyClicks", ctx, []models.HourlyClicks{}, now).Return(nil).Once()
+	rollups.On("MinuteClicks", ctx, watermark, watermark.Add(time.Hour)).Return([]models.MinuteClicks{}, nil).Once()
+	counters.On("SetMinuteClicks", ctx, []models.MinuteClicks{}, now).Return(nil).Once()
+
+	assert.NoError(t, job.Run(ctx))
+	rollups.AssertExpectations(t)
+	counters.AssertExpectations(t)
+}
+
+func TestClickCounterReconcileJob_NothingRolledUp(t *testing.T) {
+	rollups := &repoMocks.ClickRollupRepository{}
+	rollups.On("Watermark", context.Background()).Return(time.Time{}, nil).Once()
+	job := NewClickCounterReconcileJob(rollups, &repoMocks.ClickCounterRepository{}, config.CountersConfig{}, &utilsMocks.TimeProvider{})
+
+	assert.NoError(t, job.Run(context.Background()))
+	rollups.AssertExpectations(t)
+}
+
+func TestClickCounterReconcileJob_RetriesAfterError(t *testing.T) {
+	rollups := &repoMocks.ClickRollupRepository{}
+	counters := &repoMocks.ClickCounterRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	now := time.Date(2025, 6, 12, 10, 30, 0, 0, time.UTC)
+	watermark := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	weekStart := time.Date(2025, 6, 5, 10, 0, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now)
+	rollups.On("Watermark", ctx).Return(watermark, nil)
+	counters.On("Reconciled", ctx).Return(false, nil).Twice()
+	rollups.On("HourlyClicks", ctx, weekStart, watermark).Return([]models.HourlyClicks{}, nil).Twice()
+	counters.On("SetHourlyClicks", ctx, []models.HourlyClicks{}, now).Return(assert.AnError).Once()
+	counters.On("SetHourlyClicks", ctx, []models.HourlyClicks{}, now).Return(nil).Once()
+	rollups.On("MinuteClicks", ctx, now.Add(-24*time.Hour), watermark).Return([]models.MinuteClicks{}, nil).Once()
+	counters.On("SetMinuteClicks", ctx, []models.MinuteClicks{}, now).Return(nil).Once()
+	rollups.On("SetCounterWatermark", ctx, watermark).Return(nil).Once()
+	counters.On("MarkReconciled", ctx).Return(nil).Once()
+	job := NewClickCounterReconcileJob(rollups, counters, config.CountersConfig{}, timeProvider)
+
+	assert.ErrorIs(t, job.Run(ctx), assert.AnError)
+	assert.NoError(t, job.Run(ctx))
+	rollups.AssertExpectations(t)
+	counters.AssertExpectations(t)
+}
+
+func TestClickCounterReconcileJob_RedisLostCounters(t *testing.T) {
+	rollups := &repoMocks.ClickRollupRepository{}
+	counters := &repoMocks.ClickCounterRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	now := time.Date(2025, 6, 12, 10, 30, 0, 0, time.UTC)
+	watermark := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	weekStart := time.Date(2025, 6, 5, 10, 0, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now).Once()
+	rollups.On("Watermark", ctx).Return(watermark, nil).Once()
+	// The marker is gone, so the hours before the click_counters watermark are rebuilt too.
+	counters.On("Reconciled", ctx).Return(false, nil).Once()
+	rollups.On("HourlyClicks", ctx, weekStart, watermark).Return([]models.HourlyClicks{}, nil).Once()
+	counters.On("SetHourlyClicks", ctx, []models.HourlyClicks{}, now).Return(nil).Once()
+	rollups.On("MinuteClicks", ctx, now.Add(-24*time.Hour), watermark).Return([]models.MinuteClicks{}, nil).Once()
+	counters.On("SetMinuteClicks", ctx, []models.MinuteClicks{}, now).Return(nil).Once()
+	rollups.On("SetCounterWatermark", ctx, watermark).Return(nil).Once()
+	counters.On("MarkReconciled", ctx).Return(nil).Once()
+	job := NewClickCounterReconcileJob(rollups, counters, config.CountersConfig{}, timeProvider)
+
+	assert.NoError(t, job.Run(ctx))
+	rollups.AssertNotCalled(t, "CounterWatermark", ctx)
+	rollups.AssertExpectations(t)
+	counters.AssertExpectations(t)
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/utils"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatJSONL   = "jsonl"
+	ExportFormatParquet = "parquet"
+
+	defaultExportPageSize = 5000
+)
+
+var (
+	ErrInvalidExportFormat = errors.New("format must be csv, jsonl or parquet")
+	ErrInvalidExportRange  = errors.New("from must be before to")
+)
+
+var clickEventCSVHeader = []string{"id", "short_path", "accessed_at", "referrer_host", "browser", "os", "device_class",
+	"client_ip", "language", "query_string", "country", "region", "city", "is_bot", "is_duplicate", "click_id"}
+
+//go:generate mockery --name=ClickExportService --output=./mocks
+type ClickExportService interface {
+	// Export writes the clicks matching filter to w in format, one of the ExportFormat values, oldest first, and
+	// returns how many were written. A zero To exports up to now. Clicks are read a page at a time, so w sees the
+	// first rows before the last ones are read.
+	Export(ctx context.Context, filter models.ClickEventFilter, format string, w io.Writer) (int, error)
+}
+
+type clickExportServiceImpl struct {
+	repo         repositories.ClickEventRepository
+	timeProvider utils.TimeProvider
+	pageSize     int
+}
+
+func NewClickExportService(repo repositories.ClickEventRepository, timeProvider utils.TimeProvider, pageSize int) ClickExportService {
+	if pageSize <= 0 {
+		pageSize = defaultExportPageSize
+	}
+	return &clickExportServiceImpl{repo: repo, timeProvider: timeProvider, pageSize: pageSize}
+}
+
+// ExportContentType returns the MIME type and file extension of an export format.
+func ExportContentType(format string) (string, string, error) {
+	switch format {
+	case ExportFormatCSV:
+		return "text/csv", "csv", nil
+	case ExportFormatJSONL:
+		return "application/x-ndjson", "jsonl", nil
+	case ExportFormatParquet:
+		return "application/vnd.apache.parquet", "parquet", nil
+	}
+	return "", "", ErrInvalidExportFormat
+}
+
+// Export implements ClickExportService. Pages are keyset paginated on (accessed_at, id), so every page is a short
+// query however large the export is, and clicks arriving meanwhile are picked up if they are still in range.
+func (s *clickExportServiceImpl) Export(ctx context.Context, filter models.ClickEventFilter, format string, w io.Writer) (int, error) {
+	if filter.To.IsZero() {
+		filter.To = s.timeProvider.Now()
+	}
+	if !filter.From.Before(filter.To) {
+		return 0, ErrInvalidExportRange
+	}
+	encoder, err := newClickEventEncoder(format, w)
+	if err != nil {
+		return 0, err
+	}
+
+	exported := 0
+	var after models.ClickEventCursor
+	for {
+		events, err := s.repo.ListClickEvents(ctx, filter, after, s.pageSize)
+		if err != nil {
+			return exported, err
+		}
+		if err := encoder.Write(events); err != nil {
+			return exported, err
+		}
+		exported += len(events)
+		if len(events) < s.pageSize {
+			break
+		}
+		last := events[len(events)-1]
+		after = models.ClickEventCursor{AccessedAt: last.AccessedAt, ID: last.ID}
+	}
+	return exported, encoder.Close()
+}
+
+// clickEventEncoder writes pages of clicks in one export format.
+type clickEventEncoder interface {
+	Write(events []models.ClickEvent) error
+	// Close writes whatever the format needs after the last row, it does not close the underlying writer.
+	Close() error
+}
+
+func newClickEventEncoder(format string, w io.Writer) (clickEventEncoder, error) {
+	switch format {
+	case ExportFormatCSV:
+		encoder := &csvClickEventEncoder{writer: csv.NewWriter(w)}
+		return encoder, encoder.writeRecord(clickEventCSVHeader)
+	case ExportFormatJSONL:
+		return &jsonlClickEventEncoder{encoder: json.NewEncoder(w)}, nil
+	case ExportFormatParquet:
+		return &parquetClickEventEncoder{writer: parquet.NewGenericWriter[models.ClickEvent](w)}, nil
+	}
+	return nil, ErrInvalidExportFormat
+}
+
+type csvClickEventEncoder struct {
+	writer *csv.Writer
+}
+
+func (e *csvClickEventEncoder) Write(events []models.ClickEvent) error {
+	for _, event := range events {
+		record := []string{strconv.FormatInt(event.ID, 10), event.ShortPath, event.AccessedAt.UTC().Format(time.RFC3339Nano),
+			event.ReferrerHost, event.Browser, event.OS, event.DeviceClass, event.ClientIP, event.Language, event.QueryString,
+			event.Country, event.Region, event.City, strconv.FormatBool(event.IsBot), strconv.FormatBool(event.IsDuplicate),
+			event.ClickID}
+		if err := e.writer.Write(record); err != nil {
+			return err
+		}
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvClickEventEncoder) writeRecord(record []string) error {
+	if err := e.writer.Write(record); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvClickEventEncoder) Close() error {
+	return nil
+}
+
+type jsonlClickEventEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *jsonlClickEventEncoder) Write(events []models.ClickEvent) error {
+	for _, event := range events {
+		if err := e.encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *jsonlClickEventEncoder) Close() error {
+	return nil
+}
+
+// parquetClickEventEncoder writes every page as its own row group, so memory use is bounded by the page size.
+type parquetClickEventEncoder struct {
+	writer *parquet.GenericWriter[models.ClickEvent]
+}
+
+func (e *parquetClickEventEncoder) Write(events []models.ClickEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if _, err := e.writer.Write(events); err != nil {
+		return err
+	}
+	return e.writer.Flush()
+}
+
+func (e *parquetClickEventEncoder) Close() error {
+	return e.writer.Close()
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+	repoMocks "url-shortener/internal/repositories/mocks"
+	utilsMocks "url-shortener/internal/utils/mocks"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+)
+
+var exportNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func setupClickExportService(pageSize int) (*repoMocks.ClickEventRepository, ClickExportService) {
+	repo := &repoMocks.ClickEventRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	timeProvider.On("Now").Return(exportNow)
+	return repo, NewClickExportService(repo, timeProvider, pageSize)
+}
+
+func clickEvents(ids ...int64) []models.ClickEvent {
+	events := make([]models.ClickEvent, len(ids))
+	for i, id := range ids {
+		events[i] = models.ClickEvent{ID: id, ShortPath: "shortPath", AccessedAt: exportNow.Add(-time.Duration(10-id) * time.Minute), Browser: "Firefox"}
+	}
+	return events
+}
+
+func TestClickExportServiceImpl_Export_PaginatesByKeyset(t *testing.T) {
+	repo, service := setupClickExportService(2)
+	ctx := context.Background()
+	filter := models.ClickEventFilter{ShortPath: "shortPath", To: exportNow}
+	firstPage, secondPage := clickEvents(1, 2), clickEvents(3)
+	repo.On("ListClickEvents", ctx, filter, models.ClickEventCursor{}, 2).Return(firstPage, nil).Once()
+	repo.On("ListClickEvents", ctx, filter, models.ClickEventCursor{AccessedAt: firstPage[1].AccessedAt, ID: 2}, 2).Return(secondPage, nil).Once()
+
+	var out bytes.Buffer
+	exported, err := service.Export(ctx, models.ClickEventFilter{ShortPath: "shortPath"}, ExportFormatJSONL, &out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, exported)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 3)
+	var event models.ClickEvent
+	assert.NoError(t, json.Unmarshal([]byte(lines[2]), &event))
+	assert.Equal(t, int64(3), event.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestClickExportServiceImpl_Export_CSV(t *testing.T) {
+	repo, service := setupClickExportService(10)
+	events := clickEvents(1)
+	events[0].QueryString = "a=1,b=2"
+	events[0].IsDuplicate = true
+	events[0].ClickID = "clk_123"
+	repo.On("ListClickEvents", context.Background(), models.ClickEventFilter{To: exportNow}, models.ClickEventCursor{}, 10).Return(events, nil).Once()
+
+	var out bytes.Buffer
+	_, err := service.Export(context.Background(), models.ClickEventFilter{}, ExportFormatCSV, &out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "id,short_path,accessed_at,referrer_host,browser,os,device_class,client_ip,language,query_string,country,region,city,is_bot,is_duplicate,click_id\n"+
+		"1,shortPath,2024-05-01T11:51:00Z,,Firefox,,,,,\"a=1,b=2\",,,,false,true,clk_123\n", out.String())
+}
+
+func TestClickExportServiceImpl_Export_Parquet(t *testing.T) {
+	repo, service := setupClickExportService(2)
+	ctx := context.Background()
+	filter := models.ClickEventFilter{To: exportNow}
+	firstPage := clickEvents(1, 2)
+	firstPage[0].ClickID = "clk_123"
+	repo.On("ListClickEvents", ctx, filter, models.ClickEventCursor{}, 2).Return(firstPage, nil).Once()
+	repo.On("ListClickEvents", ctx, filter, models.ClickEventCursor{AccessedAt: firstPage[1].AccessedAt, ID: 2}, 2).Return([]models.ClickEvent{}, nil).Once()
+
+	var out bytes.Buffer
+	_, err := service.Export(ctx, filter, ExportFormatParquet, &out)
+
+	assert.NoError(t, err)
+	rows, err := parquet.Read[models.ClickEvent](bytes.NewReader(out.Bytes()), int64(out.Len()))
+	assert.NoError(t, err)
+	assert.Equal(t, firstPage, rows)
+}
+
+func TestClickExportServiceImpl_Export_InvalidRequest(t *testing.T) {
+	repo, service := setupClickExportService(10)
+
+	_, err := service.Export(context.Background(), models.ClickEventFilter{}, "xml", &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrInvalidExportFormat)
+
+	_, err = service.Export(context.Background(), models.ClickEventFilter{From: exportNow}, ExportFormatCSV, &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrInvalidExportRange)
+	repo.AssertNotCalled(t, "ListClickEvents")
+}
+
+func TestClickExportServiceImpl_Export_RepositoryError(t *testing.T) {
+	repo, service := setupClickExportService(2)
+	ctx := context.Background()
+	filter := models.ClickEventFilter{To: exportNow}
+	firstPage := clickEvents(1, 2)
+	repo.On("ListClickEvents", ctx, filter, models.ClickEventCursor{}, 2).Return(firstPage, nil).Once()
+	repo.On("ListClickEvents", ctx, filter, models.ClickEventCursor{AccessedAt: firstPage[1].AccessedAt, ID: 2}, 2).Return(nil, assert.AnError).Once()
+
+	exported, err := service.Export(ctx, filter, ExportFormatJSONL, &bytes.Buffer{})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 2, exported)
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	repoMocks "url-shortener/internal/repositories/mocks"
+	utilsMocks "url-shortener/internal/utils/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClickRollupJob_CatchesUpSpanBySpan(t *testing.T) {
+	repo := &repoMocks.ClickRollupRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	timeProvider.On("Now").Return(time.Date(2025, 6, 12, 10, 3, 0, 0, time.UTC)).Once()
+	// Ten o'clock is not rolled up yet because it ended less than the lateness ago.
+	until := time.Date(2025, 6, 12, 9, 0, 0, 0, time.UTC)
+	repo.On("RollUp", context.Background(), until, 24*time.Hour).Return(until.Add(-time.Hour), nil).Once()
+	repo.On("RollUp", context.Background(), until, 24*time.Hour).Return(until, nil).Once()
+	job := NewClickRollupJob(repo, config.RollupsConfig{Lateness: 5 * time.Minute, MaxSpan: 24 * time.Hour}, timeProvider)
+
+	err := job.Run(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestClickRollupJob_Error(t *testing.T) {
+	repo := &repoMocks.ClickRollupRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	timeProvider.On("Now").Return(time.Date(2025, 6, 12, 10, 30, 0, 0, time.UTC)).Once()
+	repo.On("RollUp", context.Background(), time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC), time.Duration(0)).Return(time.Time{}, assert.AnError).Once()
+	job := NewClickRollupJob(repo, config.RollupsConfig{Lateness: 5 * time.Minute}, timeProvider)
+
+	err := job.Run(context.Background())
+
+	assert.ErrorIs(t, err, assert.AnError)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/utils"
+)
+
+const (
+	clickStreamPublishBatch   = 100
+	clickStreamPublishTimeout = 5 * time.Second
+)
+
+var (
+	ErrTooManyClickStreams = errors.New("too many live click streams")
+	ErrClickStreamsClosed  = errors.New("live click streams are shutting down")
+)
+
+// LinkClickStream and OwnerClickStream name the streams a click is published to.
+func LinkClickStream(shortPath string) string {
+	return "link:" + shortPath
+}
+
+func OwnerClickStream(owner string) string {
+	return "owner:" + owner
+}
+
+//go:generate mockery --name=ClickStreamPublisher --output=./mocks
+type ClickStreamPublisher interface {
+	// Publish queues a click for the live streams of its link and owner. It never blocks, clicks are dropped from
+	// the streams while the queue is full.
+	Publish(event models.ClickStreamEvent)
+}
+
+//go:generate mockery --name=ClickStreamService --output=./mocks
+type ClickStreamService interface {
+	ClickStreamPublisher
+	// Watch subscribes to stream. With lastEventID set, the events kept since that one are replayed first, or all
+	// kept events when it is too old. clientKey identifies the client for the per client connection limit.
+	Watch(ctx context.Context, stream string, clientKey string, lastEventID string) (*models.ClickStreamSubscription, error)
+}
+
+type clickStreamSubscriber struct {
+	stream    string
+	clientKey string
+	events    chan models.ClickStreamEvent
+}
+
+// ClickStreamHub publishes clicks from a bounded queue and fans the clicks of every replica out to the streams
+// open on this one. A replica listens to a stream through Redis only while it has subscribers for it.
+type ClickStreamHub struct {
+	repo   repositories.ClickStreamRepository
+	geoIP  utils.GeoIPResolver
+	config config.StreamsConfig
+	queue  chan models.ClickStreamEvent
+	wg     sync.WaitGroup
+
+	// queueMu guards queueClosed so Publish never sends on the queue after Shutdown has closed it.
+	queueMu     sync.RWMutex
+	queueClosed bool
+
+	// mu guards the subscribers, and serializes subscribing and unsubscribing so a stream is never left
+	// unsubscribed while it has subscribers.
+	mu          sync.Mutex
+	started     bool
+	streams     map[string]map[*clickStreamSubscriber]struct{}
+	clients     map[string]int
+	connections int
+	closed      bool
+
+	dropped atomic.Int64
+}
+
+func NewClickStreamHub(repo repositories.ClickStreamRepository, geoIP utils.GeoIPResolver, streamsConfig config.StreamsConfig) *ClickStreamHub {
+	if streamsConfig.BufferSize <= 0 {
+		streamsConfig.BufferSize = 1
+	}
+	return &ClickStreamHub{
+		repo:    repo,
+		geoIP:   geoIP,
+		config:  streamsConfig,
+		queue:   make(chan models.ClickStreamEvent, streamsConfig.QueueSize),
+		streams: map[string]map[*clickStreamSubscriber]struct{}{},
+		clients: map[string]int{},
+	}
+}
+
+// Start launches the publisher and starts receiving the events of other replicas.
+func (h *ClickStreamHub) Start() {
+	h.mu.Lock()
+	h.started = true
+	h.mu.Unlock()
+	h.wg.Add(2)
+	go h.publish()
+	go h.dispatch()
+}
+
+// Publish implements ClickStreamPublisher.
+func (h *ClickStreamHub) Publish(event models.ClickStreamEvent) {
+	h.queueMu.RLock()
+	defer h.queueMu.RUnlock()
+	if !h.queueClosed {
+		select {
+		case h.queue <- event:
+			return
+		default:
+		}
+	}
+	// Log the first drop and then every thousandth so a saturated queue does not flood the logs.
+	if dropped := h.dropped.Add(1); dropped%1000 == 1 {
+		log.Printf("Click stream queue full, %d clicks dropped so far", dropped)
+	}
+}
+
+// Watch implements ClickStreamService.
+func (h *ClickStreamHub) Watch(ctx context.Context, stream string, clientKey string, lastEventID string) (*models.ClickStreamSubscription, error) {
+	subscriber, err := h.add(ctx, stream, clientKey)
+	if err != nil {
+		return nil, err
+	}
+	var once sync.Once
+	subscription := &models.ClickStreamSubscription{
+		Events: subscriber.events,
+		Close:  func() { once.Do(func() { h.remove(subscriber) }) },
+	}
+	if lastEventID == "" {
+		return subscription, nil
+	}
+
+	// The subscriber is registered first, so events published while the history is read are not missed.
+	recent, err := h.repo.Recent(ctx, stream)
+	if err != nil {
+		// The client still gets the live events.
+		return subscription, nil
+	}
+	subscription.Replay = recent
+	for i, event := range recent {
+		if event.ID == lastEventID {
+			subscription.Replay = recent[i+1:]
+			break
+		}
+	}
+	return subscription, nil
+}
+
+// CloseStreams ends every open stream and refuses new ones, so long lived connections do not hold up the shutdown
+// of the HTTP server.
+func (h *ClickStreamHub) CloseStreams() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for _, subscribers := range h.streams {
+		for subscriber := range subscribers {
+			close(subscriber.events)
+		}
+	}
+	h.streams = map[string]map[*clickStreamSubscriber]struct{}{}
+	h.clients = map[string]int{}
+	h.connections = 0
+}
+
+// Shutdown closes the streams, publishes the clicks still queued and closes the Redis subscriptions.
+func (h *ClickStreamHub) Shutdown(ctx context.Context) error {
+	h.CloseStreams()
+	h.queueMu.Lock()
+	if !h.queueClosed {
+		h.queueClosed = true
+		close(h.queue)
+	}
+	h.queueMu.Unlock()
+	if err := h.repo.Close(); err != nil {
+		log.Printf("Error closing click stream subscriptions: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *ClickStreamHub) add(ctx context.Context, stream string, clientKey string) (*clickStreamSubscriber, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed || !h.started {
+		return nil, ErrClickStreamsClosed
+	}
+	if h.config.MaxConnections > 0 && h.connections >= h.config.MaxConnections {
+		return nil, ErrTooManyClickStreams
+	}
+	if h.config.MaxConnectionsPerClient > 0 && h.clients[clientKey] >= h.config.MaxConnectionsPerClient {
+		return nil, ErrTooManyClickStreams
+	}
+	subscribers, ok := h.streams[stream]
+	if !ok {
+		if err := h.repo.Subscribe(ctx, stream); err != nil {
+			return nil, err
+		}
+		subscribers = map[*clickStreamSubscriber]struct{}{}
+		h.streams[stream] = subscribers
+	}
+	subscriber := &clickStreamSubscriber{stream: stream, clientKey: clientKey, events: make(chan models.ClickStreamEvent, h.config.BufferSize)}
+	subscribers[subscriber] = struct{}{}
+	h.connections++
+	h.clients[clientKey]++
+	return subscriber, nil
+}
+
+func (h *ClickStreamHub) remove(subscriber *clickStreamSubscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(subscriber)
+}
+
+func (h *ClickStreamHub) removeLocked(subscriber *clickStreamSubscriber) {
+	subscribers := h.streams[subscriber.stream]
+	if _, ok := subscribers[subscriber]; !ok {
+		return
+	}
+	delete(subscribers, subscriber)
+	close(subscriber.events)
+	h.connections--
+	if h.clients[subscriber.clientKey]--; h.clients[subscriber.clientKey] <= 0 {
+		delete(h.clients, subscriber.clientKey)
+	}
+	if len(subscribers) == 0 {
+		delete(h.streams, subscriber.stream)
+		// The repository logs the error, at worst this replica keeps receiving events it ignores.
+		_ = h.repo.Unsubscribe(context.Background(), subscriber.stream)
+	}
+}
+
+// dispatch hands every received event to the subscribers of its stream. A subscriber whose buffer is full is
+// dropped rather than slowing down everyone else.
+func (h *ClickStreamHub) dispatch() {
+	defer h.wg.Done()
+	for message := range h.repo.Messages() {
+		h.mu.Lock()
+		for subscriber := range h.streams[message.Stream] {
+			select {
+			case subscriber.events <- message.Event:
+			default:
+				h.removeLocked(subscriber)
+			}
+		}
+		h.mu.Unlock()
+	}
+}
+
+// publish sends queued clicks to Redis, taking whatever else is already queued along in the same round trip.
+func (h *ClickStreamHub) publish() {
+	defer h.wg.Done()
+	for event := range h.queue {
+		messages := h.messages(nil, event)
+	drain:
+		for len(messages) < clickStreamPublishBatch {
+			select {
+			case event, ok := <-h.queue:
+				if !ok {
+					break drain
+				}
+				messages = h.messages(messages, event)
+			default:
+				break drain
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), clickStreamPublishTimeout)
+		// The repository logs the error, the clicks themselves are stored by the access log pipeline.
+		_ = h.repo.Publish(ctx, messages)
+		cancel()
+	}
+}
+
+// messages appends the messages of event for its link and owner streams.
+func (h *ClickStreamHub) messages(messages []models.ClickStreamMessage, event models.ClickStreamEvent) []models.ClickStreamMessage {
+	event.ID = fmt.Sprintf("%d-%08x", event.AccessedAt.UnixMilli(), rand.Uint32())
+	if event.ClientIP != "" {
+		location := h.geoIP.Lookup(event.ClientIP)
+		event.Country = location.Country
+		event.Region = location.Region
+		event.City = location.City
+		if event.DoNotTrack {
+			event.Region, event.City = "", ""
+		}
+	}
+	messages = append(messages, models.ClickStreamMessage{Stream: LinkClickStream(event.ShortPath), Event: event})
+	if event.Owner != "" {
+		messages = append(messages, models.ClickStreamMessage{Stream: OwnerClickStream(event.Owner), Event: event})
+	}
+	return messages
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	repoMocks "url-shortener/internal/repositories/mocks"
+	"url-shortener/internal/utils"
+	utilsMocks "url-shortener/internal/utils/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupClickStreamHub(streamsConfig config.StreamsConfig) (*repoMocks.ClickStreamRepository, chan models.ClickStreamMessage, *utilsMocks.GeoIPResolver, *ClickStreamHub) {
+	repo := &repoMocks.ClickStreamRepository{}
+	messages := make(chan models.ClickStreamMessage)
+	repo.On("Messages").Return((<-chan models.ClickStreamMessage)(messages))
+	repo.On("Close").Run(func(mock.Arguments) { close(messages) }).Return(nil).Once()
+	geoIP := &utilsMocks.GeoIPResolver{}
+	hub := NewClickStreamHub(repo, geoIP, streamsConfig)
+	hub.Start()
+	return repo, messages, geoIP, hub
+}
+
+func TestClickStreamHub_PublishesToLinkAndOwner(t *testing.T) {
+	repo, _, geoIP, hub := setupClickStreamHub(config.StreamsConfig{QueueSize: 10})
+	geoIP.On("Lookup", "203.0.113.7").Return(utils.GeoLocation{Country: "DE"}).Once()
+	var published []models.ClickStreamMessage
+	repo.On("Publish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		published = args.Get(1).([]models.ClickStreamMessage)
+	}).Return(nil).Once()
+
+	hub.Publish(models.ClickStreamEvent{ShortPath: "shortPath", Owner: "alice", AccessedAt: time.Now(), ClientIP: "203.0.113.7"})
+
+	assert.NoError(t, hub.Shutdown(context.Background()))
+	assert.Len(t, published, 2)
+	assert.Equal(t, LinkClickStream("shortPath"), published[0].Stream)
+	assert.Equal(t, OwnerClickStream("alice"), published[1].Stream)
+	assert.NotEmpty(t, published[0].Event.ID)
+	assert.Equal(t, published[0].Event, published[1].Event)
+	assert.Equal(t, "DE", published[0].Event.Country)
+	repo.AssertExpectations(t)
+}
+
+func TestClickStreamHub_WatchReceivesEventsOfStream(t *testing.T) {
+	repo, messages, _, hub := setupClickStreamHub(config.StreamsConfig{BufferSize: 10})
+	stream := LinkClickStream("shortPath")
+	repo.On("Subscribe", mock.Anything, stream).Return(nil).Once()
+	repo.On("Unsubscribe", mock.Anything, stream).Return(nil).Once()
+
+	subscription, err := hub.Watch(context.Background(), stream, "client", "")
+	assert.NoError(t, err)
+	messages <- models.ClickStreamMessage{Stream: LinkClickStream("other"), Event: models.ClickStreamEvent{ID: "0"}}
+	messages <- models.ClickStreamMessage{Stream: stream, Event: models.ClickStreamEvent{ID: "1"}}
+
+	assert.Equal(t, "1", (<-subscription.Events).ID)
+	subscription.Close()
+	subscription.Close()
+	assert.NoError(t, hub.Shutdown(context.Background()))
+	repo.AssertExpectations(t)
+}
+
+func TestClickStreamHub_WatchReplaysAfterLastEventID(t *testing.T) {
+	repo, _, _, hub := setupClickStreamHub(config.StreamsConfig{BufferSize: 10})
+	stream := OwnerClickStream("alice")
+	repo.On("Subscribe", mock.Anything, stream).Return(nil)
+	repo.On("Unsubscribe", mock.Anything, stream).Return(nil)
+	repo.On("Recent", mock.Anything, stream).Return([]models.ClickStreamEvent{{ID: "1"}, {ID: "2"}, {ID: "3"}}, nil)
+
+	subscription, err := hub.Watch(context.Background(), stream, "client", "2")
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ClickStreamEvent{{ID: "3"}}, subscription.Replay)
+	subscription.Close()
+
+	// An ID that is no longer kept replays everything.
+	subscription, err = hub.Watch(context.Background(), stream, "client", "0")
+	assert.NoError(t, err)
+	assert.Len(t, subscription.Replay, 3)
+	subscription.Close()
+	assert.NoError(t, hub.Shutdown(context.Background()))
+}
+
+func TestClickStreamHub_LimitsConnections(t *testing.T) {
+	repo, _, _, hub := setupClickStreamHub(config.StreamsConfig{BufferSize: 10, MaxConnections: 2, MaxConnectionsPerClient: 1})
+	repo.On("Subscribe", mock.Anything, mock.Anything).Return(nil)
+	repo.On("Unsubscribe", mock.Anything, mock.Anything).Return(nil)
+
+	first, err := hub.Watch(context.Background(), LinkClickStream("a"), "client1", "")
+	assert.NoError(t, err)
+	_, err = hub.Watch(context.Background(), LinkClickStream("b"), "client1", "")
+	assert.ErrorIs(t, err, ErrTooManyClickStreams)
+	_, err = hub.Watch(context.Background(), LinkClickStream("b"), "client2", "")
+	assert.NoError(t, err)
+	_, err = hub.Watch(context.Background(), LinkClickStream("c"), "client3", "")
+	assert.ErrorIs(t, err, ErrTooManyClickStreams)
+
+	first.Close()
+	_, err = hub.Watch(context.Background(), LinkClickStream("b"), "client1", "")
+	assert.NoError(t, err)
+	assert.NoError(t, hub.Shutdown(context.Background()))
+}
+
+func TestClickStreamHub_ClosesSlowSubscribers(t *testing.T) {
+	repo, messages, _, hub := setupClickStreamHub(config.StreamsConfig{BufferSize: 1})
+	stream := LinkClickStream("shortPath")
+	repo.On("Subscribe", mock.Anything, stream).Return(nil).Once()
+	repo.On("Unsubscribe", mock.Anything, stream).Return(nil).Once()
+
+	subscription, err := hub.Watch(context.Background(), stream, "client", "")
+	assert.NoError(t, err)
+	messages <- models.ClickStreamMessage{Stream: stream, Event: models.ClickStreamEvent{ID: "1"}}
+	messages <- models.ClickStreamMessage{Stream: stream, Event: models.ClickStreamEvent{ID: "2"}}
+	// Messages are dispatched one at a time, so the second one has been handled once this one is taken.
+	messages <- models.ClickStreamMessage{Stream: LinkClickStream("other")}
+
+	assert.Equal(t, "1", (<-subscription.Events).ID)
+	_, ok := <-subscription.Events
+	assert.False(t, ok)
+	subscription.Close()
+	assert.NoError(t, hub.Shutdown(context.Background()))
+	repo.AssertExpectations(t)
+}
+
+func TestClickStreamHub_CloseStreamsEndsSubscriptions(t *testing.T) {
+	repo, _, _, hub := setupClickStreamHub(config.StreamsConfig{BufferSize: 1})
+	repo.On("Subscribe", mock.Anything, mock.Anything).Return(nil)
+
+	subscription, err := hub.Watch(context.Background(), LinkClickStream("shortPath"), "client", "")
+	assert.NoError(t, err)
+	hub.CloseStreams()
+
+	_, ok := <-subscription.Events
+	assert.False(t, ok)
+	subscription.Close()
+	_, err = hub.Watch(context.Background(), LinkClickStream("shortPath"), "client", "")
+	assert.ErrorIs(t, err, ErrClickStreamsClosed)
+	assert.NoError(t, hub.Shutdown(context.Background()))
+}
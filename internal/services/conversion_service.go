@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/utils"
+)
+
+const (
+	maxClickIDLength        = 32
+	maxConversionTypeLength = 64
+	maxTransactionIDLength  = 255
+)
+
+var ErrInvalidConversion = errors.New("clickId and type are required, type must be at most 64 characters and value a finite number")
+
+//go:generate mockery --name=ConversionService --output=./mocks
+type ConversionService interface {
+	// RecordConversion records conversion against its click and reports whether it was new. Clicks still queued
+	// for Postgres are found by the ID the redirect issued. It returns repositories.ErrClickNotFound for unknown
+	// clicks and clicks older than the attribution window.
+	RecordConversion(ctx context.Context, conversion *models.Conversion) (bool, error)
+}
+
+type conversionServiceImpl struct {
+	repo         repositories.ConversionRepository
+	issuedClicks repositories.IssuedClickRepository
+	config       config.ConversionsConfig
+	timeProvider utils.TimeProvider
+}
+
+func NewConversionService(repo repositories.ConversionRepository, issuedClicks repositories.IssuedClickRepository, conversionsConfig config.ConversionsConfig, timeProvider utils.TimeProvider) ConversionService {
+	return &conversionServiceImpl{repo: repo, issuedClicks: issuedClicks, config: conversionsConfig, timeProvider: timeProvider}
+}
+
+// RecordConversion implements ConversionService.
+func (s *conversionServiceImpl) RecordConversion(ctx context.Context, conversion *models.Conversion) (bool, error) {
+	if conversion.ClickID == "" || len(conversion.ClickID) > maxClickIDLength || conversion.Type == "" ||
+		len(conversion.Type) > maxConversionTypeLength || len(conversion.TransactionID) > maxTransactionIDLength ||
+		math.IsNaN(conversion.Value) || math.IsInf(conversion.Value, 0) {
+		return false, ErrInvalidConversion
+	}
+	now := s.timeProvider.Now()
+	conversion.ConvertedAt = now
+	// Without a window every stored click can convert.
+	var clickedAfter time.Time
+	if s.config.AttributionWindow > 0 {
+		clickedAfter = now.Add(-s.config.AttributionWindow)
+	}
+	created, err := s.repo.InsertConversion(ctx, conversion, clickedAfter)
+	if !errors.Is(err, repositories.ErrClickNotFound) {
+		return created, err
+	}
+	// Postbacks can arrive before the click is flushed, or after it was dropped from the queue.
+	click, err := s.issuedClicks.Get(ctx, conversion.ClickID)
+	if err != nil {
+		return false, err
+	}
+	if click.ClickedAt.Before(clickedAfter) {
+		return false, repositories.ErrClickNotFound
+	}
+	return s.repo.InsertIssuedClickConversion(ctx, conversion, click)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	repoMocks "url-shortener/internal/repositories/mocks"
+	utilsMocks "url-shortener/internal/utils/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversionService_RecordConversion(t *testing.T) {
+	repo := &repoMocks.ConversionRepository{}
+	issuedClicks := &repoMocks.IssuedClickRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now).Once()
+	conversion := &models.Conversion{ClickID: "clk_123", Type: "purchase", Value: 19.5, TransactionID: "order-7"}
+	repo.On("InsertConversion", context.Background(), conversion, now.Add(-720*time.Hour)).Return(true, nil).Once()
+	service := NewConversionService(repo, issuedClicks, config.ConversionsConfig{AttributionWindow: 720 * time.Hour}, timeProvider)
+
+	created, err := service.RecordConversion(context.Background(), conversion)
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, now, conversion.ConvertedAt)
+	repo.AssertExpectations(t)
+}
+
+func TestConversionService_RecordConversion_UnknownClick(t *testing.T) {
+	repo := &repoMocks.ConversionRepository{}
+	issuedClicks := &repoMocks.IssuedClickRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now).Once()
+	conversion := &models.Conversion{ClickID: "clk_123", Type: "signup"}
+	// Without an attribution window every stored click can convert.
+	repo.On("InsertConversion", context.Background(), conversion, time.Time{}).Return(false, repositories.ErrClickNotFound).Once()
+	issuedClicks.On("Get", context.Background(), "clk_123").Return(nil, repositories.ErrClickNotFound).Once()
+	service := NewConversionService(repo, issuedClicks, config.ConversionsConfig{}, timeProvider)
+
+	_, err := service.RecordConversion(context.Background(), conversion)
+
+	assert.ErrorIs(t, err, repositories.ErrClickNotFound)
+	repo.AssertExpectations(t)
+}
+
+func TestConversionService_RecordConversion_ClickNotFlushedYet(t *testing.T) {
+	repo := &repoMocks.ConversionRepository{}
+	issuedClicks := &repoMocks.IssuedClickRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now).Once()
+	conversion := &models.Conversion{ClickID: "clk_123", Type: "purchase", Value: 19.5}
+	// The postback arrives two seconds after the redirect, the click is still queued for Postgres.
+	click := &models.IssuedClick{ClickID: "clk_123", ShortPath: "abc", ClickedAt: now.Add(-2 * time.Second)}
+	repo.On("InsertConversion", context.Background(), conversion, now.Add(-720*time.Hour)).Return(false, repositories.ErrClickNotFound).Once()
+	issuedClicks.On("Get", context.Background(), "clk_123").Return(click, nil).Once()
+	repo.On("InsertIssuedClickConversion", context.Background(), conversion, click).Return(true, nil).Once()
+	service := NewConversionService(repo, issuedClicks, config.ConversionsConfig{AttributionWindow: 720 * time.Hour}, timeProvider)
+
+	created, err := service.RecordConversion(context.Background(), conversion)
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	repo.AssertExpectations(t)
+	issuedClicks.AssertExpectations(t)
+}
+
+func TestConversionService_RecordConversion_IssuedClickOutsideWindow(t *testing.T) {
+	repo := &repoMocks.ConversionRepository{}
+	issuedClicks := &repoMocks.IssuedClickRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now).Once()
+	conversion := &models.Conversion{ClickID: "clk_123", Type: "purchase"}
+	click := &models.IssuedClick{ClickID: "clk_123", ShortPath: "abc", ClickedAt: now.Add(-2 * time.Hour)}
+	repo.On("InsertConversion", context.Background(), conversion, now.Add(-time.Hour)).Return(false, repositories.ErrClickNotFound).Once()
+	issuedClicks.On("Get", context.Background(), "clk_123").Return(click, nil).Once()
+	service := NewConversionService(repo, issuedClicks, config.ConversionsConfig{AttributionWindow: time.Hour}, timeProvider)
+
+	_, err := service.RecordConversion(context.Background(), conversion)
+
+	assert.ErrorIs(t, err, repositories.ErrClickNotFound)
+	repo.AssertNotCalled(t, "InsertIssuedClickConversion")
+}
+
+func TestConversionService_RecordConversion_Invalid(t *testing.T) {
+	repo := &repoMocks.ConversionRepository{}
+	issuedClicks := &repoMocks.IssuedClickRepository{}
+	service := NewConversionService(repo, issuedClicks, config.ConversionsConfig{}, &utilsMocks.TimeProvider{})
+
+	for _, conversion := range []*models.Conversion{
+		{Type: "purchase"},
+		{ClickID: "clk_123"},
+		{ClickID: "clk_123", Type: string(make([]byte, 65))},
+		{ClickID: "clk_123", Type: "purchase", Value: math.Inf(1)},
+	} {
+		_, err := service.RecordConversion(context.Background(), conversion)
+		assert.ErrorIs(t, err, ErrInvalidConversion)
+	}
+	repo.AssertNotCalled(t, "InsertConversion")
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/repositories"
+)
+
+const (
+	IPModeFull     = "full"
+	IPModeTruncate = "truncate"
+	IPModeHash     = "hash"
+	IPModeNone     = "none"
+)
+
+// IPAnonymizer changes client IPs before they are stored, as set by privacy.ip_mode. Hashes are keyed with a random
+// salt per rotation period. Salts only live in Redis and expire, so once a salt is gone its hashes cannot be linked
+// to an IP or to the hashes of other periods any more.
+type IPAnonymizer struct {
+	mode     string
+	rotation time.Duration
+	salts    repositories.IPSaltRepository
+
+	// mu guards the salts of the current and previous period cached by their start.
+	mu    sync.Mutex
+	cache map[time.Time][]byte
+}
+
+func NewIPAnonymizer(privacyConfig config.PrivacyConfig, salts repositories.IPSaltRepository) (*IPAnonymizer, error) {
+	switch privacyConfig.IPMode {
+	case IPModeFull, IPModeTruncate, IPModeHash, IPModeNone:
+	default:
+		return nil, fmt.Errorf("unknown IP mode %q", privacyConfig.IPMode)
+	}
+	if privacyConfig.SaltRotation <= 0 {
+		privacyConfig.SaltRotation = 24 * time.Hour
+	}
+	return &IPAnonymizer{mode: privacyConfig.IPMode, rotation: privacyConfig.SaltRotation, salts: salts, cache: map[time.Time][]byte{}}, nil
+}
+
+// Anonymize returns ip as it is to be stored for a click at the given time. Invalid IPs, and IPs that cannot be
+// hashed because the salt is unavailable, are not stored at all.
+func (a *IPAnonymizer) Anonymize(ctx context.Context, ip string, at time.Time) string {
+	switch a.mode {
+	case IPModeFull:
+		return ip
+	case IPModeTruncate:
+		return truncateIP(ip)
+	case IPModeHash:
+		salt, err := a.salt(ctx, at.UTC().Truncate(a.rotation))
+		if err != nil {
+			// The repository logs the error.
+			return ""
+		}
+		mac := hmac.New(sha256.New, salt)
+		mac.Write([]byte(ip))
+		return hex.EncodeToString(mac.Sum(nil)[:16])
+	default:
+		return ""
+	}
+}
+
+// truncateIP zeroes the host part of an IPv4 address beyond the /24 and of an IPv6 address beyond the /48.
+func truncateIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return parsed.Mask(net.CIDRMask(48, 128)).String()
+}
+
+// salt returns the salt of the period, which is kept for two periods so clicks still queued when a period ends are
+// hashed with the salt of their own.
+func (a *IPAnonymizer) salt(ctx context.Context, period time.Time) ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if salt, ok := a.cache[period]; ok {
+		return salt, nil
+	}
+	candidate := make([]byte, 32)
+	if _, err := rand.Read(candidate); err != nil {
+		return nil, err
+	}
+	salt, err := a.salts.GetSalt(ctx, period, candidate, 2*a.rotation)
+	if err != nil {
+		return nil, err
+	}
+	for cached := range a.cache {
+		if cached.Before(period.Add(-a.rotation)) {
+			delete(a.cache, cached)
+		}
+	}
+	a.cache[period] = salt
+	return salt, nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	repoMocks "url-shortener/internal/repositories/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewIPAnonymizer_UnknownMode(t *testing.T) {
+	_, err := NewIPAnonymizer(config.PrivacyConfig{IPMode: "mask"}, nil)
+
+	assert.Error(t, err)
+}
+
+func TestIPAnonymizer_Truncate(t *testing.T) {
+	anonymizer, err := NewIPAnonymizer(config.PrivacyConfig{IPMode: IPModeTruncate}, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "203.0.113.0", anonymizer.Anonymize(context.Background(), "203.0.113.7", time.Now()))
+	assert.Equal(t, "2001:db8:85a3::", anonymizer.Anonymize(context.Background(), "2001:db8:85a3:8d3:1319:8a2e:370:7348", time.Now()))
+	assert.Equal(t, "", anonymizer.Anonymize(context.Background(), "not an ip", time.Now()))
+}
+
+func TestIPAnonymizer_FullAndNone(t *testing.T) {
+	full, err := NewIPAnonymizer(config.PrivacyConfig{IPMode: IPModeFull}, nil)
+	assert.NoError(t, err)
+	none, err := NewIPAnonymizer(config.PrivacyConfig{IPMode: IPModeNone}, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "203.0.113.7", full.Anonymize(context.Background(), "203.0.113.7", time.Now()))
+	assert.Equal(t, "", none.Anonymize(context.Background(), "203.0.113.7", time.Now()))
+}
+
+func TestIPAnonymizer_HashRotatesSalt(t *testing.T) {
+	salts := &repoMocks.IPSaltRepository{}
+	ctx := context.Background()
+	day := time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC)
+	salts.On("GetSalt", ctx, day, mock.Anything, 48*time.Hour).Return([]byte("first"), nil).Once()
+	salts.On("GetSalt", ctx, day.AddDate(0, 0, 1), mock.Anything, 48*time.Hour).Return([]byte("second"), nil).Once()
+	anonymizer, err := NewIPAnonymizer(config.PrivacyConfig{IPMode: IPModeHash, SaltRotation: 24 * time.Hour}, salts)
+	assert.NoError(t, err)
+
+	morning := anonymizer.Anonymize(ctx, "203.0.113.7", day.Add(9*time.Hour))
+	evening := anonymizer.Anonymize(ctx, "203.0.113.7", day.Add(21*time.Hour))
+	nextDay := anonymizer.Anonymize(ctx, "203.0.113.7", day.Add(33*time.Hour))
+
+	assert.Len(t, morning, 32)
+	assert.Equal(t, morning, evening)
+	assert.NotEqual(t, morning, nextDay)
+	assert.NotEqual(t, morning, anonymizer.Anonymize(ctx, "203.0.113.8", day.Add(9*time.Hour)))
+	salts.AssertExpectations(t)
+}
+
+func TestIPAnonymizer_HashWithoutSalt(t *testing.T) {
+	salts := &repoMocks.IPSaltRepository{}
+	salts.On("GetSalt", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError).Once()
+	anonymizer, err := NewIPAnonymizer(config.PrivacyConfig{IPMode: IPModeHash}, salts)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "", anonymizer.Anonymize(context.Background(), "203.0.113.7", time.Now()))
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+
+	"github.com/nats-io/nats.go"
+)
+
+// linkEventPayload is how a link event is sent to webhooks and sinks. Events are sent at least once, receivers can
+// use id to drop an event they got twice.
+type linkEventPayload struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"`
+}
+
+func marshalLinkEvent(event models.OutboxEvent) ([]byte, error) {
+	return json.Marshal(linkEventPayload{
+		ID:         event.ID,
+		Type:       event.Type,
+		OccurredAt: event.CreatedAt.UTC(),
+		Data:       event.Payload,
+	})
+}
+
+// EventSink receives the link events of the outbox relay. Its name is the outbox offset it reads from, so renaming a
+// sink sends it every event again.
+//
+//go:generate mockery --name=EventSink --output=./mocks
+type EventSink interface {
+	Name() string
+	// Publish sends events in order. On error the whole batch is sent again, so it must be safe to repeat.
+	Publish(ctx context.Context, events []models.OutboxEvent) error
+}
+
+// CacheInvalidationSink drops the cached links that were changed, deleted or expired.
+type CacheInvalidationSink struct {
+	cache repositories.URLRepository
+}
+
+func NewCacheInvalidationSink(cache repositories.URLRepository) *CacheInvalidationSink {
+	return &CacheInvalidationSink{cache: cache}
+}
+
+// Name implements EventSink.
+func (s *CacheInvalidationSink) Name() string {
+	return "cache"
+}
+
+// Publish implements EventSink. Created and restored links are not cached before they exist, so nothing is dropped
+// for them.
+func (s *CacheInvalidationSink) Publish(ctx context.Context, events []models.OutboxEvent) error {
+	for _, event := range events {
+		if event.Type == models.LinkEventCreated || event.Type == models.LinkEventRestored {
+			continue
+		}
+		if err := s.cache.DeleteShortURL(ctx, event.ShortPath, event.CreatedAt, "outbox", nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RedisStreamSink appends link events to a Redis stream, capped at about maxLen entries.
+type RedisStreamSink struct {
+	repo   repositories.LinkEventStreamRepository
+	stream string
+	maxLen int64
+}
+
+func NewRedisStreamSink(repo repositories.LinkEventStreamRepository, stream string, maxLen int64) *RedisStreamSink {
+	return &RedisStreamSink{repo: repo, stream: stream, maxLen: maxLen}
+}
+
+// Name implements EventSink.
+func (s *RedisStreamSink) Name() string {
+	return "redis_stream"
+}
+
+// Publish implements EventSink.
+func (s *RedisStreamSink) Publish(ctx context.Context, events []models.OutboxEvent) error {
+	return s.repo.AppendEvents(ctx, s.stream, s.maxLen, events)
+}
+
+// natsPublisher is the part of *nats.Conn the NATS sink uses.
+type natsPublisher interface {
+	PublishMsg(msg *nats.Msg) error
+	FlushWithContext(ctx context.Context) error
+}
+
+// NATSSink publishes every link event to <subject>.<event type>, e.g. links.link.created. The event ID is set as
+// Nats-Msg-Id, so a JetStream stream on the subjects drops events published twice within its duplicate window.
+type NATSSink struct {
+	conn    natsPublisher
+	subject string
+}
+
+func NewNATSSink(conn *nats.Conn, subject string) *NATSSink {
+	return &NATSSink{conn: conn, subject: subject}
+}
+
+// Name implements EventSink.
+func (s *NATSSink) Name() string {
+	return "nats"
+}
+
+// Publish implements EventSink. The batch only counts as published once the server has answered a flush.
+func (s *NATSSink) Publish(ctx context.Context, events []models.OutboxEvent) error {
+	for _, event := range events {
+		data, err := marshalLinkEvent(event)
+		if err != nil {
+			return err
+		}
+		msg := nats.NewMsg(s.subject + "." + event.Type)
+		msg.Header.Set(nats.MsgIdHdr, strconv.FormatInt(event.ID, 10))
+		msg.Data = data
+		if err := s.conn.PublishMsg(msg); err != nil {
+			return fmt.Errorf("publishing event %d to NATS: %w", event.ID, err)
+		}
+	}
+	if err := s.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("flushing NATS: %w", err)
+	}
+	return nil
+}
+
+// WriterSink writes every link event as a line of JSON, to stdout or a file.
+type WriterSink struct {
+	name string
+	mu   sync.Mutex
+	w    io.Writer
+}
+
+func NewWriterSink(name string, w io.Writer) *WriterSink {
+	return &WriterSink{name: name, w: w}
+}
+
+// Name implements EventSink.
+func (s *WriterSink) Name() string {
+	return s.name
+}
+
+// Publish implements EventSink.
+func (s *WriterSink) Publish(ctx context.Context, events []models.OutboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range events {
+		data, err := marshalLinkEvent(event)
+		if err != nil {
+			return err
+		}
+		if _, err := s.w.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
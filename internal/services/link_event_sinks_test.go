@@ -0,0 +1,107 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	repoMocks "url-shortener/internal/repositories/mocks"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+)
+
+var testLinkEvents = []models.OutboxEvent{
+	{ID: 21, Type: models.LinkEventCreated, ShortPath: "abc", Payload: json.RawMessage(`{"shortPath":"abc"}`),
+		CreatedAt: time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)},
+	{ID: 22, Type: models.LinkEventUpdated, ShortPath: "abc", Payload: json.RawMessage(`{"shortPath":"abc"}`),
+		CreatedAt: time.Date(2025, 6, 12, 10, 1, 0, 0, time.UTC)},
+	{ID: 23, Type: models.LinkEventExpired, ShortPath: "def", Payload: json.RawMessage(`{"shortPath":"def"}`),
+		CreatedAt: time.Date(2025, 6, 12, 10, 2, 0, 0, time.UTC)},
+}
+
+func TestCacheInvalidationSink_DropsChangedLinks(t *testing.T) {
+	cache := &repoMocks.URLRepository{}
+	cache.On("DeleteShortURL", context.Background(), "abc", testLinkEvents[1].CreatedAt, "outbox", (*models.AuditActor)(nil)).Return(nil).Once()
+	cache.On("DeleteShortURL", context.Background(), "def", testLinkEvents[2].CreatedAt, "outbox", (*models.AuditActor)(nil)).Return(nil).Once()
+
+	err := NewCacheInvalidationSink(cache).Publish(context.Background(), testLinkEvents)
+
+	assert.NoError(t, err)
+	cache.AssertExpectations(t)
+}
+
+func TestCacheInvalidationSink_Error(t *testing.T) {
+	cache := &repoMocks.URLRepository{}
+	cache.On("DeleteShortURL", context.Background(), "abc", testLinkEvents[1].CreatedAt, "outbox", (*models.AuditActor)(nil)).Return(repositories.ErrRedisError).Once()
+
+	err := NewCacheInvalidationSink(cache).Publish(context.Background(), testLinkEvents)
+
+	assert.ErrorIs(t, err, repositories.ErrRedisError)
+	cache.AssertNotCalled(t, "DeleteShortURL", context.Background(), "def", testLinkEvents[2].CreatedAt, "outbox", (*models.AuditActor)(nil))
+}
+
+func TestRedisStreamSink_Publish(t *testing.T) {
+	repo := &repoMocks.LinkEventStreamRepository{}
+	repo.On("AppendEvents", context.Background(), "link-events", int64(1000), testLinkEvents).Return(nil).Once()
+
+	err := NewRedisStreamSink(repo, "link-events", 1000).Publish(context.Background(), testLinkEvents)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+type fakeNATSPublisher struct {
+	msgs     []*nats.Msg
+	flushErr error
+}
+
+func (p *fakeNATSPublisher) PublishMsg(msg *nats.Msg) error {
+	p.msgs = append(p.msgs, msg)
+	return nil
+}
+
+func (p *fakeNATSPublisher) FlushWithContext(ctx context.Context) error {
+	return p.flushErr
+}
+
+func TestNATSSink_Publish(t *testing.T) {
+	publisher := &fakeNATSPublisher{}
+	sink := &NATSSink{conn: publisher, subject: "links"}
+
+	err := sink.Publish(context.Background(), testLinkEvents[:2])
+
+	assert.NoError(t, err)
+	assert.Len(t, publisher.msgs, 2)
+	assert.Equal(t, "links.link.created", publisher.msgs[0].Subject)
+	assert.Equal(t, "21", publisher.msgs[0].Header.Get(nats.MsgIdHdr))
+	assert.JSONEq(t, `{"id":21,"type":"link.created","occurredAt":"2025-06-12T10:00:00Z","data":{"shortPath":"abc"}}`, string(publisher.msgs[0].Data))
+	assert.Equal(t, "links.link.updated", publisher.msgs[1].Subject)
+}
+
+func TestNATSSink_FlushError(t *testing.T) {
+	flushErr := errors.New("nats: connection closed")
+	sink := &NATSSink{conn: &fakeNATSPublisher{flushErr: flushErr}, subject: "links"}
+
+	err := sink.Publish(context.Background(), testLinkEvents)
+
+	assert.ErrorIs(t, err, flushErr)
+}
+
+func TestWriterSink_WritesJSONLines(t *testing.T) {
+	var out bytes.Buffer
+	sink := NewWriterSink("stdout", &out)
+
+	err := sink.Publish(context.Background(), testLinkEvents[:2])
+
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	assert.JSONEq(t, `{"id":22,"type":"link.updated","occurredAt":"2025-06-12T10:01:00Z","data":{"shortPath":"abc"}}`, lines[1])
+}
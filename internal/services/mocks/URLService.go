@@ -16,9 +16,9 @@ type URLService struct {
 	mock.Mock
 }
 
-// CreateShortURL provides a mock function with given fields: ctx, originalURL, expiry
-func (_m *URLService) CreateShortURL(ctx context.Context, originalURL string, expiry *time.Time) (string, error) {
-	ret := _m.Called(ctx, originalURL, expiry)
+// CreateShortURL provides a mock function with given fields: ctx, originalURL, expiry, actor
+func (_m *URLService) CreateShortURL(ctx context.Context, originalURL string, expiry *time.Time, actor *models.AuditActor) (string, error) {
+	ret := _m.Called(ctx, originalURL, expiry, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateShortURL")
@@ -26,17 +26,17 @@ func (_m *URLService) CreateShortURL(ctx context.Context, originalURL string, ex
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time) (string, error)); ok {
-		return rf(ctx, originalURL, expiry)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time, *models.AuditActor) (string, error)); ok {
+		return rf(ctx, originalURL, expiry, actor)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time) string); ok {
-		r0 = rf(ctx, originalURL, expiry)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time, *models.AuditActor) string); ok {
+		r0 = rf(ctx, originalURL, expiry, actor)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, *time.Time) error); ok {
-		r1 = rf(ctx, originalURL, expiry)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *time.Time, *models.AuditActor) error); ok {
+		r1 = rf(ctx, originalURL, expiry, actor)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -44,17 +44,17 @@ func (_m *URLService) CreateShortURL(ctx context.Context, originalURL string, ex
 	return r0, r1
 }
 
-// DeleteURL provides a mock function with given fields: ctx, shortPath
-func (_m *URLService) DeleteURL(ctx context.Context, shortPath string) error {
-	ret := _m.Called(ctx, shortPath)
+// DeleteURL provides a mock function with given fields: ctx, shortPath, actor
+func (_m *URLService) DeleteURL(ctx context.Context, shortPath string, actor *models.AuditActor) error {
+	ret := _m.Called(ctx, shortPath, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteURL")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, shortPath)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.AuditActor) error); ok {
+		r0 = rf(ctx, shortPath, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -62,9 +62,9 @@ func (_m *URLService) DeleteURL(ctx context.Context, shortPath string) error {
 	return r0
 }
 
-// GetLongURL provides a mock function with given fields: ctx, shortPath
-func (_m *URLService) GetLongURL(ctx context.Context, shortPath string) (string, error) {
-	ret := _m.Called(ctx, shortPath)
+// GetLongURL provides a mock function with given fields: ctx, shortPath, accessLog
+func (_m *URLService) GetLongURL(ctx context.Context, shortPath string, accessLog *models.AccessLog) (string, error) {
+	ret := _m.Called(ctx, shortPath, accessLog)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetLongURL")
@@ -72,17 +72,17 @@ func (_m *URLService) GetLongURL(ctx context.Context, shortPath string) (string,
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return rf(ctx, shortPath)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.AccessLog) (string, error)); ok {
+		return rf(ctx, shortPath, accessLog)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = rf(ctx, shortPath)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.AccessLog) string); ok {
+		r0 = rf(ctx, shortPath, accessLog)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, shortPath)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *models.AccessLog) error); ok {
+		r1 = rf(ctx, shortPath, accessLog)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -120,17 +120,77 @@ func (_m *URLService) GetURLDetails(ctx context.Context, shortPath string) (*mod
 	return r0, r1
 }
 
-// UpdateShortURL provides a mock function with given fields: ctx, originalUrl, shortUrl, expiry
-func (_m *URLService) UpdateShortURL(ctx context.Context, originalUrl string, shortUrl string, expiry *time.Time) error {
-	ret := _m.Called(ctx, originalUrl, shortUrl, expiry)
+// ListArchivedURLs provides a mock function with given fields: ctx, shortPath, before, limit
+func (_m *URLService) ListArchivedURLs(ctx context.Context, shortPath string, before int64, limit int) ([]models.URLArchive, error) {
+	ret := _m.Called(ctx, shortPath, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListArchivedURLs")
+	}
+
+	var r0 []models.URLArchive
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int) ([]models.URLArchive, error)); ok {
+		return rf(ctx, shortPath, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int) []models.URLArchive); ok {
+		r0 = rf(ctx, shortPath, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.URLArchive)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, int) error); ok {
+		r1 = rf(ctx, shortPath, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreURL provides a mock function with given fields: ctx, shortPath, actor
+func (_m *URLService) RestoreURL(ctx context.Context, shortPath string, actor *models.AuditActor) (*models.URL, error) {
+	ret := _m.Called(ctx, shortPath, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreURL")
+	}
+
+	var r0 *models.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.AuditActor) (*models.URL, error)); ok {
+		return rf(ctx, shortPath, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.AuditActor) *models.URL); ok {
+		r0 = rf(ctx, shortPath, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.URL)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *models.AuditActor) error); ok {
+		r1 = rf(ctx, shortPath, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateShortURL provides a mock function with given fields: ctx, originalUrl, shortUrl, expiry, actor
+func (_m *URLService) UpdateShortURL(ctx context.Context, originalUrl string, shortUrl string, expiry *time.Time, actor *models.AuditActor) error {
+	ret := _m.Called(ctx, originalUrl, shortUrl, expiry, actor)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateShortURL")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, *time.Time) error); ok {
-		r0 = rf(ctx, originalUrl, shortUrl, expiry)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *time.Time, *models.AuditActor) error); ok {
+		r0 = rf(ctx, originalUrl, shortUrl, expiry, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -16,9 +16,69 @@ type URLStatsService struct {
 	mock.Mock
 }
 
-// GetURLStatistics provides a mock function with given fields: ctx, shortPath
-func (_m *URLStatsService) GetURLStatistics(ctx context.Context, shortPath string) (*models.URLStatistics, error) {
-	ret := _m.Called(ctx, shortPath)
+// GetDomainClicks provides a mock function with given fields: ctx, window, limit
+func (_m *URLStatsService) GetDomainClicks(ctx context.Context, window string, limit int) ([]models.DomainClicks, error) {
+	ret := _m.Called(ctx, window, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDomainClicks")
+	}
+
+	var r0 []models.DomainClicks
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]models.DomainClicks, error)); ok {
+		return rf(ctx, window, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []models.DomainClicks); ok {
+		r0 = rf(ctx, window, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.DomainClicks)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, window, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTopLinks provides a mock function with given fields: ctx, window, limit
+func (_m *URLStatsService) GetTopLinks(ctx context.Context, window string, limit int) ([]models.LinkClicks, error) {
+	ret := _m.Called(ctx, window, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTopLinks")
+	}
+
+	var r0 []models.LinkClicks
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]models.LinkClicks, error)); ok {
+		return rf(ctx, window, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []models.LinkClicks); ok {
+		r0 = rf(ctx, window, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.LinkClicks)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, window, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetURLStatistics provides a mock function with given fields: ctx, shortPath, includeBots
+func (_m *URLStatsService) GetURLStatistics(ctx context.Context, shortPath string, includeBots bool) (*models.URLStatistics, error) {
+	ret := _m.Called(ctx, shortPath, includeBots)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetURLStatistics")
@@ -26,19 +86,19 @@ func (_m *URLStatsService) GetURLStatistics(ctx context.Context, shortPath strin
 
 	var r0 *models.URLStatistics
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.URLStatistics, error)); ok {
-		return rf(ctx, shortPath)
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) (*models.URLStatistics, error)); ok {
+		return rf(ctx, shortPath, includeBots)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *models.URLStatistics); ok {
-		r0 = rf(ctx, shortPath)
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) *models.URLStatistics); ok {
+		r0 = rf(ctx, shortPath, includeBots)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.URLStatistics)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, shortPath)
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
+		r1 = rf(ctx, shortPath, includeBots)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -46,22 +106,34 @@ func (_m *URLStatsService) GetURLStatistics(ctx context.Context, shortPath strin
 	return r0, r1
 }
 
-// InsertAccessLog provides a mock function with given fields: ctx, shortPath, accessedAt
-func (_m *URLStatsService) InsertAccessLog(ctx context.Context, shortPath string, accessedAt time.Time) error {
-	ret := _m.Called(ctx, shortPath, accessedAt)
+// GetURLTimeseries provides a mock function with given fields: ctx, shortPath, from, to, interval, location, includeBots
+func (_m *URLStatsService) GetURLTimeseries(ctx context.Context, shortPath string, from time.Time, to time.Time, interval string, location *time.Location, includeBots bool) (*models.URLTimeseries, error) {
+	ret := _m.Called(ctx, shortPath, from, to, interval, location, includeBots)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InsertAccessLog")
+		panic("no return value specified for GetURLTimeseries")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
-		r0 = rf(ctx, shortPath, accessedAt)
+	var r0 *models.URLTimeseries
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, string, *time.Location, bool) (*models.URLTimeseries, error)); ok {
+		return rf(ctx, shortPath, from, to, interval, location, includeBots)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, string, *time.Location, bool) *models.URLTimeseries); ok {
+		r0 = rf(ctx, shortPath, from, to, interval, location, includeBots)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.URLTimeseries)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time, string, *time.Location, bool) error); ok {
+		r1 = rf(ctx, shortPath, from, to, interval, location, includeBots)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // NewURLStatsService creates a new instance of URLStatsService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/utils"
+)
+
+// OutboxRelayJob passes the link events of the outbox to every sink. Each sink has its own offset, a sink that fails
+// gets the same events again on the next run and does not hold up the others. Events every consumer has read are
+// deleted once they are older than outbox.retention.
+type OutboxRelayJob struct {
+	repo         repositories.OutboxRepository
+	sinks        []EventSink
+	config       config.OutboxConfig
+	timeProvider utils.TimeProvider
+}
+
+func NewOutboxRelayJob(repo repositories.OutboxRepository, sinks []EventSink, outboxConfig config.OutboxConfig, timeProvider utils.TimeProvider) *OutboxRelayJob {
+	if outboxConfig.BatchSize <= 0 {
+		outboxConfig.BatchSize = 500
+	}
+	return &OutboxRelayJob{repo: repo, sinks: sinks, config: outboxConfig, timeProvider: timeProvider}
+}
+
+// Name implements Job.
+func (j *OutboxRelayJob) Name() string {
+	return "outbox-relay"
+}
+
+// Run implements Job. Every sink is caught up before the run ends, then the outbox is pruned.
+func (j *OutboxRelayJob) Run(ctx context.Context) error {
+	var errs []error
+	for _, sink := range j.sinks {
+		for {
+			relayed, err := j.repo.ConsumeEvents(ctx, sink.Name(), j.config.BatchSize, sink.Publish)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("sink %s: %w", sink.Name(), err))
+				break
+			}
+			if relayed < j.config.BatchSize {
+				break
+			}
+		}
+	}
+	return errors.Join(append(errs, j.prune(ctx))...)
+}
+
+// prune deletes the expired events a batch at a time, so a large backlog does not hold locks for long.
+func (j *OutboxRelayJob) prune(ctx context.Context) error {
+	if j.config.Retention <= 0 {
+		return nil
+	}
+	before := j.timeProvider.Now().Add(-j.config.Retention)
+	for ctx.Err() == nil {
+		pruned, err := j.repo.PruneEvents(ctx, before, j.config.BatchSize)
+		if err != nil {
+			return fmt.Errorf("pruning: %w", err)
+		}
+		if pruned < j.config.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	repoMocks "url-shortener/internal/repositories/mocks"
+	"url-shortener/internal/services/mocks"
+	utilsMocks "url-shortener/internal/utils/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestSink(name string) *mocks.EventSink {
+	sink := &mocks.EventSink{}
+	sink.On("Name").Return(name)
+	return sink
+}
+
+func TestOutboxRelayJob_CatchesUpEverySink(t *testing.T) {
+	repo := &repoMocks.OutboxRepository{}
+	cache, stream := newTestSink("cache"), newTestSink("redis_stream")
+	repo.On("ConsumeEvents", context.Background(), "cache", 2, mock.Anything).Return(2, nil).Once()
+	repo.On("ConsumeEvents", context.Background(), "cache", 2, mock.Anything).Return(1, nil).Once()
+	repo.On("ConsumeEvents", context.Background(), "redis_stream", 2, mock.Anything).
+		Run(func(args mock.Arguments) {
+			handle := args.Get(3).(func(context.Context, []models.OutboxEvent) error)
+			assert.NoError(t, handle(context.Background(), testLinkEvents[:1]))
+		}).Return(1, nil).Once()
+	stream.On("Publish", context.Background(), testLinkEvents[:1]).Return(nil).Once()
+	job := NewOutboxRelayJob(repo, []EventSink{cache, stream}, config.OutboxConfig{BatchSize: 2}, nil)
+
+	err := job.Run(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	stream.AssertExpectations(t)
+}
+
+func TestOutboxRelayJob_FailingSinkDoesNotStopOthers(t *testing.T) {
+	repo := &repoMocks.OutboxRepository{}
+	nats, cache := newTestSink("nats"), newTestSink("cache")
+	repo.On("ConsumeEvents", context.Background(), "nats", 500, mock.Anything).Return(0, repositories.ErrDBError).Once()
+	repo.On("ConsumeEvents", context.Background(), "cache", 500, mock.Anything).Return(3, nil).Once()
+	job := NewOutboxRelayJob(repo, []EventSink{nats, cache}, config.OutboxConfig{}, nil)
+
+	err := job.Run(context.Background())
+
+	assert.ErrorIs(t, err, repositories.ErrDBError)
+	assert.ErrorContains(t, err, "sink nats")
+	repo.AssertExpectations(t)
+}
+
+func TestOutboxRelayJob_PrunesExpiredEvents(t *testing.T) {
+	repo := &repoMocks.OutboxRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now).Once()
+	cache := newTestSink("cache")
+	repo.On("ConsumeEvents", context.Background(), "cache", 2, mock.Anything).Return(0, nil).Once()
+	repo.On("PruneEvents", context.Background(), now.Add(-7*24*time.Hour), 2).Return(2, nil).Once()
+	repo.On("PruneEvents", context.Background(), now.Add(-7*24*time.Hour), 2).Return(1, nil).Once()
+	job := NewOutboxRelayJob(repo, []EventSink{cache}, config.OutboxConfig{BatchSize: 2, Retention: 7 * 24 * time.Hour}, timeProvider)
+
+	err := job.Run(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestOutboxRelayJob_PrunesAfterFailingSink(t *testing.T) {
+	repo := &repoMocks.OutboxRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	now := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now).Once()
+	nats := newTestSink("nats")
+	repo.On("ConsumeEvents", context.Background(), "nats", 500, mock.Anything).Return(0, repositories.ErrDBError).Once()
+	repo.On("PruneEvents", context.Background(), now.Add(-time.Hour), 500).Return(0, repositories.ErrDBError).Once()
+	job := NewOutboxRelayJob(repo, []EventSink{nats}, config.OutboxConfig{Retention: time.Hour}, timeProvider)
+
+	err := job.Run(context.Background())
+
+	assert.ErrorContains(t, err, "sink nats")
+	assert.ErrorContains(t, err, "pruning")
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is a unit of background work run periodically by a Scheduler.
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+type scheduledJob struct {
+	job      Job
+	interval time.Duration
+}
+
+// Scheduler runs every job right away and then every interval until Shutdown. A job is never run concurrently
+// with itself, a run that takes longer than the interval delays the next one.
+type Scheduler struct {
+	jobs   []scheduledJob
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Add registers a job. Jobs with an interval of zero or less are disabled.
+func (s *Scheduler) Add(job Job, interval time.Duration) {
+	if interval <= 0 {
+		log.Printf("Job %s is disabled", job.Name())
+		return
+	}
+	s.jobs = append(s.jobs, scheduledJob{job: job, interval: interval})
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, scheduled := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, scheduled)
+	}
+}
+
+// Shutdown cancels the context of running jobs and waits for them to return.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, scheduled scheduledJob) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(scheduled.interval)
+	defer ticker.Stop()
+	for {
+		if err := scheduled.job.Run(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Job %s failed: %v", scheduled.job.Name(), err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingJob struct {
+	runs atomic.Int64
+}
+
+func (j *countingJob) Name() string {
+	return "counting"
+}
+
+func (j *countingJob) Run(ctx context.Context) error {
+	j.runs.Add(1)
+	return nil
+}
+
+func TestScheduler_RunsJobsUntilShutdown(t *testing.T) {
+	job := &countingJob{}
+	disabled := &countingJob{}
+	scheduler := NewScheduler()
+	scheduler.Add(job, 10*time.Millisecond)
+	scheduler.Add(disabled, 0)
+	scheduler.Start(context.Background())
+
+	assert.Eventually(t, func() bool { return job.runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	assert.NoError(t, scheduler.Shutdown(context.Background()))
+
+	runs := job.runs.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, runs, job.runs.Load())
+	assert.Equal(t, int64(0), disabled.runs.Load())
+}
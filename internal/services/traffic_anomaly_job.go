@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/utils"
+)
+
+const (
+	// A baseline whose mean decayed below this without clicks is dropped, it would need a full warm-up anyway.
+	minTrafficBaselineMean = 0.01
+	// A queued alert the webhooks do not take is retried after alertRetryBase, doubling up to alertRetryMax, until
+	// alertMaxAttempts.
+	alertRetryBase   = time.Minute
+	alertRetryMax    = time.Hour
+	alertMaxAttempts = 10
+)
+
+// TrafficAnomalyJob compares the clicks of every link in each finished bucket with an exponentially weighted
+// baseline of its earlier buckets and alerts when they are too many standard deviations above or below it. The
+// deviation is never taken as less than the square root of the mean, the noise expected of click counts, so links
+// with very regular traffic do not alert on a handful of extra clicks.
+type TrafficAnomalyJob struct {
+	repo         repositories.TrafficAnomalyRepository
+	notifier     AlertNotifier
+	config       config.AlertsConfig
+	timeProvider utils.TimeProvider
+	alpha        float64
+}
+
+func NewTrafficAnomalyJob(repo repositories.TrafficAnomalyRepository, notifier AlertNotifier, alertsConfig config.AlertsConfig, timeProvider utils.TimeProvider) *TrafficAnomalyJob {
+	if alertsConfig.Bucket <= 0 {
+		alertsConfig.Bucket = 5 * time.Minute
+	}
+	alpha := 1.0
+	if alertsConfig.HalfLife > 0 {
+		alpha = 1 - math.Exp(-math.Ln2*float64(alertsConfig.Bucket)/float64(alertsConfig.HalfLife))
+	}
+	return &TrafficAnomalyJob{repo: repo, notifier: notifier, config: alertsConfig, timeProvider: timeProvider, alpha: alpha}
+}
+
+// Name implements Job.
+func (j *TrafficAnomalyJob) Name() string {
+	return "traffic-anomalies"
+}
+
+// Run implements Job. Buckets are processed oldest first until the last one that ended at least the lateness ago,
+// then the queued alerts that are due are sent.
+func (j *TrafficAnomalyJob) Run(ctx context.Context) error {
+	return errors.Join(j.processBuckets(ctx), j.sendAlerts(ctx))
+}
+
+// processBuckets queues the alerts of each bucket with its baselines, so an alert is neither lost nor raised twice
+// when the job stops half way.
+func (j *TrafficAnomalyJob) processBuckets(ctx context.Context) error {
+	settings, err := j.repo.ListAlertSettings(ctx)
+	if err != nil {
+		return err
+	}
+	settingsByLink := make(map[string]models.AlertSettings, len(settings))
+	for _, linkSettings := range settings {
+		settingsByLink[linkSettings.ShortPath] = linkSettings
+	}
+
+	until := j.timeProvider.Now().UTC().Add(-j.config.Lateness)
+	for ctx.Err() == nil {
+		processed, err := j.repo.ProcessNextBucket(ctx, until, j.config.Bucket, func(bucket models.TrafficBucket) ([]models.TrafficBaseline, []string, []models.TrafficAlert, error) {
+			updated, deleted, alerts := j.detect(bucket, settingsByLink)
+			return updated, deleted, alerts, nil
+		})
+		if err != nil {
+			return err
+		}
+		if !processed {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// sendAlerts sends the queued alerts one at a time, each held for as long as notifying every webhook can take. An
+// alert a webhook did not take is sent to all of them again later, receivers drop the repeats by their dedup key.
+func (j *TrafficAnomalyJob) sendAlerts(ctx context.Context) error {
+	lease := max(time.Minute, time.Duration(len(j.config.Webhooks))*alertNotifyTimeout(j.config.WebhookTimeout))
+	for ctx.Err() == nil {
+		now := j.timeProvider.Now().UTC()
+		alert, err := j.repo.ClaimAlert(ctx, now, now.Add(lease))
+		if err != nil || alert == nil {
+			return err
+		}
+		err = j.notifier.Notify(ctx, *alert)
+		switch {
+		case err == nil:
+		case alert.Attempts >= alertMaxAttempts:
+			log.Printf("Giving up %s alert of %s after %d attempts: %v", alert.Kind, alert.ShortPath, alert.Attempts, err)
+		default:
+			log.Printf("Error sending %s alert of %s, retrying: %v", alert.Kind, alert.ShortPath, err)
+			if err := j.repo.RetryAlert(ctx, alert.ID, j.timeProvider.Now().UTC().Add(webhookBackoff(alertRetryBase, alertRetryMax, alert.Attempts))); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := j.repo.DeleteAlert(ctx, alert.ID); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// detect checks every link with a baseline or clicks in bucket and returns the updated baselines, the links whose
+// baselines decayed away and the alerts to send.
+func (j *TrafficAnomalyJob) detect(bucket models.TrafficBucket, settings map[string]models.AlertSettings) ([]models.TrafficBaseline, []string, []models.TrafficAlert) {
+	updated := make([]models.TrafficBaseline, 0, len(bucket.Baselines)+len(bucket.Clicks))
+	var deleted []string
+	var alerts []models.TrafficAlert
+	for _, baseline := range bucket.Baselines {
+		clicks := bucket.Clicks[baseline.ShortPath]
+		if alert := j.check(&baseline, clicks, bucket, settings[baseline.ShortPath]); alert != nil {
+			alerts = append(alerts, *alert)
+		}
+		j.observe(&baseline, clicks)
+		if clicks == 0 && baseline.Mean < minTrafficBaselineMean {
+			deleted = append(deleted, baseline.ShortPath)
+			continue
+		}
+		updated = append(updated, baseline)
+	}
+
+	known := make(map[string]struct{}, len(bucket.Baselines))
+	for _, baseline := range bucket.Baselines {
+		known[baseline.ShortPath] = struct{}{}
+	}
+	for shortPath, clicks := range bucket.Clicks {
+		if _, ok := known[shortPath]; !ok {
+			updated = append(updated, models.TrafficBaseline{ShortPath: shortPath, Mean: float64(clicks), Samples: 1, State: models.TrafficStateNormal})
+		}
+	}
+	return updated, deleted, alerts
+}
+
+// check moves the baseline to the state of clicks and returns an alert when it entered a spike or drop, unless the
+// link alerted within the cooldown.
+func (j *TrafficAnomalyJob) check(baseline *models.TrafficBaseline, clicks int64, bucket models.TrafficBucket, settings models.AlertSettings) *models.TrafficAlert {
+	spikeZ, dropZ, minClicks, enabled := j.thresholds(baseline.ShortPath, settings)
+	deviation := math.Max(math.Sqrt(baseline.Variance), math.Max(1, math.Sqrt(baseline.Mean)))
+	zScore := (float64(clicks) - baseline.Mean) / deviation
+
+	state := models.TrafficStateNormal
+	if enabled && baseline.Samples >= j.config.WarmupBuckets {
+		if zScore >= spikeZ && clicks >= int64(minClicks) {
+			state = models.TrafficStateSpike
+		} else if zScore <= -dropZ && baseline.Mean >= float64(minClicks) {
+			state = models.TrafficStateDrop
+		}
+	}
+	previous := baseline.State
+	baseline.State = state
+	if state == models.TrafficStateNormal || state == previous {
+		return nil
+	}
+	if baseline.AlertedAt != nil && bucket.To.Sub(*baseline.AlertedAt) < j.config.Cooldown {
+		return nil
+	}
+	alertedAt := bucket.To
+	baseline.AlertedAt = &alertedAt
+	return &models.TrafficAlert{
+		Kind:        state,
+		ShortPath:   baseline.ShortPath,
+		BucketStart: bucket.From,
+		BucketEnd:   bucket.To,
+		Clicks:      clicks,
+		Baseline:    baseline.Mean,
+		ZScore:      zScore,
+		DedupKey:    baseline.ShortPath + ":" + state + ":" + bucket.From.UTC().Format(time.RFC3339),
+	}
+}
+
+// observe adds the clicks of a bucket to the exponentially weighted mean and variance.
+func (j *TrafficAnomalyJob) observe(baseline *models.TrafficBaseline, clicks int64) {
+	diff := float64(clicks) - baseline.Mean
+	baseline.Mean += j.alpha * diff
+	baseline.Variance = (1 - j.alpha) * (baseline.Variance + j.alpha*diff*diff)
+	baseline.Samples++
+}
+
+func (j *TrafficAnomalyJob) thresholds(shortPath string, settings models.AlertSettings) (float64, float64, int, bool) {
+	spikeZ, dropZ, minClicks, enabled := j.config.SpikeZ, j.config.DropZ, j.config.MinClicks, true
+	if settings.ShortPath != shortPath {
+		return spikeZ, dropZ, minClicks, enabled
+	}
+	if settings.SpikeZ != nil {
+		spikeZ = *settings.SpikeZ
+	}
+	if settings.DropZ != nil {
+		dropZ = *settings.DropZ
+	}
+	if settings.MinClicks != nil {
+		minClicks = *settings.MinClicks
+	}
+	return spikeZ, dropZ, minClicks, settings.Enabled
+}
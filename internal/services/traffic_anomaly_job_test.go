@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	repoMocks "url-shortener/internal/repositories/mocks"
+	"url-shortener/internal/services/mocks"
+	utilsMocks "url-shortener/internal/utils/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testAlertsConfig = config.AlertsConfig{
+	Bucket:        5 * time.Minute,
+	Lateness:      time.Minute,
+	HalfLife:      time.Hour,
+	WarmupBuckets: 12,
+	SpikeZ:        4,
+	DropZ:         3,
+	MinClicks:     20,
+	Cooldown:      time.Hour,
+}
+
+func testTrafficBucket(clicks map[string]int64, baselines ...models.TrafficBaseline) models.TrafficBucket {
+	from := time.Date(2025, 6, 12, 10, 0, 0, 0, time.UTC)
+	return models.TrafficBucket{From: from, To: from.Add(5 * time.Minute), Clicks: clicks, Baselines: baselines}
+}
+
+func TestTrafficAnomalyJob_DetectsSpikeOnce(t *testing.T) {
+	job := NewTrafficAnomalyJob(nil, nil, testAlertsConfig, nil)
+	baseline := models.TrafficBaseline{ShortPath: "abc", Mean: 30, Variance: 36, Samples: 100, State: models.TrafficStateNormal}
+
+	updated, deleted, alerts := job.detect(testTrafficBucket(map[string]int64{"abc": 90}, baseline), nil)
+
+	assert.Empty(t, deleted)
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, models.TrafficStateSpike, alerts[0].Kind)
+	assert.Equal(t, int64(90), alerts[0].Clicks)
+	assert.InDelta(t, 10.0, alerts[0].ZScore, 0.001)
+	assert.Equal(t, "abc:spike:2025-06-12T10:00:00Z", alerts[0].DedupKey)
+	assert.Len(t, updated, 1)
+	assert.Equal(t, models.TrafficStateSpike, updated[0].State)
+	assert.Equal(t, 101, updated[0].Samples)
+	assert.Greater(t, updated[0].Mean, 30.0)
+
+	// A spike that goes on does not alert again.
+	_, _, alerts = job.detect(testTrafficBucket(map[string]int64{"abc": 90}, updated[0]), nil)
+	assert.Empty(t, alerts)
+}
+
+func TestTrafficAnomalyJob_DetectsDrop(t *testing.T) {
+	job := NewTrafficAnomalyJob(nil, nil, testAlertsConfig, nil)
+	baseline := models.TrafficBaseline{ShortPath: "abc", Mean: 100, Variance: 100, Samples: 100, State: models.TrafficStateNormal}
+
+	updated, _, alerts := job.detect(testTrafficBucket(map[string]int64{}, baseline), nil)
+
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, models.TrafficStateDrop, alerts[0].Kind)
+	assert.Equal(t, int64(0), alerts[0].Clicks)
+	assert.Equal(t, models.TrafficStateDrop, updated[0].State)
+}
+
+func TestTrafficAnomalyJob_NoAlertDuringWarmupOrBelowMinClicks(t *testing.T) {
+	job := NewTrafficAnomalyJob(nil, nil, testAlertsConfig, nil)
+	warmingUp := models.TrafficBaseline{ShortPath: "new", Mean: 30, Variance: 25, Samples: 5, State: models.TrafficStateNormal}
+	quiet := models.TrafficBaseline{ShortPath: "quiet", Mean: 0.5, Variance: 0.25, Samples: 100, State: models.TrafficStateNormal}
+
+	_, _, alerts := job.detect(testTrafficBucket(map[string]int64{"new": 90, "quiet": 10}, warmingUp, quiet), nil)
+
+	assert.Empty(t, alerts)
+}
+
+func TestTrafficAnomalyJob_CooldownSuppressesAlert(t *testing.T) {
+	job := NewTrafficAnomalyJob(nil, nil, testAlertsConfig, nil)
+	alertedAt := time.Date(2025, 6, 12, 9, 30, 0, 0, time.UTC)
+	baseline := models.TrafficBaseline{ShortPath: "abc", Mean: 30, Variance: 25, Samples: 100, State: models.TrafficStateNormal, AlertedAt: &alertedAt}
+
+	updated, _, alerts := job.detect(testTrafficBucket(map[string]int64{"abc": 90}, baseline), nil)
+
+	assert.Empty(t, alerts)
+	assert.Equal(t, models.TrafficStateSpike, updated[0].State)
+	assert.Equal(t, alertedAt, *updated[0].AlertedAt)
+}
+
+func TestTrafficAnomalyJob_PerLinkSettings(t *testing.T) {
+	job := NewTrafficAnomalyJob(nil, nil, testAlertsConfig, nil)
+	spikeZ := 20.0
+	settings := map[string]models.AlertSettings{
+		"strict":   {ShortPath: "strict", SpikeZ: &spikeZ, Enabled: true},
+		"disabled": {ShortPath: "disabled", Enabled: false},
+	}
+	strict := models.TrafficBaseline{ShortPath: "strict", Mean: 30, Variance: 25, Samples: 100, State: models.TrafficStateNormal}
+	disabled := models.TrafficBaseline{ShortPath: "disabled", Mean: 30, Variance: 25, Samples: 100, State: models.TrafficStateNormal}
+
+	_, _, alerts := job.detect(testTrafficBucket(map[string]int64{"strict": 90, "disabled": 90}, strict, disabled), settings)
+
+	assert.Empty(t, alerts)
+}
+
+func TestTrafficAnomalyJob_StartsAndDropsBaselines(t *testing.T) {
+	job := NewTrafficAnomalyJob(nil, nil, testAlertsConfig, nil)
+	idle := models.TrafficBaseline{ShortPath: "idle", Mean: 0.005, Samples: 500, State: models.TrafficStateNormal}
+
+	updated, deleted, alerts := job.detect(testTrafficBucket(map[string]int64{"fresh": 7}, idle), nil)
+
+	assert.Empty(t, alerts)
+	assert.Equal(t, []string{"idle"}, deleted)
+	assert.Equal(t, []models.TrafficBaseline{{ShortPath: "fresh", Mean: 7, Samples: 1, State: models.TrafficStateNormal}}, updated)
+}
+
+func TestTrafficAnomalyJob_RunQueuesAlertsOfEachBucket(t *testing.T) {
+	repo := &repoMocks.TrafficAnomalyRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	now := time.Date(2025, 6, 12, 10, 7, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now)
+	until := time.Date(2025, 6, 12, 10, 6, 0, 0, time.UTC)
+	baseline := models.TrafficBaseline{ShortPath: "abc", Mean: 30, Variance: 25, Samples: 100, State: models.TrafficStateNormal}
+	var alerts []models.TrafficAlert
+	repo.On("ListAlertSettings", context.Background()).Return(nil, nil).Once()
+	repo.On("ProcessNextBucket", context.Background(), until, 5*time.Minute, mock.Anything).
+		Run(func(args mock.Arguments) {
+			process := args.Get(3).(func(models.TrafficBucket) ([]models.TrafficBaseline, []string, []models.TrafficAlert, error))
+			_, _, alerts, _ = process(testTrafficBucket(map[string]int64{"abc": 90}, baseline))
+		}).Return(true, nil).Once()
+	repo.On("ProcessNextBucket", context.Background(), until, 5*time.Minute, mock.Anything).Return(false, nil).Once()
+	repo.On("ClaimAlert", context.Background(), now, now.Add(time.Minute)).Return(nil, nil).Once()
+	job := NewTrafficAnomalyJob(repo, &mocks.AlertNotifier{}, testAlertsConfig, timeProvider)
+
+	err := job.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, "abc", alerts[0].ShortPath)
+	assert.Equal(t, models.TrafficStateSpike, alerts[0].Kind)
+	repo.AssertExpectations(t)
+}
+
+func TestTrafficAnomalyJob_RunSendsQueuedAlerts(t *testing.T) {
+	repo := &repoMocks.TrafficAnomalyRepository{}
+	notifier := &mocks.AlertNotifier{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	now := time.Date(2025, 6, 12, 10, 7, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now)
+	alertsConfig := testAlertsConfig
+	alertsConfig.Webhooks = []string{"https://a.example.com", "https://b.example.com"}
+	alertsConfig.WebhookTimeout = 10 * time.Second
+	lease := now.Add(2 * (30*time.Second + 3*time.Second))
+	sent := models.TrafficAlert{ID: 1, Attempts: 1, Kind: models.TrafficStateSpike, ShortPath: "abc"}
+	failing := models.TrafficAlert{ID: 2, Attempts: 3, Kind: models.TrafficStateDrop, ShortPath: "def"}
+	exhausted := models.TrafficAlert{ID: 3, Attempts: alertMaxAttempts, Kind: models.TrafficStateDrop, ShortPath: "ghi"}
+	repo.On("ListAlertSettings", context.Background()).Return(nil, nil).Once()
+	repo.On("ProcessNextBucket", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(false, nil).Once()
+	repo.On("ClaimAlert", context.Background(), now, lease).Return(&sent, nil).Once()
+	repo.On("ClaimAlert", context.Background(), now, lease).Return(&failing, nil).Once()
+	repo.On("ClaimAlert", context.Background(), now, lease).Return(&exhausted, nil).Once()
+	repo.On("ClaimAlert", context.Background(), now, lease).Return(nil, nil).Once()
+	notifier.On("Notify", context.Background(), sent).Return(nil).Once()
+	notifier.On("Notify", context.Background(), failing).Return(assert.AnError).Once()
+	notifier.On("Notify", context.Background(), exhausted).Return(assert.AnError).Once()
+	repo.On("DeleteAlert", context.Background(), int64(1)).Return(nil).Once()
+	repo.On("RetryAlert", context.Background(), int64(2), now.Add(4*time.Minute)).Return(nil).Once()
+	repo.On("DeleteAlert", context.Background(), int64(3)).Return(nil).Once()
+	job := NewTrafficAnomalyJob(repo, notifier, alertsConfig, timeProvider)
+
+	// A webhook that fails is retried later, it does not stop the job.
+	err := job.Run(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
+
+func TestTrafficAnomalyJob_RunError(t *testing.T) {
+	repo := &repoMocks.TrafficAnomalyRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	now := time.Date(2025, 6, 12, 10, 7, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now)
+	repo.On("ListAlertSettings", context.Background()).Return(nil, nil).Once()
+	repo.On("ProcessNextBucket", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(false, assert.AnError).Once()
+	// Alerts already queued are still sent.
+	repo.On("ClaimAlert", context.Background(), now, now.Add(time.Minute)).Return(nil, nil).Once()
+	job := NewTrafficAnomalyJob(repo, &mocks.AlertNotifier{}, testAlertsConfig, timeProvider)
+
+	err := job.Run(context.Background())
+
+	assert.ErrorIs(t, err, assert.AnError)
+	repo.AssertExpectations(t)
+}
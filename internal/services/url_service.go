@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"log"
+	neturl "net/url"
 	"time"
 
 	"url-shortener/internal/models"
@@ -10,28 +11,51 @@ import (
 	"url-shortener/internal/utils"
 )
 
+const (
+	defaultArchiveLimit = 50
+	maxArchiveLimit     = 500
+)
+
 //go:generate mockery --name=URLService --output=./mocks
 type URLService interface {
-	CreateShortURL(ctx context.Context, originalURL string, expiry *time.Time) (string, error)
-	GetLongURL(ctx context.Context, shortPath string) (string, error)
-	DeleteURL(ctx context.Context, shortPath string) error
-	UpdateShortURL(ctx context.Context, originalUrl string, shortUrl string, expiry *time.Time) error
+	// CreateShortURL, DeleteURL and UpdateShortURL record the change in the audit log as made by actor, which may
+	// be nil for changes the system makes.
+	CreateShortURL(ctx context.Context, originalURL string, expiry *time.Time, actor *models.AuditActor) (string, error)
+	GetLongURL(ctx context.Context, shortPath string, accessLog *models.AccessLog) (string, error)
+	DeleteURL(ctx context.Context, shortPath string, actor *models.AuditActor) error
+	UpdateShortURL(ctx context.Context, originalUrl string, shortUrl string, expiry *time.Time, actor *models.AuditActor) error
 	GetURLDetails(ctx context.Context, shortPath string) (*models.URL, error)
+	// RestoreURL moves the latest archived version of a deleted or expired link back, see
+	// repositories.URLArchiveRepository for its errors.
+	RestoreURL(ctx context.Context, shortPath string, actor *models.AuditActor) (*models.URL, error)
+	// ListArchivedURLs returns the latest archived versions of shortPath, or of every link when it is empty. limit
+	// defaults to 50 and is at most 500.
+	ListArchivedURLs(ctx context.Context, shortPath string, before int64, limit int) ([]models.URLArchive, error)
 }
 
 type urlServiceImpl struct {
-	repo         repositories.URLRepository
-	statRepo     repositories.URLStatisticsRepository
-	idGenerator  utils.NanoIDGenerator
-	timeProvider utils.TimeProvider
+	repo          repositories.URLRepository
+	archive       repositories.URLArchiveRepository
+	accessLogger  AccessLogger
+	clickCounters repositories.ClickCounterRepository
+	clickDedup    repositories.ClickDedupRepository
+	issuedClicks  repositories.IssuedClickRepository
+	clickStream   ClickStreamPublisher
+	idGenerator   utils.NanoIDGenerator
+	timeProvider  utils.TimeProvider
+	// clickIDParam is the query parameter click IDs are added to destinations as, empty issues none.
+	clickIDParam string
+	// dedupWindow is how long repeated clicks of a visitor are marked as duplicates, zero marks none.
+	dedupWindow time.Duration
 }
 
-func NewURLService(repo repositories.URLRepository, statRepo repositories.URLStatisticsRepository, idGenerator utils.NanoIDGenerator, timeProvider utils.TimeProvider) URLService {
-	return &urlServiceImpl{repo: repo, statRepo: statRepo, idGenerator: idGenerator, timeProvider: timeProvider}
+func NewURLService(repo repositories.URLRepository, archive repositories.URLArchiveRepository, accessLogger AccessLogger, clickCounters repositories.ClickCounterRepository, clickDedup repositories.ClickDedupRepository, issuedClicks repositories.IssuedClickRepository, clickStream ClickStreamPublisher, idGenerator utils.NanoIDGenerator, timeProvider utils.TimeProvider, clickIDParam string, dedupWindow time.Duration) URLService {
+	return &urlServiceImpl{repo: repo, archive: archive, accessLogger: accessLogger, clickCounters: clickCounters, clickDedup: clickDedup, issuedClicks: issuedClicks, clickStream: clickStream, idGenerator: idGenerator, timeProvider: timeProvider, clickIDParam: clickIDParam, dedupWindow: dedupWindow}
 }
 
-// CreateShortURL implements URLService.
-func (s *urlServiceImpl) CreateShortURL(ctx context.Context, originalURL string, expiry *time.Time) (string, error) {
+// CreateShortURL implements URLService. An original URL that is already shortened returns its short path and is not
+// audited, nothing changed.
+func (s *urlServiceImpl) CreateShortURL(ctx context.Context, originalURL string, expiry *time.Time, actor *models.AuditActor) (string, error) {
 	existingURL, err := s.repo.GetShortURL(ctx, originalURL)
 	if err != nil {
 		return "", err
@@ -45,7 +69,7 @@ func (s *urlServiceImpl) CreateShortURL(ctx context.Context, originalURL string,
 		OriginalURL: originalURL,
 		Expiry:      expiry,
 		CreatedAt:   &currentTime,
-		CreatedBy:   "system",
+		CreatedBy:   actorName(actor),
 	}
 
 	shortPath, err := s.idGenerator.Generate()
@@ -54,7 +78,7 @@ func (s *urlServiceImpl) CreateShortURL(ctx context.Context, originalURL string,
 	}
 	shortURL.ShortPath = shortPath
 
-	err = s.repo.InsertShortURL(ctx, shortURL)
+	err = s.repo.InsertShortURL(ctx, shortURL, actor)
 	if err != nil {
 		return "", err
 	}
@@ -62,8 +86,13 @@ func (s *urlServiceImpl) CreateShortURL(ctx context.Context, originalURL string,
 	return shortPath, nil
 }
 
-// GetLongURL implements URLService.
-func (s *urlServiceImpl) GetLongURL(ctx context.Context, shortPath string) (string, error) {
+// GetLongURL implements URLService. accessLog carries the request metadata of the click and may be nil. Clicks by
+// people are also counted in Redis straight away, the redirect does not fail when that does. Every click is
+// published to the live click streams of the link and its owner. With click IDs on, the returned destination
+// carries the ID of the click so the destination can report conversions, and the ID is recorded in Redis so
+// they can be reported before the click reaches Postgres. Repeated clicks of a visitor within the
+// de-dup window are still logged, marked as duplicates, but not counted.
+func (s *urlServiceImpl) GetLongURL(ctx context.Context, shortPath string, accessLog *models.AccessLog) (string, error) {
 	url, err := s.repo.GetOriginalURL(ctx, shortPath)
 	if err != nil {
 		return "", err
@@ -71,20 +100,73 @@ func (s *urlServiceImpl) GetLongURL(ctx context.Context, shortPath string) (stri
 	if url == nil {
 		return "", nil
 	}
-	go func() {
-		err := s.statRepo.InsertAccessLog(context.Background(), shortPath, s.timeProvider.Now())
+	if accessLog == nil {
+		accessLog = &models.AccessLog{}
+	}
+	accessLog.ShortPath = shortPath
+	accessLog.AccessedAt = s.timeProvider.Now()
+	destination := url.OriginalURL
+	// A click ID would let the destination link the visit back to the click, so clicks that opted out get none.
+	if s.clickIDParam != "" && !accessLog.DoNotTrack {
+		clickID, err := s.idGenerator.Generate()
 		if err != nil {
-			// Log the error.  Consider adding more robust error handling here.
-			println("Error inserting access log:", err)
+			// The click is still redirected and logged, it just cannot convert.
+			log.Printf("Error generating click ID: %v", err)
+		} else {
+			accessLog.ClickID = clickID
+			destination = appendQueryParam(destination, s.clickIDParam, clickID)
+			// Conversions reported before the click is stored are attributed through this, the repository
+			// logs the error.
+			_ = s.issuedClicks.Issue(ctx, &models.IssuedClick{ClickID: clickID, ShortPath: shortPath, ClickedAt: accessLog.AccessedAt})
 		}
-	}()
+	}
+	if s.dedupWindow > 0 && accessLog.VisitorID != "" {
+		// A click that cannot be checked is counted, the repository logs the error.
+		first, err := s.clickDedup.MarkClick(ctx, shortPath, accessLog.VisitorID, s.dedupWindow)
+		accessLog.IsDuplicate = err == nil && !first
+	}
+	// The event is built before the access is queued, the pipeline fills in the location concurrently.
+	s.clickStream.Publish(models.ClickStreamEvent{
+		ShortPath:    shortPath,
+		Owner:        url.CreatedBy,
+		AccessedAt:   accessLog.AccessedAt,
+		ReferrerHost: accessLog.ReferrerHost,
+		Browser:      accessLog.Browser,
+		OS:           accessLog.OS,
+		DeviceClass:  accessLog.DeviceClass,
+		IsBot:        accessLog.IsBot,
+		ClientIP:     accessLog.ClientIP,
+		DoNotTrack:   accessLog.DoNotTrack,
+	})
+	s.accessLogger.Log(accessLog)
+	if !accessLog.IsBot && !accessLog.IsDuplicate {
+		// The repository logs the error.
+		_ = s.clickCounters.Increment(ctx, shortPath, accessLog.AccessedAt)
+	}
 
-	return url.OriginalURL, nil
+	return destination, nil
+}
+
+// appendQueryParam adds name=value to the query of rawURL, keeping the existing query as it is rather than
+// re-encoding it. rawURL is returned unchanged when it cannot be parsed.
+func appendQueryParam(rawURL string, name string, value string) string {
+	parsed, err := neturl.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	param := neturl.QueryEscape(name) + "=" + neturl.QueryEscape(value)
+	if parsed.RawQuery == "" {
+		parsed.RawQuery = param
+	} else {
+		parsed.RawQuery += "&" + param
+	}
+	parsed.ForceQuery = false
+	return parsed.String()
 }
 
 // DeleteURL implements URLService.
-func (s *urlServiceImpl) DeleteURL(ctx context.Context, shortPath string) error {
-	err := s.repo.DeleteShortURL(ctx, shortPath, s.timeProvider.Now(), "system")
+func (s *urlServiceImpl) DeleteURL(ctx context.Context, shortPath string, actor *models.AuditActor) error {
+	err := s.repo.DeleteShortURL(ctx, shortPath, s.timeProvider.Now(), actorName(actor), actor)
 	if err != nil {
 		log.Printf(err.Error())
 		return err
@@ -92,10 +174,10 @@ func (s *urlServiceImpl) DeleteURL(ctx context.Context, shortPath string) error
 	return nil
 }
 
-// UpdateShortURL implements URLService
-func (s *urlServiceImpl) UpdateShortURL(ctx context.Context, originalUrl string, shortUrl string, expiry *time.Time) error {
+// UpdateShortURL implements URLService. Updating a link that does not exist changes nothing and is not audited.
+func (s *urlServiceImpl) UpdateShortURL(ctx context.Context, originalUrl string, shortUrl string, expiry *time.Time, actor *models.AuditActor) error {
 	currentTime := s.timeProvider.Now()
-	modifiedBy := "system"
+	modifiedBy := actorName(actor)
 	urlUpdate := &models.URL{
 		OriginalURL: originalUrl,
 		ShortPath:   shortUrl,
@@ -103,7 +185,7 @@ func (s *urlServiceImpl) UpdateShortURL(ctx context.Context, originalUrl string,
 		ModifiedAt:  &currentTime,
 		ModifiedBy:  &modifiedBy,
 	}
-	err := s.repo.UpdateShortURL(ctx, urlUpdate)
+	err := s.repo.UpdateShortURL(ctx, urlUpdate, actor)
 	if err != nil {
 		log.Printf(err.Error())
 		return err
@@ -119,3 +201,24 @@ func (s *urlServiceImpl) GetURLDetails(ctx context.Context, shortPath string) (*
 	}
 	return url, nil
 }
+
+// RestoreURL implements URLService.
+func (s *urlServiceImpl) RestoreURL(ctx context.Context, shortPath string, actor *models.AuditActor) (*models.URL, error) {
+	return s.archive.RestoreShortURL(ctx, shortPath, s.timeProvider.Now(), actorName(actor), actor)
+}
+
+// ListArchivedURLs implements URLService.
+func (s *urlServiceImpl) ListArchivedURLs(ctx context.Context, shortPath string, before int64, limit int) ([]models.URLArchive, error) {
+	if limit <= 0 {
+		limit = defaultArchiveLimit
+	}
+	limit = min(limit, maxArchiveLimit)
+	return s.archive.ListArchivedURLs(ctx, shortPath, max(before, 0), limit)
+}
+
+func actorName(actor *models.AuditActor) string {
+	if actor == nil || actor.Actor == "" {
+		return "system"
+	}
+	return actor.Actor
+}
@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
 	repoMocks "url-shortener/internal/repositories/mocks"
+	"url-shortener/internal/services/mocks"
 	utilsMocks "url-shortener/internal/utils/mocks"
 
 	"github.com/stretchr/testify/assert"
@@ -17,8 +19,11 @@ import (
 func TestURLServiceImpl_CreateShortURL(t *testing.T) {
 	repo := &repoMocks.URLRepository{}
 	defer repo.AssertExpectations(t)
-	statRepo := &repoMocks.URLStatisticsRepository{}
-	defer statRepo.AssertExpectations(t)
+	accessLogger := &mocks.AccessLogger{}
+	defer accessLogger.AssertExpectations(t)
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	defer clickCounters.AssertExpectations(t)
 	idGenerator := &utilsMocks.NanoIDGenerator{}
 	defer idGenerator.AssertExpectations(t)
 	timeProvider := &utilsMocks.TimeProvider{}
@@ -32,10 +37,10 @@ func TestURLServiceImpl_CreateShortURL(t *testing.T) {
 	idGenerator.On("Generate").Return(shortPath, nil).Once()
 	timeProvider.On("Now").Return(currentTime).Once()
 	repo.On("GetShortURL", ctx, originalURL).Return(nil, nil).Once()
-	repo.On("InsertShortURL", ctx, mock.Anything).Return(nil).Once()
+	repo.On("InsertShortURL", ctx, mock.Anything, (*models.AuditActor)(nil)).Return(nil).Once()
 
-	service := NewURLService(repo, statRepo, idGenerator, timeProvider)
-	shortPathGenerated, err := service.CreateShortURL(ctx, originalURL, &expiry)
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, idGenerator, timeProvider, "", 0)
+	shortPathGenerated, err := service.CreateShortURL(ctx, originalURL, &expiry, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, shortPath, shortPathGenerated)
 	repo.AssertExpectations(t)
@@ -46,8 +51,11 @@ func TestURLServiceImpl_CreateShortURL(t *testing.T) {
 func TestURLServiceImpl_CreateShortURL_DBError(t *testing.T) {
 	repo := &repoMocks.URLRepository{}
 	defer repo.AssertExpectations(t)
-	statRepo := &repoMocks.URLStatisticsRepository{}
-	defer statRepo.AssertExpectations(t)
+	accessLogger := &mocks.AccessLogger{}
+	defer accessLogger.AssertExpectations(t)
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	defer clickCounters.AssertExpectations(t)
 	idGenerator := &utilsMocks.NanoIDGenerator{}
 	defer idGenerator.AssertExpectations(t)
 	timeProvider := &utilsMocks.TimeProvider{}
@@ -59,9 +67,9 @@ func TestURLServiceImpl_CreateShortURL_DBError(t *testing.T) {
 	idGenerator.On("Generate").Return(shortPath, nil).Once()
 	timeProvider.On("Now").Return(time.Now()).Once()
 	repo.On("GetShortURL", ctx, originalURL).Return(nil, nil).Once()
-	repo.On("InsertShortURL", ctx, mock.Anything).Return(errors.New("Internal")).Once()
-	service := NewURLService(repo, statRepo, idGenerator, timeProvider)
-	_, err := service.CreateShortURL(ctx, originalURL, &expiry)
+	repo.On("InsertShortURL", ctx, mock.Anything, (*models.AuditActor)(nil)).Return(errors.New("Internal")).Once()
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, idGenerator, timeProvider, "", 0)
+	_, err := service.CreateShortURL(ctx, originalURL, &expiry, nil)
 	assert.NotNil(t, err)
 	repo.AssertExpectations(t)
 	idGenerator.AssertExpectations(t)
@@ -71,8 +79,11 @@ func TestURLServiceImpl_CreateShortURL_DBError(t *testing.T) {
 func TestURLServiceImpl_CreateShortURL_IDError(t *testing.T) {
 	repo := &repoMocks.URLRepository{}
 	defer repo.AssertExpectations(t)
-	statRepo := &repoMocks.URLStatisticsRepository{}
-	defer statRepo.AssertExpectations(t)
+	accessLogger := &mocks.AccessLogger{}
+	defer accessLogger.AssertExpectations(t)
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	defer clickCounters.AssertExpectations(t)
 	idGenerator := &utilsMocks.NanoIDGenerator{}
 	defer idGenerator.AssertExpectations(t)
 	timeProvider := &utilsMocks.TimeProvider{}
@@ -84,8 +95,8 @@ func TestURLServiceImpl_CreateShortURL_IDError(t *testing.T) {
 	repo.On("GetShortURL", ctx, originalURL).Return(nil, nil).Once()
 	idGenerator.On("Generate").Return("", errors.New("Internal")).Once()
 	timeProvider.On("Now").Return(currentTime).Once()
-	service := NewURLService(repo, statRepo, idGenerator, timeProvider)
-	_, err := service.CreateShortURL(ctx, originalURL, &expiry)
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, idGenerator, timeProvider, "", 0)
+	_, err := service.CreateShortURL(ctx, originalURL, &expiry, nil)
 	assert.NotNil(t, err)
 	repo.AssertExpectations(t)
 	idGenerator.AssertExpectations(t)
@@ -95,8 +106,11 @@ func TestURLServiceImpl_CreateShortURL_IDError(t *testing.T) {
 func TestURLServiceImpl_CreateShortURL_URLFound(t *testing.T) {
 	repo := &repoMocks.URLRepository{}
 	defer repo.AssertExpectations(t)
-	statRepo := &repoMocks.URLStatisticsRepository{}
-	defer statRepo.AssertExpectations(t)
+	accessLogger := &mocks.AccessLogger{}
+	defer accessLogger.AssertExpectations(t)
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	defer clickCounters.AssertExpectations(t)
 	idGenerator := &utilsMocks.NanoIDGenerator{}
 	defer idGenerator.AssertExpectations(t)
 	timeProvider := &utilsMocks.TimeProvider{}
@@ -111,8 +125,8 @@ func TestURLServiceImpl_CreateShortURL_URLFound(t *testing.T) {
 		Expiry:      &expiry,
 	}
 	repo.On("GetShortURL", ctx, originalURL).Return(shortURL, nil).Once()
-	service := NewURLService(repo, statRepo, idGenerator, timeProvider)
-	shortPathGenerated, err := service.CreateShortURL(ctx, originalURL, &expiry)
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, idGenerator, timeProvider, "", 0)
+	shortPathGenerated, err := service.CreateShortURL(ctx, originalURL, &expiry, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, shortPath, shortPathGenerated)
 	repo.AssertExpectations(t)
@@ -123,8 +137,11 @@ func TestURLServiceImpl_CreateShortURL_URLFound(t *testing.T) {
 func TestURLServiceImpl_CreateShortURL_RepoError(t *testing.T) {
 	repo := &repoMocks.URLRepository{}
 	defer repo.AssertExpectations(t)
-	statRepo := &repoMocks.URLStatisticsRepository{}
-	defer statRepo.AssertExpectations(t)
+	accessLogger := &mocks.AccessLogger{}
+	defer accessLogger.AssertExpectations(t)
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	defer clickCounters.AssertExpectations(t)
 	idGenerator := &utilsMocks.NanoIDGenerator{}
 	defer idGenerator.AssertExpectations(t)
 	timeProvider := &utilsMocks.TimeProvider{}
@@ -133,8 +150,8 @@ func TestURLServiceImpl_CreateShortURL_RepoError(t *testing.T) {
 	originalURL := "https://www.example.com"
 	expiry := time.Now().Add(time.Minute * 60)
 	repo.On("GetShortURL", ctx, originalURL).Return(nil, errors.New("Internal")).Once()
-	service := NewURLService(repo, statRepo, idGenerator, timeProvider)
-	_, err := service.CreateShortURL(ctx, originalURL, &expiry)
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, idGenerator, timeProvider, "", 0)
+	_, err := service.CreateShortURL(ctx, originalURL, &expiry, nil)
 	assert.NotNil(t, err)
 	repo.AssertExpectations(t)
 	idGenerator.AssertExpectations(t)
@@ -144,8 +161,11 @@ func TestURLServiceImpl_CreateShortURL_RepoError(t *testing.T) {
 func TestURLServiceImpl_GetLongURL(t *testing.T) {
 	repo := &repoMocks.URLRepository{}
 	defer repo.AssertExpectations(t)
-	statRepo := &repoMocks.URLStatisticsRepository{}
-	defer statRepo.AssertExpectations(t)
+	accessLogger := &mocks.AccessLogger{}
+	defer accessLogger.AssertExpectations(t)
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	defer clickCounters.AssertExpectations(t)
 	idGenerator := &utilsMocks.NanoIDGenerator{}
 	defer idGenerator.AssertExpectations(t)
 	timeProvider := &utilsMocks.TimeProvider{}
@@ -156,29 +176,229 @@ func TestURLServiceImpl_GetLongURL(t *testing.T) {
 	url := &models.URL{
 		OriginalURL: originalURL,
 		ShortPath:   shortPath,
+		CreatedBy:   "alice",
 	}
 
 	currentTime := time.Now()
 
 	repo.On("GetOriginalURL", ctx, shortPath).Return(url, nil).Once()
 	timeProvider.On("Now").Return(currentTime).Once()
-	statRepo.On("InsertAccessLog", mock.Anything, shortPath, currentTime).Return(nil).Once()
-	service := NewURLService(repo, statRepo, idGenerator, timeProvider)
-	longURL, err := service.GetLongURL(ctx, shortPath)
-	assert.Eventually(t, func() bool {
-		return err == nil
-	}, time.Millisecond*100, time.Millisecond*10)
+	accessLogger.On("Log", &models.AccessLog{ShortPath: shortPath, AccessedAt: currentTime, ReferrerHost: "example.org", Browser: "Firefox"}).Return().Once()
+	clickCounters.On("Increment", ctx, shortPath, currentTime).Return(nil).Once()
+	clickStream.On("Publish", models.ClickStreamEvent{ShortPath: shortPath, Owner: "alice", AccessedAt: currentTime, ReferrerHost: "example.org", Browser: "Firefox"}).Return().Once()
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, idGenerator, timeProvider, "", 0)
+	longURL, err := service.GetLongURL(ctx, shortPath, &models.AccessLog{ReferrerHost: "example.org", Browser: "Firefox"})
+	assert.Nil(t, err)
 	assert.Equal(t, originalURL, longURL)
 	repo.AssertExpectations(t)
+	clickStream.AssertExpectations(t)
 	idGenerator.AssertExpectations(t)
 	timeProvider.AssertExpectations(t)
 }
 
+func TestURLServiceImpl_GetLongURL_DoesNotCountBots(t *testing.T) {
+	repo := &repoMocks.URLRepository{}
+	accessLogger := &mocks.AccessLogger{}
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	currentTime := time.Now()
+	repo.On("GetOriginalURL", ctx, "shortPath").Return(&models.URL{ShortPath: "shortPath", OriginalURL: "https://www.example.com"}, nil).Once()
+	timeProvider.On("Now").Return(currentTime).Once()
+	accessLogger.On("Log", &models.AccessLog{ShortPath: "shortPath", AccessedAt: currentTime, IsBot: true}).Return().Once()
+	clickStream.On("Publish", mock.Anything).Return().Once()
+
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, &utilsMocks.NanoIDGenerator{}, timeProvider, "", 0)
+	longURL, err := service.GetLongURL(ctx, "shortPath", &models.AccessLog{IsBot: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "https://www.example.com", longURL)
+	accessLogger.AssertExpectations(t)
+	clickCounters.AssertNotCalled(t, "Increment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestURLServiceImpl_GetLongURL_CounterErrorDoesNotFailRedirect(t *testing.T) {
+	repo := &repoMocks.URLRepository{}
+	accessLogger := &mocks.AccessLogger{}
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	currentTime := time.Now()
+	repo.On("GetOriginalURL", ctx, "shortPath").Return(&models.URL{ShortPath: "shortPath", OriginalURL: "https://www.example.com"}, nil).Once()
+	timeProvider.On("Now").Return(currentTime).Once()
+	accessLogger.On("Log", mock.Anything).Return().Once()
+	clickCounters.On("Increment", ctx, "shortPath", currentTime).Return(assert.AnError).Once()
+	clickStream.On("Publish", mock.Anything).Return().Once()
+
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, &utilsMocks.NanoIDGenerator{}, timeProvider, "", 0)
+	longURL, err := service.GetLongURL(ctx, "shortPath", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "https://www.example.com", longURL)
+	clickCounters.AssertExpectations(t)
+}
+
+func TestURLServiceImpl_GetLongURL_AppendsClickID(t *testing.T) {
+	repo := &repoMocks.URLRepository{}
+	issuedClicks := &repoMocks.IssuedClickRepository{}
+	accessLogger := &mocks.AccessLogger{}
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	idGenerator := &utilsMocks.NanoIDGenerator{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	currentTime := time.Now()
+	repo.On("GetOriginalURL", ctx, "shortPath").Return(&models.URL{ShortPath: "shortPath", OriginalURL: "https://www.example.com/landing?utm_source=news%20letter#pricing"}, nil).Once()
+	timeProvider.On("Now").Return(currentTime).Once()
+	idGenerator.On("Generate").Return("clk_123", nil).Once()
+	issuedClicks.On("Issue", ctx, &models.IssuedClick{ClickID: "clk_123", ShortPath: "shortPath", ClickedAt: currentTime}).Return(nil).Once()
+	accessLogger.On("Log", &models.AccessLog{ShortPath: "shortPath", AccessedAt: currentTime, ClickID: "clk_123"}).Return().Once()
+	clickCounters.On("Increment", ctx, "shortPath", currentTime).Return(nil).Once()
+	clickStream.On("Publish", mock.Anything).Return().Once()
+
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, issuedClicks, clickStream, idGenerator, timeProvider, "click_id", 0)
+	longURL, err := service.GetLongURL(ctx, "shortPath", nil)
+
+	assert.Nil(t, err)
+	// The existing query is kept as it was and the fragment stays last.
+	assert.Equal(t, "https://www.example.com/landing?utm_source=news%20letter&click_id=clk_123#pricing", longURL)
+	accessLogger.AssertExpectations(t)
+	idGenerator.AssertExpectations(t)
+	issuedClicks.AssertExpectations(t)
+}
+
+func TestURLServiceImpl_GetLongURL_NoClickIDForDoNotTrack(t *testing.T) {
+	repo := &repoMocks.URLRepository{}
+	accessLogger := &mocks.AccessLogger{}
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	idGenerator := &utilsMocks.NanoIDGenerator{}
+	issuedClicks := &repoMocks.IssuedClickRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	currentTime := time.Now()
+	repo.On("GetOriginalURL", ctx, "shortPath").Return(&models.URL{ShortPath: "shortPath", OriginalURL: "https://www.example.com"}, nil).Once()
+	timeProvider.On("Now").Return(currentTime).Once()
+	accessLogger.On("Log", &models.AccessLog{ShortPath: "shortPath", AccessedAt: currentTime, DoNotTrack: true}).Return().Once()
+	clickCounters.On("Increment", ctx, "shortPath", currentTime).Return(nil).Once()
+	clickStream.On("Publish", mock.Anything).Return().Once()
+
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, issuedClicks, clickStream, idGenerator, timeProvider, "click_id", 0)
+	longURL, err := service.GetLongURL(ctx, "shortPath", &models.AccessLog{DoNotTrack: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "https://www.example.com", longURL)
+	accessLogger.AssertExpectations(t)
+	idGenerator.AssertNotCalled(t, "Generate")
+	issuedClicks.AssertNotCalled(t, "Issue", mock.Anything, mock.Anything)
+}
+
+func TestURLServiceImpl_GetLongURL_ClickIDErrorDoesNotFailRedirect(t *testing.T) {
+	repo := &repoMocks.URLRepository{}
+	accessLogger := &mocks.AccessLogger{}
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	idGenerator := &utilsMocks.NanoIDGenerator{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	currentTime := time.Now()
+	repo.On("GetOriginalURL", ctx, "shortPath").Return(&models.URL{ShortPath: "shortPath", OriginalURL: "https://www.example.com"}, nil).Once()
+	timeProvider.On("Now").Return(currentTime).Once()
+	idGenerator.On("Generate").Return("", assert.AnError).Once()
+	accessLogger.On("Log", &models.AccessLog{ShortPath: "shortPath", AccessedAt: currentTime}).Return().Once()
+	clickCounters.On("Increment", ctx, "shortPath", currentTime).Return(nil).Once()
+	clickStream.On("Publish", mock.Anything).Return().Once()
+
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, idGenerator, timeProvider, "click_id", 0)
+	longURL, err := service.GetLongURL(ctx, "shortPath", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "https://www.example.com", longURL)
+	accessLogger.AssertExpectations(t)
+}
+
+func TestURLServiceImpl_GetLongURL_MarksDuplicateClick(t *testing.T) {
+	repo := &repoMocks.URLRepository{}
+	accessLogger := &mocks.AccessLogger{}
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickDedup := &repoMocks.ClickDedupRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	currentTime := time.Now()
+	repo.On("GetOriginalURL", ctx, "shortPath").Return(&models.URL{ShortPath: "shortPath", OriginalURL: "https://www.example.com"}, nil).Once()
+	timeProvider.On("Now").Return(currentTime).Once()
+	clickDedup.On("MarkClick", ctx, "shortPath", "visitor", 30*time.Second).Return(false, nil).Once()
+	accessLogger.On("Log", &models.AccessLog{ShortPath: "shortPath", AccessedAt: currentTime, VisitorID: "visitor", IsDuplicate: true}).Return().Once()
+	clickStream.On("Publish", mock.Anything).Return().Once()
+
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, clickDedup, &repoMocks.IssuedClickRepository{}, clickStream, &utilsMocks.NanoIDGenerator{}, timeProvider, "", 30*time.Second)
+	longURL, err := service.GetLongURL(ctx, "shortPath", &models.AccessLog{VisitorID: "visitor"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "https://www.example.com", longURL)
+	accessLogger.AssertExpectations(t)
+	clickDedup.AssertExpectations(t)
+	clickCounters.AssertNotCalled(t, "Increment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestURLServiceImpl_GetLongURL_DedupErrorCountsClick(t *testing.T) {
+	repo := &repoMocks.URLRepository{}
+	accessLogger := &mocks.AccessLogger{}
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickDedup := &repoMocks.ClickDedupRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	currentTime := time.Now()
+	repo.On("GetOriginalURL", ctx, "shortPath").Return(&models.URL{ShortPath: "shortPath", OriginalURL: "https://www.example.com"}, nil).Once()
+	timeProvider.On("Now").Return(currentTime).Once()
+	clickDedup.On("MarkClick", ctx, "shortPath", "visitor", 30*time.Second).Return(false, assert.AnError).Once()
+	accessLogger.On("Log", &models.AccessLog{ShortPath: "shortPath", AccessedAt: currentTime, VisitorID: "visitor"}).Return().Once()
+	clickCounters.On("Increment", ctx, "shortPath", currentTime).Return(nil).Once()
+	clickStream.On("Publish", mock.Anything).Return().Once()
+
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, clickDedup, &repoMocks.IssuedClickRepository{}, clickStream, &utilsMocks.NanoIDGenerator{}, timeProvider, "", 30*time.Second)
+	longURL, err := service.GetLongURL(ctx, "shortPath", &models.AccessLog{VisitorID: "visitor"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "https://www.example.com", longURL)
+	accessLogger.AssertExpectations(t)
+	clickCounters.AssertExpectations(t)
+}
+
+func TestURLServiceImpl_GetLongURL_NoDedupWithoutVisitor(t *testing.T) {
+	repo := &repoMocks.URLRepository{}
+	accessLogger := &mocks.AccessLogger{}
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickDedup := &repoMocks.ClickDedupRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	currentTime := time.Now()
+	repo.On("GetOriginalURL", ctx, "shortPath").Return(&models.URL{ShortPath: "shortPath", OriginalURL: "https://www.example.com"}, nil).Once()
+	timeProvider.On("Now").Return(currentTime).Once()
+	accessLogger.On("Log", &models.AccessLog{ShortPath: "shortPath", AccessedAt: currentTime}).Return().Once()
+	clickCounters.On("Increment", ctx, "shortPath", currentTime).Return(nil).Once()
+	clickStream.On("Publish", mock.Anything).Return().Once()
+
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, clickDedup, &repoMocks.IssuedClickRepository{}, clickStream, &utilsMocks.NanoIDGenerator{}, timeProvider, "", 30*time.Second)
+	_, err := service.GetLongURL(ctx, "shortPath", nil)
+
+	assert.Nil(t, err)
+	clickDedup.AssertNotCalled(t, "MarkClick", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestURLServiceImpl_GetLongURL_RepoError(t *testing.T) {
 	repo := &repoMocks.URLRepository{}
 	defer repo.AssertExpectations(t)
-	statRepo := &repoMocks.URLStatisticsRepository{}
-	defer statRepo.AssertExpectations(t)
+	accessLogger := &mocks.AccessLogger{}
+	defer accessLogger.AssertExpectations(t)
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	defer clickCounters.AssertExpectations(t)
 	idGenerator := &utilsMocks.NanoIDGenerator{}
 	defer idGenerator.AssertExpectations(t)
 	timeProvider := &utilsMocks.TimeProvider{}
@@ -188,10 +408,9 @@ func TestURLServiceImpl_GetLongURL_RepoError(t *testing.T) {
 	shortPath := "shortPath"
 	repo.On("GetOriginalURL", ctx, shortPath).Return(nil, errors.New("Internal")).Once()
 	// timeProvider.On("Now").Return(currentTime).Once()
-	// statRepo.On("InsertAccessLog", mock.Anything, shortPath, currentTime).Return(nil).Once()
 
-	service := NewURLService(repo, statRepo, idGenerator, timeProvider)
-	_, err := service.GetLongURL(ctx, shortPath)
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, idGenerator, timeProvider, "", 0)
+	_, err := service.GetLongURL(ctx, shortPath, nil)
 	assert.NotNil(t, err)
 	repo.AssertExpectations(t)
 	idGenerator.AssertExpectations(t)
@@ -201,8 +420,11 @@ func TestURLServiceImpl_GetLongURL_RepoError(t *testing.T) {
 func TestURLServiceImpl_DeleteURL(t *testing.T) {
 	repo := &repoMocks.URLRepository{}
 	defer repo.AssertExpectations(t)
-	statRepo := &repoMocks.URLStatisticsRepository{}
-	defer statRepo.AssertExpectations(t)
+	accessLogger := &mocks.AccessLogger{}
+	defer accessLogger.AssertExpectations(t)
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	defer clickCounters.AssertExpectations(t)
 	idGenerator := &utilsMocks.NanoIDGenerator{}
 	defer idGenerator.AssertExpectations(t)
 	timeProvider := &utilsMocks.TimeProvider{}
@@ -211,10 +433,10 @@ func TestURLServiceImpl_DeleteURL(t *testing.T) {
 	shortPath := "shortPath"
 	currentTime := time.Now()
 	deletedBy := "system"
-	repo.On("DeleteShortURL", ctx, shortPath, currentTime, deletedBy).Return(nil).Once()
+	repo.On("DeleteShortURL", ctx, shortPath, currentTime, deletedBy, (*models.AuditActor)(nil)).Return(nil).Once()
 	timeProvider.On("Now").Return(currentTime).Once()
-	service := NewURLService(repo, statRepo, idGenerator, timeProvider)
-	err := service.DeleteURL(ctx, shortPath)
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, idGenerator, timeProvider, "", 0)
+	err := service.DeleteURL(ctx, shortPath, nil)
 	assert.Nil(t, err)
 	repo.AssertExpectations(t)
 	idGenerator.AssertExpectations(t)
@@ -224,8 +446,11 @@ func TestURLServiceImpl_DeleteURL(t *testing.T) {
 func TestURLServiceImpl_DeleteURL_RepoError(t *testing.T) {
 	repo := &repoMocks.URLRepository{}
 	defer repo.AssertExpectations(t)
-	statRepo := &repoMocks.URLStatisticsRepository{}
-	defer statRepo.AssertExpectations(t)
+	accessLogger := &mocks.AccessLogger{}
+	defer accessLogger.AssertExpectations(t)
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	defer clickCounters.AssertExpectations(t)
 	idGenerator := &utilsMocks.NanoIDGenerator{}
 	defer idGenerator.AssertExpectations(t)
 	timeProvider := &utilsMocks.TimeProvider{}
@@ -234,10 +459,10 @@ func TestURLServiceImpl_DeleteURL_RepoError(t *testing.T) {
 	shortPath := "shortPath"
 	currentTime := time.Now()
 	deletedBy := "system"
-	repo.On("DeleteShortURL", ctx, shortPath, currentTime, deletedBy).Return(errors.New("Internal")).Once()
+	repo.On("DeleteShortURL", ctx, shortPath, currentTime, deletedBy, (*models.AuditActor)(nil)).Return(errors.New("Internal")).Once()
 	timeProvider.On("Now").Return(currentTime).Once()
-	service := NewURLService(repo, statRepo, idGenerator, timeProvider)
-	err := service.DeleteURL(ctx, shortPath)
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, idGenerator, timeProvider, "", 0)
+	err := service.DeleteURL(ctx, shortPath, nil)
 	assert.NotNil(t, err)
 	repo.AssertExpectations(t)
 	idGenerator.AssertExpectations(t)
@@ -247,8 +472,11 @@ func TestURLServiceImpl_DeleteURL_RepoError(t *testing.T) {
 func TestURLServiceImpl_UpdateShortURL(t *testing.T) {
 	repo := &repoMocks.URLRepository{}
 	defer repo.AssertExpectations(t)
-	statRepo := &repoMocks.URLStatisticsRepository{}
-	defer statRepo.AssertExpectations(t)
+	accessLogger := &mocks.AccessLogger{}
+	defer accessLogger.AssertExpectations(t)
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	defer clickCounters.AssertExpectations(t)
 	idGenerator := &utilsMocks.NanoIDGenerator{}
 	defer idGenerator.AssertExpectations(t)
 	timeProvider := &utilsMocks.TimeProvider{}
@@ -267,11 +495,11 @@ func TestURLServiceImpl_UpdateShortURL(t *testing.T) {
 		ModifiedBy:  &modifiedBy,
 	}
 
-	repo.On("UpdateShortURL", ctx, urlUpdate).Return(nil).Once()
-	service := NewURLService(repo, statRepo, idGenerator, timeProvider)
+	repo.On("UpdateShortURL", ctx, urlUpdate, (*models.AuditActor)(nil)).Return(nil).Once()
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, idGenerator, timeProvider, "", 0)
 	timeProvider.On("Now").Return(currentTime).Once()
 
-	err := service.UpdateShortURL(ctx, originalURL, shortPath, &expiry)
+	err := service.UpdateShortURL(ctx, originalURL, shortPath, &expiry, nil)
 	assert.Nil(t, err)
 	repo.AssertExpectations(t)
 	idGenerator.AssertExpectations(t)
@@ -281,8 +509,11 @@ func TestURLServiceImpl_UpdateShortURL(t *testing.T) {
 func TestURLServiceImpl_UpdateShortURL_RepoError(t *testing.T) {
 	repo := &repoMocks.URLRepository{}
 	defer repo.AssertExpectations(t)
-	statRepo := &repoMocks.URLStatisticsRepository{}
-	defer statRepo.AssertExpectations(t)
+	accessLogger := &mocks.AccessLogger{}
+	defer accessLogger.AssertExpectations(t)
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	defer clickCounters.AssertExpectations(t)
 	idGenerator := &utilsMocks.NanoIDGenerator{}
 	defer idGenerator.AssertExpectations(t)
 	timeProvider := &utilsMocks.TimeProvider{}
@@ -301,20 +532,42 @@ func TestURLServiceImpl_UpdateShortURL_RepoError(t *testing.T) {
 		ModifiedBy:  &modifiedBy,
 	}
 	timeProvider.On("Now").Return(currentTime).Once()
-	repo.On("UpdateShortURL", ctx, urlUpdate).Return(errors.New("Internal")).Once()
-	service := NewURLService(repo, statRepo, idGenerator, timeProvider)
-	err := service.UpdateShortURL(ctx, originalURL, shortPath, &expiry)
+	repo.On("UpdateShortURL", ctx, urlUpdate, (*models.AuditActor)(nil)).Return(errors.New("Internal")).Once()
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, idGenerator, timeProvider, "", 0)
+	err := service.UpdateShortURL(ctx, originalURL, shortPath, &expiry, nil)
 	assert.NotNil(t, err)
 	repo.AssertExpectations(t)
 	idGenerator.AssertExpectations(t)
 	timeProvider.AssertExpectations(t)
 }
 
+func TestURLServiceImpl_CreateShortURL_PassesActor(t *testing.T) {
+	repo := &repoMocks.URLRepository{}
+	defer repo.AssertExpectations(t)
+	idGenerator := &utilsMocks.NanoIDGenerator{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	currentTime := time.Now()
+	actor := &models.AuditActor{Actor: "alice", SourceIP: "203.0.113.7", UserAgent: "curl/8.0", RequestID: "req-1"}
+	idGenerator.On("Generate").Return("abc", nil).Once()
+	timeProvider.On("Now").Return(currentTime).Once()
+	repo.On("GetShortURL", ctx, "https://www.example.com").Return(nil, nil).Once()
+	repo.On("InsertShortURL", ctx, mock.MatchedBy(func(url *models.URL) bool { return url.CreatedBy == "alice" }), actor).Return(nil).Once()
+
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, &mocks.AccessLogger{}, &repoMocks.ClickCounterRepository{}, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, &mocks.ClickStreamPublisher{}, idGenerator, timeProvider, "", 0)
+	shortPath, err := service.CreateShortURL(ctx, "https://www.example.com", nil, actor)
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", shortPath)
+}
+
 func TestURLServiceImpl_GetURLDetails(t *testing.T) {
 	repo := &repoMocks.URLRepository{}
 	defer repo.AssertExpectations(t)
-	statRepo := &repoMocks.URLStatisticsRepository{}
-	defer statRepo.AssertExpectations(t)
+	accessLogger := &mocks.AccessLogger{}
+	defer accessLogger.AssertExpectations(t)
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	defer clickCounters.AssertExpectations(t)
 	idGenerator := &utilsMocks.NanoIDGenerator{}
 	defer idGenerator.AssertExpectations(t)
 	timeProvider := &utilsMocks.TimeProvider{}
@@ -327,7 +580,7 @@ func TestURLServiceImpl_GetURLDetails(t *testing.T) {
 		ShortPath:   shortPath,
 	}
 	repo.On("GetOriginalURL", ctx, shortPath).Return(url, nil).Once()
-	service := NewURLService(repo, statRepo, idGenerator, timeProvider)
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, idGenerator, timeProvider, "", 0)
 	urlDetails, err := service.GetURLDetails(ctx, shortPath)
 	assert.Nil(t, err)
 	assert.Equal(t, originalURL, urlDetails.OriginalURL)
@@ -339,8 +592,11 @@ func TestURLServiceImpl_GetURLDetails(t *testing.T) {
 func TestURLServiceImpl_GetURLDetails_RepoError(t *testing.T) {
 	repo := &repoMocks.URLRepository{}
 	defer repo.AssertExpectations(t)
-	statRepo := &repoMocks.URLStatisticsRepository{}
-	defer statRepo.AssertExpectations(t)
+	accessLogger := &mocks.AccessLogger{}
+	defer accessLogger.AssertExpectations(t)
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickStream := &mocks.ClickStreamPublisher{}
+	defer clickCounters.AssertExpectations(t)
 	idGenerator := &utilsMocks.NanoIDGenerator{}
 	defer idGenerator.AssertExpectations(t)
 	timeProvider := &utilsMocks.TimeProvider{}
@@ -348,10 +604,55 @@ func TestURLServiceImpl_GetURLDetails_RepoError(t *testing.T) {
 	ctx := context.Background()
 	shortPath := "shortPath"
 	repo.On("GetOriginalURL", ctx, shortPath).Return(nil, errors.New("Internal")).Once()
-	service := NewURLService(repo, statRepo, idGenerator, timeProvider)
+	service := NewURLService(repo, &repoMocks.URLArchiveRepository{}, accessLogger, clickCounters, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, clickStream, idGenerator, timeProvider, "", 0)
 	_, err := service.GetURLDetails(ctx, shortPath)
 	assert.NotNil(t, err)
 	repo.AssertExpectations(t)
 	idGenerator.AssertExpectations(t)
 	timeProvider.AssertExpectations(t)
 }
+
+func TestURLServiceImpl_RestoreURL(t *testing.T) {
+	archive := &repoMocks.URLArchiveRepository{}
+	defer archive.AssertExpectations(t)
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	currentTime := time.Now()
+	restored := &models.URL{ShortPath: "abc", OriginalURL: "https://www.example.com"}
+	actor := &models.AuditActor{Actor: "alice"}
+	timeProvider.On("Now").Return(currentTime).Once()
+	archive.On("RestoreShortURL", ctx, "abc", currentTime, "alice", actor).Return(restored, nil).Once()
+
+	service := NewURLService(&repoMocks.URLRepository{}, archive, &mocks.AccessLogger{}, &repoMocks.ClickCounterRepository{}, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, &mocks.ClickStreamPublisher{}, &utilsMocks.NanoIDGenerator{}, timeProvider, "", 0)
+	url, err := service.RestoreURL(ctx, "abc", actor)
+	assert.Nil(t, err)
+	assert.Equal(t, restored, url)
+}
+
+func TestURLServiceImpl_RestoreURL_PathReused(t *testing.T) {
+	archive := &repoMocks.URLArchiveRepository{}
+	defer archive.AssertExpectations(t)
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	currentTime := time.Now()
+	timeProvider.On("Now").Return(currentTime).Once()
+	archive.On("RestoreShortURL", ctx, "abc", currentTime, "system", (*models.AuditActor)(nil)).Return(nil, repositories.ErrShortURLAlreadyExists).Once()
+
+	service := NewURLService(&repoMocks.URLRepository{}, archive, &mocks.AccessLogger{}, &repoMocks.ClickCounterRepository{}, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, &mocks.ClickStreamPublisher{}, &utilsMocks.NanoIDGenerator{}, timeProvider, "", 0)
+	_, err := service.RestoreURL(ctx, "abc", nil)
+	assert.ErrorIs(t, err, repositories.ErrShortURLAlreadyExists)
+}
+
+func TestURLServiceImpl_ListArchivedURLs_ClampsLimit(t *testing.T) {
+	archive := &repoMocks.URLArchiveRepository{}
+	defer archive.AssertExpectations(t)
+	ctx := context.Background()
+	archive.On("ListArchivedURLs", ctx, "", int64(0), defaultArchiveLimit).Return([]models.URLArchive{}, nil).Once()
+	archive.On("ListArchivedURLs", ctx, "abc", int64(12), maxArchiveLimit).Return([]models.URLArchive{}, nil).Once()
+
+	service := NewURLService(&repoMocks.URLRepository{}, archive, &mocks.AccessLogger{}, &repoMocks.ClickCounterRepository{}, &repoMocks.ClickDedupRepository{}, &repoMocks.IssuedClickRepository{}, &mocks.ClickStreamPublisher{}, &utilsMocks.NanoIDGenerator{}, &utilsMocks.TimeProvider{}, "", 0)
+	_, err := service.ListArchivedURLs(ctx, "", -5, 0)
+	assert.Nil(t, err)
+	_, err = service.ListArchivedURLs(ctx, "abc", 12, 10000)
+	assert.Nil(t, err)
+}
@@ -2,39 +2,208 @@ package services
 
 import (
 	"context"
-	"log"
+	"errors"
 	"time"
 	"url-shortener/internal/models"
 	"url-shortener/internal/repositories"
+	"url-shortener/internal/utils"
+)
+
+const (
+	TimeseriesHour = "hour"
+	TimeseriesDay  = "day"
+	TimeseriesWeek = "week"
+
+	maxTimeseriesBuckets = 1000
+
+	StatsWindowDay   = "24h"
+	StatsWindowWeek  = "7d"
+	StatsWindowMonth = "30d"
+	StatsWindowAll   = "all"
+)
+
+var (
+	ErrInvalidTimeseriesRange = errors.New("from must be before to")
+	ErrTooManyBuckets         = errors.New("range has too many buckets for the interval")
+	ErrInvalidStatsWindow     = errors.New("window must be 24h, 7d, 30d or all")
 )
 
 //go:generate mockery --name=URLStatsService --output=./mocks
 type URLStatsService interface {
-	GetURLStatistics(ctx context.Context, shortPath string) (*models.URLStatistics, error)
-	InsertAccessLog(ctx context.Context, shortPath string, accessedAt time.Time) error
+	// GetURLStatistics leaves out clicks by bots unless includeBots is set. Unique visitors never include bots.
+	GetURLStatistics(ctx context.Context, shortPath string, includeBots bool) (*models.URLStatistics, error)
+	// GetURLTimeseries counts accesses per interval between from and to, with buckets aligned to the wall clock in location.
+	// from is moved back to the start of its bucket and buckets without accesses are included with a count of 0.
+	GetURLTimeseries(ctx context.Context, shortPath string, from time.Time, to time.Time, interval string, location *time.Location, includeBots bool) (*models.URLTimeseries, error)
+	// GetTopLinks returns the limit links with the most clicks in window, one of the StatsWindow values.
+	GetTopLinks(ctx context.Context, window string, limit int) ([]models.LinkClicks, error)
+	// GetDomainClicks returns the limit destination hosts with the most clicks in window.
+	GetDomainClicks(ctx context.Context, window string, limit int) ([]models.DomainClicks, error)
 }
 
 type urlStatsServiceImpl struct {
-	repo repositories.URLStatisticsRepository
+	repo          repositories.URLStatisticsRepository
+	visitors      repositories.UniqueVisitorRepository
+	clickCounters repositories.ClickCounterRepository
+	conversions   repositories.ConversionRepository
+	timeProvider  utils.TimeProvider
 }
 
-func NewURLStatsService(repo repositories.URLStatisticsRepository) URLStatsService {
-	return &urlStatsServiceImpl{repo: repo}
+func NewURLStatsService(repo repositories.URLStatisticsRepository, visitors repositories.UniqueVisitorRepository, clickCounters repositories.ClickCounterRepository, conversions repositories.ConversionRepository, timeProvider utils.TimeProvider) URLStatsService {
+	return &urlStatsServiceImpl{repo: repo, visitors: visitors, clickCounters: clickCounters, conversions: conversions, timeProvider: timeProvider}
 }
 
-func (s *urlStatsServiceImpl) GetURLStatistics(ctx context.Context, shortPath string) (*models.URLStatistics, error) {
-	urlStats, err := s.repo.GetURLStatistics(ctx, shortPath)
+// GetURLStatistics implements URLStatsService. The Redis click counters and unique visitors are left out when
+// Redis cannot be reached rather than failing the whole response. The conversion rate is worked out against all
+// time clicks, so it includes bots when the clicks do.
+func (s *urlStatsServiceImpl) GetURLStatistics(ctx context.Context, shortPath string, includeBots bool) (*models.URLStatistics, error) {
+	urlStats, err := s.repo.GetURLStatistics(ctx, shortPath, includeBots)
 	if err != nil {
 		return nil, err
 	}
+	now := s.timeProvider.Now()
+	if !includeBots {
+		s.addRecentClicks(ctx, urlStats, now)
+	}
+	uniqueVisitors, err := s.visitors.CountUniqueVisitors(ctx, shortPath, now)
+	if err == nil {
+		urlStats.UniqueLast24Hours = uniqueVisitors.Last24Hours
+		urlStats.UniquePastWeek = uniqueVisitors.PastWeek
+		urlStats.UniqueAllTime = uniqueVisitors.AllTime
+	}
+	conversions, err := s.conversions.GetConversionStatistics(ctx, shortPath)
+	if err != nil {
+		return nil, err
+	}
+	urlStats.Conversions = conversions.Conversions
+	urlStats.ConversionValue = conversions.Value
+	urlStats.ConversionTypes = conversions.Types
+	if urlStats.AllTime > 0 {
+		urlStats.ConversionRate = min(float64(conversions.ConvertedClicks)/float64(urlStats.AllTime), 1)
+	}
 	return urlStats, nil
 }
 
-func (s *urlStatsServiceImpl) InsertAccessLog(ctx context.Context, shortPath string, accessedAt time.Time) error {
-	err := s.repo.InsertAccessLog(ctx, shortPath, accessedAt)
+// addRecentClicks serves the recent windows from the Redis counters, which include clicks still waiting in the
+// ingestion queue and are corrected by ClickCounterReconcileJob. Each window takes the larger of the two counts, so
+// counters Redis lost and has not been reconciled yet never hide the clicks in Postgres. Clicks Redis counted on top
+// of Postgres are still queued and added to the all time count.
+func (s *urlStatsServiceImpl) addRecentClicks(ctx context.Context, urlStats *models.URLStatistics, now time.Time) {
+	recent, err := s.clickCounters.CountRecentClicks(ctx, urlStats.ShortPath, now)
 	if err != nil {
-		log.Printf(err.Error())
-		return err
+		return
+	}
+	if pending := recent.Last24Hours - urlStats.Last24Hours; pending > 0 {
+		urlStats.AllTime += pending
+	}
+	urlStats.Last24Hours = max(urlStats.Last24Hours, recent.Last24Hours)
+	urlStats.PastWeek = max(urlStats.PastWeek, recent.PastWeek)
+}
+
+func (s *urlStatsServiceImpl) GetURLTimeseries(ctx context.Context, shortPath string, from time.Time, to time.Time, interval string, location *time.Location, includeBots bool) (*models.URLTimeseries, error) {
+	from = bucketStart(from.In(location), interval)
+	to = to.In(location)
+	if !from.Before(to) {
+		return nil, ErrInvalidTimeseriesRange
+	}
+	starts := []time.Time{}
+	for start := from; start.Before(to); start = nextBucketStart(start, interval) {
+		// The repeated hour when clocks go back is a single bucket, as it is in the database.
+		if len(starts) > 0 && bucketKey(starts[len(starts)-1], interval) == bucketKey(start, interval) {
+			continue
+		}
+		if len(starts) == maxTimeseriesBuckets {
+			return nil, ErrTooManyBuckets
+		}
+		starts = append(starts, start)
+	}
+
+	counts, err := s.repo.GetAccessCounts(ctx, shortPath, from, to, interval, location.String(), includeBots)
+	if err != nil {
+		return nil, err
+	}
+	countsByKey := make(map[string]int64, len(counts))
+	for _, bucket := range counts {
+		countsByKey[bucketKey(bucket.Start, interval)] += bucket.Count
+	}
+
+	timeseries := &models.URLTimeseries{
+		ShortPath: shortPath,
+		Interval:  interval,
+		TimeZone:  location.String(),
+		From:      from,
+		To:        to,
+		Buckets:   make([]models.TimeseriesBucket, 0, len(starts)),
+	}
+	for _, start := range starts {
+		timeseries.Buckets = append(timeseries.Buckets, models.TimeseriesBucket{Start: start, Count: countsByKey[bucketKey(start, interval)]})
+	}
+	return timeseries, nil
+}
+
+// bucketStart aligns t to the start of its hour, day or ISO week (Monday) on its own wall clock.
+func bucketStart(t time.Time, interval string) time.Time {
+	switch interval {
+	case TimeseriesHour:
+		// Subtracting the wall clock minutes keeps half hour offsets such as Asia/Kolkata aligned.
+		return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case TimeseriesWeek:
+		year, month, day := t.Date()
+		return time.Date(year, month, day-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location())
+	default:
+		year, month, day := t.Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func nextBucketStart(start time.Time, interval string) time.Time {
+	year, month, day := start.Date()
+	switch interval {
+	case TimeseriesHour:
+		return start.Add(time.Hour)
+	case TimeseriesWeek:
+		return time.Date(year, month, day+7, 0, 0, 0, 0, start.Location())
+	default:
+		return time.Date(year, month, day+1, 0, 0, 0, 0, start.Location())
+	}
+}
+
+// bucketKey identifies a bucket by its wall clock start, which is how the database groups them. Days and weeks
+// are keyed by date alone because midnight does not exist in zones that change clocks at midnight.
+func bucketKey(start time.Time, interval string) string {
+	if interval == TimeseriesHour {
+		return start.Format("2006-01-02T15")
+	}
+	return start.Format("2006-01-02")
+}
+
+func (s *urlStatsServiceImpl) GetTopLinks(ctx context.Context, window string, limit int) ([]models.LinkClicks, error) {
+	since, err := s.windowStart(window)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetTopLinks(ctx, since, limit)
+}
+
+func (s *urlStatsServiceImpl) GetDomainClicks(ctx context.Context, window string, limit int) ([]models.DomainClicks, error) {
+	since, err := s.windowStart(window)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetDomainClicks(ctx, since, limit)
+}
+
+// windowStart returns when window started, the zero time for all.
+func (s *urlStatsServiceImpl) windowStart(window string) (time.Time, error) {
+	switch window {
+	case StatsWindowDay:
+		return s.timeProvider.Now().Add(-24 * time.Hour), nil
+	case StatsWindowWeek:
+		return s.timeProvider.Now().AddDate(0, 0, -7), nil
+	case StatsWindowMonth:
+		return s.timeProvider.Now().AddDate(0, 0, -30), nil
+	case StatsWindowAll:
+		return time.Time{}, nil
 	}
-	return nil
+	return time.Time{}, ErrInvalidStatsWindow
 }
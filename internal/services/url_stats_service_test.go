@@ -7,6 +7,7 @@ import (
 
 	"url-shortener/internal/models"
 	repoMocks "url-shortener/internal/repositories/mocks"
+	utilsMocks "url-shortener/internal/utils/mocks"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -16,49 +17,256 @@ func TestURLStatsServiceImpl_GetURLStatistics(t *testing.T) {
 	defer repo.AssertExpectations(t)
 	ctx := context.Background()
 	shortPath := "shortPath"
+	now := time.Now()
 	mockStats := &models.URLStatistics{ShortPath: shortPath, Last24Hours: 5, PastWeek: 5, AllTime: 5}
-	repo.On("GetURLStatistics", ctx, shortPath).Return(mockStats, nil).Once()
-	service := NewURLStatsService(repo)
-	urlStats, err := service.GetURLStatistics(ctx, shortPath)
+	repo.On("GetURLStatistics", ctx, shortPath, false).Return(mockStats, nil).Once()
+	visitors := &repoMocks.UniqueVisitorRepository{}
+	visitors.On("CountUniqueVisitors", ctx, shortPath, now).Return(&models.UniqueVisitorCounts{Last24Hours: 2, PastWeek: 3, AllTime: 4}, nil).Once()
+	timeProvider := &utilsMocks.TimeProvider{}
+	timeProvider.On("Now").Return(now).Once()
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickCounters.On("CountRecentClicks", ctx, shortPath, now).Return(&models.RecentClickCounts{Last24Hours: 8, PastWeek: 9}, nil).Once()
+	conversions := &repoMocks.ConversionRepository{}
+	conversionTypes := []models.ConversionTypeStats{{Type: "purchase", Conversions: 3, Value: 59.5}}
+	conversions.On("GetConversionStatistics", ctx, shortPath).Return(&models.ConversionStatistics{Conversions: 3, ConvertedClicks: 2, Value: 59.5, Types: conversionTypes}, nil).Once()
+	service := NewURLStatsService(repo, visitors, clickCounters, conversions, timeProvider)
+	urlStats, err := service.GetURLStatistics(ctx, shortPath, false)
 	assert.Nil(t, err)
-	assert.Equal(t, mockStats, urlStats)
+	// The 3 clicks Redis counted on top of Postgres are still queued, so they are added to the all time count too,
+	// and 2 of the 8 clicks converted.
+	assert.Equal(t, &models.URLStatistics{ShortPath: shortPath, Last24Hours: 8, PastWeek: 9, AllTime: 8, UniqueLast24Hours: 2, UniquePastWeek: 3, UniqueAllTime: 4,
+		Conversions: 3, ConversionValue: 59.5, ConversionRate: 0.25, ConversionTypes: conversionTypes}, urlStats)
 	repo.AssertExpectations(t)
+	visitors.AssertExpectations(t)
+	clickCounters.AssertExpectations(t)
 }
 
-func TestURLStatsServiceImpl_GetURLStatistics_RepoError(t *testing.T) {
+func TestURLStatsServiceImpl_GetURLStatistics_TakesLargerCountPerWindow(t *testing.T) {
 	repo := &repoMocks.URLStatisticsRepository{}
-	defer repo.AssertExpectations(t)
 	ctx := context.Background()
 	shortPath := "shortPath"
-	repo.On("GetURLStatistics", ctx, shortPath).Return(nil, assert.AnError).Once()
-	service := NewURLStatsService(repo)
-	_, err := service.GetURLStatistics(ctx, shortPath)
-	assert.NotNil(t, err)
-	repo.AssertExpectations(t)
+	now := time.Now()
+	repo.On("GetURLStatistics", ctx, shortPath, false).Return(&models.URLStatistics{ShortPath: shortPath, Last24Hours: 5, PastWeek: 40, AllTime: 90}, nil).Once()
+	visitors := &repoMocks.UniqueVisitorRepository{}
+	visitors.On("CountUniqueVisitors", ctx, shortPath, now).Return(&models.UniqueVisitorCounts{}, nil).Once()
+	timeProvider := &utilsMocks.TimeProvider{}
+	timeProvider.On("Now").Return(now).Once()
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	// Redis lost the older hours of the week, e.g. after a flush, but has the clicks still queued for today.
+	clickCounters.On("CountRecentClicks", ctx, shortPath, now).Return(&models.RecentClickCounts{Last24Hours: 7, PastWeek: 7}, nil).Once()
+	conversions := &repoMocks.ConversionRepository{}
+	conversions.On("GetConversionStatistics", ctx, shortPath).Return(&models.ConversionStatistics{}, nil).Once()
+	service := NewURLStatsService(repo, visitors, clickCounters, conversions, timeProvider)
+	urlStats, err := service.GetURLStatistics(ctx, shortPath, false)
+	assert.Nil(t, err)
+	assert.Equal(t, &models.URLStatistics{ShortPath: shortPath, Last24Hours: 7, PastWeek: 40, AllTime: 92}, urlStats)
 }
 
-func TestURLStatsServiceImpl_InsertAccessLog(t *testing.T) {
+func TestURLStatsServiceImpl_GetURLStatistics_EmptyRedisKeepsPostgres(t *testing.T) {
 	repo := &repoMocks.URLStatisticsRepository{}
-	defer repo.AssertExpectations(t)
 	ctx := context.Background()
 	shortPath := "shortPath"
-	accessedAt := time.Now()
-	repo.On("InsertAccessLog", ctx, shortPath, accessedAt).Return(nil).Once()
-	service := NewURLStatsService(repo)
-	err := service.InsertAccessLog(ctx, shortPath, accessedAt)
+	now := time.Now()
+	mockStats := &models.URLStatistics{ShortPath: shortPath, Last24Hours: 5, PastWeek: 5, AllTime: 5}
+	repo.On("GetURLStatistics", ctx, shortPath, false).Return(mockStats, nil).Once()
+	visitors := &repoMocks.UniqueVisitorRepository{}
+	visitors.On("CountUniqueVisitors", ctx, shortPath, now).Return(&models.UniqueVisitorCounts{}, nil).Once()
+	timeProvider := &utilsMocks.TimeProvider{}
+	timeProvider.On("Now").Return(now).Once()
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickCounters.On("CountRecentClicks", ctx, shortPath, now).Return(&models.RecentClickCounts{}, nil).Once()
+	conversions := &repoMocks.ConversionRepository{}
+	conversions.On("GetConversionStatistics", ctx, shortPath).Return(&models.ConversionStatistics{}, nil).Once()
+	service := NewURLStatsService(repo, visitors, clickCounters, conversions, timeProvider)
+	urlStats, err := service.GetURLStatistics(ctx, shortPath, false)
 	assert.Nil(t, err)
-	repo.AssertExpectations(t)
+	assert.Equal(t, &models.URLStatistics{ShortPath: shortPath, Last24Hours: 5, PastWeek: 5, AllTime: 5}, urlStats)
+}
+
+func TestURLStatsServiceImpl_GetURLStatistics_IncludeBotsSkipsCounters(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	ctx := context.Background()
+	shortPath := "shortPath"
+	now := time.Now()
+	mockStats := &models.URLStatistics{ShortPath: shortPath, Last24Hours: 5, PastWeek: 5, AllTime: 5}
+	repo.On("GetURLStatistics", ctx, shortPath, true).Return(mockStats, nil).Once()
+	visitors := &repoMocks.UniqueVisitorRepository{}
+	visitors.On("CountUniqueVisitors", ctx, shortPath, now).Return(&models.UniqueVisitorCounts{}, nil).Once()
+	timeProvider := &utilsMocks.TimeProvider{}
+	timeProvider.On("Now").Return(now).Once()
+	// The counters leave out bots, so the mock fails the test if they are read.
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	conversions := &repoMocks.ConversionRepository{}
+	conversions.On("GetConversionStatistics", ctx, shortPath).Return(&models.ConversionStatistics{}, nil).Once()
+	service := NewURLStatsService(repo, visitors, clickCounters, conversions, timeProvider)
+	urlStats, err := service.GetURLStatistics(ctx, shortPath, true)
+	assert.Nil(t, err)
+	assert.Equal(t, mockStats, urlStats)
+}
+
+func TestURLStatsServiceImpl_GetURLStatistics_RedisError(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	ctx := context.Background()
+	shortPath := "shortPath"
+	now := time.Now()
+	mockStats := &models.URLStatistics{ShortPath: shortPath, Last24Hours: 5, PastWeek: 5, AllTime: 5}
+	repo.On("GetURLStatistics", ctx, shortPath, false).Return(mockStats, nil).Once()
+	visitors := &repoMocks.UniqueVisitorRepository{}
+	visitors.On("CountUniqueVisitors", ctx, shortPath, now).Return(nil, assert.AnError).Once()
+	timeProvider := &utilsMocks.TimeProvider{}
+	timeProvider.On("Now").Return(now).Once()
+	clickCounters := &repoMocks.ClickCounterRepository{}
+	clickCounters.On("CountRecentClicks", ctx, shortPath, now).Return(nil, assert.AnError).Once()
+	conversions := &repoMocks.ConversionRepository{}
+	conversions.On("GetConversionStatistics", ctx, shortPath).Return(&models.ConversionStatistics{}, nil).Once()
+	service := NewURLStatsService(repo, visitors, clickCounters, conversions, timeProvider)
+	urlStats, err := service.GetURLStatistics(ctx, shortPath, false)
+	assert.Nil(t, err)
+	assert.Equal(t, mockStats, urlStats)
+	assert.Zero(t, urlStats.UniqueAllTime)
+	visitors.AssertExpectations(t)
 }
 
-func TestURLStatsServiceImpl_InsertAccessLog_RepoError(t *testing.T) {
+func TestURLStatsServiceImpl_GetURLStatistics_RepoError(t *testing.T) {
 	repo := &repoMocks.URLStatisticsRepository{}
 	defer repo.AssertExpectations(t)
 	ctx := context.Background()
 	shortPath := "shortPath"
-	accessedAt := time.Now()
-	repo.On("InsertAccessLog", ctx, shortPath, accessedAt).Return(assert.AnError).Once()
-	service := NewURLStatsService(repo)
-	err := service.InsertAccessLog(ctx, shortPath, accessedAt)
+	repo.On("GetURLStatistics", ctx, shortPath, false).Return(nil, assert.AnError).Once()
+	service := NewURLStatsService(repo, nil, nil, nil, nil)
+	_, err := service.GetURLStatistics(ctx, shortPath, false)
 	assert.NotNil(t, err)
 	repo.AssertExpectations(t)
 }
+
+func TestURLStatsServiceImpl_GetURLTimeseries_ZeroFillsDays(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	service := NewURLStatsService(repo, nil, nil, nil, nil)
+	ctx := context.Background()
+	from := time.Date(2025, 3, 1, 15, 4, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	alignedFrom := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	repo.On("GetAccessCounts", ctx, "shortPath", alignedFrom, to, TimeseriesDay, "UTC", false).Return([]models.TimeseriesBucket{
+		{Start: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), Count: 7},
+	}, nil).Once()
+
+	timeseries, err := service.GetURLTimeseries(ctx, "shortPath", from, to, TimeseriesDay, time.UTC, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, alignedFrom, timeseries.From)
+	assert.Equal(t, []models.TimeseriesBucket{
+		{Start: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Count: 0},
+		{Start: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), Count: 7},
+		{Start: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), Count: 0},
+		{Start: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), Count: 0},
+	}, timeseries.Buckets)
+	repo.AssertExpectations(t)
+}
+
+func TestURLStatsServiceImpl_GetURLTimeseries_WeeksStartOnMondayInTimeZone(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	service := NewURLStatsService(repo, nil, nil, nil, nil)
+	ctx := context.Background()
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	// Sunday 23:30 UTC is already Monday in Berlin.
+	from := time.Date(2025, 6, 8, 23, 30, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC)
+	repo.On("GetAccessCounts", ctx, "shortPath", time.Date(2025, 6, 9, 0, 0, 0, 0, berlin), to.In(berlin), TimeseriesWeek, "Europe/Berlin", false).Return([]models.TimeseriesBucket{
+		{Start: time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC), Count: 3},
+	}, nil).Once()
+
+	timeseries, err := service.GetURLTimeseries(ctx, "shortPath", from, to, TimeseriesWeek, berlin, false)
+
+	assert.NoError(t, err)
+	assert.Len(t, timeseries.Buckets, 2)
+	assert.Equal(t, time.Date(2025, 6, 9, 0, 0, 0, 0, berlin), timeseries.Buckets[0].Start)
+	assert.Equal(t, int64(0), timeseries.Buckets[0].Count)
+	assert.Equal(t, time.Date(2025, 6, 16, 0, 0, 0, 0, berlin), timeseries.Buckets[1].Start)
+	assert.Equal(t, int64(3), timeseries.Buckets[1].Count)
+}
+
+func TestURLStatsServiceImpl_GetURLTimeseries_MergesRepeatedHourWhenClocksGoBack(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	service := NewURLStatsService(repo, nil, nil, nil, nil)
+	ctx := context.Background()
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	// 2025-11-02 01:00-02:00 happens twice in New York.
+	from := time.Date(2025, 11, 2, 4, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 11, 2, 8, 0, 0, 0, time.UTC)
+	repo.On("GetAccessCounts", ctx, "shortPath", from.In(newYork), to.In(newYork), TimeseriesHour, "America/New_York", false).Return([]models.TimeseriesBucket{
+		{Start: time.Date(2025, 11, 2, 1, 0, 0, 0, time.UTC), Count: 5},
+	}, nil).Once()
+
+	timeseries, err := service.GetURLTimeseries(ctx, "shortPath", from, to, TimeseriesHour, newYork, false)
+
+	assert.NoError(t, err)
+	hours := []int{}
+	for _, bucket := range timeseries.Buckets {
+		hours = append(hours, bucket.Start.Hour())
+	}
+	assert.Equal(t, []int{0, 1, 2}, hours)
+	assert.Equal(t, int64(5), timeseries.Buckets[1].Count)
+}
+
+func TestURLStatsServiceImpl_GetURLTimeseries_InvalidRange(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	service := NewURLStatsService(repo, nil, nil, nil, nil)
+	now := time.Now()
+
+	_, err := service.GetURLTimeseries(context.Background(), "shortPath", now, now.Add(-time.Hour), TimeseriesHour, time.UTC, false)
+
+	assert.ErrorIs(t, err, ErrInvalidTimeseriesRange)
+	repo.AssertExpectations(t)
+}
+
+func TestURLStatsServiceImpl_GetURLTimeseries_TooManyBuckets(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	service := NewURLStatsService(repo, nil, nil, nil, nil)
+	to := time.Now()
+
+	_, err := service.GetURLTimeseries(context.Background(), "shortPath", to.AddDate(-1, 0, 0), to, TimeseriesHour, time.UTC, false)
+
+	assert.ErrorIs(t, err, ErrTooManyBuckets)
+	repo.AssertExpectations(t)
+}
+
+func TestURLStatsServiceImpl_GetTopLinks(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	ctx := context.Background()
+	now := time.Date(2025, 6, 12, 10, 30, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now).Once()
+	links := []models.LinkClicks{{ShortPath: "path1", OriginalURL: "https://www.example.com", Clicks: 3}}
+	repo.On("GetTopLinks", ctx, now.AddDate(0, 0, -7), 10).Return(links, nil).Once()
+	service := NewURLStatsService(repo, nil, nil, nil, timeProvider)
+
+	topLinks, err := service.GetTopLinks(ctx, StatsWindowWeek, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, links, topLinks)
+	repo.AssertExpectations(t)
+}
+
+func TestURLStatsServiceImpl_GetDomainClicks_AllTime(t *testing.T) {
+	repo := &repoMocks.URLStatisticsRepository{}
+	ctx := context.Background()
+	domains := []models.DomainClicks{{Domain: "example.com", Links: 2, Clicks: 5}}
+	repo.On("GetDomainClicks", ctx, time.Time{}, 10).Return(domains, nil).Once()
+	service := NewURLStatsService(repo, nil, nil, nil, &utilsMocks.TimeProvider{})
+
+	domainClicks, err := service.GetDomainClicks(ctx, StatsWindowAll, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domains, domainClicks)
+	repo.AssertExpectations(t)
+}
+
+func TestURLStatsServiceImpl_GetTopLinks_InvalidWindow(t *testing.T) {
+	service := NewURLStatsService(&repoMocks.URLStatisticsRepository{}, nil, nil, nil, &utilsMocks.TimeProvider{})
+
+	_, err := service.GetTopLinks(context.Background(), "1y", 10)
+
+	assert.ErrorIs(t, err, ErrInvalidStatsWindow)
+}
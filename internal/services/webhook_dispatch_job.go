@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/utils"
+)
+
+const (
+	// maxWebhookErrorLength bounds the error stored with a failed attempt.
+	maxWebhookErrorLength = 500
+	// defaultWebhookTimeout is used when webhooks.timeout is not set, without one a delivery could hang forever
+	// and its claim would expire at once.
+	defaultWebhookTimeout = 10 * time.Second
+)
+
+// WebhookDispatchJob fans new link events out to the webhook subscriptions and sends the deliveries that are due.
+// The body is signed with the secret of the subscription, see signWebhook. A failed delivery is retried with
+// exponential backoff until webhooks.max_attempts. Connections are only made to public addresses, checked when
+// dialling so a name that resolves to this host's network is refused too.
+type WebhookDispatchJob struct {
+	repo         repositories.WebhookDeliveryRepository
+	client       *http.Client
+	config       config.WebhooksConfig
+	timeProvider utils.TimeProvider
+}
+
+func NewWebhookDispatchJob(repo repositories.WebhookDeliveryRepository, webhooksConfig config.WebhooksConfig, timeProvider utils.TimeProvider) *WebhookDispatchJob {
+	if webhooksConfig.BatchSize <= 0 {
+		webhooksConfig.BatchSize = 100
+	}
+	if webhooksConfig.Timeout <= 0 {
+		webhooksConfig.Timeout = defaultWebhookTimeout
+	}
+	if webhooksConfig.MaxAttempts <= 0 {
+		webhooksConfig.MaxAttempts = 1
+	}
+	return &WebhookDispatchJob{
+		repo:         repo,
+		client:       newWebhookClient(webhooksConfig.Timeout),
+		config:       webhooksConfig,
+		timeProvider: timeProvider,
+	}
+}
+
+// Name implements Job.
+func (j *WebhookDispatchJob) Name() string {
+	return "webhook-dispatch"
+}
+
+// Run implements Job. Claimed deliveries are sent concurrently and held for twice the timeout, an instance that
+// stops half way leaves them to be sent again once that passes.
+func (j *WebhookDispatchJob) Run(ctx context.Context) error {
+	for {
+		events, err := j.repo.EnqueueDeliveries(ctx, j.timeProvider.Now(), j.config.BatchSize)
+		if err != nil {
+			return err
+		}
+		if events < j.config.BatchSize {
+			break
+		}
+	}
+
+	now := j.timeProvider.Now()
+	dispatches, err := j.repo.ClaimDeliveries(ctx, now, now.Add(2*j.config.Timeout), j.config.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(dispatches))
+	for i := range dispatches {
+		wg.Add(1)
+		go func(dispatch *models.WebhookDispatch) {
+			defer wg.Done()
+			errs[i] = j.dispatch(ctx, dispatch)
+		}(&dispatches[i])
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// dispatch sends a delivery and stores the outcome, only failing to store it is an error.
+func (j *WebhookDispatchJob) dispatch(ctx context.Context, dispatch *models.WebhookDispatch) error {
+	delivery := &dispatch.Delivery
+	statusCode, err := j.send(ctx, dispatch)
+	now := j.timeProvider.Now()
+	if statusCode != 0 {
+		delivery.LastStatusCode = &statusCode
+	}
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= j.config.MaxAttempts:
+		log.Printf("Giving up webhook delivery %d to %s after %d attempts: %v", delivery.ID, dispatch.URL, delivery.Attempts, err)
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = truncateError(err)
+	default:
+		next := now.Add(webhookBackoff(j.config.RetryBase, j.config.RetryMax, delivery.Attempts))
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.LastError = truncateError(err)
+		delivery.NextAttemptAt = &next
+	}
+	return j.repo.CompleteDelivery(ctx, delivery)
+}
+
+func (j *WebhookDispatchJob) send(ctx context.Context, dispatch *models.WebhookDispatch) (int, error) {
+	body, err := marshalLinkEvent(dispatch.Event)
+	if err != nil {
+		return 0, err
+	}
+	return postWebhook(ctx, j.client, dispatch.URL, body, map[string]string{
+		"X-Webhook-Event":    dispatch.Event.Type,
+		"X-Webhook-Event-Id": strconv.FormatInt(dispatch.Event.ID, 10),
+		"X-Webhook-Delivery": strconv.FormatInt(dispatch.Delivery.ID, 10),
+		"X-Signature-256":    signWebhook([]byte(dispatch.Secret), body),
+	})
+}
+
+func truncateError(err error) string {
+	message := err.Error()
+	if len(message) > maxWebhookErrorLength {
+		return message[:maxWebhookErrorLength]
+	}
+	return message
+}
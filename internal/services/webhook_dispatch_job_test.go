@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/models"
+	repoMocks "url-shortener/internal/repositories/mocks"
+	utilsMocks "url-shortener/internal/utils/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testWebhooksConfig = config.WebhooksConfig{
+	BatchSize:   10,
+	Timeout:     time.Second,
+	MaxAttempts: 3,
+	RetryBase:   30 * time.Second,
+	RetryMax:    time.Minute,
+}
+
+func setupWebhookDispatchJob(dispatches ...models.WebhookDispatch) (*repoMocks.WebhookDeliveryRepository, *WebhookDispatchJob) {
+	repo := &repoMocks.WebhookDeliveryRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	timeProvider.On("Now").Return(webhookNow)
+	repo.On("EnqueueDeliveries", context.Background(), webhookNow, 10).Return(len(dispatches), nil).Once()
+	repo.On("ClaimDeliveries", context.Background(), webhookNow, webhookNow.Add(2*time.Second), 10).Return(dispatches, nil).Once()
+	job := NewWebhookDispatchJob(repo, testWebhooksConfig, timeProvider)
+	// httptest servers listen on loopback, which the job's own client refuses.
+	job.client = &http.Client{Timeout: testWebhooksConfig.Timeout}
+	return repo, job
+}
+
+func testWebhookDispatch(url string, attempts int) models.WebhookDispatch {
+	return models.WebhookDispatch{
+		Delivery: models.WebhookDelivery{ID: 5, SubscriptionID: 7, EventID: 40, EventType: models.LinkEventCreated, Attempts: attempts},
+		URL:      url,
+		Secret:   "s3cret",
+		Event: models.OutboxEvent{ID: 40, Type: models.LinkEventCreated, ShortPath: "abc",
+			Payload: json.RawMessage(`{"shortPath":"abc"}`), CreatedAt: webhookNow.Add(-time.Minute)},
+	}
+}
+
+func TestWebhookDispatchJob_SendsSignedEvent(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write(body)
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get("X-Signature-256"))
+		assert.Equal(t, models.LinkEventCreated, r.Header.Get("X-Webhook-Event"))
+		assert.Equal(t, "40", r.Header.Get("X-Webhook-Event-Id"))
+		assert.Equal(t, "5", r.Header.Get("X-Webhook-Delivery"))
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	repo, job := setupWebhookDispatchJob(testWebhookDispatch(server.URL, 1))
+	statusCode := http.StatusNoContent
+	repo.On("CompleteDelivery", context.Background(), &models.WebhookDelivery{ID: 5, SubscriptionID: 7, EventID: 40,
+		EventType: models.LinkEventCreated, Status: models.WebhookDeliverySucceeded, Attempts: 1, LastStatusCode: &statusCode,
+		DeliveredAt: &webhookNow}).Return(nil).Once()
+
+	err := job.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(40), payload["id"])
+	assert.Equal(t, models.LinkEventCreated, payload["type"])
+	assert.Equal(t, "2026-05-20T11:59:00Z", payload["occurredAt"])
+	assert.Equal(t, map[string]interface{}{"shortPath": "abc"}, payload["data"])
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookDispatchJob_RetriesWithBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	repo, job := setupWebhookDispatchJob(testWebhookDispatch(server.URL, 2))
+	repo.On("CompleteDelivery", context.Background(), mock.MatchedBy(func(delivery *models.WebhookDelivery) bool {
+		return delivery.Status == models.WebhookDeliveryPending && *delivery.LastStatusCode == http.StatusServiceUnavailable &&
+			delivery.NextAttemptAt.Equal(webhookNow.Add(time.Minute)) && delivery.LastError == "unexpected status 503 Service Unavailable"
+	})).Return(nil).Once()
+
+	err := job.Run(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookDispatchJob_GivesUpAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	repo, job := setupWebhookDispatchJob(testWebhookDispatch(server.URL, 3))
+	repo.On("CompleteDelivery", context.Background(), mock.MatchedBy(func(delivery *models.WebhookDelivery) bool {
+		return delivery.Status == models.WebhookDeliveryFailed && delivery.NextAttemptAt == nil
+	})).Return(nil).Once()
+
+	err := job.Run(context.Background())
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookDispatchJob_RefusesNonPublicTargets(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+	repo, job := setupWebhookDispatchJob(testWebhookDispatch(server.URL, 1))
+	job.client = newWebhookClient(testWebhooksConfig.Timeout)
+	repo.On("CompleteDelivery", context.Background(), mock.MatchedBy(func(delivery *models.WebhookDelivery) bool {
+		return delivery.Status == models.WebhookDeliveryPending && delivery.LastStatusCode == nil &&
+			strings.Contains(delivery.LastError, errWebhookTargetNotPublic.Error())
+	})).Return(nil).Once()
+
+	err := job.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Zero(t, requests)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookDispatchJob_DefaultsTimeout(t *testing.T) {
+	job := NewWebhookDispatchJob(nil, config.WebhooksConfig{}, nil)
+
+	assert.Equal(t, defaultWebhookTimeout, job.config.Timeout)
+	assert.Equal(t, defaultWebhookTimeout, job.client.Timeout)
+}
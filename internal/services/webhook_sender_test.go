@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignWebhook(t *testing.T) {
+	// echo -n '{"shortPath":"abc"}' | openssl dgst -sha256 -hmac s3cret
+	assert.Equal(t, "sha256=34d6b8e03f21692a8b60cb72f06f556b43924f59d32e9760c4e000b126064c4f", signWebhook([]byte("s3cret"), []byte(`{"shortPath":"abc"}`)))
+}
+
+func TestPostWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "42", r.Header.Get("X-Webhook-Delivery"))
+		assert.Equal(t, `{"shortPath":"abc"}`, string(body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	statusCode, err := postWebhook(context.Background(), &http.Client{Timeout: time.Second}, server.URL, []byte(`{"shortPath":"abc"}`),
+		map[string]string{"X-Webhook-Delivery": "42"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, statusCode)
+}
+
+func TestPostWebhook_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	statusCode, err := postWebhook(context.Background(), &http.Client{Timeout: time.Second}, server.URL, []byte("{}"), nil)
+	assert.ErrorContains(t, err, "unexpected status 502")
+	assert.Equal(t, http.StatusBadGateway, statusCode)
+
+	statusCode, err = postWebhook(context.Background(), newWebhookClient(time.Second), server.URL, []byte("{}"), nil)
+	assert.ErrorIs(t, err, errWebhookTargetNotPublic)
+	assert.Zero(t, statusCode)
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		base     time.Duration
+		limit    time.Duration
+		attempts int
+		want     time.Duration
+	}{
+		{base: 30 * time.Second, limit: 6 * time.Hour, attempts: 1, want: 30 * time.Second},
+		{base: 30 * time.Second, limit: 6 * time.Hour, attempts: 2, want: time.Minute},
+		{base: 30 * time.Second, limit: 6 * time.Hour, attempts: 4, want: 4 * time.Minute},
+		{base: 30 * time.Second, limit: 6 * time.Hour, attempts: 50, want: 6 * time.Hour},
+		{base: time.Minute, limit: time.Hour, attempts: 10, want: time.Hour},
+		{base: time.Second, limit: 0, attempts: 3, want: 4 * time.Second},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, webhookBackoff(tt.base, tt.limit, tt.attempts))
+	}
+}
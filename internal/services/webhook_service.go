@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	"url-shortener/internal/utils"
+)
+
+const (
+	webhookSecretBytes          = 32
+	defaultWebhookDeliveryLimit = 50
+	maxWebhookDeliveryLimit     = 500
+)
+
+var ErrInvalidWebhook = errors.New("url must be an absolute http or https URL of a public host and eventTypes known link event types")
+
+//go:generate mockery --name=WebhookService --output=./mocks
+type WebhookService interface {
+	// CreateWebhook stores a subscription, with a random secret when it has none.
+	CreateWebhook(ctx context.Context, subscription *models.WebhookSubscription) error
+	GetWebhook(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+	ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error)
+	// UpdateWebhook replaces a subscription, keeping its secret when it has none.
+	UpdateWebhook(ctx context.Context, subscription *models.WebhookSubscription) error
+	DeleteWebhook(ctx context.Context, id int64) error
+	// ListDeliveries returns the latest deliveries of a subscription, limit defaults to 50 and is at most 500.
+	ListDeliveries(ctx context.Context, id int64, before int64, limit int) ([]models.WebhookDelivery, error)
+}
+
+type webhookServiceImpl struct {
+	repo         repositories.WebhookSubscriptionRepository
+	timeProvider utils.TimeProvider
+}
+
+func NewWebhookService(repo repositories.WebhookSubscriptionRepository, timeProvider utils.TimeProvider) WebhookService {
+	return &webhookServiceImpl{repo: repo, timeProvider: timeProvider}
+}
+
+// CreateWebhook implements WebhookService.
+func (s *webhookServiceImpl) CreateWebhook(ctx context.Context, subscription *models.WebhookSubscription) error {
+	if err := validateWebhook(subscription); err != nil {
+		return err
+	}
+	if subscription.Secret == "" {
+		secret := make([]byte, webhookSecretBytes)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		subscription.Secret = hex.EncodeToString(secret)
+	}
+	subscription.CreatedAt = s.timeProvider.Now()
+	return s.repo.CreateSubscription(ctx, subscription)
+}
+
+// GetWebhook implements WebhookService.
+func (s *webhookServiceImpl) GetWebhook(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	return s.repo.GetSubscription(ctx, id)
+}
+
+// ListWebhooks implements WebhookService.
+func (s *webhookServiceImpl) ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+// UpdateWebhook implements WebhookService.
+func (s *webhookServiceImpl) UpdateWebhook(ctx context.Context, subscription *models.WebhookSubscription) error {
+	if err := validateWebhook(subscription); err != nil {
+		return err
+	}
+	current, err := s.repo.GetSubscription(ctx, subscription.ID)
+	if err != nil {
+		return err
+	}
+	if subscription.Secret == "" {
+		subscription.Secret = current.Secret
+	}
+	subscription.CreatedAt = current.CreatedAt
+	subscription.UpdatedAt = s.timeProvider.Now()
+	return s.repo.UpdateSubscription(ctx, subscription)
+}
+
+// DeleteWebhook implements WebhookService.
+func (s *webhookServiceImpl) DeleteWebhook(ctx context.Context, id int64) error {
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+// ListDeliveries implements WebhookService. It returns repositories.ErrWebhookNotFound for unknown subscriptions
+// rather than an empty list.
+func (s *webhookServiceImpl) ListDeliveries(ctx context.Context, id int64, before int64, limit int) ([]models.WebhookDelivery, error) {
+	if _, err := s.repo.GetSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultWebhookDeliveryLimit
+	}
+	limit = min(limit, maxWebhookDeliveryLimit)
+	return s.repo.ListDeliveries(ctx, id, max(before, 0), limit)
+}
+
+func validateWebhook(subscription *models.WebhookSubscription) error {
+	target, err := url.Parse(subscription.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return ErrInvalidWebhook
+	}
+	// Names are checked again against the address dialled when the webhook is sent, see WebhookDispatchJob.
+	host := strings.ToLower(strings.TrimSuffix(target.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrInvalidWebhook
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicWebhookIP(ip) {
+		return ErrInvalidWebhook
+	}
+	for _, eventType := range subscription.EventTypes {
+		if !slices.Contains(models.LinkEventTypes, eventType) {
+			return ErrInvalidWebhook
+		}
+	}
+	return nil
+}
+
+// publicWebhookIP reports whether webhooks may be sent to ip, loopback, private, link-local, multicast and
+// unspecified addresses belong to this host or its network.
+func publicWebhookIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified()
+}
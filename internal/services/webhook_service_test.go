@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/models"
+	"url-shortener/internal/repositories"
+	repoMocks "url-shortener/internal/repositories/mocks"
+	utilsMocks "url-shortener/internal/utils/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var webhookNow = time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+
+func setupWebhookService() (*repoMocks.WebhookSubscriptionRepository, WebhookService) {
+	repo := &repoMocks.WebhookSubscriptionRepository{}
+	timeProvider := &utilsMocks.TimeProvider{}
+	timeProvider.On("Now").Return(webhookNow)
+	return repo, NewWebhookService(repo, timeProvider)
+}
+
+func TestWebhookService_CreateGeneratesSecret(t *testing.T) {
+	repo, service := setupWebhookService()
+	repo.On("CreateSubscription", context.Background(), mock.AnythingOfType("*models.WebhookSubscription")).Return(nil).Once()
+	subscription := &models.WebhookSubscription{URL: "https://example.com/hook", EventTypes: []string{models.LinkEventCreated}, Active: true}
+
+	err := service.CreateWebhook(context.Background(), subscription)
+
+	assert.NoError(t, err)
+	assert.Len(t, subscription.Secret, 64)
+	assert.Equal(t, webhookNow, subscription.CreatedAt)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookService_CreateRejectsInvalidWebhooks(t *testing.T) {
+	repo, service := setupWebhookService()
+
+	for _, subscription := range []*models.WebhookSubscription{
+		{URL: "ftp://example.com/hook"},
+		{URL: "/hook"},
+		{URL: "https://example.com/hook", EventTypes: []string{"link.clicked"}},
+		{URL: "http://localhost:8080/hook"},
+		{URL: "http://api.localhost./hook"},
+		{URL: "http://127.0.0.1/hook"},
+		{URL: "http://10.1.2.3/hook"},
+		{URL: "http://192.168.0.10:9000/hook"},
+		{URL: "http://169.254.169.254/latest/meta-data"},
+		{URL: "http://[::1]/hook"},
+		{URL: "http://[fe80::1]/hook"},
+		{URL: "http://0.0.0.0/hook"},
+	} {
+		assert.ErrorIs(t, service.CreateWebhook(context.Background(), subscription), ErrInvalidWebhook, subscription.URL)
+	}
+	repo.AssertNotCalled(t, "CreateSubscription")
+}
+
+func TestWebhookService_UpdateKeepsSecret(t *testing.T) {
+	repo, service := setupWebhookService()
+	createdAt := webhookNow.Add(-time.Hour)
+	repo.On("GetSubscription", context.Background(), int64(7)).
+		Return(&models.WebhookSubscription{ID: 7, URL: "https://old.example.com", Secret: "s3cret", CreatedAt: createdAt}, nil).Once()
+	repo.On("UpdateSubscription", context.Background(), &models.WebhookSubscription{ID: 7, URL: "https://example.com/hook",
+		Secret: "s3cret", CreatedAt: createdAt, UpdatedAt: webhookNow}).Return(nil).Once()
+
+	err := service.UpdateWebhook(context.Background(), &models.WebhookSubscription{ID: 7, URL: "https://example.com/hook"})
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookService_ListDeliveriesOfUnknownWebhook(t *testing.T) {
+	repo, service := setupWebhookService()
+	repo.On("GetSubscription", context.Background(), int64(9)).Return(nil, repositories.ErrWebhookNotFound).Once()
+
+	_, err := service.ListDeliveries(context.Background(), 9, 0, 0)
+
+	assert.ErrorIs(t, err, repositories.ErrWebhookNotFound)
+	repo.AssertNotCalled(t, "ListDeliveries")
+}
+
+func TestWebhookService_ListDeliveriesClampsLimit(t *testing.T) {
+	repo, service := setupWebhookService()
+	repo.On("GetSubscription", context.Background(), int64(7)).Return(&models.WebhookSubscription{ID: 7}, nil).Twice()
+	repo.On("ListDeliveries", context.Background(), int64(7), int64(0), 50).Return([]models.WebhookDelivery{}, nil).Once()
+	repo.On("ListDeliveries", context.Background(), int64(7), int64(30), 500).Return([]models.WebhookDelivery{}, nil).Once()
+
+	_, err := service.ListDeliveries(context.Background(), 7, 0, 0)
+	assert.NoError(t, err)
+	_, err = service.ListDeliveries(context.Background(), 7, 30, 10000)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//go:embed bot_rules.txt
+var defaultBotRules string
+
+// BotClassifier tells crawlers, link unfurlers and scripts apart from people by their User-Agent header.
+type BotClassifier struct {
+	rules []string
+}
+
+// NewBotClassifier uses the rules in the file at path, or the rules built into the binary when path is empty.
+func NewBotClassifier(path string) (*BotClassifier, error) {
+	if path == "" {
+		return &BotClassifier{rules: parseBotRules(defaultBotRules)}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading bot rules: %w", err)
+	}
+	rules := parseBotRules(string(data))
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("no bot rules in %s", path)
+	}
+	return &BotClassifier{rules: rules}, nil
+}
+
+// IsBot reports whether the User-Agent matches a rule. Requests without one are not counted as bots.
+func (c *BotClassifier) IsBot(userAgent string) bool {
+	ua := strings.ToLower(userAgent)
+	for _, rule := range c.rules {
+		if strings.Contains(ua, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBotRules reads one substring per line, skipping blank lines and # comments.
+func parseBotRules(data string) []string {
+	rules := []string{}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		rule := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if rule == "" || strings.HasPrefix(rule, "#") {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBotRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		rules []string
+	}{
+		{name: "empty", data: "", rules: []string{}},
+		{name: "one rule per line", data: "slackbot\ncurl/\n", rules: []string{"slackbot", "curl/"}},
+		{name: "lower cased and trimmed", data: "  Google-PageRenderer \t\n", rules: []string{"google-pagerenderer"}},
+		{name: "comments and blank lines skipped", data: "# link unfurlers\n\nslackbot\n   \n  # indented comment\ndiscordbot", rules: []string{"slackbot", "discordbot"}},
+		{name: "windows line endings", data: "slackbot\r\ndiscordbot\r\n", rules: []string{"slackbot", "discordbot"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.rules, parseBotRules(tt.data))
+		})
+	}
+}
+
+func TestBotClassifier_DefaultRules(t *testing.T) {
+	bots, err := NewBotClassifier("")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		userAgent string
+		isBot     bool
+	}{
+		{userAgent: "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", isBot: true},
+		{userAgent: "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", isBot: true},
+		{userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", isBot: true},
+		{userAgent: "curl/8.4.0", isBot: true},
+		{userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", isBot: false},
+		{userAgent: "", isBot: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.isBot, bots.IsBot(tt.userAgent), tt.userAgent)
+	}
+}
+
+func TestBotClassifier_RulesFileOverridesDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bots.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("# only our monitor\nUptimeChecker\n"), 0o600))
+
+	bots, err := NewBotClassifier(path)
+
+	assert.NoError(t, err)
+	assert.True(t, bots.IsBot("Mozilla/5.0 (compatible; uptimechecker/2.0)"))
+	assert.False(t, bots.IsBot("Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)"))
+}
+
+func TestBotClassifier_RulesFileErrors(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "bots.txt")
+	assert.NoError(t, os.WriteFile(empty, []byte("# nothing yet\n\n"), 0o600))
+
+	for _, path := range []string{empty, filepath.Join(t.TempDir(), "missing.txt")} {
+		bots, err := NewBotClassifier(path)
+
+		assert.Error(t, err, path)
+		assert.Nil(t, bots, path)
+	}
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+type GeoLocation struct {
+	// Country is the ISO 3166-1 code, e.g. "DE".
+	Country string
+	// Region is the ISO 3166-2 code of the largest subdivision, e.g. "DE-BY".
+	Region string
+	City   string
+}
+
+//go:generate mockery --name=GeoIPResolver --output=./mocks
+type GeoIPResolver interface {
+	// Lookup returns an empty location for invalid, private or unknown addresses.
+	Lookup(ip string) GeoLocation
+}
+
+// MaxMindGeoIPResolver resolves addresses against a local MaxMind (.mmdb) database such as GeoLite2-City.
+// The file is read into memory, so replacing it on disk never affects lookups in flight.
+type MaxMindGeoIPResolver struct {
+	path    string
+	reader  atomic.Pointer[maxminddb.Reader]
+	modTime time.Time
+}
+
+type geoIPRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"subdivisions"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+}
+
+// NewGeoIPResolver opens the database at path. An empty path gives a resolver that never finds a location.
+func NewGeoIPResolver(path string) (*MaxMindGeoIPResolver, error) {
+	resolver := &MaxMindGeoIPResolver{path: path}
+	if path == "" {
+		return resolver, nil
+	}
+	if err := resolver.load(); err != nil {
+		return nil, err
+	}
+	return resolver, nil
+}
+
+// Lookup implements GeoIPResolver.
+func (r *MaxMindGeoIPResolver) Lookup(ip string) GeoLocation {
+	reader := r.reader.Load()
+	parsed := net.ParseIP(ip)
+	if reader == nil || parsed == nil {
+		return GeoLocation{}
+	}
+	var record geoIPRecord
+	if err := reader.Lookup(parsed, &record); err != nil {
+		return GeoLocation{}
+	}
+	location := GeoLocation{Country: record.Country.ISOCode, City: record.City.Names["en"]}
+	if len(record.Subdivisions) > 0 && record.Subdivisions[0].ISOCode != "" && location.Country != "" {
+		location.Region = location.Country + "-" + record.Subdivisions[0].ISOCode
+	}
+	return location
+}
+
+// StartReloading checks the database file every interval until ctx is cancelled and swaps in the new
+// version when its modification time changes. A file that fails to load is logged and the old one kept.
+func (r *MaxMindGeoIPResolver) StartReloading(ctx context.Context, interval time.Duration) {
+	if r.path == "" || interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				info, err := os.Stat(r.path)
+				if err != nil || info.ModTime().Equal(r.modTime) {
+					continue
+				}
+				if err := r.load(); err != nil {
+					log.Printf("Error reloading geo-IP database: %v", err)
+					continue
+				}
+				log.Printf("Reloaded geo-IP database %s", r.path)
+			}
+		}
+	}()
+}
+
+func (r *MaxMindGeoIPResolver) load() error {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return fmt.Errorf("error opening geo-IP database: %w", err)
+	}
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("error reading geo-IP database: %w", err)
+	}
+	reader, err := maxminddb.FromBytes(data)
+	if err != nil {
+		return fmt.Errorf("error parsing geo-IP database: %w", err)
+	}
+	r.reader.Store(reader)
+	r.modTime = info.ModTime()
+	return nil
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The fixtures in testdata hold a single network, 81.2.69.0/24, located in London and in Munich.
+var (
+	london = GeoLocation{Country: "GB", Region: "GB-ENG", City: "London"}
+	munich = GeoLocation{Country: "DE", Region: "DE-BY", City: "Munich"}
+)
+
+// copyGeoIPFixture writes the fixture to path with the given modification time.
+func copyGeoIPFixture(t *testing.T, fixture string, path string, modTime time.Time) {
+	data, err := os.ReadFile(filepath.Join("testdata", fixture))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestMaxMindGeoIPResolver_Lookup(t *testing.T) {
+	resolver, err := NewGeoIPResolver(filepath.Join("testdata", "geoip-london.mmdb"))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		ip   string
+		want GeoLocation
+	}{
+		{ip: "81.2.69.142", want: london},
+		{ip: "81.2.70.1", want: GeoLocation{}},
+		{ip: "10.0.0.1", want: GeoLocation{}},
+		{ip: "not an ip", want: GeoLocation{}},
+		{ip: "", want: GeoLocation{}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, resolver.Lookup(tt.ip), tt.ip)
+	}
+}
+
+func TestMaxMindGeoIPResolver_WithoutDatabase(t *testing.T) {
+	resolver, err := NewGeoIPResolver("")
+	assert.NoError(t, err)
+
+	assert.Equal(t, GeoLocation{}, resolver.Lookup("81.2.69.142"))
+	// Nothing to reload, this must not start a goroutine reading an empty path.
+	resolver.StartReloading(context.Background(), time.Millisecond)
+	assert.Equal(t, GeoLocation{}, resolver.Lookup("81.2.69.142"))
+}
+
+func TestNewGeoIPResolver_Errors(t *testing.T) {
+	broken := filepath.Join(t.TempDir(), "broken.mmdb")
+	assert.NoError(t, os.WriteFile(broken, []byte("not a database"), 0o600))
+
+	for _, path := range []string{broken, filepath.Join(t.TempDir(), "missing.mmdb")} {
+		resolver, err := NewGeoIPResolver(path)
+
+		assert.Error(t, err, path)
+		assert.Nil(t, resolver, path)
+	}
+}
+
+func TestMaxMindGeoIPResolver_StartReloading(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "GeoLite2-City.mmdb")
+	modTime := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	copyGeoIPFixture(t, "geoip-london.mmdb", path, modTime)
+	resolver, err := NewGeoIPResolver(path)
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resolver.StartReloading(ctx, 5*time.Millisecond)
+
+	// A file that does not load keeps the old database.
+	assert.NoError(t, os.WriteFile(path, []byte("half written"), 0o600))
+	assert.NoError(t, os.Chtimes(path, modTime.Add(time.Hour), modTime.Add(time.Hour)))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, london, resolver.Lookup("81.2.69.142"))
+
+	copyGeoIPFixture(t, "geoip-munich.mmdb", path, modTime.Add(2*time.Hour))
+	assert.Eventually(t, func() bool {
+		return resolver.Lookup("81.2.69.142") == munich
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestMaxMindGeoIPResolver_StartReloadingKeepsUnchangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "GeoLite2-City.mmdb")
+	modTime := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	copyGeoIPFixture(t, "geoip-london.mmdb", path, modTime)
+	resolver, err := NewGeoIPResolver(path)
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resolver.StartReloading(ctx, 5*time.Millisecond)
+
+	// Same modification time, so the new contents are not picked up.
+	copyGeoIPFixture(t, "geoip-munich.mmdb", path, modTime)
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, london, resolver.Lookup("81.2.69.142"))
+}
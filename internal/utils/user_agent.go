@@ -0,0 +1,95 @@
+package utils
+
+import "strings"
+
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	Unknown       = "unknown"
+)
+
+type UserAgent struct {
+	Browser     string
+	OS          string
+	DeviceClass string
+	IsBot       bool
+}
+
+// The order matters: Edge and Opera also send Chrome, Chrome also sends Safari.
+var browserTokens = []struct{ token, name string }{
+	{"edg/", "Edge"},
+	{"edga/", "Edge"},
+	{"edgios/", "Edge"},
+	{"opr/", "Opera"},
+	{"opera", "Opera"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"firefox/", "Firefox"},
+	{"fxios/", "Firefox"},
+	{"crios/", "Chrome"},
+	{"chrome/", "Chrome"},
+	{"chromium/", "Chrome"},
+	{"safari/", "Safari"},
+	{"msie ", "Internet Explorer"},
+	{"trident/", "Internet Explorer"},
+	{"curl/", "curl"},
+	{"wget/", "Wget"},
+}
+
+// iOS and Android come before macOS and Linux because their user agents mention those too.
+var osTokens = []struct{ token, name string }{
+	{"iphone", "iOS"},
+	{"ipad", "iOS"},
+	{"ipod", "iOS"},
+	{"android", "Android"},
+	{"windows", "Windows"},
+	{"cros", "ChromeOS"},
+	{"mac os x", "macOS"},
+	{"macintosh", "macOS"},
+	{"linux", "Linux"},
+}
+
+// ParseUserAgent classifies a User-Agent header into browser, OS and device class. It only knows the
+// common families, anything else is reported as unknown. The device class is bot whenever bots says so, so the
+// stats breakdown and the bot flag never disagree.
+func ParseUserAgent(header string, bots *BotClassifier) UserAgent {
+	userAgent := UserAgent{Browser: Unknown, OS: Unknown, DeviceClass: Unknown}
+	ua := strings.ToLower(header)
+	if ua == "" {
+		return userAgent
+	}
+	for _, b := range browserTokens {
+		if strings.Contains(ua, b.token) {
+			userAgent.Browser = b.name
+			break
+		}
+	}
+	for _, o := range osTokens {
+		if strings.Contains(ua, o.token) {
+			userAgent.OS = o.name
+			break
+		}
+	}
+	userAgent.IsBot = bots.IsBot(header)
+	if userAgent.IsBot {
+		userAgent.DeviceClass = DeviceBot
+	} else {
+		userAgent.DeviceClass = deviceClass(ua)
+	}
+	return userAgent
+}
+
+func deviceClass(ua string) string {
+	switch {
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") ||
+		(strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")):
+		return DeviceTablet
+	case strings.Contains(ua, "mobi") || strings.Contains(ua, "iphone") || strings.Contains(ua, "ipod"):
+		return DeviceMobile
+	case strings.Contains(ua, "windows") || strings.Contains(ua, "macintosh") ||
+		strings.Contains(ua, "linux") || strings.Contains(ua, "cros"):
+		return DeviceDesktop
+	}
+	return Unknown
+}
@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	bots, err := NewBotClassifier("")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		header string
+		want   UserAgent
+	}{
+		{
+			name:   "empty",
+			header: "",
+			want:   UserAgent{Browser: Unknown, OS: Unknown, DeviceClass: Unknown},
+		},
+		{
+			name:   "unknown family",
+			header: "Nokia6230/2.0 (04.44) Profile/MIDP-2.0 Configuration/CLDC-1.1",
+			want:   UserAgent{Browser: Unknown, OS: Unknown, DeviceClass: Unknown},
+		},
+		{
+			name:   "Edge before Chrome and Safari",
+			header: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.2478.80",
+			want:   UserAgent{Browser: "Edge", OS: "Windows", DeviceClass: DeviceDesktop},
+		},
+		{
+			name:   "Edge on Android",
+			header: "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36 EdgA/124.0.2478.62",
+			want:   UserAgent{Browser: "Edge", OS: "Android", DeviceClass: DeviceMobile},
+		},
+		{
+			name:   "Edge on iOS",
+			header: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 EdgiOS/124.0.2478.50 Mobile/15E148 Safari/605.1.15",
+			want:   UserAgent{Browser: "Edge", OS: "iOS", DeviceClass: DeviceMobile},
+		},
+		{
+			name:   "Opera before Chrome",
+			header: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 OPR/106.0.0.0",
+			want:   UserAgent{Browser: "Opera", OS: "Windows", DeviceClass: DeviceDesktop},
+		},
+		{
+			name:   "Presto Opera",
+			header: "Opera/9.80 (Windows NT 6.1) Presto/2.12.388 Version/12.18",
+			want:   UserAgent{Browser: "Opera", OS: "Windows", DeviceClass: DeviceDesktop},
+		},
+		{
+			name:   "Samsung Internet before Chrome",
+			header: "Mozilla/5.0 (Linux; Android 13; SM-S911B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/24.0 Chrome/117.0.0.0 Mobile Safari/537.36",
+			want:   UserAgent{Browser: "Samsung Internet", OS: "Android", DeviceClass: DeviceMobile},
+		},
+		{
+			name:   "Firefox on Linux",
+			header: "Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0",
+			want:   UserAgent{Browser: "Firefox", OS: "Linux", DeviceClass: DeviceDesktop},
+		},
+		{
+			name:   "Firefox on iOS before Safari",
+			header: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) FxiOS/125.0 Mobile/15E148 Safari/605.1.15",
+			want:   UserAgent{Browser: "Firefox", OS: "iOS", DeviceClass: DeviceMobile},
+		},
+		{
+			name:   "Chrome on iPad is a tablet although it says Mobile",
+			header: "Mozilla/5.0 (iPad; CPU OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/124.0.6367.88 Mobile/15E148 Safari/604.1",
+			want:   UserAgent{Browser: "Chrome", OS: "iOS", DeviceClass: DeviceTablet},
+		},
+		{
+			name:   "Chrome before Safari on macOS",
+			header: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			want:   UserAgent{Browser: "Chrome", OS: "macOS", DeviceClass: DeviceDesktop},
+		},
+		{
+			name:   "Chromium",
+			header: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Ubuntu Chromium/79.0.3945.79 Safari/537.36",
+			want:   UserAgent{Browser: "Chrome", OS: "Linux", DeviceClass: DeviceDesktop},
+		},
+		{
+			name:   "Android without Mobile is a tablet",
+			header: "Mozilla/5.0 (Linux; Android 13; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			want:   UserAgent{Browser: "Chrome", OS: "Android", DeviceClass: DeviceTablet},
+		},
+		{
+			name:   "Android phone before Linux",
+			header: "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36",
+			want:   UserAgent{Browser: "Chrome", OS: "Android", DeviceClass: DeviceMobile},
+		},
+		{
+			name:   "ChromeOS",
+			header: "Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			want:   UserAgent{Browser: "Chrome", OS: "ChromeOS", DeviceClass: DeviceDesktop},
+		},
+		{
+			name:   "Safari on iPhone before macOS",
+			header: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+			want:   UserAgent{Browser: "Safari", OS: "iOS", DeviceClass: DeviceMobile},
+		},
+		{
+			name:   "Safari on iPad",
+			header: "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1",
+			want:   UserAgent{Browser: "Safari", OS: "iOS", DeviceClass: DeviceTablet},
+		},
+		{
+			name:   "Safari on macOS",
+			header: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15",
+			want:   UserAgent{Browser: "Safari", OS: "macOS", DeviceClass: DeviceDesktop},
+		},
+		{
+			name:   "Internet Explorer 11",
+			header: "Mozilla/5.0 (Windows NT 10.0; WOW64; Trident/7.0; rv:11.0) like Gecko",
+			want:   UserAgent{Browser: "Internet Explorer", OS: "Windows", DeviceClass: DeviceDesktop},
+		},
+		{
+			name:   "Internet Explorer 9",
+			header: "Mozilla/5.0 (compatible; MSIE 9.0; Windows NT 6.1; Trident/5.0)",
+			want:   UserAgent{Browser: "Internet Explorer", OS: "Windows", DeviceClass: DeviceDesktop},
+		},
+		{
+			name:   "curl is a bot",
+			header: "curl/8.4.0",
+			want:   UserAgent{Browser: "curl", OS: Unknown, DeviceClass: DeviceBot, IsBot: true},
+		},
+		{
+			name:   "Wget is a bot",
+			header: "Wget/1.21.4",
+			want:   UserAgent{Browser: "Wget", OS: Unknown, DeviceClass: DeviceBot, IsBot: true},
+		},
+		{
+			name:   "bot before mobile",
+			header: "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			want:   UserAgent{Browser: "Chrome", OS: "Android", DeviceClass: DeviceBot, IsBot: true},
+		},
+		{
+			name:   "link unfurler",
+			header: "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)",
+			want:   UserAgent{Browser: Unknown, OS: Unknown, DeviceClass: DeviceBot, IsBot: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseUserAgent(tt.header, bots))
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// VisitorFingerprinter derives a pseudonymous visitor ID from request attributes with a keyed hash, so unique
+// visitors can be counted without storing IP addresses and the IDs cannot be reversed without the secret.
+type VisitorFingerprinter struct {
+	secret []byte
+}
+
+func NewVisitorFingerprinter(secret string) *VisitorFingerprinter {
+	return &VisitorFingerprinter{secret: []byte(secret)}
+}
+
+// Fingerprint returns an empty ID when no secret is configured, which turns unique visitor counting off.
+func (f *VisitorFingerprinter) Fingerprint(clientIP string, userAgent string) string {
+	if len(f.secret) == 0 {
+		return ""
+	}
+	mac := hmac.New(sha256.New, f.secret)
+	mac.Write([]byte(clientIP))
+	mac.Write([]byte{0})
+	mac.Write([]byte(userAgent))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}